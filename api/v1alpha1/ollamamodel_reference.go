@@ -0,0 +1,115 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import "strings"
+
+const (
+	// DefaultRegistryHost is the registry of the models named without a host
+	DefaultRegistryHost = "registry.ollama.ai"
+	// DefaultModelNamespace is the namespace of the models named without one,
+	// holding the Ollama library
+	DefaultModelNamespace = "library"
+	// DefaultModelTag is the tag of the models referenced without one
+	DefaultModelTag = "latest"
+)
+
+// ModelReference is a model reference split the way Ollama splits them:
+// [host/][namespace/]model[:tag]. A name with two segments is a namespace and
+// a model, such as "username/custom-model"; the host comes first when there
+// are three, such as "registry.example.com/team/model".
+// +kubebuilder:object:generate=false
+type ModelReference struct {
+	Host      string
+	Namespace string
+	Model     string
+	Tag       string
+}
+
+// ParseModelReference splits ref, filling the parts it omits with the
+// defaults of Ollama: the public registry, the library namespace and the
+// latest tag.
+func ParseModelReference(ref string) ModelReference {
+	name, tag := ref, ""
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		name, tag = ref[:i], ref[i+1:]
+	}
+	host, namespace, model := splitModelName(name)
+
+	r := ModelReference{Host: host, Namespace: namespace, Model: model, Tag: tag}
+	if r.Host == "" {
+		r.Host = DefaultRegistryHost
+	}
+	if r.Namespace == "" {
+		r.Namespace = DefaultModelNamespace
+	}
+	if r.Tag == "" {
+		r.Tag = DefaultModelTag
+	}
+	return r
+}
+
+// splitModelName splits a model name without its tag from the right, as the
+// host may itself contain slashes once it is invalid
+func splitModelName(name string) (host, namespace, model string) {
+	model = name
+	if i := strings.LastIndex(model, "/"); i >= 0 {
+		namespace, model = model[:i], model[i+1:]
+	}
+	if i := strings.LastIndex(namespace, "/"); i >= 0 {
+		host, namespace = namespace[:i], namespace[i+1:]
+	}
+	return host, namespace, model
+}
+
+// Repository returns the repository of the model in its registry
+func (r ModelReference) Repository() string {
+	return r.Namespace + "/" + r.Model
+}
+
+// Name returns the shortest name Ollama knows the model by, leaving out the
+// default host and namespace, as the Ollama server lists it
+func (r ModelReference) Name() string {
+	switch {
+	case r.Host != DefaultRegistryHost:
+		return r.Host + "/" + r.Repository()
+	case r.Namespace != DefaultModelNamespace:
+		return r.Repository()
+	}
+	return r.Model
+}
+
+// String returns the shortest reference of the model, as the Ollama server
+// lists it
+func (r ModelReference) String() string {
+	return r.Name() + ":" + r.Tag
+}
+
+// NormalizeModelReference returns the canonical form of a model reference:
+// its shortest form, lowercased since Ollama compares names regardless of
+// case. References naming the same model, such as "llama3.2",
+// "llama3.2:latest" and "registry.ollama.ai/library/llama3.2:latest", have
+// the same canonical form.
+func NormalizeModelReference(ref string) string {
+	return strings.ToLower(ParseModelReference(ref).String())
+}
+
+// SameModel tells whether two references name the same model, however
+// qualified
+func SameModel(a, b string) bool {
+	return NormalizeModelReference(a) == NormalizeModelReference(b)
+}
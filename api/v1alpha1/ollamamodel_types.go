@@ -17,6 +17,10 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -24,7 +28,7 @@ import (
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
 // ModelState represents the current state of a model
-// +kubebuilder:validation:Enum=Pending;Pulling;Ready;Failed
+// +kubebuilder:validation:Enum=Pending;Pulling;Ready;Failed;Deleting
 type ModelState string
 
 const (
@@ -36,36 +40,387 @@ const (
 	StateReady ModelState = "Ready"
 	// StateFailed indicates the model pull has failed
 	StateFailed ModelState = "Failed"
+	// StateDeleting indicates the model is being deleted from Ollama after its
+	// resource was deleted
+	StateDeleting ModelState = "Deleting"
+)
+
+// ModelType is the kind of model, which determines how it is checked once pulled
+// +kubebuilder:validation:Enum=generation;embedding
+type ModelType string
+
+const (
+	// ModelTypeGeneration is a model generating text or chat completions
+	ModelTypeGeneration ModelType = "generation"
+	// ModelTypeEmbedding is a model producing embedding vectors
+	ModelTypeEmbedding ModelType = "embedding"
+)
+
+// UpdatePolicy selects whether new versions of a model found by the update
+// check are pulled automatically
+// +kubebuilder:validation:Enum=Manual;Auto
+type UpdatePolicy string
+
+const (
+	// UpdatePolicyManual only reports new versions, which are pulled by a refresh
+	UpdatePolicyManual UpdatePolicy = "Manual"
+	// UpdatePolicyAuto refreshes the model whenever a new version is found
+	UpdatePolicyAuto UpdatePolicy = "Auto"
 )
 
-// OllamaModelSpec defines the desired state of OllamaModel.
+// FailureReason classifies why the last pull of a model failed
+// +kubebuilder:validation:Enum=NotFound;Unauthorized;NetworkTimeout;OutOfSpace;Cancelled;DerivedModel;EmbeddingCheck;Unknown
+type FailureReason string
+
+const (
+	// FailureNotFound means the model or tag does not exist in the registry
+	FailureNotFound FailureReason = "NotFound"
+	// FailureUnauthorized means the registry refused access to the model
+	FailureUnauthorized FailureReason = "Unauthorized"
+	// FailureNetworkTimeout means the Ollama server or the registry could not
+	// be reached, or did not answer in time
+	FailureNetworkTimeout FailureReason = "NetworkTimeout"
+	// FailureOutOfSpace means the Ollama server ran out of disk space
+	FailureOutOfSpace FailureReason = "OutOfSpace"
+	// FailureCancelled means the pull was cancelled on request
+	FailureCancelled FailureReason = "Cancelled"
+	// FailureDerivedModel means the model was pulled, but the derived model
+	// with the customizations of the spec could not be created
+	FailureDerivedModel FailureReason = "DerivedModel"
+	// FailureEmbeddingCheck means the model was pulled, but failed the check
+	// of an embedding model
+	FailureEmbeddingCheck FailureReason = "EmbeddingCheck"
+	// FailureUnknown is any other failure
+	FailureUnknown FailureReason = "Unknown"
+)
+
+// Terminal reports whether the model must not be pulled again automatically,
+// because pulling it again cannot fix the failure or the pull was cancelled
+func (r FailureReason) Terminal() bool {
+	return r == FailureNotFound || r == FailureUnauthorized || r == FailureCancelled
+}
+
+// RefreshAnnotation requests a model to be pulled again whenever it is set to
+// a new value, such as the current time the way `kubectl rollout restart`
+// does. The controller records the last value it handled in
+// status.lastHandledRefresh and leaves the annotation alone.
+const RefreshAnnotation = "ollama.smithforge.dev/refresh"
+
+// DigestAnnotation pins a model to a digest. The controller reports a
+// DigestMismatch event when the pulled model has a different digest.
+const DigestAnnotation = "ollama.smithforge.dev/digest"
+
+// CreatedByAnnotation and RefreshedByAnnotation record the authenticated
+// principal, such as "apikey:ci", that created or last refreshed a model
+// through the API
+const (
+	CreatedByAnnotation   = "ollama.smithforge.dev/created-by"
+	RefreshedByAnnotation = "ollama.smithforge.dev/refreshed-by"
+)
+
+// RetryAnnotation, when set to "true", pulls a Failed model again even though
+// its retries are exhausted or its failure is terminal. The controller removes
+// it once the retry has started.
+const RetryAnnotation = "ollama.smithforge.dev/retry"
+
+// CancelPullAnnotation, when set to "true", cancels the pull or refresh of a
+// model. The controller removes it once the pull has stopped.
+const CancelPullAnnotation = "ollama.smithforge.dev/cancel-pull"
+
+// ForceDeleteAnnotation, when set to "true", lets a deleted model go without
+// removing it from Ollama, such as when the Ollama server is gone for good
+const ForceDeleteAnnotation = "ollama.smithforge.dev/force-delete"
+
+// ModelReferenceField is the field index, registered by the controller, that
+// maps OllamaModels to the Ollama model they manage ("name:tag"), in the
+// canonical form of NormalizeModelReference
+const ModelReferenceField = "spec.modelReference"
+
+// PullLogKey is the ConfigMap data key holding the log of a model's most recent pull
+const PullLogKey = "pull.log"
+
+// PullProgressKey is the ConfigMap data key holding the progress of the layer
+// currently being downloaded, as "<completed>/<total>" bytes
+const PullProgressKey = "progress"
+
+// PullLogConfigMapName returns the name of the ConfigMap holding the pull log of a model
+func PullLogConfigMapName(modelName string) string {
+	return modelName + "-pull-log"
+}
+
+// PrewarmLabel, set to "true" on a PersistentVolumeClaim holding the models
+// directory of an Ollama replica, has the models marked for pre-warming pulled
+// into it once, before the replica uses it
+const PrewarmLabel = "ollama.smithforge.dev/prewarm"
+
+// PrewarmedAnnotation is set on a pre-warmed PersistentVolumeClaim to the
+// comma-separated list of the models pulled into it
+const PrewarmedAnnotation = "ollama.smithforge.dev/prewarmed"
+
+// BlobsClaimName returns the name of the PersistentVolumeClaim the blobs of a model are exported to
+func BlobsClaimName(modelName string) string {
+	return modelName + "-blobs"
+}
+
+// OllamaModelSpec defines the desired state of OllamaModel. Combinations of
+// fields that cannot work together are rejected by the API server.
+// +kubebuilder:validation:XValidation:rule="!has(self.modelRef) || !has(self.quantization)",message="quantization cannot be set with modelRef, which names the quantization itself"
+// +kubebuilder:validation:XValidation:rule="!has(self.expectedDimensions) || (has(self.type) && self.type == 'embedding')",message="expectedDimensions can only be set for models of type embedding"
+// +kubebuilder:validation:XValidation:rule="!has(self.restoreFrom) || !has(self.updatePolicy) || self.updatePolicy != 'Auto'",message="updatePolicy cannot be Auto with restoreFrom, as restored models are not pulled from the registry"
 type OllamaModelSpec struct {
-	// Name is the name of the Ollama model (e.g., "llama3.2", "gemma3")
+	// Name is the name of the Ollama model (e.g., "llama3.2", "gemma3"). It may be
+	// qualified with a namespace and the host of the registry serving the model,
+	// as in "registry.example.com/team/model".
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=415
+	// +kubebuilder:validation:Pattern=`^(([A-Za-z0-9_][A-Za-z0-9_.-]*(:[0-9]+)?/)?[A-Za-z0-9_][A-Za-z0-9_-]*/)?[A-Za-z0-9_][A-Za-z0-9_.-]*$`
 	Name string `json:"name"`
 
 	// Tag is the version/tag of the model (e.g., "7b", "1b")
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=80
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_][A-Za-z0-9_.-]*$`
 	Tag string `json:"tag"`
+
+	// Quantization selects a quantization of the model (e.g., "q4_K_M", "q8_0").
+	// It is appended to the tag, following the Ollama library's tag naming, so
+	// that tag "8b-instruct" with quantization "q4_K_M" pulls "8b-instruct-q4_K_M".
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_]+$`
+	// +kubebuilder:validation:MaxLength=32
+	// +optional
+	Quantization string `json:"quantization,omitempty"`
+
+	// ModelRef, when set, is the Ollama model reference pulled and served
+	// verbatim instead of name:tag, for the naming schemes name and tag cannot
+	// express (e.g., "hf.co/bartowski/Llama-3.2-1B-Instruct-GGUF:Q4_K_M"). Name
+	// and tag are then only used for display, and quantization cannot be set.
+	// +kubebuilder:validation:MaxLength=512
+	// +kubebuilder:validation:Pattern=`^\S+$`
+	// +optional
+	ModelRef string `json:"modelRef,omitempty"`
+
+	// Parameters are runtime parameters such as num_ctx, temperature or stop,
+	// as given in the PARAMETER lines of a Modelfile. When set, a model derived
+	// from the pulled one is created with them and reported in
+	// status.derivedModel. Parameters taking several values, such as stop, take
+	// one value per line.
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+
+	// System is the system prompt baked into the derived model, as given in the
+	// SYSTEM instruction of a Modelfile
+	// +optional
+	System string `json:"system,omitempty"`
+
+	// Template is the prompt template baked into the derived model, as given in
+	// the TEMPLATE instruction of a Modelfile (Go template syntax)
+	// +optional
+	Template string `json:"template,omitempty"`
+
+	// Type is the kind of model, "generation" (the default) or "embedding".
+	// Embedding models are checked once pulled by embedding a probe text, and
+	// the dimension of their vectors is reported in status.embeddingDimensions.
+	// +optional
+	Type ModelType `json:"type,omitempty"`
+
+	// ExpectedDimensions is the dimension of the vectors an embedding model must
+	// produce. The model is marked Failed when it produces vectors of another
+	// dimension, which would break the vector stores built with it.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ExpectedDimensions *int32 `json:"expectedDimensions,omitempty"`
+
+	// Prewarm marks the model to be pulled into new Ollama volumes, the
+	// PersistentVolumeClaims labeled ollama.smithforge.dev/prewarm=true, before
+	// their replica starts serving
+	// +optional
+	Prewarm bool `json:"prewarm,omitempty"`
+
+	// Export, when set, copies the blobs of the model to a PersistentVolumeClaim
+	// once it is Ready, so that other pods can mount the weights without going
+	// through the Ollama server
+	// +optional
+	Export *ModelExport `json:"export,omitempty"`
+
+	// RetryPolicy bounds how often a failed pull is retried
+	// +optional
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+
+	// Limits bounds the inference requests the gateway lets through for the
+	// model, so that a single tenant cannot monopolize it
+	// +optional
+	Limits *InferenceLimits `json:"limits,omitempty"`
+
+	// RestoreFrom, when set, restores the model from the frozen copy of an
+	// OllamaModelSnapshot of the namespace instead of pulling it from the
+	// registry, so that it is exactly the model the snapshot captured
+	// +optional
+	RestoreFrom *SnapshotReference `json:"restoreFrom,omitempty"`
+
+	// RepullOnCorruption deletes the model and pulls it again when the
+	// integrity check finds it corrupted, rather than only reporting it with
+	// the Corrupted condition
+	// +optional
+	RepullOnCorruption bool `json:"repullOnCorruption,omitempty"`
+
+	// UpdatePolicy is Manual (the default) to only report new versions of the
+	// model's tag found by the update check with the NewVersionAvailable
+	// condition, or Auto to also refresh the model when one is found. Models
+	// pinned to a digest are not refreshed automatically.
+	// +optional
+	UpdatePolicy UpdatePolicy `json:"updatePolicy,omitempty"`
+
+	// UpdateWindow restricts the refreshes of the Auto update policy to daily
+	// periods, such as nights. A new version found outside of them is pulled
+	// once the next one opens. Refreshes requested explicitly are not held.
+	// +optional
+	UpdateWindow *UpdateWindow `json:"updateWindow,omitempty"`
+}
+
+// SnapshotReference names an OllamaModelSnapshot
+type SnapshotReference struct {
+	// Name is the name of the OllamaModelSnapshot of the namespace
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// UpdateWindow is the periods during which the Auto update policy may
+// refresh a model
+type UpdateWindow struct {
+	// TimeZone is the IANA time zone the windows are in, such as
+	// "Europe/Berlin"; UTC when empty
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// Windows are the periods during which new versions are pulled
+	// +kubebuilder:validation:MinItems=1
+	Windows []ScheduleWindow `json:"windows"`
+}
+
+// InferenceLimits bounds the inference requests for a model. Each replica of
+// the gateway enforces them on the requests it receives.
+type InferenceLimits struct {
+	// RequestsPerSecond is the rate of requests let through, in bursts of as
+	// many requests; the others are rejected with 429 Too Many Requests
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	RequestsPerSecond *int32 `json:"requestsPerSecond,omitempty"`
+
+	// MaxConcurrentRequests is the number of requests served at the same time;
+	// the others are rejected with 429 Too Many Requests
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxConcurrentRequests *int32 `json:"maxConcurrentRequests,omitempty"`
+
+	// MaxTokens caps the tokens generated for a request, lowering the
+	// num_predict option of generate and chat requests asking for more
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxTokens *int32 `json:"maxTokens,omitempty"`
+}
+
+// RetryPolicy bounds the automatic retries of failed pulls
+type RetryPolicy struct {
+	// MaxAttempts is the number of consecutive failed pulls after which the
+	// model stays Failed until a retry is requested with the
+	// ollama.smithforge.dev/retry annotation; unset retries forever
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxAttempts *int32 `json:"maxAttempts,omitempty"`
+}
+
+// ModelExport configures the PersistentVolumeClaim the blobs of a model are exported to
+type ModelExport struct {
+	// StorageClassName is the storage class of the claim; the cluster default is used when empty
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
+
+	// AccessModes of the claim, ReadWriteOnce by default. A storage class
+	// supporting ReadOnlyMany or ReadWriteMany lets several pods mount the
+	// blobs at the same time.
+	// +optional
+	AccessModes []corev1.PersistentVolumeAccessMode `json:"accessModes,omitempty"`
+
+	// Size is the capacity requested for the claim, by default the size of the
+	// model with some headroom
+	// +optional
+	Size *resource.Quantity `json:"size,omitempty"`
+}
+
+// QualifiedTag returns the tag with the quantization, if any, appended
+func (s OllamaModelSpec) QualifiedTag() string {
+	if s.Quantization == "" || strings.HasSuffix(s.Tag, "-"+s.Quantization) {
+		return s.Tag
+	}
+	return s.Tag + "-" + s.Quantization
+}
+
+// Reference returns the Ollama model reference the spec resolves to: its
+// modelRef when set, "name:tag" otherwise
+func (s OllamaModelSpec) Reference() string {
+	if s.ModelRef != "" {
+		return s.ModelRef
+	}
+	return s.Name + ":" + s.QualifiedTag()
+}
+
+// ParsedReference returns the Ollama model reference the spec resolves to,
+// split into its parts
+func (s OllamaModelSpec) ParsedReference() ModelReference {
+	return ParseModelReference(s.Reference())
 }
 
 // OllamaModelStatus defines the observed state of OllamaModel.
 // +kubebuilder:default=Pending
 type OllamaModelStatus struct {
-	// State represents the current state of the model (Pending, Pulling, Ready, Failed)
+	// State represents the current state of the model (Pending, Pulling, Ready, Failed, Deleting)
 	State ModelState `json:"state,omitempty"`
 
+	// ObservedGeneration is the generation of the spec the status was last written for
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
 	// LastPullTime is the timestamp of the last successful model pull
 	// +kubebuilder:validation:Type=string
 	// +kubebuilder:validation:Format=date-time
 	LastPullTime *metav1.Time `json:"lastPullTime,omitempty"`
 
+	// FirstReadyTime is when the model first became Ready. Its difference with
+	// the creation of the OllamaModel is the time it took to make the model
+	// available.
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Format=date-time
+	FirstReadyTime *metav1.Time `json:"firstReadyTime,omitempty"`
+
+	// LastIntegrityCheckTime is when the stored model was last verified by the
+	// integrity check
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Format=date-time
+	LastIntegrityCheckTime *metav1.Time `json:"lastIntegrityCheckTime,omitempty"`
+
+	// LatestDigest is the digest the registry served for the model's tag at the
+	// last update check, which differs from Digest when a new version was
+	// published since the model was pulled
+	LatestDigest string `json:"latestDigest,omitempty"`
+
+	// LastUpdateCheckTime is when the registry was last checked for a new
+	// version of the model
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Format=date-time
+	LastUpdateCheckTime *metav1.Time `json:"lastUpdateCheckTime,omitempty"`
+
+	// LastHandledRefresh is the value of the refresh annotation the last
+	// refresh was made for. The annotation requests a refresh while it differs.
+	LastHandledRefresh string `json:"lastHandledRefresh,omitempty"`
+
 	// Digest is the SHA256 digest of the model file
 	// +kubebuilder:validation:Pattern=`^[a-f0-9]{64}$`
 	Digest string `json:"digest,omitempty"`
 
+	// ShortDigest is the first 12 characters of Digest, shown by kubectl get
+	ShortDigest string `json:"shortDigest,omitempty"`
+
 	// Size is the size of the model in bytes
 	// +kubebuilder:validation:Minimum=0
 	Size int64 `json:"size,omitempty"`
@@ -73,16 +428,203 @@ type OllamaModelStatus struct {
 	// FormattedSize is the human-readable size of the model (e.g., "4.2 GiB")
 	FormattedSize string `json:"formattedSize,omitempty"`
 
-	// Error message if the model is in failed state
+	// DerivedModel is the Ollama model created from the pulled one with the
+	// spec's parameters, system prompt and template. Use it instead of the
+	// pulled model to get them applied.
+	DerivedModel string `json:"derivedModel,omitempty"`
+
+	// DerivedModelHash identifies the parameters, system prompt and template
+	// DerivedModel was created with
+	DerivedModelHash string `json:"derivedModelHash,omitempty"`
+
+	// EmbeddingDimensions is the dimension of the vectors an embedding model produces
+	EmbeddingDimensions int32 `json:"embeddingDimensions,omitempty"`
+
+	// Endpoints lists the Ollama servers the model is managed on, with its
+	// state and digest on each
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Endpoints []EndpointStatus `json:"endpoints,omitempty"`
+
+	// Progress is the download progress of the pull in progress, or of the
+	// last pull if it failed
+	// +optional
+	Progress *PullProgress `json:"progress,omitempty"`
+
+	// ResumedFrom is the download progress an interrupted pull had reached
+	// when the last pull started, which picked up the layers Ollama had
+	// already downloaded. It is absent when the last pull started afresh.
+	// +optional
+	ResumedFrom *PullProgress `json:"resumedFrom,omitempty"`
+
+	// PullHolder is the operator replica running the pull in progress. A
+	// replica finding a model left Pulling by another one, such as after a
+	// leader failover, pulls it again.
+	// +optional
+	PullHolder string `json:"pullHolder,omitempty"`
+
+	// PullStartTime is when the pull in progress started
+	// +optional
+	PullStartTime *metav1.Time `json:"pullStartTime,omitempty"`
+
+	// PullInterruptedTime is when the pull in progress was interrupted by the
+	// shutdown of its replica. The model stays Pulling until a replica pulls
+	// it again, resuming the download.
+	// +optional
+	PullInterruptedTime *metav1.Time `json:"pullInterruptedTime,omitempty"`
+
+	// Export reports the export of the model's blobs
+	// +optional
+	Export *ExportStatus `json:"export,omitempty"`
+
+	// Error message if the model is in failed state, or failed to be deleted
 	// +kubebuilder:validation:MaxLength=1024
 	Error string `json:"error,omitempty"`
+
+	// FailureReason classifies the error of the last failed pull, and is
+	// cleared once the model is pulled
+	// +optional
+	FailureReason FailureReason `json:"failureReason,omitempty"`
+
+	// PullAttempts is the number of consecutive failed pulls, reset once the
+	// model is pulled
+	// +optional
+	PullAttempts int32 `json:"pullAttempts,omitempty"`
+
+	// LastFailureTime is when a pull of the model last failed
+	// +optional
+	LastFailureTime *metav1.Time `json:"lastFailureTime,omitempty"`
+
+	// Conditions represent the latest observations of the model's environment
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// DefaultEndpointName names the Ollama server of the operator in the endpoints
+// of a model's status
+const DefaultEndpointName = "default"
+
+// EndpointStatus reports a model on one Ollama server
+type EndpointStatus struct {
+	// Name identifies the Ollama server
+	Name string `json:"name"`
+
+	// URL is the address of the Ollama server's API
+	URL string `json:"url"`
+
+	// State is the state of the model on the Ollama server
+	State ModelState `json:"state,omitempty"`
+
+	// Digest is the digest of the model stored on the Ollama server
+	// +optional
+	Digest string `json:"digest,omitempty"`
+}
+
+// PullProgress is the download progress of a pull. It is updated in steps, not
+// for every chunk downloaded.
+type PullProgress struct {
+	// Percent is the share of the download completed
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Percent int32 `json:"percent"`
+
+	// CompletedBytes is the number of bytes downloaded so far
+	CompletedBytes int64 `json:"completedBytes,omitempty"`
+
+	// TotalBytes is the size of the layers discovered so far
+	TotalBytes int64 `json:"totalBytes,omitempty"`
+
+	// BytesPerSecond is the download rate, averaged over the last seconds
+	BytesPerSecond int64 `json:"bytesPerSecond,omitempty"`
+
+	// RemainingSeconds is the estimated time left to download the layers
+	// discovered so far at the current rate
+	RemainingSeconds int64 `json:"remainingSeconds,omitempty"`
+
+	// Message summarizes the progress, such as "62%, ~4m remaining at 85.0 MiB/s"
+	Message string `json:"message,omitempty"`
+}
+
+// ExportStatus reports the export of a model's blobs to a PersistentVolumeClaim
+type ExportStatus struct {
+	// ClaimName is the PersistentVolumeClaim holding the blobs
+	ClaimName string `json:"claimName"`
+
+	// Digest is the digest of the model the claim holds, once exported
+	Digest string `json:"digest,omitempty"`
+
+	// Job is the Job exporting the current digest, until it succeeds
+	Job string `json:"job,omitempty"`
+
+	// Error is the reason the last export failed
+	Error string `json:"error,omitempty"`
 }
 
+// Condition types. Ready, Reconciling and Stalled follow the kstatus
+// conventions, so that tools such as Argo CD and Flux can tell whether a model
+// is still being pulled or failed.
+const (
+	// ConditionReady is True once the model is pulled and ready to use
+	ConditionReady = "Ready"
+	// ConditionReconciling is True while the model is pending, being pulled or
+	// being deleted, and removed otherwise
+	ConditionReconciling = "Reconciling"
+	// ConditionStalled is True when the pull failed, and removed otherwise
+	ConditionStalled = "Stalled"
+	// ConditionOllamaAvailable reports whether the Ollama server could be reached
+	// when the model was last reconciled
+	ConditionOllamaAvailable = "OllamaAvailable"
+	// ConditionStale is True when a Ready model was last pulled longer ago than
+	// the operator's staleness threshold, and only present when one is set
+	ConditionStale = "Stale"
+	// ConditionWontFit is True when the estimated memory requirement of a model
+	// exceeds the memory available to the Ollama server, which keeps it from
+	// being pulled. It is only present when the available memory is configured.
+	ConditionWontFit = "WontFit"
+	// ConditionTerminal is True when the controller stopped retrying a failed
+	// pull, either because pulling again cannot fix the failure, such as a
+	// model that does not exist, because the pull was cancelled, or because
+	// the attempts of the retry policy are exhausted, and removed otherwise.
+	// Such models are not retried until their spec changes or a retry is
+	// requested.
+	ConditionTerminal = "Terminal"
+	// ConditionCorrupted is True when the integrity check found the stored
+	// model unreadable or stored with another digest than the status records.
+	// It is only present when integrity checks are enabled.
+	ConditionCorrupted = "Corrupted"
+	// ConditionNewVersionAvailable is True when the registry serves another
+	// version of the model's tag than the one pulled, which a refresh would
+	// pull. It is only present when update checks are enabled.
+	ConditionNewVersionAvailable = "NewVersionAvailable"
+)
+
+// ReasonRetriesExhausted is the reason of the Terminal condition of a model
+// whose retry policy allows no more attempts
+const ReasonRetriesExhausted = "RetriesExhausted"
+
+// Reasons of the Ready, Reconciling and Stalled conditions
+const (
+	ReasonPending = "Pending"
+	ReasonPulling = "Pulling"
+	// ReasonInterrupted is the reason of a model whose pull was interrupted
+	// by the shutdown of the operator
+	ReasonInterrupted = "Interrupted"
+	ReasonPulled      = "Pulled"
+	ReasonPullFailed  = "PullFailed"
+	ReasonDeleting    = "Deleting"
+)
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=om,categories=ai
 // +kubebuilder:printcolumn:name="Name",type="string",JSONPath=".spec.name"
 // +kubebuilder:printcolumn:name="Tag",type="string",JSONPath=".spec.tag"
 // +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="Progress",type="integer",JSONPath=".status.progress.percent"
+// +kubebuilder:printcolumn:name="Digest",type="string",JSONPath=".status.shortDigest"
 // +kubebuilder:printcolumn:name="Size",type="string",JSONPath=".status.formattedSize"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
@@ -95,6 +637,27 @@ type OllamaModel struct {
 	Status OllamaModelStatus `json:"status,omitempty"`
 }
 
+// RefreshRequested reports whether the refresh annotation asks for a refresh
+// that was not handled yet. Values written by earlier versions of the
+// controller once they handled a refresh, "completed-<time>" and
+// "cancelled-<time>", do not request one.
+func (m *OllamaModel) RefreshRequested() bool {
+	value := m.Annotations[RefreshAnnotation]
+	if value == "" || value == m.Status.LastHandledRefresh {
+		return false
+	}
+	return !strings.HasPrefix(value, "completed-") && !strings.HasPrefix(value, "cancelled-")
+}
+
+// ServedModel returns the Ollama model requests for the OllamaModel go to: the
+// derived model carrying the parameters of the spec, if any, or the pulled one
+func (m *OllamaModel) ServedModel() string {
+	if m.Status.DerivedModel != "" {
+		return m.Status.DerivedModel
+	}
+	return m.Spec.Reference()
+}
+
 // +kubebuilder:object:root=true
 
 // OllamaModelList contains a list of OllamaModel.
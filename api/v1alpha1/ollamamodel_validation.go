@@ -0,0 +1,132 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// MaxModelPartLength is the longest model, namespace or tag Ollama accepts
+const MaxModelPartLength = 80
+
+// maxHostLength is the longest registry host of a model name
+const maxHostLength = 253
+
+// maxModelRefLength is the longest modelRef of a model
+const maxModelRefLength = 512
+
+// modelPartPattern matches the models and tags Ollama accepts: letters,
+// digits, underscores, dashes and dots, starting with a letter, a digit or an
+// underscore. It is also the pattern of the CRD schema of spec.tag.
+var modelPartPattern = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]*$`)
+
+// modelPartMessage explains modelPartPattern in validation errors
+const modelPartMessage = "must consist of letters, digits, '_', '-' and '.', and start with a letter, a digit or '_'"
+
+// namespacePattern matches the namespaces of model names, such as the
+// username of a community model
+var namespacePattern = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_-]*$`)
+
+// namespaceMessage explains namespacePattern in validation errors
+const namespaceMessage = "must consist of letters, digits, '_' and '-', and start with a letter, a digit or '_'"
+
+// hostPattern matches the registry hosts of model names, with an optional port
+var hostPattern = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]*(:[0-9]+)?$`)
+
+// hostMessage explains hostPattern in validation errors
+const hostMessage = "must be a host name with an optional port"
+
+// ValidateReference checks the name and tag of the spec against the naming
+// rules of Ollama, so that malformed references are rejected up front rather
+// than failing at pull time. A modelRef is passed to Ollama verbatim, so it is
+// only checked to be a single word. The combinations of fields rejected by
+// the CEL rules of the CRD are checked as well. fldPath is the path of the
+// spec.
+func (s OllamaModelSpec) ValidateReference(fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	errs = append(errs, validateModelName(fldPath.Child("name"), s.Name)...)
+	errs = append(errs, validateModelPart(fldPath.Child("tag"), s.Tag)...)
+	if s.ExpectedDimensions != nil && s.Type != ModelTypeEmbedding {
+		errs = append(errs, field.Forbidden(fldPath.Child("expectedDimensions"), "can only be set for models of type embedding"))
+	}
+	if s.RestoreFrom != nil && s.UpdatePolicy == UpdatePolicyAuto {
+		errs = append(errs, field.Forbidden(fldPath.Child("updatePolicy"), "cannot be Auto with restoreFrom"))
+	}
+	if s.ModelRef == "" {
+		return errs
+	}
+	switch {
+	case len(s.ModelRef) > maxModelRefLength:
+		errs = append(errs, field.TooLong(fldPath.Child("modelRef"), s.ModelRef, maxModelRefLength))
+	case strings.ContainsFunc(s.ModelRef, unicode.IsSpace):
+		errs = append(errs, field.Invalid(fldPath.Child("modelRef"), s.ModelRef, "must not contain spaces"))
+	}
+	if s.Quantization != "" {
+		errs = append(errs, field.Forbidden(fldPath.Child("quantization"), "cannot be set with modelRef"))
+	}
+	return errs
+}
+
+// validateModelName checks a model name, which may be qualified with a
+// namespace and a registry host as in [host/][namespace/]model
+func validateModelName(fldPath *field.Path, value string) field.ErrorList {
+	if value == "" {
+		return field.ErrorList{field.Required(fldPath, "")}
+	}
+	host, namespace, model := splitModelName(value)
+
+	var errs field.ErrorList
+	if strings.Contains(value, "/") {
+		switch {
+		case len(host) > maxHostLength:
+			errs = append(errs, field.Invalid(fldPath, value, fmt.Sprintf("the registry host must be no more than %d characters", maxHostLength)))
+		case host != "" && !hostPattern.MatchString(host):
+			errs = append(errs, field.Invalid(fldPath, value, "the registry host "+hostMessage))
+		}
+		switch {
+		case len(namespace) > MaxModelPartLength:
+			errs = append(errs, field.Invalid(fldPath, value, fmt.Sprintf("the namespace must be no more than %d characters", MaxModelPartLength)))
+		case !namespacePattern.MatchString(namespace):
+			errs = append(errs, field.Invalid(fldPath, value, "the namespace "+namespaceMessage))
+		}
+	}
+	switch {
+	case len(model) > MaxModelPartLength:
+		errs = append(errs, field.Invalid(fldPath, value, fmt.Sprintf("the model must be no more than %d characters", MaxModelPartLength)))
+	case !modelPartPattern.MatchString(model):
+		errs = append(errs, field.Invalid(fldPath, value, "the model "+modelPartMessage))
+	}
+	return errs
+}
+
+// validateModelPart checks a model tag
+func validateModelPart(fldPath *field.Path, value string) field.ErrorList {
+	switch {
+	case value == "":
+		return field.ErrorList{field.Required(fldPath, "")}
+	case len(value) > MaxModelPartLength:
+		return field.ErrorList{field.TooLong(fldPath, value, MaxModelPartLength)}
+	case !modelPartPattern.MatchString(value):
+		return field.ErrorList{field.Invalid(fldPath, value, modelPartMessage)}
+	}
+	return nil
+}
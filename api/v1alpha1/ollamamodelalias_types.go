@@ -0,0 +1,146 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Reasons of the Ready condition of an OllamaModelAlias
+const (
+	// ReasonResolved means the model the alias points at is Ready
+	ReasonResolved = "Resolved"
+	// ReasonModelNotFound means the model the alias points at does not exist
+	ReasonModelNotFound = "ModelNotFound"
+	// ReasonModelNotReady means the model the alias points at is not Ready
+	ReasonModelNotReady = "ModelNotReady"
+	// ReasonInvalidSpec means the alias sets both or neither of model and split
+	ReasonInvalidSpec = "InvalidSpec"
+)
+
+// OllamaModelAliasSpec defines the desired state of OllamaModelAlias.
+// Exactly one of Model and Split is set.
+type OllamaModelAliasSpec struct {
+	// Model is the name of the OllamaModel of the namespace the alias points
+	// at. Changing it repoints the alias for every request that follows.
+	// +optional
+	Model string `json:"model,omitempty"`
+
+	// Split spreads the requests for the alias over several OllamaModels of the
+	// namespace in proportion to their weights, such as 90/10 to try a
+	// fine-tuned model on a share of the traffic. Models that are not Ready get
+	// no requests, their share going to the others.
+	// +listType=map
+	// +listMapKey=model
+	// +kubebuilder:validation:MinItems=2
+	// +optional
+	Split []AliasBranch `json:"split,omitempty"`
+}
+
+// AliasBranch is a model receiving a share of the requests for an alias
+type AliasBranch struct {
+	// Model is the name of the OllamaModel of the namespace
+	// +kubebuilder:validation:MinLength=1
+	Model string `json:"model"`
+
+	// Weight is the share of the requests the model receives, relative to the
+	// weights of the other models of the split
+	// +kubebuilder:validation:Minimum=0
+	Weight int32 `json:"weight"`
+}
+
+// Branches returns the models of an alias with their weights, a single model
+// receiving all requests when no split is set
+func (s OllamaModelAliasSpec) Branches() []AliasBranch {
+	if len(s.Split) > 0 {
+		return s.Split
+	}
+	return []AliasBranch{{Model: s.Model, Weight: 1}}
+}
+
+// Valid reports whether exactly one of Model and Split is set
+func (s OllamaModelAliasSpec) Valid() bool {
+	return (s.Model == "") != (len(s.Split) == 0)
+}
+
+// OllamaModelAliasStatus defines the observed state of OllamaModelAlias.
+type OllamaModelAliasStatus struct {
+	// Reference is the Ollama model serving the requests for the alias, when
+	// it is not split
+	Reference string `json:"reference,omitempty"`
+
+	// Split reports the models of a split and the share of the requests each
+	// receives
+	// +listType=map
+	// +listMapKey=model
+	// +optional
+	Split []AliasBranchStatus `json:"split,omitempty"`
+
+	// ObservedGeneration is the generation of the spec the status was last written for
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represent the latest observations of the alias
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// AliasBranchStatus is the observed state of a model of a split
+type AliasBranchStatus struct {
+	// Model is the name of the OllamaModel
+	Model string `json:"model"`
+
+	// Reference is the Ollama model serving the requests for the branch
+	Reference string `json:"reference,omitempty"`
+
+	// Percent is the share of the requests the model receives, 0 while it is
+	// not Ready
+	Percent int32 `json:"percent"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories=ai
+// +kubebuilder:printcolumn:name="Model",type="string",JSONPath=".spec.model"
+// +kubebuilder:printcolumn:name="Reference",type="string",JSONPath=".status.reference"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// OllamaModelAlias is the Schema for the ollamamodelaliases API. It is a
+// stable name that applications request from the inference gateway, while the
+// OllamaModel serving it can be swapped, such as for a fine-tuned replacement.
+type OllamaModelAlias struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OllamaModelAliasSpec   `json:"spec,omitempty"`
+	Status OllamaModelAliasStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OllamaModelAliasList contains a list of OllamaModelAlias.
+type OllamaModelAliasList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OllamaModelAlias `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OllamaModelAlias{}, &OllamaModelAliasList{})
+}
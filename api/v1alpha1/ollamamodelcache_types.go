@@ -0,0 +1,97 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ModelCacheClaimName returns the name of the PersistentVolumeClaim of a model cache
+func ModelCacheClaimName(cacheName string) string {
+	return cacheName + "-models"
+}
+
+// OllamaModelCacheSpec defines the desired state of OllamaModelCache.
+type OllamaModelCacheSpec struct {
+	// StorageClassName is the storage class of the cache volume, which must
+	// support ReadWriteMany; the cluster default is used when empty
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
+
+	// Size is the capacity requested for the cache volume
+	Size resource.Quantity `json:"size"`
+
+	// ModelSelector selects the OllamaModels of the namespace held by the
+	// cache; all of them are when it is empty
+	// +optional
+	ModelSelector *metav1.LabelSelector `json:"modelSelector,omitempty"`
+}
+
+// OllamaModelCacheStatus defines the observed state of OllamaModelCache.
+type OllamaModelCacheStatus struct {
+	// ClaimName is the ReadWriteMany PersistentVolumeClaim holding the models,
+	// which Ollama servers mount as their models directory
+	ClaimName string `json:"claimName,omitempty"`
+
+	// Models are the models ("name:tag") held by the cache
+	// +optional
+	Models []string `json:"models,omitempty"`
+
+	// Job is the Job pulling the selected models into the cache, while it runs
+	Job string `json:"job,omitempty"`
+
+	// ObservedGeneration is the generation of the spec the status was last written for
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represent the latest observations of the cache
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Claim",type="string",JSONPath=".status.claimName"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="Size",type="string",JSONPath=".spec.size"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// OllamaModelCache is the Schema for the ollamamodelcaches API. It is a
+// ReadWriteMany volume of models shared by several Ollama servers, into which
+// the operator pulls each selected model once for all of them.
+type OllamaModelCache struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OllamaModelCacheSpec   `json:"spec,omitempty"`
+	Status OllamaModelCacheStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OllamaModelCacheList contains a list of OllamaModelCache.
+type OllamaModelCacheList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OllamaModelCache `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OllamaModelCache{}, &OllamaModelCacheList{})
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Reasons of the Ready condition of an OllamaModelSnapshot
+const (
+	// ReasonCaptured means the state of the model was recorded
+	ReasonCaptured = "Captured"
+	// ReasonCaptureFailed means the state of the model could not be read or frozen
+	ReasonCaptureFailed = "CaptureFailed"
+)
+
+// OllamaModelSnapshotSpec defines the desired state of OllamaModelSnapshot.
+// The snapshot is captured once; changes to the spec afterwards are ignored.
+type OllamaModelSnapshotSpec struct {
+	// Model is the name of the OllamaModel of the namespace to capture. The
+	// snapshot waits for it to be Ready.
+	// +kubebuilder:validation:MinLength=1
+	Model string `json:"model"`
+
+	// FrozenTag, when set, copies the model on the Ollama server under this
+	// tag of the same name (e.g., "llama3.2:exp-42"), so that the exact model
+	// can be restored after the original tag moved or was removed. The copy is
+	// deleted along with the snapshot.
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_][A-Za-z0-9_.-]*$`
+	// +kubebuilder:validation:MaxLength=128
+	// +optional
+	FrozenTag string `json:"frozenTag,omitempty"`
+}
+
+// OllamaModelSnapshotStatus defines the observed state of OllamaModelSnapshot.
+type OllamaModelSnapshotStatus struct {
+	// Model is the Ollama model reference ("name:tag") that was captured, the
+	// model derived with the parameters of the OllamaModel when it has any
+	Model string `json:"model,omitempty"`
+
+	// Digest is the digest of the model's manifest when it was captured
+	Digest string `json:"digest,omitempty"`
+
+	// Modelfile is the Modelfile of the model
+	Modelfile string `json:"modelfile,omitempty"`
+
+	// Parameters are the parameters of the model, one per line
+	Parameters string `json:"parameters,omitempty"`
+
+	// Template is the prompt template of the model
+	Template string `json:"template,omitempty"`
+
+	// System is the system prompt of the model
+	System string `json:"system,omitempty"`
+
+	// License is the license of the model
+	License string `json:"license,omitempty"`
+
+	// FrozenModel is the copy of the model made for FrozenTag
+	FrozenModel string `json:"frozenModel,omitempty"`
+
+	// CaptureTime is when the snapshot was captured
+	// +optional
+	CaptureTime *metav1.Time `json:"captureTime,omitempty"`
+
+	// Conditions represent the latest observations of the snapshot
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// Captured reports whether the state of the model was recorded
+func (s OllamaModelSnapshotStatus) Captured() bool {
+	return s.CaptureTime != nil
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=oms,categories=ai
+// +kubebuilder:printcolumn:name="Model",type="string",JSONPath=".status.model"
+// +kubebuilder:printcolumn:name="Frozen",type="string",JSONPath=".status.frozenModel"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// OllamaModelSnapshot is the Schema for the ollamamodelsnapshots API. It
+// records the exact state of an OllamaModel at a point in time, for
+// reproducible experiments and audits, and OllamaModels can be restored from
+// it with restoreFrom.
+type OllamaModelSnapshot struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OllamaModelSnapshotSpec   `json:"spec,omitempty"`
+	Status OllamaModelSnapshotStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OllamaModelSnapshotList contains a list of OllamaModelSnapshot.
+type OllamaModelSnapshotList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OllamaModelSnapshot `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OllamaModelSnapshot{}, &OllamaModelSnapshotList{})
+}
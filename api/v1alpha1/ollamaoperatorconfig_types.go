@@ -0,0 +1,124 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultOperatorConfigName is the name of the OllamaOperatorConfig the
+// operator applies unless configured otherwise
+const DefaultOperatorConfigName = "default"
+
+// PruneMode controls what the prune endpoint may do with unmanaged models
+// +kubebuilder:validation:Enum=Disabled;DryRun;Enabled
+type PruneMode string
+
+const (
+	// PruneDisabled rejects prune requests
+	PruneDisabled PruneMode = "Disabled"
+	// PruneDryRun only reports unmanaged models, as if every request asked for a dry run
+	PruneDryRun PruneMode = "DryRun"
+	// PruneEnabled deletes unmanaged models unless a dry run is requested
+	PruneEnabled PruneMode = "Enabled"
+)
+
+// OllamaOperatorConfigSpec defines the runtime configuration of the operator.
+// Unset fields keep the values given on the command line.
+type OllamaOperatorConfigSpec struct {
+	// MaxConcurrentPulls caps the number of models pulled at the same time.
+	// Pulls beyond --max-concurrent-reconciles never run in parallel; 0 removes the cap.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxConcurrentPulls *int32 `json:"maxConcurrentPulls,omitempty"`
+
+	// ResyncInterval is how often Ready models are checked against the Ollama
+	// server, so that models deleted outside the operator are pulled again; 0s disables it
+	// +optional
+	ResyncInterval *metav1.Duration `json:"resyncInterval,omitempty"`
+
+	// PruneMode controls whether the prune endpoint deletes unmanaged models
+	// +optional
+	PruneMode PruneMode `json:"pruneMode,omitempty"`
+
+	// API configures the API server
+	// +optional
+	API *OperatorAPIConfig `json:"api,omitempty"`
+}
+
+// OperatorAPIConfig defines the runtime configuration of the API server
+type OperatorAPIConfig struct {
+	// MaxBodyBytes bounds the size of request bodies
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxBodyBytes *int64 `json:"maxBodyBytes,omitempty"`
+}
+
+// OllamaOperatorConfigStatus defines the observed state of OllamaOperatorConfig.
+type OllamaOperatorConfigStatus struct {
+	// ObservedGeneration is the generation of the spec last applied by the operator
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// UnmanagedModels are the models stored on the Ollama server that no
+	// OllamaModel manages, and that the prune endpoint would delete
+	// +optional
+	UnmanagedModels []string `json:"unmanagedModels,omitempty"`
+
+	// UnmanagedCheckTime is when the unmanaged models were last listed
+	// +optional
+	UnmanagedCheckTime *metav1.Time `json:"unmanagedCheckTime,omitempty"`
+
+	// Conditions represent the latest observations of the configuration
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// ConditionApplied reports whether the operator applied the configuration
+const ConditionApplied = "Applied"
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Pulls",type="integer",JSONPath=".spec.maxConcurrentPulls"
+// +kubebuilder:printcolumn:name="Resync",type="string",JSONPath=".spec.resyncInterval"
+// +kubebuilder:printcolumn:name="Prune",type="string",JSONPath=".spec.pruneMode"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// OllamaOperatorConfig is the Schema for the ollamaoperatorconfigs API. The
+// operator watches a single OllamaOperatorConfig and applies changes live.
+type OllamaOperatorConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OllamaOperatorConfigSpec   `json:"spec,omitempty"`
+	Status OllamaOperatorConfigStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OllamaOperatorConfigList contains a list of OllamaOperatorConfig.
+type OllamaOperatorConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OllamaOperatorConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OllamaOperatorConfig{}, &OllamaOperatorConfigList{})
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PromotedFromAnnotation records on a promoted OllamaModel the promotion that
+// last updated it, as "namespace/name"
+const PromotedFromAnnotation = "ollama.smithforge.dev/promoted-from"
+
+// Reasons of the Ready condition of an OllamaPromotion
+const (
+	// ReasonAwaitingApproval means the promotion waits for approval.approvedBy
+	ReasonAwaitingApproval = "AwaitingApproval"
+	// ReasonPromoted means the target model was created or updated with the
+	// digest of the source model
+	ReasonPromoted = "Promoted"
+	// ReasonPromotionFailed means the target model could not be promoted, or
+	// does not have the digest of the source model
+	ReasonPromotionFailed = "PromotionFailed"
+)
+
+// OllamaPromotionSpec defines the desired state of OllamaPromotion. A
+// promotion is carried out once; changes to the spec afterwards are ignored.
+type OllamaPromotionSpec struct {
+	// Source is the OllamaModel to promote, typically in a staging namespace.
+	// It must be Ready.
+	Source PromotionSource `json:"source"`
+
+	// Target is the name of the OllamaModel of the promotion's namespace to
+	// create or update; the name of the source model when empty
+	// +optional
+	Target string `json:"target,omitempty"`
+
+	// CopyToTag, when set, copies the source model on the Ollama server under
+	// this tag of the same name, and points the target model at the copy, so
+	// that it keeps the promoted digest when the source tag moves
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_][A-Za-z0-9_.-]*$`
+	// +kubebuilder:validation:MaxLength=128
+	// +optional
+	CopyToTag string `json:"copyToTag,omitempty"`
+
+	// Approval records who approved the promotion. The promotion waits until
+	// ApprovedBy is set.
+	// +optional
+	Approval PromotionApproval `json:"approval,omitempty"`
+}
+
+// PromotionSource names the OllamaModel a promotion promotes
+type PromotionSource struct {
+	// Namespace is the namespace of the OllamaModel; the promotion's namespace
+	// when empty
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name is the name of the OllamaModel
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// PromotionApproval records the approval of a promotion
+type PromotionApproval struct {
+	// ApprovedBy is who approved the promotion
+	// +optional
+	ApprovedBy string `json:"approvedBy,omitempty"`
+
+	// Reason is why the promotion was approved, such as a change ticket
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// OllamaPromotionStatus defines the observed state of OllamaPromotion.
+type OllamaPromotionStatus struct {
+	// Model is the Ollama model reference ("name:tag") the target model manages
+	Model string `json:"model,omitempty"`
+
+	// Digest is the digest of the source model that was promoted
+	Digest string `json:"digest,omitempty"`
+
+	// PromotedBy is the approver the promotion was carried out for
+	PromotedBy string `json:"promotedBy,omitempty"`
+
+	// PromotionTime is when the target model was created or updated
+	// +optional
+	PromotionTime *metav1.Time `json:"promotionTime,omitempty"`
+
+	// Conditions represent the latest observations of the promotion
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// Promoted reports whether the target model was created or updated
+func (s OllamaPromotionStatus) Promoted() bool {
+	return s.PromotionTime != nil
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories=ai
+// +kubebuilder:printcolumn:name="Source",type="string",JSONPath=".spec.source.name"
+// +kubebuilder:printcolumn:name="Model",type="string",JSONPath=".status.model"
+// +kubebuilder:printcolumn:name="Approved By",type="string",JSONPath=".spec.approval.approvedBy"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// OllamaPromotion is the Schema for the ollamapromotions API. It promotes an
+// OllamaModel Ready in one namespace, such as staging, to an OllamaModel of
+// its own namespace, such as production, pinned to the same digest, once it
+// is approved.
+type OllamaPromotion struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OllamaPromotionSpec   `json:"spec,omitempty"`
+	Status OllamaPromotionStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OllamaPromotionList contains a list of OllamaPromotion.
+type OllamaPromotionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OllamaPromotion `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OllamaPromotion{}, &OllamaPromotionList{})
+}
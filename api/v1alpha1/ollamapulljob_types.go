@@ -0,0 +1,162 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PullJobPhase is the phase of an OllamaPullJob
+// +kubebuilder:validation:Enum=Pending;Running;Succeeded;Failed
+type PullJobPhase string
+
+// Phases of an OllamaPullJob
+const (
+	// PullJobPending means the pull has not started yet
+	PullJobPending PullJobPhase = "Pending"
+	// PullJobRunning means the model is being pulled
+	PullJobRunning PullJobPhase = "Running"
+	// PullJobSucceeded means the model was pulled
+	PullJobSucceeded PullJobPhase = "Succeeded"
+	// PullJobFailed means the pull failed more often than the backoff limit allows
+	PullJobFailed PullJobPhase = "Failed"
+)
+
+// Conditions of an OllamaPullJob, named after those of batch Jobs so that
+// `kubectl wait --for=condition=Complete` works the same
+const (
+	// ConditionComplete is True once the model was pulled
+	ConditionComplete = "Complete"
+	// ConditionFailed is True once the job gave up pulling the model
+	ConditionFailed = "Failed"
+)
+
+// DefaultPullJobBackoffLimit is the number of retries of a failed pull when BackoffLimit is unset
+const DefaultPullJobBackoffLimit = 3
+
+// OllamaPullJobSpec defines the desired state of OllamaPullJob. It cannot be
+// changed once the pull has started.
+type OllamaPullJobSpec struct {
+	// Name is the name of the Ollama model (e.g., "llama3.2", "gemma3"). It may be
+	// qualified with a namespace and the host of the registry serving the model,
+	// as in "registry.example.com/team/model".
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=415
+	// +kubebuilder:validation:Pattern=`^(([A-Za-z0-9_][A-Za-z0-9_.-]*(:[0-9]+)?/)?[A-Za-z0-9_][A-Za-z0-9_-]*/)?[A-Za-z0-9_][A-Za-z0-9_.-]*$`
+	Name string `json:"name"`
+
+	// Tag is the version/tag of the model (e.g., "7b", "1b")
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=80
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_][A-Za-z0-9_.-]*$`
+	Tag string `json:"tag"`
+
+	// Quantization selects a quantization of the model, appended to the tag as
+	// for OllamaModels
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_]+$`
+	// +kubebuilder:validation:MaxLength=32
+	// +optional
+	Quantization string `json:"quantization,omitempty"`
+
+	// BackoffLimit is the number of times a failed pull is retried before the
+	// job is marked Failed; 3 when unset
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+
+	// TTLSecondsAfterFinished deletes the job this long after it succeeded or
+	// failed. The pulled model stays on the Ollama server. The job is kept
+	// when unset.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+}
+
+// Reference returns the Ollama model reference ("name:tag") the job pulls
+func (s OllamaPullJobSpec) Reference() string {
+	return OllamaModelSpec{Name: s.Name, Tag: s.Tag, Quantization: s.Quantization}.Reference()
+}
+
+// OllamaPullJobStatus defines the observed state of OllamaPullJob.
+type OllamaPullJobStatus struct {
+	// Phase is the phase of the job (Pending, Running, Succeeded, Failed)
+	Phase PullJobPhase `json:"phase,omitempty"`
+
+	// Model is the Ollama model reference being pulled
+	Model string `json:"model,omitempty"`
+
+	// Attempts is the number of pulls that failed so far
+	Attempts int32 `json:"attempts,omitempty"`
+
+	// Error is the error of the last failed pull
+	Error string `json:"error,omitempty"`
+
+	// Progress reports how much of the model has been downloaded
+	// +optional
+	Progress *PullProgress `json:"progress,omitempty"`
+
+	// StartTime is when the first pull started
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is when the job succeeded or failed
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Conditions represent the latest observations of the job
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// Finished reports whether the job succeeded or failed
+func (s OllamaPullJobStatus) Finished() bool {
+	return s.Phase == PullJobSucceeded || s.Phase == PullJobFailed
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories=ai
+// +kubebuilder:printcolumn:name="Model",type="string",JSONPath=".status.model"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Progress",type="integer",JSONPath=".status.progress.percent"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// OllamaPullJob is the Schema for the ollamapulljobs API. It pulls a model
+// onto the Ollama server once and reports the outcome, without managing the
+// model afterwards, such as to warm up a server as a step of a pipeline.
+type OllamaPullJob struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OllamaPullJobSpec   `json:"spec,omitempty"`
+	Status OllamaPullJobStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OllamaPullJobList contains a list of OllamaPullJob.
+type OllamaPullJobList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OllamaPullJob `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OllamaPullJob{}, &OllamaPullJobList{})
+}
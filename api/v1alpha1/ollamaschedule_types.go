@@ -0,0 +1,132 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ScheduleLabel is set on the OllamaModels created by an OllamaSchedule to the name of the schedule
+const ScheduleLabel = "ollama.smithforge.dev/schedule"
+
+// ConditionActive is the type of the condition reporting whether a schedule is in one of its windows
+const ConditionActive = "Active"
+
+// Weekday is a day of the week, abbreviated
+// +kubebuilder:validation:Enum=Mon;Tue;Wed;Thu;Fri;Sat;Sun
+type Weekday string
+
+// ScheduleWindow is a daily period during which the models of a schedule are
+// kept on the Ollama server
+type ScheduleWindow struct {
+	// Start is the time of day the window opens, as HH:MM
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
+
+	// End is the time of day the window closes, as HH:MM. A window ending at or
+	// before its start runs past midnight, into the next day.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	End string `json:"end"`
+
+	// Days are the days the window opens on; every day when empty
+	// +optional
+	Days []Weekday `json:"days,omitempty"`
+}
+
+// ScheduledModel is an OllamaModel created by a schedule during its windows
+type ScheduledModel struct {
+	// Name is the name of the OllamaModel
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Spec is the spec of the OllamaModel
+	Spec OllamaModelSpec `json:"spec"`
+}
+
+// OllamaScheduleSpec defines the desired state of OllamaSchedule.
+type OllamaScheduleSpec struct {
+	// TimeZone is the IANA time zone the windows are in, such as
+	// "Europe/Berlin"; UTC when empty
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// Windows are the periods during which the models are pulled and kept
+	// +kubebuilder:validation:MinItems=1
+	Windows []ScheduleWindow `json:"windows"`
+
+	// Models are created as OllamaModels of the namespace when a window opens,
+	// and deleted, which removes them from the Ollama server, when it closes
+	// +kubebuilder:validation:MinItems=1
+	// +listType=map
+	// +listMapKey=name
+	Models []ScheduledModel `json:"models"`
+}
+
+// OllamaScheduleStatus defines the observed state of OllamaSchedule.
+type OllamaScheduleStatus struct {
+	// Active reports whether the schedule is in one of its windows
+	Active bool `json:"active,omitempty"`
+
+	// NextTransition is when the schedule next opens or closes a window
+	// +optional
+	NextTransition *metav1.Time `json:"nextTransition,omitempty"`
+
+	// Models are the OllamaModels the schedule currently keeps
+	// +optional
+	Models []string `json:"models,omitempty"`
+
+	// ObservedGeneration is the generation of the spec the status was last written for
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represent the latest observations of the schedule
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories=ai
+// +kubebuilder:printcolumn:name="Active",type="boolean",JSONPath=".status.active"
+// +kubebuilder:printcolumn:name="Next",type="date",JSONPath=".status.nextTransition"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// OllamaSchedule is the Schema for the ollamaschedules API. It keeps a set of
+// models on the Ollama server during time windows only, such as batch
+// inference models pulled every night, freeing memory and disk the rest of
+// the time.
+type OllamaSchedule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OllamaScheduleSpec   `json:"spec,omitempty"`
+	Status OllamaScheduleStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OllamaScheduleList contains a list of OllamaSchedule.
+type OllamaScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OllamaSchedule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OllamaSchedule{}, &OllamaScheduleList{})
+}
@@ -21,15 +21,137 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AliasBranch) DeepCopyInto(out *AliasBranch) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AliasBranch.
+func (in *AliasBranch) DeepCopy() *AliasBranch {
+	if in == nil {
+		return nil
+	}
+	out := new(AliasBranch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AliasBranchStatus) DeepCopyInto(out *AliasBranchStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AliasBranchStatus.
+func (in *AliasBranchStatus) DeepCopy() *AliasBranchStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AliasBranchStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EndpointStatus) DeepCopyInto(out *EndpointStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointStatus.
+func (in *EndpointStatus) DeepCopy() *EndpointStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EndpointStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExportStatus) DeepCopyInto(out *ExportStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExportStatus.
+func (in *ExportStatus) DeepCopy() *ExportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ExportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InferenceLimits) DeepCopyInto(out *InferenceLimits) {
+	*out = *in
+	if in.RequestsPerSecond != nil {
+		in, out := &in.RequestsPerSecond, &out.RequestsPerSecond
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxConcurrentRequests != nil {
+		in, out := &in.MaxConcurrentRequests, &out.MaxConcurrentRequests
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxTokens != nil {
+		in, out := &in.MaxTokens, &out.MaxTokens
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InferenceLimits.
+func (in *InferenceLimits) DeepCopy() *InferenceLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(InferenceLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelExport) DeepCopyInto(out *ModelExport) {
+	*out = *in
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.AccessModes != nil {
+		in, out := &in.AccessModes, &out.AccessModes
+		*out = make([]corev1.PersistentVolumeAccessMode, len(*in))
+		copy(*out, *in)
+	}
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelExport.
+func (in *ModelExport) DeepCopy() *ModelExport {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelExport)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OllamaModel) DeepCopyInto(out *OllamaModel) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -51,6 +173,224 @@ func (in *OllamaModel) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OllamaModelAlias) DeepCopyInto(out *OllamaModelAlias) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OllamaModelAlias.
+func (in *OllamaModelAlias) DeepCopy() *OllamaModelAlias {
+	if in == nil {
+		return nil
+	}
+	out := new(OllamaModelAlias)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OllamaModelAlias) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OllamaModelAliasList) DeepCopyInto(out *OllamaModelAliasList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OllamaModelAlias, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OllamaModelAliasList.
+func (in *OllamaModelAliasList) DeepCopy() *OllamaModelAliasList {
+	if in == nil {
+		return nil
+	}
+	out := new(OllamaModelAliasList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OllamaModelAliasList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OllamaModelAliasSpec) DeepCopyInto(out *OllamaModelAliasSpec) {
+	*out = *in
+	if in.Split != nil {
+		in, out := &in.Split, &out.Split
+		*out = make([]AliasBranch, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OllamaModelAliasSpec.
+func (in *OllamaModelAliasSpec) DeepCopy() *OllamaModelAliasSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OllamaModelAliasSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OllamaModelAliasStatus) DeepCopyInto(out *OllamaModelAliasStatus) {
+	*out = *in
+	if in.Split != nil {
+		in, out := &in.Split, &out.Split
+		*out = make([]AliasBranchStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OllamaModelAliasStatus.
+func (in *OllamaModelAliasStatus) DeepCopy() *OllamaModelAliasStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OllamaModelAliasStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OllamaModelCache) DeepCopyInto(out *OllamaModelCache) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OllamaModelCache.
+func (in *OllamaModelCache) DeepCopy() *OllamaModelCache {
+	if in == nil {
+		return nil
+	}
+	out := new(OllamaModelCache)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OllamaModelCache) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OllamaModelCacheList) DeepCopyInto(out *OllamaModelCacheList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OllamaModelCache, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OllamaModelCacheList.
+func (in *OllamaModelCacheList) DeepCopy() *OllamaModelCacheList {
+	if in == nil {
+		return nil
+	}
+	out := new(OllamaModelCacheList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OllamaModelCacheList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OllamaModelCacheSpec) DeepCopyInto(out *OllamaModelCacheSpec) {
+	*out = *in
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
+	}
+	out.Size = in.Size.DeepCopy()
+	if in.ModelSelector != nil {
+		in, out := &in.ModelSelector, &out.ModelSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OllamaModelCacheSpec.
+func (in *OllamaModelCacheSpec) DeepCopy() *OllamaModelCacheSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OllamaModelCacheSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OllamaModelCacheStatus) DeepCopyInto(out *OllamaModelCacheStatus) {
+	*out = *in
+	if in.Models != nil {
+		in, out := &in.Models, &out.Models
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OllamaModelCacheStatus.
+func (in *OllamaModelCacheStatus) DeepCopy() *OllamaModelCacheStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OllamaModelCacheStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OllamaModelList) DeepCopyInto(out *OllamaModelList) {
 	*out = *in
@@ -84,35 +424,841 @@ func (in *OllamaModelList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OllamaModelSpec) DeepCopyInto(out *OllamaModelSpec) {
+func (in *OllamaModelSnapshot) DeepCopyInto(out *OllamaModelSnapshot) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OllamaModelSpec.
-func (in *OllamaModelSpec) DeepCopy() *OllamaModelSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OllamaModelSnapshot.
+func (in *OllamaModelSnapshot) DeepCopy() *OllamaModelSnapshot {
 	if in == nil {
 		return nil
 	}
-	out := new(OllamaModelSpec)
+	out := new(OllamaModelSnapshot)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OllamaModelSnapshot) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OllamaModelStatus) DeepCopyInto(out *OllamaModelStatus) {
+func (in *OllamaModelSnapshotList) DeepCopyInto(out *OllamaModelSnapshotList) {
 	*out = *in
-	if in.LastPullTime != nil {
-		in, out := &in.LastPullTime, &out.LastPullTime
-		*out = (*in).DeepCopy()
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OllamaModelSnapshot, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OllamaModelStatus.
-func (in *OllamaModelStatus) DeepCopy() *OllamaModelStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OllamaModelSnapshotList.
+func (in *OllamaModelSnapshotList) DeepCopy() *OllamaModelSnapshotList {
 	if in == nil {
 		return nil
 	}
-	out := new(OllamaModelStatus)
+	out := new(OllamaModelSnapshotList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OllamaModelSnapshotList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OllamaModelSnapshotSpec) DeepCopyInto(out *OllamaModelSnapshotSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OllamaModelSnapshotSpec.
+func (in *OllamaModelSnapshotSpec) DeepCopy() *OllamaModelSnapshotSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OllamaModelSnapshotSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OllamaModelSnapshotStatus) DeepCopyInto(out *OllamaModelSnapshotStatus) {
+	*out = *in
+	if in.CaptureTime != nil {
+		in, out := &in.CaptureTime, &out.CaptureTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OllamaModelSnapshotStatus.
+func (in *OllamaModelSnapshotStatus) DeepCopy() *OllamaModelSnapshotStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OllamaModelSnapshotStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OllamaModelSpec) DeepCopyInto(out *OllamaModelSpec) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ExpectedDimensions != nil {
+		in, out := &in.ExpectedDimensions, &out.ExpectedDimensions
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Export != nil {
+		in, out := &in.Export, &out.Export
+		*out = new(ModelExport)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Limits != nil {
+		in, out := &in.Limits, &out.Limits
+		*out = new(InferenceLimits)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RestoreFrom != nil {
+		in, out := &in.RestoreFrom, &out.RestoreFrom
+		*out = new(SnapshotReference)
+		**out = **in
+	}
+	if in.UpdateWindow != nil {
+		in, out := &in.UpdateWindow, &out.UpdateWindow
+		*out = new(UpdateWindow)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OllamaModelSpec.
+func (in *OllamaModelSpec) DeepCopy() *OllamaModelSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OllamaModelSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OllamaModelStatus) DeepCopyInto(out *OllamaModelStatus) {
+	*out = *in
+	if in.LastPullTime != nil {
+		in, out := &in.LastPullTime, &out.LastPullTime
+		*out = (*in).DeepCopy()
+	}
+	if in.FirstReadyTime != nil {
+		in, out := &in.FirstReadyTime, &out.FirstReadyTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastIntegrityCheckTime != nil {
+		in, out := &in.LastIntegrityCheckTime, &out.LastIntegrityCheckTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastUpdateCheckTime != nil {
+		in, out := &in.LastUpdateCheckTime, &out.LastUpdateCheckTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]EndpointStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Progress != nil {
+		in, out := &in.Progress, &out.Progress
+		*out = new(PullProgress)
+		**out = **in
+	}
+	if in.ResumedFrom != nil {
+		in, out := &in.ResumedFrom, &out.ResumedFrom
+		*out = new(PullProgress)
+		**out = **in
+	}
+	if in.PullStartTime != nil {
+		in, out := &in.PullStartTime, &out.PullStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.PullInterruptedTime != nil {
+		in, out := &in.PullInterruptedTime, &out.PullInterruptedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Export != nil {
+		in, out := &in.Export, &out.Export
+		*out = new(ExportStatus)
+		**out = **in
+	}
+	if in.LastFailureTime != nil {
+		in, out := &in.LastFailureTime, &out.LastFailureTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OllamaModelStatus.
+func (in *OllamaModelStatus) DeepCopy() *OllamaModelStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OllamaModelStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OllamaOperatorConfig) DeepCopyInto(out *OllamaOperatorConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OllamaOperatorConfig.
+func (in *OllamaOperatorConfig) DeepCopy() *OllamaOperatorConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(OllamaOperatorConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OllamaOperatorConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OllamaOperatorConfigList) DeepCopyInto(out *OllamaOperatorConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OllamaOperatorConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OllamaOperatorConfigList.
+func (in *OllamaOperatorConfigList) DeepCopy() *OllamaOperatorConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(OllamaOperatorConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OllamaOperatorConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OllamaOperatorConfigSpec) DeepCopyInto(out *OllamaOperatorConfigSpec) {
+	*out = *in
+	if in.MaxConcurrentPulls != nil {
+		in, out := &in.MaxConcurrentPulls, &out.MaxConcurrentPulls
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ResyncInterval != nil {
+		in, out := &in.ResyncInterval, &out.ResyncInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.API != nil {
+		in, out := &in.API, &out.API
+		*out = new(OperatorAPIConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OllamaOperatorConfigSpec.
+func (in *OllamaOperatorConfigSpec) DeepCopy() *OllamaOperatorConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OllamaOperatorConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OllamaOperatorConfigStatus) DeepCopyInto(out *OllamaOperatorConfigStatus) {
+	*out = *in
+	if in.UnmanagedModels != nil {
+		in, out := &in.UnmanagedModels, &out.UnmanagedModels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.UnmanagedCheckTime != nil {
+		in, out := &in.UnmanagedCheckTime, &out.UnmanagedCheckTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OllamaOperatorConfigStatus.
+func (in *OllamaOperatorConfigStatus) DeepCopy() *OllamaOperatorConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OllamaOperatorConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OllamaPromotion) DeepCopyInto(out *OllamaPromotion) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OllamaPromotion.
+func (in *OllamaPromotion) DeepCopy() *OllamaPromotion {
+	if in == nil {
+		return nil
+	}
+	out := new(OllamaPromotion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OllamaPromotion) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OllamaPromotionList) DeepCopyInto(out *OllamaPromotionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OllamaPromotion, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OllamaPromotionList.
+func (in *OllamaPromotionList) DeepCopy() *OllamaPromotionList {
+	if in == nil {
+		return nil
+	}
+	out := new(OllamaPromotionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OllamaPromotionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OllamaPromotionSpec) DeepCopyInto(out *OllamaPromotionSpec) {
+	*out = *in
+	out.Source = in.Source
+	out.Approval = in.Approval
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OllamaPromotionSpec.
+func (in *OllamaPromotionSpec) DeepCopy() *OllamaPromotionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OllamaPromotionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OllamaPromotionStatus) DeepCopyInto(out *OllamaPromotionStatus) {
+	*out = *in
+	if in.PromotionTime != nil {
+		in, out := &in.PromotionTime, &out.PromotionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OllamaPromotionStatus.
+func (in *OllamaPromotionStatus) DeepCopy() *OllamaPromotionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OllamaPromotionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OllamaPullJob) DeepCopyInto(out *OllamaPullJob) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OllamaPullJob.
+func (in *OllamaPullJob) DeepCopy() *OllamaPullJob {
+	if in == nil {
+		return nil
+	}
+	out := new(OllamaPullJob)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OllamaPullJob) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OllamaPullJobList) DeepCopyInto(out *OllamaPullJobList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OllamaPullJob, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OllamaPullJobList.
+func (in *OllamaPullJobList) DeepCopy() *OllamaPullJobList {
+	if in == nil {
+		return nil
+	}
+	out := new(OllamaPullJobList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OllamaPullJobList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OllamaPullJobSpec) DeepCopyInto(out *OllamaPullJobSpec) {
+	*out = *in
+	if in.BackoffLimit != nil {
+		in, out := &in.BackoffLimit, &out.BackoffLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TTLSecondsAfterFinished != nil {
+		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OllamaPullJobSpec.
+func (in *OllamaPullJobSpec) DeepCopy() *OllamaPullJobSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OllamaPullJobSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OllamaPullJobStatus) DeepCopyInto(out *OllamaPullJobStatus) {
+	*out = *in
+	if in.Progress != nil {
+		in, out := &in.Progress, &out.Progress
+		*out = new(PullProgress)
+		**out = **in
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OllamaPullJobStatus.
+func (in *OllamaPullJobStatus) DeepCopy() *OllamaPullJobStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OllamaPullJobStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OllamaSchedule) DeepCopyInto(out *OllamaSchedule) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OllamaSchedule.
+func (in *OllamaSchedule) DeepCopy() *OllamaSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(OllamaSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OllamaSchedule) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OllamaScheduleList) DeepCopyInto(out *OllamaScheduleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OllamaSchedule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OllamaScheduleList.
+func (in *OllamaScheduleList) DeepCopy() *OllamaScheduleList {
+	if in == nil {
+		return nil
+	}
+	out := new(OllamaScheduleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OllamaScheduleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OllamaScheduleSpec) DeepCopyInto(out *OllamaScheduleSpec) {
+	*out = *in
+	if in.Windows != nil {
+		in, out := &in.Windows, &out.Windows
+		*out = make([]ScheduleWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Models != nil {
+		in, out := &in.Models, &out.Models
+		*out = make([]ScheduledModel, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OllamaScheduleSpec.
+func (in *OllamaScheduleSpec) DeepCopy() *OllamaScheduleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OllamaScheduleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OllamaScheduleStatus) DeepCopyInto(out *OllamaScheduleStatus) {
+	*out = *in
+	if in.NextTransition != nil {
+		in, out := &in.NextTransition, &out.NextTransition
+		*out = (*in).DeepCopy()
+	}
+	if in.Models != nil {
+		in, out := &in.Models, &out.Models
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OllamaScheduleStatus.
+func (in *OllamaScheduleStatus) DeepCopy() *OllamaScheduleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OllamaScheduleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorAPIConfig) DeepCopyInto(out *OperatorAPIConfig) {
+	*out = *in
+	if in.MaxBodyBytes != nil {
+		in, out := &in.MaxBodyBytes, &out.MaxBodyBytes
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorAPIConfig.
+func (in *OperatorAPIConfig) DeepCopy() *OperatorAPIConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorAPIConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromotionApproval) DeepCopyInto(out *PromotionApproval) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionApproval.
+func (in *PromotionApproval) DeepCopy() *PromotionApproval {
+	if in == nil {
+		return nil
+	}
+	out := new(PromotionApproval)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromotionSource) DeepCopyInto(out *PromotionSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionSource.
+func (in *PromotionSource) DeepCopy() *PromotionSource {
+	if in == nil {
+		return nil
+	}
+	out := new(PromotionSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PullProgress) DeepCopyInto(out *PullProgress) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PullProgress.
+func (in *PullProgress) DeepCopy() *PullProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(PullProgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetryPolicy) DeepCopyInto(out *RetryPolicy) {
+	*out = *in
+	if in.MaxAttempts != nil {
+		in, out := &in.MaxAttempts, &out.MaxAttempts
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetryPolicy.
+func (in *RetryPolicy) DeepCopy() *RetryPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RetryPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduleWindow) DeepCopyInto(out *ScheduleWindow) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]Weekday, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduleWindow.
+func (in *ScheduleWindow) DeepCopy() *ScheduleWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduleWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledModel) DeepCopyInto(out *ScheduledModel) {
+	*out = *in
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledModel.
+func (in *ScheduledModel) DeepCopy() *ScheduledModel {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledModel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotReference) DeepCopyInto(out *SnapshotReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotReference.
+func (in *SnapshotReference) DeepCopy() *SnapshotReference {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpdateWindow) DeepCopyInto(out *UpdateWindow) {
+	*out = *in
+	if in.Windows != nil {
+		in, out := &in.Windows, &out.Windows
+		*out = make([]ScheduleWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpdateWindow.
+func (in *UpdateWindow) DeepCopy() *UpdateWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(UpdateWindow)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -17,22 +17,35 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"crypto/tls"
+	"errors"
 	"flag"
+	"fmt"
 	"net/http"
+	"net/netip"
 	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
@@ -41,7 +54,19 @@ import (
 
 	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
 	httpapi "github.com/dmk/ollama-operator/internal/api"
+	"github.com/dmk/ollama-operator/internal/audit"
+	"github.com/dmk/ollama-operator/internal/breaker"
 	"github.com/dmk/ollama-operator/internal/controller"
+	"github.com/dmk/ollama-operator/internal/gateway"
+	"github.com/dmk/ollama-operator/internal/notify"
+	"github.com/dmk/ollama-operator/internal/ollamaauth"
+	"github.com/dmk/ollama-operator/internal/ollamacache"
+	"github.com/dmk/ollama-operator/internal/ollamatls"
+	"github.com/dmk/ollama-operator/internal/opconfig"
+	"github.com/dmk/ollama-operator/internal/prune"
+	"github.com/dmk/ollama-operator/internal/registry"
+	"github.com/dmk/ollama-operator/internal/secrets"
+	webhookv1alpha1 "github.com/dmk/ollama-operator/internal/webhook/v1alpha1"
 	ollamaapi "github.com/ollama/ollama/api"
 	// +kubebuilder:scaffold:imports
 )
@@ -51,6 +76,15 @@ var (
 	setupLog = ctrl.Log.WithName("setup")
 )
 
+// Modes the operator runs in
+const (
+	// modeAll runs the controllers, along with the API servers if enabled
+	modeAll = "all"
+	// modeAPI only runs the HTTP and gRPC API servers, so that they can be
+	// deployed and scaled apart from the controllers
+	modeAPI = "api"
+)
+
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
@@ -58,21 +92,77 @@ func init() {
 	// +kubebuilder:scaffold:scheme
 }
 
+// stringSliceFlag is a flag.Value collecting repeated string flags
+type stringSliceFlag []string
+
+func (f *stringSliceFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringSliceFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
 // nolint:gocyclo
 func main() {
+	var mode string
 	var metricsAddr string
 	var metricsCertPath, metricsCertName, metricsCertKey string
 	var webhookCertPath, webhookCertName, webhookCertKey string
+	var enableModelWebhook bool
 	var enableLeaderElection bool
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
 	var ollamaAPIURL string
 	var apiServerAddr string
+	var grpcServerAddr string
+	var gatewayAddr string
+	var gatewayWriteTimeout time.Duration
 	var apiServerKey string
+	var apiServerReadOnlyKey string
+	var apiKeysSecret string
 	var namespace string = "default"
 	var enableAPIServer bool
+	var apiReadOnly bool
+	var apiLockoutFailures int
+	var apiLockoutDuration time.Duration
+	var apiAllowedCIDRs stringSliceFlag
+	var apiTrustedProxies stringSliceFlag
+	var apiTLSSecret string
+	var apiRequireClientCert bool
+	var registryURLs stringSliceFlag
+	var availableMemory string
+	var exportImage string
+	var auditLogPath string
+	var auditWebhookURL string
+	var stateWebhooks stringSliceFlag
+	var alertProviders stringSliceFlag
+	var alertTemplateFile string
+	var stuckPullThreshold time.Duration
+	var finalizerTimeout time.Duration
+	var staleThreshold time.Duration
+	var integrityCheckInterval time.Duration
+	var updateCheckInterval time.Duration
+	var unmanagedCheckInterval time.Duration
+	var apiLimits httpapi.Limits
+	var ollamaTLS ollamatls.Options
+	var ollamaTLSSecret string
+	var ollamaTokenSecret string
+	var ollamaTokenSecretKey string
+	var ollamaCacheTTL time.Duration
+	var ollamaPollInterval time.Duration
+	var breakerThreshold int
+	var breakerCooldown time.Duration
+	var controllerOpts controller.Options
+	var operatorConfigName string
+	var watchNamespaces stringSliceFlag
+	var watchNamespaceSelector string
+	var watchLabelSelector string
 	var tlsOpts []func(*tls.Config)
+	flag.StringVar(&mode, "mode", modeAll, "What the process runs: \"all\" for the controllers, and the API servers "+
+		"when enabled, or \"api\" for the HTTP and gRPC API servers alone, without leader election, to deploy them apart.")
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
@@ -80,15 +170,130 @@ func main() {
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
 	flag.StringVar(&ollamaAPIURL, "ollama-api-url", "http://localhost:11434", "The URL of the Ollama API server")
+	flag.StringVar(&ollamaTLS.CAFile, "ollama-ca-file", "",
+		"A PEM bundle of CAs trusted, in addition to the system roots, for an HTTPS Ollama API URL.")
+	flag.StringVar(&ollamaTLS.CertFile, "ollama-client-cert-file", "",
+		"The client certificate presented to an Ollama server requiring mTLS.")
+	flag.StringVar(&ollamaTLS.KeyFile, "ollama-client-key-file", "", "The key of --ollama-client-cert-file.")
+	flag.BoolVar(&ollamaTLS.InsecureSkipVerify, "ollama-insecure-skip-verify", false,
+		"Do not verify the Ollama server's certificate. Only meant for testing.")
+	flag.StringVar(&ollamaTLSSecret, "ollama-tls-secret", "",
+		"A Secret ([namespace/]name) holding ca.crt, tls.crt and tls.key for the Ollama server, reloaded when it "+
+			"changes. Replaces the --ollama-ca-file and --ollama-client-*-file flags.")
+	flag.StringVar(&ollamaTokenSecret, "ollama-token-secret", "",
+		"A Secret ([namespace/]name) holding a bearer token sent to the Ollama server, reloaded when it changes.")
+	flag.StringVar(&ollamaTokenSecretKey, "ollama-token-secret-key", ollamaauth.DefaultSecretKey,
+		"The key of the token in --ollama-token-secret.")
+	flag.DurationVar(&ollamaCacheTTL, "ollama-cache-ttl", 10*time.Second,
+		"How long the controller caches Ollama model details and listings. Set to 0 to disable the cache.")
+	flag.DurationVar(&ollamaPollInterval, "ollama-poll-interval", 0,
+		"How often the models stored on the Ollama server are polled, so that Ready models deleted or replaced "+
+			"outside the operator are reconciled before their next resync. Set to 0 to disable polling.")
+	flag.IntVar(&breakerThreshold, "ollama-breaker-threshold", 5,
+		"The number of consecutive failures to reach Ollama after which the controller stops calling it "+
+			"for the cooldown. Set to 0 to disable the circuit breaker.")
+	flag.DurationVar(&breakerCooldown, "ollama-breaker-cooldown", 30*time.Second,
+		"How long the controller waits before calling Ollama again after the circuit breaker opened.")
+	flag.IntVar(&controllerOpts.MaxConcurrentReconciles, "max-concurrent-reconciles", 1,
+		"The number of OllamaModels reconciled, and so pulled, in parallel.")
+	flag.DurationVar(&controllerOpts.RateLimiterBaseDelay, "reconcile-base-delay", 5*time.Millisecond,
+		"The requeue delay after a model fails to reconcile for the first time; it doubles on each further failure.")
+	flag.DurationVar(&controllerOpts.RateLimiterMaxDelay, "reconcile-max-delay", 1000*time.Second,
+		"The maximum requeue delay of a model that keeps failing to reconcile.")
+	flag.StringVar(&operatorConfigName, "operator-config", ollamav1alpha1.DefaultOperatorConfigName,
+		"The name of the cluster-scoped OllamaOperatorConfig applied while the operator runs.")
+	flag.Var(&watchNamespaces, "watch-namespace", "A namespace whose resources the operator caches and reconciles. "+
+		"Can be repeated; defaults to all namespaces.")
+	flag.StringVar(&watchNamespaceSelector, "watch-namespace-selector", "",
+		"A label selector of Namespaces whose resources the operator caches and reconciles, besides those of "+
+			"--watch-namespace. It is resolved when the operator starts, so namespaces labeled later are watched "+
+			"after a restart.")
+	flag.StringVar(&watchLabelSelector, "watch-label-selector", "",
+		"A label selector restricting the OllamaModels, and the other namespaced resources of the operator, that are "+
+			"cached and reconciled. Models created through the API are given its labels when it only has equalities.")
 	flag.StringVar(&apiServerAddr, "api-server-bind-address", ":8082", "The address the HTTP API server binds to.")
+	flag.StringVar(&grpcServerAddr, "grpc-server-bind-address", "",
+		"The address the gRPC API server binds to. Leave empty to disable the gRPC API.")
+	flag.StringVar(&gatewayAddr, "gateway-bind-address", "",
+		"The address the inference gateway binds to. Leave empty to disable the gateway. Requests need one of "+
+			"the API keys once any is configured.")
+	flag.DurationVar(&gatewayWriteTimeout, "gateway-write-timeout", gateway.DefaultWriteTimeout,
+		"The maximum duration before timing out writes of a gateway response. Streamed inference responses "+
+			"are exempt as a whole, but each of their writes must complete within it.")
 	flag.StringVar(&apiServerKey, "api-server-key", "", "The API key for authenticating requests to the API server.")
-	flag.StringVar(&namespace, "namespace", namespace, "The namespace to use for operations.")
+	flag.StringVar(&apiServerReadOnlyKey, "api-server-read-only-key", "",
+		"An API key only allowed to list and get resources from the API server, for dashboards and other observers.")
+	flag.IntVar(&apiLockoutFailures, "api-lockout-failures", 0,
+		"The number of failed authentications in a row after which the API servers and the gateway refuse the requests of a source "+
+			"for --api-lockout-duration, answering 429 Too Many Requests. 0 disables lockouts.")
+	flag.DurationVar(&apiLockoutDuration, "api-lockout-duration", 5*time.Minute,
+		"How long a source stays locked out after too many failed authentications.")
+	flag.Var(&apiAllowedCIDRs, "api-allowed-cidr", "A network, in CIDR notation, the clients of the API servers and the gateway must "+
+		"connect from; others are refused with 403 Forbidden. May be repeated; defaults to every network.")
+	flag.Var(&apiTrustedProxies, "api-trusted-proxy", "The network, in CIDR notation, of a reverse proxy in front of the "+
+		"API servers or the gateway whose X-Forwarded-For header tells the client address. May be repeated.")
+	flag.StringVar(&apiTLSSecret, "api-tls-secret", "", "A Secret ([namespace/]name) holding tls.crt and tls.key to "+
+		"serve the API servers and the gateway over TLS, and optionally ca.crt to verify client certificates, reloaded when it changes.")
+	flag.BoolVar(&apiRequireClientCert, "api-require-client-cert", false,
+		"Refuse API clients without a certificate signed by the ca.crt of --api-tls-secret.")
+	flag.StringVar(&apiKeysSecret, "api-keys-secret", "",
+		"A Secret ([namespace/]name) holding named API keys for the API server, reloaded when it changes.")
+	flag.StringVar(&namespace, "namespace", namespace, "The default namespace for API routes that are not namespace-scoped.")
 	flag.BoolVar(&enableAPIServer, "enable-api-server", false, "Enable the HTTP API server.")
+	flag.BoolVar(&apiReadOnly, "api-read-only", false,
+		"Disable the API endpoints changing models, for installs managing them through GitOps. "+
+			"They answer 405 Method Not Allowed, and Unimplemented over gRPC.")
+	flag.Var(&registryURLs, "registry-url", "The URL of a model registry to search from the API server and to "+
+		"estimate memory requirements from. May be repeated; defaults to the public Ollama library.")
+	flag.DurationVar(&apiLimits.ReadTimeout, "api-read-timeout", httpapi.DefaultLimits.ReadTimeout,
+		"The maximum duration for reading an entire API request, including the body.")
+	flag.DurationVar(&apiLimits.WriteTimeout, "api-write-timeout", httpapi.DefaultLimits.WriteTimeout,
+		"The maximum duration before timing out writes of an API response. Watch streams are exempt.")
+	flag.DurationVar(&apiLimits.IdleTimeout, "api-idle-timeout", httpapi.DefaultLimits.IdleTimeout,
+		"The maximum time to wait for the next request on a keep-alive API connection.")
+	flag.IntVar(&apiLimits.MaxHeaderBytes, "api-max-header-bytes", httpapi.DefaultLimits.MaxHeaderBytes,
+		"The maximum size of API request headers in bytes.")
+	flag.Int64Var(&apiLimits.MaxBodyBytes, "api-max-body-bytes", httpapi.DefaultLimits.MaxBodyBytes,
+		"The maximum size of API request bodies in bytes. Larger requests are rejected with 413.")
+	flag.StringVar(&auditLogPath, "audit-log-path", "-",
+		"Where to write the audit log of mutating operations: \"-\" for stdout, a file path, or empty to disable.")
+	flag.StringVar(&auditWebhookURL, "audit-webhook-url", "", "If set, audit events are also posted as JSON to this URL.")
+	flag.Var(&stateWebhooks, "state-webhook", "A URL to post model state transitions (ready, failed, deleted) to, "+
+		"optionally followed by \",secret-file=PATH\" to sign deliveries with the HMAC secret in PATH. Can be repeated.")
+	flag.Var(&alertProviders, "alert", "Where to send alerts about failed and stuck pulls: slack=WEBHOOK_URL, "+
+		"webhook=URL, or pagerduty=PATH with PATH holding a PagerDuty routing key. Can be repeated.")
+	flag.StringVar(&alertTemplateFile, "alert-template-file", "",
+		"A Go template file rendering alert messages. Defaults to a one-line summary of the alert.")
+	flag.DurationVar(&finalizerTimeout, "finalizer-timeout", 15*time.Minute,
+		"How long a deleted OllamaModel waits for its model to be deleted from Ollama before it is removed anyway, "+
+			"possibly leaving the model behind. Set to 0 to wait forever.")
+	flag.DurationVar(&staleThreshold, "model-stale-threshold", 0,
+		"How long after its last pull a Ready model gets a True Stale condition. Set to 0 to disable the condition.")
+	flag.DurationVar(&integrityCheckInterval, "integrity-check-interval", 0,
+		"How often Ready models are verified on the Ollama server, setting their Corrupted condition when a model "+
+			"cannot be read or its digest changed. Set to 0 to disable the check.")
+	flag.DurationVar(&updateCheckInterval, "update-check-interval", 0,
+		"How often the registries are checked for a new version of the tag of Ready models, setting their "+
+			"NewVersionAvailable condition without pulling it. Set to 0 to disable the check.")
+	flag.DurationVar(&unmanagedCheckInterval, "unmanaged-check-interval", 10*time.Minute,
+		"How often the models stored on the Ollama server that no OllamaModel manages are listed, for the "+
+			"ollama_unmanaged_models metric and the OllamaOperatorConfig status. Set to 0 to disable the check.")
+	flag.StringVar(&availableMemory, "ollama-available-memory", "",
+		"The memory available to the Ollama server for models (e.g. 24Gi). Models estimated to need more get a "+
+			"True WontFit condition and are not pulled. Leave empty to disable the check.")
+	flag.StringVar(&exportImage, "export-image", controller.DefaultExportImage,
+		"The image of the Jobs exporting and pre-warming model blobs on PersistentVolumeClaims. "+
+			"It must provide the ollama CLI.")
+	flag.DurationVar(&stuckPullThreshold, "alert-pull-threshold", time.Hour,
+		"How long a model may be pulling before an alert is sent. Set to 0 to disable the alert.")
 	flag.BoolVar(&secureMetrics, "metrics-secure", true,
 		"If set, the metrics endpoint is served securely via HTTPS. Use --metrics-secure=false to use HTTP instead.")
 	flag.StringVar(&webhookCertPath, "webhook-cert-path", "", "The directory that contains the webhook certificate.")
 	flag.StringVar(&webhookCertName, "webhook-cert-name", "tls.crt", "The name of the webhook certificate file.")
 	flag.StringVar(&webhookCertKey, "webhook-cert-key", "tls.key", "The name of the webhook key file.")
+	flag.BoolVar(&enableModelWebhook, "enable-model-webhook", false,
+		"Serve the validating webhook of OllamaModels, which looks models up in the --registry-url registries "+
+			"so that unknown models are rejected when applied. The webhook needs a certificate, see --webhook-cert-path.")
 	flag.StringVar(&metricsCertPath, "metrics-cert-path", "",
 		"The directory that contains the metrics server certificate.")
 	flag.StringVar(&metricsCertName, "metrics-cert-name", "tls.crt", "The name of the metrics server certificate file.")
@@ -103,6 +308,20 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	// A standalone API server serves requests on every replica and leaves the
+	// models to the controllers running elsewhere
+	apiOnly := false
+	switch mode {
+	case modeAll:
+	case modeAPI:
+		apiOnly = true
+		enableAPIServer = true
+		enableLeaderElection = false
+	default:
+		setupLog.Error(errors.New("must be \"all\" or \"api\""), "invalid --mode", "mode", mode)
+		os.Exit(1)
+	}
+
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
 	// prevent from being vulnerable to the HTTP/2 Stream Cancellation and
@@ -192,8 +411,34 @@ func main() {
 		})
 	}
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	restConfig := ctrl.GetConfigOrDie()
+	// Tag Kubernetes API calls made on behalf of HTTP API requests with the request ID
+	restConfig.Wrap(httpapi.RequestIDTransport)
+
+	if watchNamespaceSelector != "" {
+		selected, err := selectNamespaces(restConfig, watchNamespaceSelector)
+		if err != nil {
+			setupLog.Error(err, "unable to resolve --watch-namespace-selector")
+			os.Exit(1)
+		}
+		setupLog.Info("watching the namespaces matching --watch-namespace-selector", "namespaces", selected)
+		for _, namespace := range selected {
+			if !slices.Contains(watchNamespaces, namespace) {
+				watchNamespaces = append(watchNamespaces, namespace)
+			}
+		}
+	}
+
+	// Cache, and so reconcile, only the resources the operator is meant to own
+	cacheOpts, err := cacheOptions(watchNamespaces, watchLabelSelector)
+	if err != nil {
+		setupLog.Error(err, "invalid --watch-label-selector")
+		os.Exit(1)
+	}
+
+	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
 		Scheme:                 scheme,
+		Cache:                  cacheOpts,
 		Metrics:                metricsServerOptions,
 		WebhookServer:          webhookServer,
 		HealthProbeBindAddress: probeAddr,
@@ -209,7 +454,17 @@ func main() {
 		// the manager stops, so would be fine to enable this option. However,
 		// if you are doing or is intended to do any operation such as perform cleanups
 		// after the manager stops then its usage might be unsafe.
-		// LeaderElectionReleaseOnCancel: true,
+		//
+		// The operator ends right after the manager, and a standby replica
+		// takes over interrupted pulls sooner when the lease is released.
+		LeaderElectionReleaseOnCancel: true,
+		Client: client.Options{
+			Cache: &client.CacheOptions{
+				// Events and pull log ConfigMaps are only read on demand, so query
+				// them directly instead of caching every one in the cluster
+				DisableFor: []client.Object{&corev1.Event{}, &corev1.ConfigMap{}},
+			},
+		},
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
@@ -223,18 +478,239 @@ func main() {
 		setupLog.Error(err, "invalid Ollama API URL")
 		os.Exit(1)
 	}
-	ollamaClient := ollamaapi.NewClient(ollamaURL, http.DefaultClient)
+	var ollamaTransport http.RoundTripper = http.DefaultTransport
+	if ollamaTLSSecret != "" || ollamaTLS != (ollamatls.Options{}) {
+		ollamaTransport, err = newOllamaTransport(mgr, ollamaTLS, ollamaTLSSecret, namespace)
+		if err != nil {
+			setupLog.Error(err, "unable to configure TLS for the Ollama client")
+			os.Exit(1)
+		}
+	}
+	// The inference gateway sends the token only for authenticated requests
+	ollamaTLSTransport := ollamaTransport
+	if ollamaTokenSecret != "" {
+		ollamaTransport, err = newOllamaTokenTransport(mgr, ollamaTransport, ollamaTokenSecret, ollamaTokenSecretKey, namespace)
+		if err != nil {
+			setupLog.Error(err, "unable to configure the Ollama bearer token")
+			os.Exit(1)
+		}
+	}
+	ollamaClient := ollamaapi.NewClient(ollamaURL, &http.Client{Transport: ollamaTransport})
+
+	// Reconciles stop calling the Ollama server while it is down, and read model
+	// details through a short-lived cache so that resyncing many models does not
+	// hammer it
+	var controllerOllama controller.OllamaClient = ollamaClient
+	if breakerThreshold > 0 {
+		controllerOllama = breaker.New(controllerOllama, breakerThreshold, breakerCooldown)
+	}
+	var invalidate func(model string)
+	if ollamaCacheTTL > 0 {
+		cache := ollamacache.New(controllerOllama, ollamaCacheTTL)
+		controllerOllama, invalidate = cache, cache.Invalidate
+	}
+	var tagsPoller *controller.TagsPoller
+	if ollamaPollInterval > 0 {
+		tagsPoller = controller.NewTagsPoller(mgr.GetClient(), ollamaClient, ollamaPollInterval, invalidate)
+	}
+
+	// Initialize the audit log shared by the API servers and the controller
+	var auditSinks []audit.Sink
+	switch auditLogPath {
+	case "":
+	case "-":
+		auditSinks = append(auditSinks, audit.NewWriterSink(os.Stdout))
+	default:
+		auditFile, err := os.OpenFile(auditLogPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			setupLog.Error(err, "unable to open audit log", "path", auditLogPath)
+			os.Exit(1)
+		}
+		auditSinks = append(auditSinks, audit.NewWriterSink(auditFile))
+	}
+	if auditWebhookURL != "" {
+		auditSinks = append(auditSinks, audit.NewWebhookSink(auditWebhookURL, nil))
+	}
+	auditor := audit.NewLogger(auditSinks...)
+
+	// Initialize webhooks for model state transitions
+	var webhooks []notify.Webhook
+	for _, spec := range stateWebhooks {
+		webhook, err := notify.ParseWebhook(spec)
+		if err != nil {
+			setupLog.Error(err, "invalid state webhook")
+			os.Exit(1)
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	// Initialize alerting about failed and stuck pulls
+	var providers []notify.Provider
+	for _, spec := range alertProviders {
+		provider, err := notify.ParseProvider(spec)
+		if err != nil {
+			setupLog.Error(err, "invalid alert provider")
+			os.Exit(1)
+		}
+		providers = append(providers, provider)
+	}
+	var alertTemplate []byte
+	if alertTemplateFile != "" {
+		if alertTemplate, err = os.ReadFile(alertTemplateFile); err != nil {
+			setupLog.Error(err, "unable to read alert template")
+			os.Exit(1)
+		}
+	}
+	alerter, err := notify.NewAlerter(providers, string(alertTemplate), nil)
+	if err != nil {
+		setupLog.Error(err, "invalid alert template")
+		os.Exit(1)
+	}
+
+	var memoryLimit int64
+	if availableMemory != "" {
+		quantity, err := resource.ParseQuantity(availableMemory)
+		if err != nil {
+			setupLog.Error(err, "invalid --ollama-available-memory")
+			os.Exit(1)
+		}
+		memoryLimit = quantity.Value()
+	}
 
-	if err = (&controller.OllamaModelReconciler{
-		Client:   mgr.GetClient(),
-		Scheme:   mgr.GetScheme(),
-		Ollama:   ollamaClient,
-		Recorder: mgr.GetEventRecorderFor("ollama-controller"),
+	// Runtime settings start out with the flags and follow the OllamaOperatorConfig
+	settings := opconfig.NewStore(opconfig.Settings{PruneMode: ollamav1alpha1.PruneEnabled})
+	if err = (&controller.OperatorConfigReconciler{
+		Client:    mgr.GetClient(),
+		Name:      operatorConfigName,
+		Settings:  settings,
+		ApplyOnly: apiOnly,
 	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "OllamaModel")
+		setupLog.Error(err, "unable to create controller", "controller", "OllamaOperatorConfig")
 		os.Exit(1)
 	}
-	// +kubebuilder:scaffold:builder
+
+	// Models and pull jobs share the limit on concurrent pulls
+	pulls := &controller.PullSlots{}
+
+	// The controllers only run in the operator, not in standalone API servers
+	if !apiOnly {
+		// Models report the Ollama server they are managed on, without credentials
+		endpointURL := *ollamaURL
+		endpointURL.User = nil
+
+		// Models record the replica pulling them, named after its pod
+		identity, err := os.Hostname()
+		if err != nil {
+			setupLog.Error(err, "unable to determine the replica identity")
+			os.Exit(1)
+		}
+
+		if err = (&controller.OllamaModelReconciler{
+			Client:   mgr.GetClient(),
+			Scheme:   mgr.GetScheme(),
+			Ollama:   controllerOllama,
+			Recorder: mgr.GetEventRecorderFor("ollama-controller"),
+			Audit:    auditor,
+			Notifier: notify.NewNotifier(webhooks, nil),
+			Alerter:  alerter,
+			Settings: settings,
+
+			StuckPullThreshold:     stuckPullThreshold,
+			FinalizerTimeout:       finalizerTimeout,
+			StaleThreshold:         staleThreshold,
+			IntegrityCheckInterval: integrityCheckInterval,
+			UpdateCheckInterval:    updateCheckInterval,
+			AvailableMemory:        memoryLimit,
+			Registry:               registry.NewClient(registryURLs, nil),
+			ExportImage:            exportImage,
+			TagsPoller:             tagsPoller,
+			Identity:               identity,
+			OllamaURL:              endpointURL.String(),
+			Pulls:                  pulls,
+			APIReader:              mgr.GetAPIReader(),
+		}).SetupWithManager(mgr, controllerOpts); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "OllamaModel")
+			os.Exit(1)
+		}
+		if err = (&controller.PrewarmReconciler{
+			Client:   mgr.GetClient(),
+			Scheme:   mgr.GetScheme(),
+			Recorder: mgr.GetEventRecorderFor("ollama-prewarm"),
+			Image:    exportImage,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Prewarm")
+			os.Exit(1)
+		}
+		if err = (&controller.OllamaModelCacheReconciler{
+			Client:   mgr.GetClient(),
+			Scheme:   mgr.GetScheme(),
+			Recorder: mgr.GetEventRecorderFor("ollama-model-cache"),
+			Image:    exportImage,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "OllamaModelCache")
+			os.Exit(1)
+		}
+		if err = (&controller.OllamaModelAliasReconciler{
+			Client: mgr.GetClient(),
+			Scheme: mgr.GetScheme(),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "OllamaModelAlias")
+			os.Exit(1)
+		}
+		if err = (&controller.OllamaScheduleReconciler{
+			Client:   mgr.GetClient(),
+			Scheme:   mgr.GetScheme(),
+			Recorder: mgr.GetEventRecorderFor("ollama-schedule"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "OllamaSchedule")
+			os.Exit(1)
+		}
+		if err = (&controller.OllamaPullJobReconciler{
+			Client:   mgr.GetClient(),
+			Scheme:   mgr.GetScheme(),
+			Ollama:   controllerOllama,
+			Recorder: mgr.GetEventRecorderFor("ollama-pull-job"),
+			Settings: settings,
+			Pulls:    pulls,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "OllamaPullJob")
+			os.Exit(1)
+		}
+		if err = (&controller.OllamaModelSnapshotReconciler{
+			Client:   mgr.GetClient(),
+			Scheme:   mgr.GetScheme(),
+			Ollama:   controllerOllama,
+			Recorder: mgr.GetEventRecorderFor("ollama-snapshot"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "OllamaModelSnapshot")
+			os.Exit(1)
+		}
+		if err = (&controller.OllamaPromotionReconciler{
+			Client:   mgr.GetClient(),
+			Scheme:   mgr.GetScheme(),
+			Ollama:   controllerOllama,
+			Recorder: mgr.GetEventRecorderFor("ollama-promotion"),
+			Audit:    auditor,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "OllamaPromotion")
+			os.Exit(1)
+		}
+		if enableModelWebhook {
+			if err = webhookv1alpha1.SetupOllamaModelWebhookWithManager(mgr, registry.NewClient(registryURLs, nil)); err != nil {
+				setupLog.Error(err, "unable to create webhook", "webhook", "OllamaModel")
+				os.Exit(1)
+			}
+		}
+		// +kubebuilder:scaffold:builder
+
+		if unmanagedCheckInterval > 0 {
+			inventory := prune.NewInventory(mgr.GetClient(), mgr.GetAPIReader(), ollamaClient, operatorConfigName, unmanagedCheckInterval)
+			if err := mgr.Add(inventory); err != nil {
+				setupLog.Error(err, "unable to add unmanaged model inventory to manager")
+				os.Exit(1)
+			}
+		}
+	}
 
 	if metricsCertWatcher != nil {
 		setupLog.Info("Adding metrics certificate watcher to manager")
@@ -252,20 +728,152 @@ func main() {
 		}
 	}
 
+	// The API servers and the inference gateway accept the same API keys, from
+	// the same networks, over the same TLS, and share the lockout of the
+	// sources failing to authenticate
+	var keyring *httpapi.Keyring
+	var lockout *httpapi.Lockout
+	var serverTLS *httpapi.ServerTLS
+	var allowedNetworks, trustedProxies []netip.Prefix
+	if enableAPIServer || gatewayAddr != "" {
+		keyring = httpapi.NewKeyring(apiServerKey, apiKeysSecret != "")
+		if apiServerReadOnlyKey != "" {
+			keyring.AddReadOnlyKey(apiServerReadOnlyKey)
+		}
+		if apiKeysSecret != "" {
+			secretKey, err := secrets.ParseKey(apiKeysSecret, namespace)
+			if err != nil {
+				setupLog.Error(err, "invalid API keys secret")
+				os.Exit(1)
+			}
+
+			setupLog.Info("loading API keys from secret", "secret", secretKey.String())
+			keysWatcher, err := secrets.NewWatcher(mgr.GetConfig(), mgr.GetScheme(), secretKey, func(secret *corev1.Secret) {
+				if err := keyring.LoadSecret(secret); err != nil {
+					setupLog.Error(err, "failed to load API keys", "secret", secretKey.String())
+				}
+			})
+			if err != nil {
+				setupLog.Error(err, "unable to watch API keys secret")
+				os.Exit(1)
+			}
+			if err := mgr.Add(keysWatcher); err != nil {
+				setupLog.Error(err, "unable to add API keys secret watcher to manager")
+				os.Exit(1)
+			}
+		}
+		lockout = httpapi.NewLockout(apiLockoutFailures, apiLockoutDuration)
+
+		if apiRequireClientCert && apiTLSSecret == "" {
+			setupLog.Error(errors.New("--api-tls-secret is required"), "invalid --api-require-client-cert")
+			os.Exit(1)
+		}
+		if apiTLSSecret != "" {
+			serverTLS, err = newServerTLS(mgr, apiTLSSecret, namespace, apiRequireClientCert)
+			if err != nil {
+				setupLog.Error(err, "unable to configure TLS for the API servers")
+				os.Exit(1)
+			}
+		}
+
+		allowedNetworks, err = httpapi.ParsePrefixes(apiAllowedCIDRs)
+		if err != nil {
+			setupLog.Error(err, "invalid --api-allowed-cidr")
+			os.Exit(1)
+		}
+		trustedProxies, err = httpapi.ParsePrefixes(apiTrustedProxies)
+		if err != nil {
+			setupLog.Error(err, "invalid --api-trusted-proxy")
+			os.Exit(1)
+		}
+	}
+
 	// Initialize API server if enabled
 	if enableAPIServer {
 		setupLog.Info("initializing API server", "address", apiServerAddr)
 
-		apiServer := httpapi.NewServer(httpapi.Config{
-			BindAddress: apiServerAddr,
-			APIKey:      apiServerKey,
-			Namespace:   namespace,
-		}, mgr.GetClient())
+		apiConfig := httpapi.Config{
+			BindAddress:     apiServerAddr,
+			GRPCBindAddress: grpcServerAddr,
+			Keyring:         keyring,
+			Auditor:         auditor,
+			Namespace:       namespace,
+			RegistryURLs:    registryURLs,
+			Limits:          apiLimits,
+			Settings:        settings,
+			Elected:         mgr.Elected(),
+			Reader:          mgr.GetAPIReader(),
+			WatchNamespaces: watchNamespaces,
+			ReadOnly:        apiReadOnly,
+			Lockout:         lockout,
+			AllowedNetworks: allowedNetworks,
+			TrustedProxies:  trustedProxies,
+			TLS:             serverTLS,
+		}
+		// Standalone API servers have no pull queue to show
+		if !apiOnly {
+			apiConfig.PullQueue = pulls
+		}
+		// Models created through the API are labeled to be watched
+		if watchLabelSelector != "" {
+			modelLabels, err := labels.ConvertSelectorToLabelsMap(watchLabelSelector)
+			if err != nil {
+				setupLog.Error(err, "models created through the API are not labeled, and are not reconciled "+
+					"unless labeled otherwise, as --watch-label-selector is not made only of equalities")
+			} else {
+				apiConfig.ModelLabels = modelLabels
+			}
+		}
+		apiServer := httpapi.NewServer(apiConfig, mgr.GetClient(), ollamaClient, mgr.GetCache())
 
 		if err := mgr.Add(apiServer); err != nil {
 			setupLog.Error(err, "unable to set up API server")
 			os.Exit(1)
 		}
+		// A standalone API server only serves the API, so it is not ready
+		// while the API cannot answer; the operator stays ready regardless,
+		// for its controllers and webhooks
+		if apiOnly {
+			if err := mgr.AddReadyzCheck("api-server", apiServer.ReadyzCheck); err != nil {
+				setupLog.Error(err, "unable to set up API server ready check")
+				os.Exit(1)
+			}
+		}
+
+		if grpcServerAddr != "" {
+			setupLog.Info("initializing gRPC server", "address", grpcServerAddr)
+			if err := mgr.Add(httpapi.NewGRPCServer(apiConfig, mgr.GetClient())); err != nil {
+				setupLog.Error(err, "unable to set up gRPC server")
+				os.Exit(1)
+			}
+		}
+	}
+
+	// Initialize the inference gateway if enabled
+	if gatewayAddr != "" {
+		setupLog.Info("initializing inference gateway", "address", gatewayAddr)
+		gatewayTransport := ollamaTransport
+		if !keyring.Enabled() {
+			gatewayTransport = ollamaTLSTransport
+			if ollamaTokenSecret != "" {
+				setupLog.Info("the inference gateway does not send the Ollama bearer token, as it has no API key to authenticate requests with")
+			}
+		}
+		gw := gateway.New(gateway.Config{
+			BindAddress:     gatewayAddr,
+			OllamaURL:       ollamaURL,
+			Transport:       gatewayTransport,
+			Keyring:         keyring,
+			Lockout:         lockout,
+			AllowedNetworks: allowedNetworks,
+			TrustedProxies:  trustedProxies,
+			TLS:             serverTLS,
+			WriteTimeout:    gatewayWriteTimeout,
+		}, mgr.GetClient())
+		if err := mgr.Add(gw); err != nil {
+			setupLog.Error(err, "unable to set up inference gateway")
+			os.Exit(1)
+		}
 	}
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -283,3 +891,170 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=list
+
+// selectNamespaces returns the names of the Namespaces matching the label
+// selector. Matching none is an error, as an empty list of namespaces to
+// watch would watch every namespace.
+func selectNamespaces(config *rest.Config, labelSelector string) ([]string, error) {
+	selector, err := labels.Parse(labelSelector)
+	if err != nil {
+		return nil, err
+	}
+	c, err := client.New(config, client.Options{})
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	var list corev1.NamespaceList
+	if err := c.List(ctx, &list, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, err
+	}
+	if len(list.Items) == 0 {
+		return nil, fmt.Errorf("no namespace matches %q", labelSelector)
+	}
+	names := make([]string, 0, len(list.Items))
+	for _, namespace := range list.Items {
+		names = append(names, namespace.Name)
+	}
+	return names, nil
+}
+
+// cacheOptions restricts the manager's cache to the watched namespaces, and
+// the namespaced resources of the operator to those matching the label
+// selector. The resources the operator creates itself, such as export Jobs,
+// and the cluster-scoped OllamaOperatorConfig are not filtered by labels.
+func cacheOptions(namespaces []string, labelSelector string) (cache.Options, error) {
+	var opts cache.Options
+	if len(namespaces) > 0 {
+		opts.DefaultNamespaces = make(map[string]cache.Config, len(namespaces))
+		for _, namespace := range namespaces {
+			opts.DefaultNamespaces[namespace] = cache.Config{}
+		}
+	}
+	if labelSelector == "" {
+		return opts, nil
+	}
+
+	selector, err := labels.Parse(labelSelector)
+	if err != nil {
+		return opts, err
+	}
+	opts.ByObject = map[client.Object]cache.ByObject{}
+	for _, obj := range []client.Object{
+		&ollamav1alpha1.OllamaModel{},
+		&ollamav1alpha1.OllamaModelAlias{},
+		&ollamav1alpha1.OllamaModelCache{},
+		&ollamav1alpha1.OllamaModelSnapshot{},
+		&ollamav1alpha1.OllamaPromotion{},
+		&ollamav1alpha1.OllamaPullJob{},
+		&ollamav1alpha1.OllamaSchedule{},
+	} {
+		opts.ByObject[obj] = cache.ByObject{Label: selector}
+	}
+	return opts, nil
+}
+
+// newOllamaTransport returns a transport connecting to the Ollama server with
+// TLS configured from files, or from a Secret that is watched for rotated
+// certificates. Requests fail until the Secret has been loaded.
+func newOllamaTransport(mgr ctrl.Manager, opts ollamatls.Options, secretRef, namespace string) (*ollamatls.Transport, error) {
+	if secretRef == "" {
+		config, err := opts.Load()
+		if err != nil {
+			return nil, err
+		}
+		return ollamatls.NewTransport(config), nil
+	}
+
+	if opts.CAFile != "" || opts.CertFile != "" || opts.KeyFile != "" {
+		return nil, errors.New("--ollama-tls-secret cannot be combined with TLS files")
+	}
+	secretKey, err := secrets.ParseKey(secretRef, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	setupLog.Info("loading Ollama TLS configuration from secret", "secret", secretKey.String())
+	transport := ollamatls.NewTransport(nil)
+	watcher, err := secrets.NewWatcher(mgr.GetConfig(), mgr.GetScheme(), secretKey, func(secret *corev1.Secret) {
+		// Keep the last configuration when the Secret is deleted, so that a
+		// botched rotation does not cut the controller off the Ollama server
+		if secret == nil {
+			return
+		}
+		config, err := ollamatls.FromSecret(secret, opts.InsecureSkipVerify)
+		if err != nil {
+			setupLog.Error(err, "failed to load Ollama TLS configuration", "secret", secretKey.String())
+			return
+		}
+		transport.SetConfig(config)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := mgr.Add(watcher); err != nil {
+		return nil, err
+	}
+	return transport, nil
+}
+
+// newServerTLS returns the TLS configuration of the API servers, loaded from a
+// Secret that is watched for rotated certificates. Handshakes fail until the
+// Secret has been loaded.
+func newServerTLS(mgr ctrl.Manager, secretRef, namespace string, requireClientCert bool) (*httpapi.ServerTLS, error) {
+	secretKey, err := secrets.ParseKey(secretRef, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	setupLog.Info("loading API server TLS configuration from secret", "secret", secretKey.String())
+	serverTLS := httpapi.NewServerTLS(requireClientCert)
+	watcher, err := secrets.NewWatcher(mgr.GetConfig(), mgr.GetScheme(), secretKey, func(secret *corev1.Secret) {
+		// Keep serving the last certificate when the Secret is deleted
+		if secret == nil {
+			return
+		}
+		if err := serverTLS.LoadSecret(secret); err != nil {
+			setupLog.Error(err, "failed to load API server TLS configuration", "secret", secretKey.String())
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := mgr.Add(watcher); err != nil {
+		return nil, err
+	}
+	return serverTLS, nil
+}
+
+// newOllamaTokenTransport returns a transport sending a bearer token from a
+// Secret with every request to the Ollama server. Requests fail until the
+// Secret has been loaded.
+func newOllamaTokenTransport(mgr ctrl.Manager, base http.RoundTripper, secretRef, key, namespace string) (*ollamaauth.Transport, error) {
+	secretKey, err := secrets.ParseKey(secretRef, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	setupLog.Info("loading Ollama bearer token from secret", "secret", secretKey.String())
+	transport := ollamaauth.NewTransport(base)
+	watcher, err := secrets.NewWatcher(mgr.GetConfig(), mgr.GetScheme(), secretKey, func(secret *corev1.Secret) {
+		// Keep the last token when the Secret is deleted, as with the TLS configuration
+		if secret == nil {
+			return
+		}
+		if err := transport.LoadSecret(secret, key); err != nil {
+			setupLog.Error(err, "failed to load Ollama bearer token", "secret", secretKey.String())
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := mgr.Add(watcher); err != nil {
+		return nil, err
+	}
+	return transport, nil
+}
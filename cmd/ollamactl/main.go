@@ -0,0 +1,246 @@
+// Command ollamactl manages the models of an ollama-operator through its REST API.
+//
+//	ollamactl [global flags] get [NAME]
+//	ollamactl [global flags] create NAME TAG [-wait]
+//	ollamactl [global flags] refresh NAME [-wait]
+//	ollamactl [global flags] retry NAME
+//	ollamactl [global flags] cancel NAME
+//	ollamactl [global flags] delete NAME
+//	ollamactl [global flags] export [-A]
+//	ollamactl version
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/dmk/ollama-operator/internal/version"
+	"github.com/dmk/ollama-operator/pkg/client"
+)
+
+const usage = `ollamactl manages the models of an ollama-operator through its REST API.
+
+Usage:
+  ollamactl [global flags] get [NAME]            List models, or show one model
+  ollamactl [global flags] create NAME TAG       Pull a model (-wait to follow the pull)
+  ollamactl [global flags] refresh NAME          Pull a model again (-wait to follow the pull)
+  ollamactl [global flags] retry NAME            Pull a Failed model again, even after its retries ran out
+  ollamactl [global flags] cancel NAME           Cancel the running pull or refresh of a model
+  ollamactl [global flags] delete NAME           Delete a model
+  ollamactl [global flags] export [-A]           Print models as YAML manifests (-A for all namespaces)
+  ollamactl version                              Print the ollamactl version
+
+Global flags:
+`
+
+func main() {
+	flags := flag.NewFlagSet("ollamactl", flag.ExitOnError)
+	server := flags.String("server", envOr("OLLAMACTL_SERVER", "http://localhost:8082"),
+		"The URL of the operator's API server. Defaults to $OLLAMACTL_SERVER.")
+	apiKey := flags.String("api-key", os.Getenv("OLLAMACTL_API_KEY"), "The API key to authenticate with. Defaults to $OLLAMACTL_API_KEY.")
+	namespace := flags.String("namespace", "", "The namespace of the models. Defaults to the server's namespace.")
+	flags.StringVar(namespace, "n", "", "Shorthand for -namespace.")
+	output := flags.String("o", "", "The output format: wide or json. Defaults to a table.")
+	noColor := flags.Bool("no-color", os.Getenv("NO_COLOR") != "", "Disable colored output.")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(os.Args[1:])
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	command, args := flags.Arg(0), flags.Args()[1:]
+	if command == "version" {
+		fmt.Println(version.Version)
+		return
+	}
+
+	switch *output {
+	case "", outputWide, outputJSON:
+	default:
+		fail(fmt.Errorf("unsupported output %q, must be %q or %q", *output, outputWide, outputJSON))
+	}
+
+	c, err := client.New(client.Config{BaseURL: *server, APIKey: *apiKey, Namespace: *namespace})
+	if err != nil {
+		fail(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	terminal := isTerminal(os.Stdout)
+	p := newPrinter(os.Stdout, *output, terminal && !*noColor, terminal)
+	switch command {
+	case "get", "list":
+		err = runGet(ctx, c, p, args)
+	case "create":
+		err = runCreate(ctx, c, p, args)
+	case "refresh":
+		err = runRefresh(ctx, c, p, args)
+	case "retry":
+		err = runRetry(ctx, c, p, args)
+	case "cancel":
+		err = runCancel(ctx, c, p, args)
+	case "delete":
+		err = runDelete(ctx, c, p, args)
+	case "export":
+		err = runExport(ctx, c, args)
+	default:
+		flags.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fail(err)
+	}
+}
+
+// runGet lists models, or prints a single model when a name is given
+func runGet(ctx context.Context, c *client.Client, p *printer, args []string) error {
+	switch len(args) {
+	case 0:
+		models, err := c.List(ctx)
+		if err != nil {
+			return err
+		}
+		return p.models(models)
+	case 1:
+		model, err := c.Get(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		return p.model(*model)
+	default:
+		return errors.New("usage: ollamactl get [NAME]")
+	}
+}
+
+// runCreate creates a model and optionally follows its pull
+func runCreate(ctx context.Context, c *client.Client, p *printer, args []string) error {
+	flags := flag.NewFlagSet("create", flag.ExitOnError)
+	wait := flags.Bool("wait", false, "Wait for the pull to finish, showing its progress.")
+	_ = flags.Parse(args)
+	if flags.NArg() != 2 {
+		return errors.New("usage: ollamactl create NAME TAG [-wait]")
+	}
+
+	model, err := c.Create(ctx, flags.Arg(0), flags.Arg(1))
+	if err != nil {
+		return err
+	}
+	if *wait {
+		return follow(ctx, c, p, model.OperationID)
+	}
+	return p.model(*model)
+}
+
+// runRefresh refreshes a model and optionally follows its pull
+func runRefresh(ctx context.Context, c *client.Client, p *printer, args []string) error {
+	flags := flag.NewFlagSet("refresh", flag.ExitOnError)
+	wait := flags.Bool("wait", false, "Wait for the pull to finish, showing its progress.")
+	_ = flags.Parse(args)
+	if flags.NArg() != 1 {
+		return errors.New("usage: ollamactl refresh NAME [-wait]")
+	}
+
+	model, err := c.Refresh(ctx, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	if *wait {
+		return follow(ctx, c, p, model.OperationID)
+	}
+	return p.model(*model)
+}
+
+// runRetry retries the pull of a Failed model
+func runRetry(ctx context.Context, c *client.Client, p *printer, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: ollamactl retry NAME")
+	}
+	model, err := c.Retry(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	return p.model(*model)
+}
+
+// runCancel cancels the running pull of a model
+func runCancel(ctx context.Context, c *client.Client, p *printer, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: ollamactl cancel NAME")
+	}
+	model, err := c.CancelPull(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	return p.model(*model)
+}
+
+// runDelete deletes a model
+func runDelete(ctx context.Context, c *client.Client, p *printer, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: ollamactl delete NAME")
+	}
+	if err := c.Delete(ctx, args[0]); err != nil {
+		return err
+	}
+	return p.deleted(args[0])
+}
+
+// runExport prints the models as YAML manifests that can be committed to Git
+func runExport(ctx context.Context, c *client.Client, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	allNamespaces := flags.Bool("A", false, "Export the models of all namespaces.")
+	_ = flags.Parse(args)
+	if flags.NArg() != 0 {
+		return errors.New("usage: ollamactl export [-A]")
+	}
+
+	manifests, err := c.Export(ctx, *allNamespaces)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(manifests)
+	return err
+}
+
+// follow renders the progress of an operation until it finishes and prints the resulting model
+func follow(ctx context.Context, c *client.Client, p *printer, operationID string) error {
+	op, err := c.WatchProgress(ctx, operationID, p.progress)
+	p.endProgress()
+	if op != nil && op.Result != nil {
+		if printErr := p.model(*op.Result); printErr != nil {
+			return printErr
+		}
+	}
+	return err
+}
+
+// envOr returns the value of an environment variable, or fallback when it is unset
+func envOr(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return fallback
+}
+
+// isTerminal reports whether f is an interactive terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// fail prints err and exits
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "error:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/dmk/ollama-operator/pkg/client"
+)
+
+// Output formats
+const (
+	outputWide = "wide"
+	outputJSON = "json"
+)
+
+const (
+	// progressBarWidth is the number of cells in a progress bar
+	progressBarWidth = 30
+
+	colorReset  = "\033[0m"
+	colorRed    = "\033[31m"
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+	colorCyan   = "\033[36m"
+)
+
+// printer renders models and pull progress in the selected output format
+type printer struct {
+	out      io.Writer
+	format   string
+	color    bool
+	terminal bool
+
+	// inProgress is set while a progress line is drawn on a terminal
+	inProgress bool
+}
+
+// newPrinter creates a printer. Progress lines are only redrawn in place on terminals.
+func newPrinter(out io.Writer, format string, color, terminal bool) *printer {
+	return &printer{out: out, format: format, color: color, terminal: terminal}
+}
+
+// models prints a list of models
+func (p *printer) models(models []client.Model) error {
+	if p.format == outputJSON {
+		return p.json(map[string]interface{}{"items": models})
+	}
+
+	w := tabwriter.NewWriter(p.out, 0, 0, 3, ' ', 0)
+	if p.format == outputWide {
+		fmt.Fprintln(w, "NAMESPACE\tNAME\tMODEL\tSTATE\tSIZE\tLAST PULL\tCREATED BY\tERROR")
+	} else {
+		fmt.Fprintln(w, "NAME\tMODEL\tSTATE\tSIZE")
+	}
+	for _, model := range models {
+		reference := model.ModelName + ":" + model.Tag
+		if p.format == outputWide {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", model.Namespace, model.Name, reference,
+				p.state(model.State), orNone(model.FormattedSize), orNone(model.LastPullTime), orNone(model.CreatedBy), model.Error)
+		} else {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", model.Name, reference, p.state(model.State), orNone(model.FormattedSize))
+		}
+	}
+	return w.Flush()
+}
+
+// model prints a single model
+func (p *printer) model(model client.Model) error {
+	if p.format == outputJSON {
+		return p.json(model)
+	}
+	return p.models([]client.Model{model})
+}
+
+// deleted reports a deleted model
+func (p *printer) deleted(name string) error {
+	if p.format == outputJSON {
+		return p.json(map[string]string{"name": name, "status": "deleted"})
+	}
+	_, err := fmt.Fprintf(p.out, "model %s deleted\n", name)
+	return err
+}
+
+// progress renders the progress of an operation. On terminals a single line
+// with a progress bar is redrawn; otherwise a line is printed per update.
+func (p *printer) progress(op *client.Operation) {
+	if p.format == outputJSON {
+		_ = p.json(op)
+		return
+	}
+
+	line := fmt.Sprintf("%s %s", op.Name, p.operationState(op.State))
+	if op.Total > 0 {
+		line += " " + progressBar(op.Completed, op.Total)
+	}
+	if op.Progress != "" {
+		line += " " + pullStatus(op.Progress)
+	}
+	if op.Estimate != "" {
+		line += " (" + op.Estimate + ")"
+	}
+
+	if !p.terminal {
+		fmt.Fprintln(p.out, line)
+		return
+	}
+	// Clear the rest of the line, as the previous status may have been longer
+	fmt.Fprintf(p.out, "\r%s\033[K", line)
+	p.inProgress = true
+}
+
+// endProgress finishes a progress line drawn on a terminal
+func (p *printer) endProgress() {
+	if p.inProgress {
+		fmt.Fprintln(p.out)
+		p.inProgress = false
+	}
+}
+
+// state colorizes a model state
+func (p *printer) state(state string) string {
+	switch state {
+	case client.StateReady:
+		return p.colorize(colorGreen, state)
+	case client.StateFailed:
+		return p.colorize(colorRed, state)
+	case client.StatePulling, client.StateDeleting:
+		return p.colorize(colorYellow, state)
+	case "":
+		return p.colorize(colorCyan, "Pending")
+	}
+	return state
+}
+
+// operationState colorizes an operation state
+func (p *printer) operationState(state string) string {
+	switch state {
+	case client.OperationSucceeded:
+		return p.colorize(colorGreen, state)
+	case client.OperationFailed:
+		return p.colorize(colorRed, state)
+	case client.OperationRunning:
+		return p.colorize(colorYellow, state)
+	}
+	return p.colorize(colorCyan, state)
+}
+
+// colorize wraps text in an ANSI color when colors are enabled
+func (p *printer) colorize(color, text string) string {
+	if !p.color {
+		return text
+	}
+	return color + text + colorReset
+}
+
+// json prints v as indented JSON
+func (p *printer) json(v interface{}) error {
+	encoder := json.NewEncoder(p.out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// progressBar renders completed out of total bytes as a bar with a percentage
+func progressBar(completed, total int64) string {
+	completed = min(completed, total)
+	filled := int(completed * progressBarWidth / total)
+	bar := strings.Repeat("=", filled)
+	if filled < progressBarWidth {
+		bar += ">" + strings.Repeat(" ", progressBarWidth-filled-1)
+	}
+	return fmt.Sprintf("[%s] %3d%% %s/%s", bar, completed*100/total, formatBytes(completed), formatBytes(total))
+}
+
+// pullStatus strips the timestamp from a pull log line
+func pullStatus(line string) string {
+	if _, status, ok := strings.Cut(line, " "); ok {
+		return status
+	}
+	return line
+}
+
+// formatBytes formats a byte count in binary units
+func formatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+// orNone returns "<none>" for empty values
+func orNone(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+	return value
+}
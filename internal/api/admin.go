@@ -0,0 +1,192 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	ollamaapi "github.com/ollama/ollama/api"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+	"github.com/dmk/ollama-operator/internal/prune"
+)
+
+// PruneFailure describes a model that could not be deleted
+type PruneFailure struct {
+	Name  string `json:"name"`
+	Error string `json:"error"`
+}
+
+// PruneResponse represents the API response for the prune endpoint
+type PruneResponse struct {
+	DryRun    bool           `json:"dryRun"`
+	Unmanaged []string       `json:"unmanaged"`
+	Deleted   []string       `json:"deleted"`
+	Failed    []PruneFailure `json:"failed,omitempty"`
+}
+
+// PullQueueResponse represents the API response for the pull queue endpoint
+type PullQueueResponse struct {
+	Items []QueuedPullResponse `json:"items"`
+}
+
+// QueuedPullResponse represents a pull waiting for a free pull slot
+type QueuedPullResponse struct {
+	Position     int     `json:"position"`
+	Kind         string  `json:"kind"`
+	Namespace    string  `json:"namespace"`
+	Name         string  `json:"name"`
+	Model        string  `json:"model"`
+	WaitingSince string  `json:"waitingSince"`
+	WaitSeconds  float64 `json:"waitSeconds"`
+}
+
+// UnmanagedResponse represents the API response for the unmanaged models endpoint
+type UnmanagedResponse struct {
+	Unmanaged []string  `json:"unmanaged"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+// pruneModels handles the POST /api/v1/admin/prune endpoint. It deletes models
+// stored on the Ollama server that no OllamaModel in any namespace references.
+// The prune mode of the operator configuration may force a dry run or reject
+// the request.
+func (s *Server) pruneModels(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := log.FromContext(ctx).WithName("api-pruneModels")
+
+	dryRun := false
+	if value := r.URL.Query().Get("dryRun"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			sendError(w, fmt.Errorf("invalid dryRun: %w", err), http.StatusBadRequest)
+			return
+		}
+		dryRun = parsed
+	}
+
+	switch s.config.Settings.Get().PruneMode {
+	case ollamav1alpha1.PruneDisabled:
+		sendError(w, errors.New("pruning is disabled by the operator configuration"), http.StatusForbidden)
+		return
+	case ollamav1alpha1.PruneDryRun:
+		dryRun = true
+	}
+
+	unmanaged, ok := s.unmanagedModels(w, r)
+	if !ok {
+		return
+	}
+	response := PruneResponse{
+		DryRun:    dryRun,
+		Unmanaged: unmanaged,
+		Deleted:   []string{},
+	}
+
+	if !dryRun {
+		for _, name := range response.Unmanaged {
+			if err := s.ollama.Delete(ctx, &ollamaapi.DeleteRequest{Model: name}); err != nil {
+				logger.Error(err, "failed to delete unmanaged model", "model", name)
+				response.Failed = append(response.Failed, PruneFailure{Name: name, Error: err.Error()})
+				continue
+			}
+			logger.Info("deleted unmanaged model", "model", name)
+			response.Deleted = append(response.Deleted, name)
+		}
+	}
+	setAuditTarget(ctx, "", "", strings.Join(response.Deleted, ","))
+
+	status := http.StatusOK
+	if len(response.Failed) > 0 {
+		status = http.StatusBadGateway
+	}
+	sendResponse(w, r, response, status)
+}
+
+// listUnmanaged handles the GET /api/v1/admin/unmanaged endpoint. It reports
+// the models a prune would delete without deleting them, whatever the prune
+// mode.
+func (s *Server) listUnmanaged(w http.ResponseWriter, r *http.Request) {
+	unmanaged, ok := s.unmanagedModels(w, r)
+	if !ok {
+		return
+	}
+	sendResponse(w, r, UnmanagedResponse{Unmanaged: unmanaged, CheckedAt: time.Now().UTC()}, http.StatusOK)
+}
+
+// unmanagedModels returns the models stored on the Ollama server that no
+// OllamaModel or OllamaModelSnapshot in any namespace references. On failure,
+// the error has been sent and false is returned.
+func (s *Server) unmanagedModels(w http.ResponseWriter, r *http.Request) ([]string, bool) {
+	ctx := r.Context()
+	logger := log.FromContext(ctx).WithName("api-unmanagedModels")
+
+	if s.ollama == nil {
+		sendError(w, errors.New("ollama client is not configured"), http.StatusServiceUnavailable)
+		return nil, false
+	}
+
+	stored, err := s.ollama.List(ctx)
+	if err != nil {
+		logger.Error(err, "failed to list Ollama models")
+		sendError(w, err, http.StatusBadGateway)
+		return nil, false
+	}
+
+	// The Ollama server is shared by every namespace, so all OllamaModels count
+	var modelList ollamav1alpha1.OllamaModelList
+	if err := s.reader().List(ctx, &modelList); err != nil {
+		logger.Error(err, "failed to list models")
+		sendError(w, err, http.StatusInternalServerError)
+		return nil, false
+	}
+	var snapshotList ollamav1alpha1.OllamaModelSnapshotList
+	if err := s.reader().List(ctx, &snapshotList); err != nil {
+		logger.Error(err, "failed to list model snapshots")
+		sendError(w, err, http.StatusInternalServerError)
+		return nil, false
+	}
+
+	unmanaged := prune.Unmanaged(stored.Models, modelList.Items, snapshotList.Items)
+	if unmanaged == nil {
+		unmanaged = []string{}
+	}
+	return unmanaged, true
+}
+
+// getPullQueue handles the GET /api/v1/admin/pull-queue endpoint. It lists the
+// pulls waiting for a free pull slot, in the order they started waiting. The
+// queue is held by the leader, so other replicas answer 503.
+func (s *Server) getPullQueue(w http.ResponseWriter, r *http.Request) {
+	if s.config.PullQueue == nil {
+		sendError(w, errors.New("the pull queue is not available"), http.StatusServiceUnavailable)
+		return
+	}
+	if s.config.Elected != nil {
+		select {
+		case <-s.config.Elected:
+		default:
+			sendError(w, errors.New("the pull queue is held by the leader replica"), http.StatusServiceUnavailable)
+			return
+		}
+	}
+
+	now := time.Now()
+	response := PullQueueResponse{Items: []QueuedPullResponse{}}
+	for i, pull := range s.config.PullQueue.Queue() {
+		response.Items = append(response.Items, QueuedPullResponse{
+			Position:     i + 1,
+			Kind:         pull.Kind,
+			Namespace:    pull.Namespace,
+			Name:         pull.Name,
+			Model:        pull.Model,
+			WaitingSince: pull.Since.UTC().Format(time.RFC3339),
+			WaitSeconds:  now.Sub(pull.Since).Seconds(),
+		})
+	}
+	sendResponse(w, r, response, http.StatusOK)
+}
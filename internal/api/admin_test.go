@@ -0,0 +1,218 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ollamaapi "github.com/ollama/ollama/api"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+	"github.com/dmk/ollama-operator/internal/controller"
+	"github.com/dmk/ollama-operator/internal/opconfig"
+)
+
+// fakePullQueue is a pull queue holding a fixed list of pulls
+type fakePullQueue []controller.QueuedPull
+
+func (q fakePullQueue) Queue() []controller.QueuedPull {
+	return q
+}
+
+func (q fakePullQueue) Active() int {
+	return 0
+}
+
+var _ = Describe("Prune", func() {
+	var (
+		server *Server
+		ollama *fakeOllama
+	)
+
+	prune := func(path, key string) (*httptest.ResponseRecorder, PruneResponse) {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req.Header.Set("X-API-Key", key)
+		rec := httptest.NewRecorder()
+		server.router.ServeHTTP(rec, req)
+
+		var resp PruneResponse
+		if rec.Code != http.StatusForbidden {
+			Expect(json.Unmarshal(rec.Body.Bytes(), &resp)).To(Succeed())
+		}
+		return rec, resp
+	}
+
+	BeforeEach(func() {
+		ollama = &fakeOllama{models: []ollamaapi.ListModelResponse{
+			{Name: "llama3.2:1b"},
+			{Name: "gemma3:1b"},
+			{Name: "phi3:latest"},
+			{Name: "scratch:latest"},
+			{Name: "llama3.2:exp-1"},
+		}}
+
+		keyring := NewKeyring("", true)
+		Expect(keyring.LoadSecret(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "api-keys",
+				Annotations: map[string]string{APIKeyRolesAnnotation: "ci=admin"},
+			},
+			Data: map[string][]byte{"ci": []byte("ci-key"), "dashboard": []byte("dash-key")},
+		})).To(Succeed())
+
+		server = NewServer(Config{Namespace: "default", Keyring: keyring}, newFakeClient(
+			&ollamav1alpha1.OllamaModel{
+				ObjectMeta: metav1.ObjectMeta{Name: "llama3.2-1b", Namespace: "default"},
+				Spec:       ollamav1alpha1.OllamaModelSpec{Name: "llama3.2", Tag: "1b"},
+			},
+			&ollamav1alpha1.OllamaModel{
+				ObjectMeta: metav1.ObjectMeta{Name: "phi3-latest", Namespace: "team-a"},
+				Spec:       ollamav1alpha1.OllamaModelSpec{Name: "phi3", Tag: "latest"},
+			},
+			&ollamav1alpha1.OllamaModelSnapshot{
+				ObjectMeta: metav1.ObjectMeta{Name: "exp-1", Namespace: "default"},
+				Spec:       ollamav1alpha1.OllamaModelSnapshotSpec{Model: "llama3.2-1b", FrozenTag: "exp-1"},
+				Status:     ollamav1alpha1.OllamaModelSnapshotStatus{FrozenModel: "llama3.2:exp-1"},
+			},
+		), ollama, nil)
+	})
+
+	It("reports unmanaged models on a dry run without deleting them", func() {
+		rec, resp := prune("/api/v1/admin/prune?dryRun=true", "ci-key")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(resp.DryRun).To(BeTrue())
+		Expect(resp.Unmanaged).To(Equal([]string{"gemma3:1b", "scratch:latest"}))
+		Expect(resp.Deleted).To(BeEmpty())
+		Expect(ollama.deleted).To(BeEmpty())
+	})
+
+	It("deletes models not managed in any namespace", func() {
+		rec, resp := prune("/api/v1/admin/prune", "ci-key")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(resp.Deleted).To(Equal([]string{"gemma3:1b", "scratch:latest"}))
+		Expect(ollama.deleted).To(Equal([]string{"gemma3:1b", "scratch:latest"}))
+	})
+
+	It("matches user-namespaced and qualified models with the names Ollama lists", func() {
+		ollama.models = []ollamaapi.ListModelResponse{
+			{Name: "username/custom-model:v2"},
+			{Name: "nous-hermes2:latest"},
+			{Name: "Registry.Example.com/team/model:1b"},
+			{Name: "username/other-model:latest"},
+		}
+		server = NewServer(Config{Namespace: "default", APIKey: "ci-key"}, newFakeClient(
+			&ollamav1alpha1.OllamaModel{
+				ObjectMeta: metav1.ObjectMeta{Name: "username-custom-model-v2", Namespace: "default"},
+				Spec:       ollamav1alpha1.OllamaModelSpec{Name: "registry.ollama.ai/username/custom-model", Tag: "v2"},
+			},
+			&ollamav1alpha1.OllamaModel{
+				ObjectMeta: metav1.ObjectMeta{Name: "nous-hermes2-latest", Namespace: "default"},
+				Spec:       ollamav1alpha1.OllamaModelSpec{Name: "library/nous-hermes2", Tag: "latest"},
+			},
+			&ollamav1alpha1.OllamaModel{
+				ObjectMeta: metav1.ObjectMeta{Name: "registry.example.com-team-model-1b", Namespace: "default"},
+				Spec:       ollamav1alpha1.OllamaModelSpec{Name: "registry.example.com/team/model", Tag: "1b"},
+			},
+		), ollama, nil)
+
+		rec, resp := prune("/api/v1/admin/prune", "ci-key")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(resp.Deleted).To(Equal([]string{"username/other-model:latest"}))
+	})
+
+	It("follows the prune mode of the operator configuration", func() {
+		server.config.Settings = opconfig.NewStore(opconfig.Settings{PruneMode: ollamav1alpha1.PruneDryRun})
+		rec, resp := prune("/api/v1/admin/prune", "ci-key")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(resp.DryRun).To(BeTrue())
+		Expect(ollama.deleted).To(BeEmpty())
+
+		server.config.Settings.Apply(&ollamav1alpha1.OllamaOperatorConfigSpec{PruneMode: ollamav1alpha1.PruneDisabled})
+		rec, _ = prune("/api/v1/admin/prune", "ci-key")
+		Expect(rec.Code).To(Equal(http.StatusForbidden))
+		Expect(ollama.deleted).To(BeEmpty())
+	})
+
+	It("lists unmanaged models without deleting them whatever the prune mode", func() {
+		server.config.Settings = opconfig.NewStore(opconfig.Settings{PruneMode: ollamav1alpha1.PruneDisabled})
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/unmanaged", nil)
+		req.Header.Set("X-API-Key", "dash-key")
+		rec := httptest.NewRecorder()
+		server.router.ServeHTTP(rec, req)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+
+		var report UnmanagedResponse
+		Expect(json.Unmarshal(rec.Body.Bytes(), &report)).To(Succeed())
+		Expect(report.Unmanaged).To(Equal([]string{"gemma3:1b", "scratch:latest"}))
+		Expect(report.CheckedAt).NotTo(BeZero())
+		Expect(ollama.deleted).To(BeEmpty())
+	})
+
+	It("counts the models of every namespace when the client is scoped", func() {
+		// The reader sees gemma3 in a namespace the scoped client leaves out
+		server.config.Reader = newFakeClient(
+			&ollamav1alpha1.OllamaModel{
+				ObjectMeta: metav1.ObjectMeta{Name: "llama3.2-1b", Namespace: "default"},
+				Spec:       ollamav1alpha1.OllamaModelSpec{Name: "llama3.2", Tag: "1b"},
+			},
+			&ollamav1alpha1.OllamaModel{
+				ObjectMeta: metav1.ObjectMeta{Name: "gemma3-1b", Namespace: "unwatched"},
+				Spec:       ollamav1alpha1.OllamaModelSpec{Name: "gemma3", Tag: "1b"},
+			},
+		)
+
+		rec, resp := prune("/api/v1/admin/prune", "ci-key")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(resp.Deleted).To(Equal([]string{"llama3.2:exp-1", "phi3:latest", "scratch:latest"}))
+		Expect(ollama.deleted).NotTo(ContainElement("gemma3:1b"))
+	})
+
+	It("is only available to admin keys", func() {
+		rec, _ := prune("/api/v1/admin/prune", "dash-key")
+		Expect(rec.Code).To(Equal(http.StatusForbidden))
+		Expect(ollama.deleted).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Pull queue", func() {
+	get := func(server *Server) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		server.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/admin/pull-queue", nil))
+		return rec
+	}
+
+	It("lists the pulls waiting for a slot in order", func() {
+		since := time.Now().Add(-time.Minute)
+		queue := fakePullQueue{
+			{Kind: "OllamaModel", Namespace: "default", Name: "llama3.2-1b", Model: "llama3.2:1b", Since: since},
+			{Kind: "OllamaPullJob", Namespace: "team-a", Name: "warmup", Model: "gemma3:1b", Since: since.Add(time.Second)},
+		}
+		rec := get(NewServer(Config{Namespace: "default", PullQueue: queue}, newFakeClient(), nil, nil))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+
+		var resp PullQueueResponse
+		Expect(json.Unmarshal(rec.Body.Bytes(), &resp)).To(Succeed())
+		Expect(resp.Items).To(HaveLen(2))
+		Expect(resp.Items[0].Position).To(Equal(1))
+		Expect(resp.Items[0].Model).To(Equal("llama3.2:1b"))
+		Expect(resp.Items[0].WaitSeconds).To(BeNumerically(">=", 60))
+		Expect(resp.Items[1].Position).To(Equal(2))
+		Expect(resp.Items[1].Kind).To(Equal("OllamaPullJob"))
+	})
+
+	It("is only served by the leader", func() {
+		elected := make(chan struct{})
+		server := NewServer(Config{Namespace: "default", PullQueue: fakePullQueue{}, Elected: elected}, newFakeClient(), nil, nil)
+		Expect(get(server).Code).To(Equal(http.StatusServiceUnavailable))
+
+		close(elected)
+		Expect(get(server).Code).To(Equal(http.StatusOK))
+	})
+})
@@ -0,0 +1,142 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+var sourceDenied = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "ollama_api_source_denied_total",
+		Help: "Total number of API requests refused because their client address is not in the allowed networks",
+	},
+	[]string{"api"},
+)
+
+// errSourceNotAllowed is returned to clients outside of the allowed networks
+var errSourceNotAllowed = errors.New("the client address is not allowed to use the API")
+
+// ParsePrefixes parses network prefixes in CIDR notation, such as 10.0.0.0/8.
+// A bare IP address is a network of its own.
+func ParsePrefixes(values []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if !strings.Contains(value, "/") {
+			addr, err := netip.ParseAddr(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid network %q: %w", value, err)
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid network %q: %w", value, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// containsAddr reports whether one of prefixes contains the address ip
+func containsAddr(prefixes []netip.Prefix, ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP returns the IP address of a client connected from remote, a host
+// and port. Behind trustedProxies, the client is the last address of the
+// X-Forwarded-For values that is not a trusted proxy itself; the addresses
+// before it are set by the client and may be forged.
+func ClientIP(trustedProxies []netip.Prefix, remote string, forwarded []string) string {
+	ip := remote
+	if host, _, err := net.SplitHostPort(remote); err == nil {
+		ip = host
+	}
+	if !containsAddr(trustedProxies, ip) {
+		return ip
+	}
+
+	var hops []string
+	for _, value := range forwarded {
+		for _, hop := range strings.Split(value, ",") {
+			if hop = strings.TrimSpace(hop); hop != "" {
+				hops = append(hops, hop)
+			}
+		}
+	}
+	for i := len(hops) - 1; i >= 0; i-- {
+		ip = hops[i]
+		if !containsAddr(trustedProxies, ip) {
+			break
+		}
+	}
+	return ip
+}
+
+// SourceAllowed reports whether a client at ip may connect, given the
+// networks allowed to; no network allows every client
+func SourceAllowed(allowedNetworks []netip.Prefix, ip string) bool {
+	return len(allowedNetworks) == 0 || containsAddr(allowedNetworks, ip)
+}
+
+// clientIP returns the IP address of a client of the API servers connected
+// from remote
+func (c Config) clientIP(remote string, forwarded []string) string {
+	return ClientIP(c.TrustedProxies, remote, forwarded)
+}
+
+// sourceAllowed reports whether a client at ip may use the API
+func (c Config) sourceAllowed(ip string) bool {
+	return SourceAllowed(c.AllowedNetworks, ip)
+}
+
+// sourceIP returns the IP address of the client that sent r
+func (s *Server) sourceIP(r *http.Request) string {
+	return s.config.clientIP(r.RemoteAddr, r.Header.Values("X-Forwarded-For"))
+}
+
+// allowlistMiddleware refuses the requests from clients outside of the
+// allowed networks with 403 Forbidden. The probe endpoints stay open to the
+// kubelet.
+func (s *Server) allowlistMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" || r.URL.Path == "/readiness" || s.config.sourceAllowed(s.sourceIP(r)) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		sourceDenied.WithLabelValues(apiHTTP).Inc()
+		sendError(w, errSourceNotAllowed, http.StatusForbidden)
+	})
+}
+
+// sourceIP returns the IP address of the client of a call, from the
+// x-forwarded-for metadata behind trusted proxies
+func (s *GRPCServer) sourceIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	return s.config.clientIP(p.Addr.String(), md.Get("x-forwarded-for"))
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Source allowlist", func() {
+	config := func(allowed, trusted []string) Config {
+		allowedNetworks, err := ParsePrefixes(allowed)
+		Expect(err).NotTo(HaveOccurred())
+		trustedProxies, err := ParsePrefixes(trusted)
+		Expect(err).NotTo(HaveOccurred())
+		return Config{Namespace: "default", AllowedNetworks: allowedNetworks, TrustedProxies: trustedProxies}
+	}
+
+	get := func(server *Server, path, remote, forwarded string) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = remote
+		if forwarded != "" {
+			req.Header.Set("X-Forwarded-For", forwarded)
+		}
+		rec := httptest.NewRecorder()
+		server.router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	It("parses networks and bare addresses", func() {
+		prefixes, err := ParsePrefixes([]string{"10.1.2.3/8", "192.168.1.10", "fd00::/8"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(prefixes).To(HaveLen(3))
+		Expect(prefixes[0].String()).To(Equal("10.0.0.0/8"))
+		Expect(prefixes[1].String()).To(Equal("192.168.1.10/32"))
+
+		_, err = ParsePrefixes([]string{"10.0.0.0/33"})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("only serves clients from the allowed networks", func() {
+		server := NewServer(config([]string{"10.0.0.0/8"}, nil), newFakeClient(), nil, nil)
+		Expect(get(server, "/api/v1/models", "10.2.3.4:51234", "")).To(Equal(http.StatusOK))
+		Expect(get(server, "/api/v1/models", "203.0.113.7:51234", "")).To(Equal(http.StatusForbidden))
+		Expect(get(server, "/health", "203.0.113.7:51234", "")).To(Equal(http.StatusOK))
+	})
+
+	It("ignores X-Forwarded-For from untrusted clients", func() {
+		server := NewServer(config([]string{"10.0.0.0/8"}, nil), newFakeClient(), nil, nil)
+		Expect(get(server, "/api/v1/models", "203.0.113.7:51234", "10.2.3.4")).To(Equal(http.StatusForbidden))
+	})
+
+	It("takes the client address from X-Forwarded-For behind trusted proxies", func() {
+		server := NewServer(config([]string{"10.0.0.0/8"}, []string{"172.16.0.0/12"}), newFakeClient(), nil, nil)
+		Expect(get(server, "/api/v1/models", "172.16.0.5:443", "10.2.3.4")).To(Equal(http.StatusOK))
+		Expect(get(server, "/api/v1/models", "172.16.0.5:443", "203.0.113.7, 172.16.0.9")).To(Equal(http.StatusForbidden))
+		// Addresses before the first untrusted hop may be forged by the client
+		Expect(get(server, "/api/v1/models", "172.16.0.5:443", "10.2.3.4, 203.0.113.7")).To(Equal(http.StatusForbidden))
+	})
+
+	It("resolves the client address of proxied requests", func() {
+		c := config(nil, []string{"172.16.0.0/12"})
+		Expect(c.clientIP("172.16.0.5:443", []string{"203.0.113.7, 172.16.0.9"})).To(Equal("203.0.113.7"))
+		Expect(c.clientIP("172.16.0.5:443", nil)).To(Equal("172.16.0.5"))
+		Expect(c.clientIP("203.0.113.7:51234", []string{"10.2.3.4"})).To(Equal("203.0.113.7"))
+	})
+})
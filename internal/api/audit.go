@@ -0,0 +1,48 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/dmk/ollama-operator/internal/audit"
+)
+
+// audited wraps a mutating handler so that its outcome is recorded in the audit log.
+// The handler identifies the affected model with setAuditTarget.
+func (s *Server) audited(action string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		event := &audit.Event{
+			Source:    audit.SourceAPI,
+			Action:    action,
+			Principal: principalFromContext(r.Context()),
+			SourceIP:  s.sourceIP(r),
+			RequestID: RequestIDFromContext(r.Context()),
+			Namespace: s.namespaceFor(r),
+			Name:      mux.Vars(r)["name"],
+		}
+		if info, ok := r.Context().Value(requestInfoKey{}).(*requestInfo); ok {
+			info.audit = event
+		}
+
+		rw := &responseWriter{w, http.StatusOK}
+		next(rw, r)
+
+		event.Outcome = audit.OutcomeSuccess
+		if rw.statusCode >= http.StatusBadRequest {
+			event.Outcome = audit.OutcomeFailure
+			event.Error = http.StatusText(rw.statusCode)
+		}
+		s.audit.Record(r.Context(), *event)
+	}
+}
+
+// setAuditTarget records the model affected by the current request for auditing
+func setAuditTarget(ctx context.Context, namespace, name, model string) {
+	if info, ok := ctx.Value(requestInfoKey{}).(*requestInfo); ok && info.audit != nil {
+		info.audit.Namespace = namespace
+		info.audit.Name = name
+		info.audit.Model = model
+	}
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+	"github.com/dmk/ollama-operator/internal/audit"
+)
+
+// recordingSink keeps audit events in memory
+type recordingSink struct {
+	mu     sync.Mutex
+	events []audit.Event
+}
+
+func (s *recordingSink) Write(_ context.Context, event audit.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+	return nil
+}
+
+var _ = Describe("Audit logging", func() {
+	var (
+		server *Server
+		sink   *recordingSink
+	)
+
+	do := func(method, path, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("X-API-Key", key)
+		rec := httptest.NewRecorder()
+		server.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	BeforeEach(func() {
+		sink = &recordingSink{}
+		keyring := NewKeyring("", true)
+		Expect(keyring.LoadSecret(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "api-keys",
+				Annotations: map[string]string{APIKeyRolesAnnotation: "ci=admin"},
+			},
+			Data: map[string][]byte{"ci": []byte("ci-key")},
+		})).To(Succeed())
+
+		server = NewServer(Config{
+			Namespace: "default",
+			Keyring:   keyring,
+			Auditor:   audit.NewLogger(sink),
+		}, newFakeClient(&ollamav1alpha1.OllamaModel{
+			ObjectMeta: metav1.ObjectMeta{Name: "llama3.2-1b", Namespace: "default"},
+			Spec:       ollamav1alpha1.OllamaModelSpec{Name: "llama3.2", Tag: "1b"},
+		}), nil, nil)
+	})
+
+	It("records who created a model", func() {
+		rec := do(http.MethodPost, "/api/v1/namespaces/team-a/models", "ci-key", `{"name":"phi3","tag":"mini"}`)
+		Expect(rec.Code).To(Equal(http.StatusCreated))
+
+		Expect(sink.events).To(HaveLen(1))
+		event := sink.events[0]
+		Expect(event.Source).To(Equal(audit.SourceAPI))
+		Expect(event.Action).To(Equal(audit.ActionCreate))
+		Expect(event.Principal).To(Equal("apikey:ci"))
+		Expect(event.Namespace).To(Equal("team-a"))
+		Expect(event.Name).To(Equal("phi3-mini"))
+		Expect(event.Model).To(Equal("phi3:mini"))
+		Expect(event.Outcome).To(Equal(audit.OutcomeSuccess))
+		Expect(event.RequestID).To(Equal(rec.Header().Get(requestIDHeader)))
+	})
+
+	It("records failed deletes", func() {
+		rec := do(http.MethodDelete, "/api/v1/models/missing", "ci-key", "")
+		Expect(rec.Code).To(Equal(http.StatusNotFound))
+
+		Expect(sink.events).To(HaveLen(1))
+		Expect(sink.events[0].Action).To(Equal(audit.ActionDelete))
+		Expect(sink.events[0].Name).To(Equal("missing"))
+		Expect(sink.events[0].Outcome).To(Equal(audit.OutcomeFailure))
+	})
+
+	It("does not audit reads", func() {
+		Expect(do(http.MethodGet, "/api/v1/models", "ci-key", "").Code).To(Equal(http.StatusOK))
+		Expect(sink.events).To(BeEmpty())
+	})
+})
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// APIs whose authorization decisions are counted
+const (
+	apiHTTP = "http"
+	apiGRPC = "grpc"
+)
+
+// Reasons API requests fail to authenticate
+const (
+	reasonMissingKey = "missing_key"
+	reasonInvalidKey = "invalid_key"
+	reasonLockedOut  = "locked_out"
+)
+
+// Reasons authenticated API requests are refused
+const (
+	reasonRole           = "role"
+	reasonReadOnlyServer = "read_only_server"
+	reasonNamespace      = "namespace"
+)
+
+var (
+	authenticationFailed = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ollama_api_authentication_failed_total",
+			Help: "Total number of API requests refused because they carry no valid API key, or come from a locked out source",
+		},
+		[]string{"api", "reason"},
+	)
+	authorizationDenied = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ollama_api_authorization_denied_total",
+			Help: "Total number of authenticated API requests refused, because the role or the namespaces of their key do not allow them or the API server is read-only",
+		},
+		[]string{"api", "key", "role", "reason"},
+	)
+	lockouts = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ollama_api_lockouts_total",
+			Help: "Total number of sources locked out after repeated failed authentications",
+		},
+		[]string{"api"},
+	)
+)
+
+// Errors of the requests refused by the authentication and authorization
+var (
+	errUnauthenticated = errors.New("a valid API key is required")
+	errLockedOut       = errors.New("too many failed authentications from this address, retry later")
+	errRoleForbidden   = errors.New("the role of the key does not allow this request")
+	// errNamespacesForbidden refuses keys restricted to some namespaces
+	// the endpoints spanning every namespace
+	errNamespacesForbidden = errors.New("the key is restricted to some namespaces and may not use this endpoint")
+)
+
+// allows reports whether the role may perform an operation. Read-only roles
+// may only list and get resources.
+func (r Role) allows(mutating bool) bool {
+	return r == RoleAdmin || !mutating
+}
+
+// authorize reports whether key may perform an operation over api, counting
+// the refusals
+func authorize(api string, key APIKey, mutating bool) bool {
+	if key.Role.allows(mutating) {
+		return true
+	}
+	authorizationDenied.WithLabelValues(api, key.Name, string(key.Role), reasonRole).Inc()
+	return false
+}
+
+// denyReadOnly counts a request refused because the API server is read-only
+func denyReadOnly(api, principal string, role Role) {
+	authorizationDenied.WithLabelValues(api, principalKey(principal), string(role), reasonReadOnlyServer).Inc()
+}
+
+// authorizeNamespace reports whether the principal of ctx may reach the
+// models of namespace, or of every namespace when it is empty, counting the
+// refusals
+func authorizeNamespace(ctx context.Context, api, namespace string) bool {
+	info, ok := ctx.Value(requestInfoKey{}).(*requestInfo)
+	if !ok || len(info.namespaces) == 0 {
+		return true
+	}
+	if namespace != "" && slices.Contains(info.namespaces, namespace) {
+		return true
+	}
+	authorizationDenied.WithLabelValues(api, principalKey(info.principal), string(info.role), reasonNamespace).Inc()
+	return false
+}
+
+// mutatingMethod reports whether an HTTP method may change resources
+func mutatingMethod(method string) bool {
+	return method != http.MethodGet && method != http.MethodHead
+}
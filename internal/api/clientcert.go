@@ -0,0 +1,120 @@
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"slices"
+	"sync/atomic"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// caKey is the key of the Secret of the API servers holding the CA bundle
+// that client certificates are verified against
+const caKey = "ca.crt"
+
+// certPrincipalPrefix prefixes the name of client certificate principals
+const certPrincipalPrefix = "cert:"
+
+// errTLSNotLoaded fails the TLS handshakes until a certificate is loaded
+var errTLSNotLoaded = errors.New("the API server certificate has not been loaded yet")
+
+// ServerTLS serves the API servers over TLS with the certificate of a Secret,
+// and verifies the certificates of their clients against the CA bundle of the
+// Secret. It may be reloaded while serving so that certificates can be
+// rotated, and is safe for concurrent use.
+type ServerTLS struct {
+	requireClientCert bool
+	current           atomic.Pointer[tls.Config]
+}
+
+// NewServerTLS returns a ServerTLS failing handshakes until LoadSecret is
+// called. With requireClientCert, connections without a client certificate
+// signed by the CA are refused; otherwise client certificates are optional,
+// and clients may authenticate with an API key instead.
+func NewServerTLS(requireClientCert bool) *ServerTLS {
+	return &ServerTLS{requireClientCert: requireClientCert}
+}
+
+// LoadSecret loads the certificate from the tls.crt and tls.key keys of
+// secret, and the CA verifying client certificates from its ca.crt key. Client
+// certificates are not accepted without ca.crt.
+func (t *ServerTLS) LoadSecret(secret *corev1.Secret) error {
+	cert, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return fmt.Errorf("invalid API server certificate: %w", err)
+	}
+	config := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	}
+
+	caPEM := secret.Data[caKey]
+	switch {
+	case len(caPEM) > 0:
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return fmt.Errorf("key %q contains no PEM certificates", caKey)
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+		if t.requireClientCert {
+			config.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	case t.requireClientCert:
+		return fmt.Errorf("key %q is required to verify client certificates", caKey)
+	}
+
+	t.current.Store(config)
+	return nil
+}
+
+// Config returns the TLS configuration of a server negotiating nextProtos.
+// Each handshake uses the last configuration loaded.
+func (t *ServerTLS) Config(nextProtos ...string) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			current := t.current.Load()
+			if current == nil {
+				return nil, errTLSNotLoaded
+			}
+			config := current.Clone()
+			config.NextProtos = nextProtos
+			return config, nil
+		},
+	}
+}
+
+// clientCertKey returns the identity of the verified client certificate of a
+// connection, as a key named after the certificate: its common name, or else
+// its first DNS, URI or email subject alternative name. Like Kubernetes
+// groups, the organizations of the certificate grant it its role: admin with
+// the admin organization, read-only otherwise.
+func clientCertKey(state *tls.ConnectionState) (APIKey, bool) {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return APIKey{}, false
+	}
+	cert := state.VerifiedChains[0][0]
+
+	name := cert.Subject.CommonName
+	switch {
+	case name != "":
+	case len(cert.DNSNames) > 0:
+		name = cert.DNSNames[0]
+	case len(cert.URIs) > 0:
+		name = cert.URIs[0].String()
+	case len(cert.EmailAddresses) > 0:
+		name = cert.EmailAddresses[0]
+	default:
+		return APIKey{}, false
+	}
+
+	role := RoleReadOnly
+	if slices.Contains(cert.Subject.Organization, string(RoleAdmin)) {
+		role = RoleAdmin
+	}
+	return APIKey{Name: certPrincipalPrefix + name, Role: role}, true
+}
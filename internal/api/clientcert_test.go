@@ -0,0 +1,167 @@
+package api
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// testCA issues certificates for the TLS tests
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA() *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).NotTo(HaveOccurred())
+	cert, err := x509.ParseCertificate(der)
+	Expect(err).NotTo(HaveOccurred())
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns the PEM certificate and key of a leaf certificate for subject
+func (ca *testCA) issue(subject pkix.Name, usage x509.ExtKeyUsage) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      subject,
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	Expect(err).NotTo(HaveOccurred())
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	Expect(err).NotTo(HaveOccurred())
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+var _ = Describe("Client certificates", func() {
+	var ca *testCA
+
+	BeforeEach(func() {
+		ca = newTestCA()
+	})
+
+	serverSecret := func(withCA bool) *corev1.Secret {
+		certPEM, keyPEM := ca.issue(pkix.Name{CommonName: "ollama-operator-api"}, x509.ExtKeyUsageServerAuth)
+		secret := &corev1.Secret{Data: map[string][]byte{
+			corev1.TLSCertKey:       certPEM,
+			corev1.TLSPrivateKeyKey: keyPEM,
+		}}
+		if withCA {
+			secret.Data[caKey] = ca.pem
+		}
+		return secret
+	}
+
+	serve := func(serverTLS *ServerTLS) *httptest.Server {
+		server := NewServer(Config{Namespace: "default", APIKey: "secret", TLS: serverTLS}, newFakeClient(), nil, nil)
+		ts := httptest.NewUnstartedServer(server.router)
+		ts.TLS = serverTLS.Config()
+		ts.StartTLS()
+		DeferCleanup(ts.Close)
+		return ts
+	}
+
+	client := func(subject *pkix.Name) *http.Client {
+		roots := x509.NewCertPool()
+		roots.AddCert(ca.cert)
+		config := &tls.Config{RootCAs: roots}
+		if subject != nil {
+			certPEM, keyPEM := ca.issue(*subject, x509.ExtKeyUsageClientAuth)
+			cert, err := tls.X509KeyPair(certPEM, keyPEM)
+			Expect(err).NotTo(HaveOccurred())
+			config.Certificates = []tls.Certificate{cert}
+		}
+		return &http.Client{Transport: &http.Transport{TLSClientConfig: config}}
+	}
+
+	do := func(c *http.Client, method, url string) (int, error) {
+		req, err := http.NewRequest(method, url+"/api/v1/models", strings.NewReader(`{"name":"phi3","tag":"mini"}`))
+		Expect(err).NotTo(HaveOccurred())
+		resp, err := c.Do(req)
+		if err != nil {
+			return 0, err
+		}
+		defer resp.Body.Close()
+		return resp.StatusCode, nil
+	}
+
+	It("authenticates clients with a certificate signed by the CA in place of a key", func() {
+		serverTLS := NewServerTLS(false)
+		Expect(serverTLS.LoadSecret(serverSecret(true))).To(Succeed())
+		ts := serve(serverTLS)
+
+		Expect(do(client(&pkix.Name{CommonName: "dashboard"}), http.MethodGet, ts.URL)).To(Equal(http.StatusOK))
+		Expect(do(client(&pkix.Name{CommonName: "dashboard"}), http.MethodPost, ts.URL)).To(Equal(http.StatusForbidden))
+		Expect(do(client(&pkix.Name{CommonName: "ci", Organization: []string{"admin"}}), http.MethodPost, ts.URL)).
+			To(Equal(http.StatusCreated))
+		// Without a certificate, the client needs a key
+		Expect(do(client(nil), http.MethodGet, ts.URL)).To(Equal(http.StatusUnauthorized))
+	})
+
+	It("refuses clients without a certificate when one is required", func() {
+		serverTLS := NewServerTLS(true)
+		Expect(serverTLS.LoadSecret(serverSecret(false))).NotTo(Succeed())
+		Expect(serverTLS.LoadSecret(serverSecret(true))).To(Succeed())
+		ts := serve(serverTLS)
+
+		_, err := do(client(nil), http.MethodGet, ts.URL)
+		Expect(err).To(HaveOccurred())
+		Expect(do(client(&pkix.Name{CommonName: "dashboard"}), http.MethodGet, ts.URL)).To(Equal(http.StatusOK))
+	})
+
+	It("fails handshakes until a certificate is loaded", func() {
+		ts := serve(NewServerTLS(false))
+		_, err := do(client(nil), http.MethodGet, ts.URL)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("names principals after the certificate", func() {
+		state := func(cert *x509.Certificate) *tls.ConnectionState {
+			return &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+		}
+
+		key, ok := clientCertKey(state(&x509.Certificate{DNSNames: []string{"ci.example.com"}}))
+		Expect(ok).To(BeTrue())
+		Expect(key).To(Equal(APIKey{Name: "cert:ci.example.com", Role: RoleReadOnly}))
+
+		_, ok = clientCertKey(state(&x509.Certificate{}))
+		Expect(ok).To(BeFalse())
+		_, ok = clientCertKey(&tls.ConnectionState{})
+		Expect(ok).To(BeFalse())
+	})
+})
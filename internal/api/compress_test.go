@@ -0,0 +1,54 @@
+package api
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Response compression", func() {
+	var server *Server
+
+	get := func(path, acceptEncoding, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+		req.Header.Set("If-None-Match", ifNoneMatch)
+		rec := httptest.NewRecorder()
+		server.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	BeforeEach(func() {
+		server = NewServer(Config{Namespace: "default"}, newFakeClient(), nil, nil)
+	})
+
+	It("gzips responses for clients that accept it", func() {
+		rec := get("/api/v1/models", "br, gzip", "")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Header().Get("Content-Encoding")).To(Equal("gzip"))
+		Expect(rec.Header().Values("Vary")).To(ContainElement("Accept-Encoding"))
+
+		reader, err := gzip.NewReader(rec.Body)
+		Expect(err).NotTo(HaveOccurred())
+		var list ModelListResponse
+		Expect(json.NewDecoder(reader).Decode(&list)).To(Succeed())
+	})
+
+	It("leaves responses alone for other clients", func() {
+		rec := get("/api/v1/models", "gzip;q=0", "")
+		Expect(rec.Header().Get("Content-Encoding")).To(BeEmpty())
+		Expect(rec.Body.String()).To(ContainSubstring(`"items"`))
+	})
+
+	It("does not compress empty responses", func() {
+		etag := get("/api/v1/models", "", "").Header().Get("ETag")
+		rec := get("/api/v1/models", "gzip", etag)
+		Expect(rec.Code).To(Equal(http.StatusNotModified))
+		Expect(rec.Header().Get("Content-Encoding")).To(BeEmpty())
+		Expect(rec.Body.Len()).To(BeZero())
+	})
+})
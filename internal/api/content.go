@@ -0,0 +1,233 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+const (
+	contentTypeJSON = "application/json"
+	contentTypeYAML = "application/yaml"
+
+	// outputManifest is the ?output= value selecting full OllamaModel manifests
+	outputManifest = "manifest"
+)
+
+// isYAML reports whether a media type denotes YAML
+func isYAML(mediaType string) bool {
+	switch mediaType {
+	case contentTypeYAML, "application/x-yaml", "text/yaml", "text/x-yaml":
+		return true
+	}
+	return false
+}
+
+// wantsYAML reports whether the client prefers YAML over JSON according to
+// its Accept header. JSON wins ties so existing clients are unaffected.
+func wantsYAML(r *http.Request) bool {
+	var yamlQ, jsonQ float64
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if value, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+
+		switch {
+		case isYAML(mediaType):
+			yamlQ = max(yamlQ, q)
+		case mediaType == contentTypeJSON || mediaType == "application/*" || mediaType == "*/*":
+			jsonQ = max(jsonQ, q)
+		}
+	}
+	return yamlQ > jsonQ
+}
+
+// sendResponse sends data as YAML if the client asked for it and as JSON otherwise
+func sendResponse(w http.ResponseWriter, r *http.Request, data interface{}, status int) {
+	if !wantsYAML(r) {
+		sendJSON(w, data, status)
+		return
+	}
+	sendYAML(w, data, status)
+}
+
+// sendYAML sends data as YAML
+func sendYAML(w http.ResponseWriter, data interface{}, status int) {
+	out, err := yaml.Marshal(data)
+	if err != nil {
+		sendError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentTypeYAML)
+	w.WriteHeader(status)
+	w.Write(out)
+}
+
+// decodeBody decodes a JSON or YAML request body, depending on its Content-Type, into v
+func decodeBody(r *http.Request, v interface{}) error {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if isYAML(mediaType) {
+		return yaml.Unmarshal(body, v)
+	}
+	return json.Unmarshal(body, v)
+}
+
+// decodeErrorStatus returns the status for a request body that failed to decode
+func decodeErrorStatus(err error) int {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
+
+// bodyLimitMiddleware rejects request bodies larger than the configured limit.
+// The limit of the runtime configuration, if any, takes precedence.
+func (s *Server) bodyLimitMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Body != nil {
+			limit := s.config.Limits.MaxBodyBytes
+			if override := s.config.Settings.Get().MaxBodyBytes; override > 0 {
+				limit = override
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// decodeModelRequest decodes a create request. Besides the plain name and tag
+// it accepts a full OllamaModel manifest, as returned with ?output=manifest.
+func decodeModelRequest(r *http.Request) (ModelRequest, error) {
+	var body struct {
+		ModelRequest
+		Kind string                          `json:"kind"`
+		Spec *ollamav1alpha1.OllamaModelSpec `json:"spec"`
+	}
+	if err := decodeBody(r, &body); err != nil {
+		return ModelRequest{}, err
+	}
+
+	if body.Kind == "OllamaModel" && body.Spec != nil {
+		return ModelRequest{
+			Name:               body.Spec.Name,
+			Tag:                body.Spec.Tag,
+			Quantization:       body.Spec.Quantization,
+			Parameters:         body.Spec.Parameters,
+			System:             body.Spec.System,
+			Template:           body.Spec.Template,
+			Type:               string(body.Spec.Type),
+			ExpectedDimensions: body.Spec.ExpectedDimensions,
+		}, nil
+	}
+	return body.ModelRequest, nil
+}
+
+// ModelManifest is an OllamaModel stripped of server-set fields so that it can
+// be committed to a repository and applied again
+type ModelManifest struct {
+	APIVersion string                         `json:"apiVersion"`
+	Kind       string                         `json:"kind"`
+	Metadata   ManifestMetadata               `json:"metadata"`
+	Spec       ollamav1alpha1.OllamaModelSpec `json:"spec"`
+}
+
+// ManifestMetadata is the subset of object metadata kept in a manifest
+type ManifestMetadata struct {
+	Name        string            `json:"name"`
+	Namespace   string            `json:"namespace"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ModelManifestList is a Kubernetes List of model manifests
+type ModelManifestList struct {
+	APIVersion string          `json:"apiVersion"`
+	Kind       string          `json:"kind"`
+	Items      []ModelManifest `json:"items"`
+}
+
+// manifestAnnotations are annotations managed by the operator or kubectl that
+// are dropped from manifests
+var manifestAnnotations = []string{
+	ollamav1alpha1.RefreshAnnotation,
+	ollamav1alpha1.CreatedByAnnotation,
+	ollamav1alpha1.RefreshedByAnnotation,
+	"kubectl.kubernetes.io/last-applied-configuration",
+}
+
+// wantsManifest reports whether the request asked for manifests, and
+// validates the output parameter
+func wantsManifest(r *http.Request) (bool, error) {
+	switch output := r.URL.Query().Get("output"); output {
+	case "":
+		return false, nil
+	case outputManifest:
+		return true, nil
+	default:
+		return false, fmt.Errorf("unsupported output %q, must be %q", output, outputManifest)
+	}
+}
+
+// convertModelToManifest converts an OllamaModel into a manifest
+func convertModelToManifest(model ollamav1alpha1.OllamaModel) ModelManifest {
+	manifest := ModelManifest{
+		APIVersion: ollamav1alpha1.GroupVersion.String(),
+		Kind:       "OllamaModel",
+		Metadata: ManifestMetadata{
+			Name:      model.Name,
+			Namespace: model.Namespace,
+			Labels:    model.Labels,
+		},
+		Spec: model.Spec,
+	}
+
+	for key, value := range model.Annotations {
+		dropped := false
+		for _, annotation := range manifestAnnotations {
+			if key == annotation {
+				dropped = true
+				break
+			}
+		}
+		if !dropped {
+			if manifest.Metadata.Annotations == nil {
+				manifest.Metadata.Annotations = make(map[string]string)
+			}
+			manifest.Metadata.Annotations[key] = value
+		}
+	}
+
+	// Pin the manifest to the pulled model so that a mismatch is reported when
+	// it is applied elsewhere
+	if model.Status.Digest != "" {
+		if manifest.Metadata.Annotations == nil {
+			manifest.Metadata.Annotations = make(map[string]string)
+		}
+		manifest.Metadata.Annotations[ollamav1alpha1.DigestAnnotation] = model.Status.Digest
+	}
+
+	return manifest
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+var _ = Describe("Content negotiation", func() {
+	var server *Server
+
+	do := func(method, path string, headers map[string]string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+		rec := httptest.NewRecorder()
+		server.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	BeforeEach(func() {
+		server = NewServer(Config{Namespace: "default"}, newFakeClient(&ollamav1alpha1.OllamaModel{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "llama3.2-1b",
+				Namespace: "default",
+				Labels:    map[string]string{"team": "search"},
+				Annotations: map[string]string{
+					"ollama.smithforge.dev/refresh": "completed-2025-03-25T19:04:53Z",
+					"owner":                         "search-team",
+				},
+			},
+			Spec:   ollamav1alpha1.OllamaModelSpec{Name: "llama3.2", Tag: "1b"},
+			Status: ollamav1alpha1.OllamaModelStatus{State: ollamav1alpha1.StateReady},
+		}), nil, nil)
+	})
+
+	It("returns YAML when the client prefers it", func() {
+		rec := do(http.MethodGet, "/api/v1/models/llama3.2-1b", map[string]string{"Accept": "application/yaml"}, "")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Header().Get("Content-Type")).To(Equal(contentTypeYAML))
+
+		var model ModelResponse
+		Expect(yaml.Unmarshal(rec.Body.Bytes(), &model)).To(Succeed())
+		Expect(model.State).To(Equal("Ready"))
+	})
+
+	It("keeps JSON as the default", func() {
+		rec := do(http.MethodGet, "/api/v1/models", map[string]string{"Accept": "application/json, application/yaml;q=0.5"}, "")
+		Expect(rec.Header().Get("Content-Type")).To(Equal(contentTypeJSON))
+	})
+
+	It("accepts YAML on create", func() {
+		rec := do(http.MethodPost, "/api/v1/models", map[string]string{"Content-Type": "application/yaml"}, "name: phi3\ntag: mini\n")
+		Expect(rec.Code).To(Equal(http.StatusCreated))
+	})
+
+	It("round-trips a model manifest", func() {
+		rec := do(http.MethodGet, "/api/v1/models/llama3.2-1b?output=manifest", map[string]string{"Accept": "application/yaml"}, "")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+
+		var manifest ModelManifest
+		Expect(yaml.Unmarshal(rec.Body.Bytes(), &manifest)).To(Succeed())
+		Expect(manifest.APIVersion).To(Equal("ollama.smithforge.dev/v1alpha1"))
+		Expect(manifest.Kind).To(Equal("OllamaModel"))
+		Expect(manifest.Metadata.Labels).To(HaveKeyWithValue("team", "search"))
+		Expect(manifest.Metadata.Annotations).To(Equal(map[string]string{"owner": "search-team"}))
+		Expect(rec.Body.String()).NotTo(ContainSubstring("status"))
+
+		// Posting the manifest into another namespace creates the same model there
+		rec = do(http.MethodPost, "/api/v1/namespaces/team-a/models", map[string]string{"Content-Type": "application/yaml"}, rec.Body.String())
+		Expect(rec.Code).To(Equal(http.StatusCreated))
+		model := &ollamav1alpha1.OllamaModel{}
+		key := types.NamespacedName{Namespace: "team-a", Name: "llama3.2-1b"}
+		Expect(server.client.Get(context.Background(), key, model)).To(Succeed())
+		Expect(model.Spec.Tag).To(Equal("1b"))
+	})
+
+	It("rejects bodies over the configured limit", func() {
+		server = NewServer(Config{Namespace: "default", Limits: Limits{MaxBodyBytes: 16}}, newFakeClient(), nil, nil)
+		rec := do(http.MethodPost, "/api/v1/models", map[string]string{"Content-Type": "application/json"}, `{"name":"llama3.2","tag":"1b"}`)
+		Expect(rec.Code).To(Equal(http.StatusRequestEntityTooLarge))
+	})
+
+	It("rejects unknown output formats", func() {
+		Expect(do(http.MethodGet, "/api/v1/models?output=wide", nil, "").Code).To(Equal(http.StatusBadRequest))
+	})
+})
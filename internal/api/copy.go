@@ -0,0 +1,109 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	ollamaapi "github.com/ollama/ollama/api"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+// copyModel handles the POST /api/v1/models/{name}/copy endpoint. It copies a
+// pulled model to a new name and tag on the Ollama server and creates an
+// OllamaModel managing the copy.
+func (s *Server) copyModel(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := log.FromContext(ctx).WithName("api-copyModel")
+	vars := mux.Vars(r)
+	name := vars["name"]
+	namespace := s.namespaceFor(r)
+
+	req, err := decodeModelRequest(r)
+	if err != nil {
+		sendError(w, fmt.Errorf("invalid request: %w", err), decodeErrorStatus(err))
+		return
+	}
+	if req.Name == "" || req.Tag == "" {
+		sendError(w, fmt.Errorf("name and tag are required"), http.StatusBadRequest)
+		return
+	}
+	modelName := modelResourceName(req.Name, req.Tag)
+	if err := validateModel(modelName, ollamav1alpha1.OllamaModelSpec{Name: req.Name, Tag: req.Tag}); err != nil {
+		sendError(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+
+	source := &ollamav1alpha1.OllamaModel{}
+	if err := s.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, source); err != nil {
+		if apierrors.IsNotFound(err) {
+			sendError(w, fmt.Errorf("model not found: %s", name), http.StatusNotFound)
+		} else {
+			logger.Error(err, "failed to get model", "name", name)
+			sendError(w, err, http.StatusInternalServerError)
+		}
+		return
+	}
+	if source.Status.State != ollamav1alpha1.StateReady {
+		sendError(w, fmt.Errorf("model %s is %s, only ready models can be copied", name, source.Status.State), http.StatusConflict)
+		return
+	}
+
+	destination := fmt.Sprintf("%s:%s", req.Name, req.Tag)
+	setAuditTarget(ctx, namespace, modelName, destination)
+
+	existing := &ollamav1alpha1.OllamaModel{}
+	err = s.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: modelName}, existing)
+	if err == nil {
+		sendError(w, withCode(CodeAlreadyExists, fmt.Errorf("model already exists: %s", modelName)), http.StatusConflict)
+		return
+	} else if !apierrors.IsNotFound(err) {
+		logger.Error(err, "failed to check if model exists", "name", modelName)
+		sendError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	if !s.checkUnmanaged(w, r, namespace, modelName, destination) {
+		return
+	}
+
+	if s.ollama == nil {
+		sendError(w, errors.New("ollama client is not configured"), http.StatusServiceUnavailable)
+		return
+	}
+
+	// Copy first so the controller finds the model present instead of pulling it
+	if err := s.ollama.Copy(ctx, &ollamaapi.CopyRequest{Source: modelReference(source), Destination: destination}); err != nil {
+		logger.Error(err, "failed to copy model", "source", modelReference(source), "destination", destination)
+		sendError(w, err, http.StatusBadGateway)
+		return
+	}
+
+	model := &ollamav1alpha1.OllamaModel{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      modelName,
+			Namespace: namespace,
+			Labels:    s.config.modelLabels(),
+		},
+		Spec: ollamav1alpha1.OllamaModelSpec{
+			Name: req.Name,
+			Tag:  req.Tag,
+		},
+	}
+	annotatePrincipal(ctx, model, ollamav1alpha1.CreatedByAnnotation)
+	if err := s.client.Create(ctx, model); err != nil {
+		logger.Error(err, "failed to create model for copy", "name", modelName)
+		sendError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	response := convertModelToResponse(*model)
+	response.OperationID = s.startOperation(w, OperationCreate, model)
+	sendResponse(w, r, response, http.StatusCreated)
+}
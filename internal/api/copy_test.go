@@ -0,0 +1,66 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	ollamaapi "github.com/ollama/ollama/api"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+var _ = Describe("Copy", func() {
+	var (
+		server *Server
+		ollama *fakeOllama
+	)
+
+	post := func(path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		server.router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+		return rec
+	}
+
+	BeforeEach(func() {
+		ollama = &fakeOllama{}
+		server = NewServer(Config{Namespace: "default"}, newFakeClient(
+			&ollamav1alpha1.OllamaModel{
+				ObjectMeta: metav1.ObjectMeta{Name: "llama3.2-1b", Namespace: "default"},
+				Spec:       ollamav1alpha1.OllamaModelSpec{Name: "llama3.2", Tag: "1b"},
+				Status:     ollamav1alpha1.OllamaModelStatus{State: ollamav1alpha1.StateReady},
+			},
+			&ollamav1alpha1.OllamaModel{
+				ObjectMeta: metav1.ObjectMeta{Name: "gemma3-1b", Namespace: "default"},
+				Spec:       ollamav1alpha1.OllamaModelSpec{Name: "gemma3", Tag: "1b"},
+				Status:     ollamav1alpha1.OllamaModelStatus{State: ollamav1alpha1.StatePulling},
+			},
+		), ollama, nil)
+	})
+
+	It("copies the model in Ollama and manages the copy", func() {
+		rec := post("/api/v1/models/llama3.2-1b/copy", `{"name":"assistant","tag":"prod"}`)
+		Expect(rec.Code).To(Equal(http.StatusCreated))
+		Expect(ollama.copied).To(Equal([]ollamaapi.CopyRequest{{Source: "llama3.2:1b", Destination: "assistant:prod"}}))
+
+		model := &ollamav1alpha1.OllamaModel{}
+		Expect(server.client.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "assistant-prod"}, model)).To(Succeed())
+		Expect(model.Spec).To(Equal(ollamav1alpha1.OllamaModelSpec{Name: "assistant", Tag: "prod"}))
+	})
+
+	It("only copies ready models", func() {
+		Expect(post("/api/v1/models/gemma3-1b/copy", `{"name":"assistant","tag":"prod"}`).Code).To(Equal(http.StatusConflict))
+		Expect(ollama.copied).To(BeEmpty())
+	})
+
+	It("refuses to overwrite an existing model", func() {
+		Expect(post("/api/v1/models/llama3.2-1b/copy", `{"name":"gemma3","tag":"1b"}`).Code).To(Equal(http.StatusConflict))
+		Expect(ollama.copied).To(BeEmpty())
+	})
+})
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+	"github.com/dmk/ollama-operator/internal/registry"
+)
+
+// UpstreamResponse describes the model tag found in the configured registries
+type UpstreamResponse struct {
+	Registry string `json:"registry,omitempty"`
+	Digest   string `json:"digest,omitempty"`
+	Size     int64  `json:"size,omitempty"`
+	// Error is set when the registries could not be reached, in which case
+	// the existence of the tag is unknown
+	Error string `json:"error,omitempty"`
+}
+
+// DryRunResponse represents the API response for a dry run of model creation
+type DryRunResponse struct {
+	DryRun   bool             `json:"dryRun"`
+	Model    ModelResponse    `json:"model"`
+	Upstream UpstreamResponse `json:"upstream"`
+}
+
+// dryRunCreate validates the creation of model without persisting it. The
+// model is submitted to the API server as a dry run, so that schema validation
+// and admission apply, and its tag is looked up in the configured registries.
+func (s *Server) dryRunCreate(w http.ResponseWriter, r *http.Request, model *ollamav1alpha1.OllamaModel) {
+	ctx := r.Context()
+	logger := log.FromContext(ctx).WithName("api-createModel")
+
+	if err := s.client.Create(ctx, model.DeepCopy(), client.DryRunAll); err != nil {
+		switch {
+		case apierrors.IsInvalid(err):
+			sendError(w, err, http.StatusUnprocessableEntity)
+		case apierrors.IsAlreadyExists(err):
+			sendError(w, err, http.StatusConflict)
+		case apierrors.IsForbidden(err):
+			sendError(w, err, http.StatusForbidden)
+		default:
+			logger.Error(err, "failed to validate model", "name", model.Name)
+			sendError(w, err, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	response := DryRunResponse{
+		DryRun: true,
+		Model:  convertModelToResponse(*model),
+	}
+
+	reference := model.Spec.ParsedReference()
+	source, manifest, err := s.registry.Resolve(ctx, reference.Name(), reference.Tag)
+	switch {
+	case errors.Is(err, registry.ErrNotFound):
+		sendError(w, fmt.Errorf("model %s was not found in any registry, check its name and tag", model.Spec.Reference()),
+			http.StatusUnprocessableEntity)
+		return
+	case err != nil:
+		logger.Info("could not check model in registries", "name", model.Name, "error", err.Error())
+		response.Upstream.Error = err.Error()
+	default:
+		response.Upstream.Registry = source
+		response.Upstream.Digest = manifest.Digest
+		response.Upstream.Size = manifest.Size
+	}
+
+	sendResponse(w, r, response, http.StatusOK)
+}
@@ -0,0 +1,151 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Codes of API error responses, telling clients what went wrong without
+// parsing messages
+const (
+	CodeInvalidRequest     = "InvalidRequest"
+	CodeValidationFailed   = "ValidationFailed"
+	CodeUnauthenticated    = "Unauthenticated"
+	CodeForbidden          = "Forbidden"
+	CodeNotFound           = "NotFound"
+	CodeMethodNotAllowed   = "MethodNotAllowed"
+	CodeAlreadyExists      = "AlreadyExists"
+	CodePullInProgress     = "PullInProgress"
+	CodeConflict           = "Conflict"
+	CodeRequestTooLarge    = "RequestTooLarge"
+	CodeTooManyRequests    = "TooManyRequests"
+	CodeInternal           = "Internal"
+	CodeBackendUnavailable = "BackendUnavailable"
+	CodeUnavailable        = "Unavailable"
+	CodeTimeout            = "Timeout"
+)
+
+// ErrorResponse is the body of every API error response
+type ErrorResponse struct {
+	// Code classifies the error, such as NotFound or ValidationFailed
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// Details lists the fields of the request that failed validation
+	Details []ErrorDetail `json:"details,omitempty"`
+	// Retryable tells whether the same request may succeed later, such as
+	// once the Ollama server is back
+	Retryable bool   `json:"retryable"`
+	RequestID string `json:"requestID,omitempty"`
+	// Error repeats Message for the clients of earlier versions
+	Error string `json:"error"`
+}
+
+// ErrorDetail describes why a field of a request is invalid
+type ErrorDetail struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// statusCodes are the codes of the errors sent with each HTTP status, unless
+// the error tells otherwise
+var statusCodes = map[int]string{
+	http.StatusBadRequest:            CodeInvalidRequest,
+	http.StatusUnauthorized:          CodeUnauthenticated,
+	http.StatusForbidden:             CodeForbidden,
+	http.StatusNotFound:              CodeNotFound,
+	http.StatusMethodNotAllowed:      CodeMethodNotAllowed,
+	http.StatusConflict:              CodeConflict,
+	http.StatusRequestEntityTooLarge: CodeRequestTooLarge,
+	http.StatusUnprocessableEntity:   CodeValidationFailed,
+	http.StatusTooManyRequests:       CodeTooManyRequests,
+	http.StatusInternalServerError:   CodeInternal,
+	http.StatusBadGateway:            CodeBackendUnavailable,
+	http.StatusServiceUnavailable:    CodeUnavailable,
+	http.StatusGatewayTimeout:        CodeTimeout,
+}
+
+// codedError is an error with the code, details and retryability of its
+// error response, when the HTTP status alone does not tell them
+type codedError struct {
+	code      string
+	details   []ErrorDetail
+	retryable bool
+	err       error
+}
+
+func (e *codedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *codedError) Unwrap() error {
+	return e.err
+}
+
+// withCode returns err with the code of its error response
+func withCode(code string, err error) error {
+	return &codedError{code: code, err: err}
+}
+
+// validationError returns err with the fields of the request it is about
+func validationError(err error, details ...ErrorDetail) error {
+	return &codedError{code: CodeValidationFailed, details: details, err: err}
+}
+
+// newErrorResponse returns the error response for err, sent with status.
+// Kubernetes API errors tell their own code and invalid fields.
+func newErrorResponse(w http.ResponseWriter, err error, status int) ErrorResponse {
+	response := ErrorResponse{
+		Code:      statusCodes[status],
+		Message:   err.Error(),
+		Retryable: status == http.StatusTooManyRequests || status >= http.StatusBadGateway,
+		RequestID: w.Header().Get(requestIDHeader),
+		Error:     err.Error(),
+	}
+	if response.Code == "" {
+		response.Code = http.StatusText(status)
+	}
+
+	var statusErr apierrors.APIStatus
+	if errors.As(err, &statusErr) {
+		kubeStatus := statusErr.Status()
+		switch kubeStatus.Reason {
+		case metav1.StatusReasonAlreadyExists:
+			response.Code = CodeAlreadyExists
+		case metav1.StatusReasonConflict:
+			// The model changed meanwhile; the request applies to the new version
+			response.Code = CodeConflict
+			response.Retryable = true
+		case metav1.StatusReasonInvalid:
+			response.Code = CodeValidationFailed
+		case metav1.StatusReasonTimeout, metav1.StatusReasonServerTimeout, metav1.StatusReasonTooManyRequests:
+			response.Retryable = true
+		}
+		if kubeStatus.Details != nil {
+			for _, cause := range kubeStatus.Details.Causes {
+				response.Details = append(response.Details, ErrorDetail{Field: cause.Field, Message: cause.Message})
+			}
+		}
+	}
+
+	var coded *codedError
+	if errors.As(err, &coded) {
+		response.Code = coded.code
+		response.Details = append(response.Details, coded.details...)
+		response.Retryable = response.Retryable || coded.retryable
+	}
+	return response
+}
+
+// notFound answers the requests for unknown paths
+func notFound(w http.ResponseWriter, r *http.Request) {
+	sendError(w, fmt.Errorf("no such endpoint: %s", r.URL.Path), http.StatusNotFound)
+}
+
+// methodNotAllowed answers the requests with a method an endpoint does not serve
+func methodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	sendError(w, fmt.Errorf("method %s is not allowed on %s", r.Method, r.URL.Path), http.StatusMethodNotAllowed)
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+var _ = Describe("Error responses", func() {
+	send := func(err error, status int) ErrorResponse {
+		rec := httptest.NewRecorder()
+		rec.Header().Set(requestIDHeader, "req-1")
+		sendError(rec, err, status)
+		Expect(rec.Code).To(Equal(status))
+
+		var resp ErrorResponse
+		Expect(json.Unmarshal(rec.Body.Bytes(), &resp)).To(Succeed())
+		return resp
+	}
+
+	It("classifies errors by status", func() {
+		resp := send(errors.New("connection refused"), http.StatusBadGateway)
+		Expect(resp.Code).To(Equal(CodeBackendUnavailable))
+		Expect(resp.Message).To(Equal("connection refused"))
+		Expect(resp.Error).To(Equal(resp.Message))
+		Expect(resp.Retryable).To(BeTrue())
+		Expect(resp.RequestID).To(Equal("req-1"))
+
+		resp = send(errors.New("model not found: phi3-mini"), http.StatusNotFound)
+		Expect(resp.Code).To(Equal(CodeNotFound))
+		Expect(resp.Retryable).To(BeFalse())
+	})
+
+	It("tells conflicts apart", func() {
+		resp := send(withCode(CodeAlreadyExists, errors.New("model already exists: phi3-mini")), http.StatusConflict)
+		Expect(resp.Code).To(Equal(CodeAlreadyExists))
+		Expect(resp.Retryable).To(BeFalse())
+
+		gr := schema.GroupResource{Group: "ollama.smithforge.dev", Resource: "ollamamodels"}
+		resp = send(apierrors.NewConflict(gr, "phi3-mini", errors.New("the object has been modified")), http.StatusInternalServerError)
+		Expect(resp.Code).To(Equal(CodeConflict))
+		Expect(resp.Retryable).To(BeTrue())
+	})
+
+	It("lists the invalid fields of Kubernetes validation errors", func() {
+		gk := schema.GroupKind{Group: "ollama.smithforge.dev", Kind: "OllamaModel"}
+		err := apierrors.NewInvalid(gk, "phi3-mini", field.ErrorList{
+			field.Invalid(field.NewPath("spec", "tag"), "Mini", "must be lowercase"),
+		})
+		resp := send(err, http.StatusUnprocessableEntity)
+		Expect(resp.Code).To(Equal(CodeValidationFailed))
+		Expect(resp.Details).To(HaveLen(1))
+		Expect(resp.Details[0].Field).To(Equal("spec.tag"))
+	})
+
+	It("answers unknown endpoints with an error response", func() {
+		server := NewServer(Config{Namespace: "default"}, newFakeClient(), nil, nil)
+		rec := httptest.NewRecorder()
+		server.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/nothing", nil))
+		Expect(rec.Code).To(Equal(http.StatusNotFound))
+
+		var resp ErrorResponse
+		Expect(json.Unmarshal(rec.Body.Bytes(), &resp)).To(Succeed())
+		Expect(resp.Code).To(Equal(CodeNotFound))
+	})
+})
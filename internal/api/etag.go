@@ -0,0 +1,61 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+// modelETag returns the ETag of a single model response. The resourceVersion
+// changes on every write to the model, including status updates.
+func modelETag(r *http.Request, model *ollamav1alpha1.OllamaModel) string {
+	return variantETag(r, model.ResourceVersion)
+}
+
+// modelListETag returns the ETag of a model list response. List resourceVersions
+// from the cache are not reliable, so it is derived from the items instead.
+func modelListETag(r *http.Request, namespace string, models []ollamav1alpha1.OllamaModel) string {
+	hash := sha256.New()
+	hash.Write([]byte(namespace))
+	for _, model := range models {
+		fmt.Fprintf(hash, "\n%s/%s", model.UID, model.ResourceVersion)
+	}
+	return variantETag(r, hex.EncodeToString(hash.Sum(nil))[:32])
+}
+
+// variantETag builds a weak ETag from version, distinguishing the YAML,
+// manifest and sparse fieldset representations of the same resource
+func variantETag(r *http.Request, version string) string {
+	if wantsYAML(r) {
+		version += "-yaml"
+	}
+	if r.URL.Query().Get("output") == outputManifest {
+		version += "-manifest"
+	}
+	if fields := r.URL.Query().Get("fields"); fields != "" {
+		sum := sha256.Sum256([]byte(fields))
+		version += "-" + hex.EncodeToString(sum[:4])
+	}
+	return fmt.Sprintf(`W/"%s"`, version)
+}
+
+// notModified sets the ETag header and reports whether the request's
+// If-None-Match header matches it, in which case a 304 has been written
+func notModified(w http.ResponseWriter, r *http.Request, etag string) bool {
+	w.Header().Set("ETag", etag)
+	w.Header().Add("Vary", "Accept")
+
+	for _, candidate := range strings.Split(r.Header.Get("If-None-Match"), ",") {
+		candidate = strings.TrimSpace(candidate)
+		// If-None-Match uses weak comparison, so the W/ prefix is ignored
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			w.WriteHeader(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gorilla/mux"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+// +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch
+
+// EventResponse represents a Kubernetes event related to a model
+type EventResponse struct {
+	Type           string `json:"type"`
+	Reason         string `json:"reason"`
+	Message        string `json:"message"`
+	Count          int32  `json:"count,omitempty"`
+	Source         string `json:"source,omitempty"`
+	FirstTimestamp string `json:"firstTimestamp,omitempty"`
+	LastTimestamp  string `json:"lastTimestamp,omitempty"`
+}
+
+// EventListResponse represents the API response for listing model events
+type EventListResponse struct {
+	Items []EventResponse `json:"items"`
+}
+
+// getModelEvents handles the GET /api/v1/models/{name}/events endpoint
+func (s *Server) getModelEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := log.FromContext(ctx).WithName("api-getModelEvents")
+	vars := mux.Vars(r)
+	name := vars["name"]
+	namespace := s.namespaceFor(r)
+
+	// Get the model to ensure it exists and to match events by UID
+	model := &ollamav1alpha1.OllamaModel{}
+	if err := s.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, model); err != nil {
+		if apierrors.IsNotFound(err) {
+			sendError(w, fmt.Errorf("model not found: %s", name), http.StatusNotFound)
+		} else {
+			logger.Error(err, "failed to get model", "name", name)
+			sendError(w, err, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	var eventList corev1.EventList
+	if err := s.client.List(ctx, &eventList,
+		client.InNamespace(namespace),
+		client.MatchingFields{"involvedObject.uid": string(model.UID)},
+	); err != nil {
+		logger.Error(err, "failed to list events", "name", name)
+		sendError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	// Oldest first, matching kubectl describe
+	sort.SliceStable(eventList.Items, func(i, j int) bool {
+		return eventTime(eventList.Items[i]).Before(eventTime(eventList.Items[j]))
+	})
+
+	response := EventListResponse{
+		Items: make([]EventResponse, len(eventList.Items)),
+	}
+	for i, event := range eventList.Items {
+		response.Items[i] = convertEventToResponse(event)
+	}
+
+	sendResponse(w, r, response, http.StatusOK)
+}
+
+// eventTime returns the most recent time an event was observed
+func eventTime(event corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
+
+// convertEventToResponse converts a Kubernetes Event to an EventResponse
+func convertEventToResponse(event corev1.Event) EventResponse {
+	response := EventResponse{
+		Type:    event.Type,
+		Reason:  event.Reason,
+		Message: event.Message,
+		Count:   event.Count,
+		Source:  event.Source.Component,
+	}
+
+	if response.Source == "" {
+		response.Source = event.ReportingController
+	}
+	if !event.FirstTimestamp.IsZero() {
+		response.FirstTimestamp = event.FirstTimestamp.Format(time.RFC3339)
+	}
+	response.LastTimestamp = eventTime(event).Format(time.RFC3339)
+
+	return response
+}
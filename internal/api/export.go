@@ -0,0 +1,75 @@
+package api
+
+import (
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+// exportModels handles the GET /api/v1/export endpoint. It returns the models of
+// a namespace, or of all namespaces with ?allNamespaces=true, as a List of
+// manifests pinned to their pulled digests. Exports are meant to be committed
+// to Git, so they are YAML unless the client explicitly asks for JSON.
+func (s *Server) exportModels(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := log.FromContext(ctx).WithName("api-exportModels")
+	namespace := s.namespaceFor(r)
+
+	var opts []client.ListOption
+	if r.URL.Query().Get("allNamespaces") != "true" {
+		opts = append(opts, client.InNamespace(namespace))
+	}
+
+	var modelList ollamav1alpha1.OllamaModelList
+	if err := s.client.List(ctx, &modelList, opts...); err != nil {
+		logger.Error(err, "failed to list models")
+		sendError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	manifests := ModelManifestList{
+		APIVersion: "v1",
+		Kind:       "List",
+		Items:      []ModelManifest{},
+	}
+	for _, model := range modelList.Items {
+		// Models being deleted are not part of the environment anymore
+		if model.DeletionTimestamp != nil {
+			continue
+		}
+		manifests.Items = append(manifests.Items, convertModelToManifest(model))
+	}
+
+	// Keep exports stable so that they diff cleanly
+	sort.Slice(manifests.Items, func(i, j int) bool {
+		a, b := manifests.Items[i].Metadata, manifests.Items[j].Metadata
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+
+	if prefersJSON(r) {
+		sendJSON(w, manifests, http.StatusOK)
+		return
+	}
+	sendYAML(w, manifests, http.StatusOK)
+}
+
+// prefersJSON reports whether the client explicitly listed JSON in its Accept
+// header and prefers it over YAML
+func prefersJSON(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err == nil && mediaType == contentTypeJSON {
+			return !wantsYAML(r)
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+var _ = Describe("Export", func() {
+	var server *Server
+
+	get := func(path, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		rec := httptest.NewRecorder()
+		server.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	BeforeEach(func() {
+		digest := "a80c4f17acd55265feec403c7aef86be0c25983ab279d83f3bcd3abbcb5b8b72"
+		server = NewServer(Config{Namespace: "default"}, newFakeClient(
+			&ollamav1alpha1.OllamaModel{
+				ObjectMeta: metav1.ObjectMeta{Name: "phi3-mini", Namespace: "default"},
+				Spec:       ollamav1alpha1.OllamaModelSpec{Name: "phi3", Tag: "mini"},
+				Status:     ollamav1alpha1.OllamaModelStatus{State: ollamav1alpha1.StateReady, Digest: digest},
+			},
+			&ollamav1alpha1.OllamaModel{
+				ObjectMeta: metav1.ObjectMeta{Name: "llama3.2-1b", Namespace: "default"},
+				Spec:       ollamav1alpha1.OllamaModelSpec{Name: "llama3.2", Tag: "1b"},
+			},
+			&ollamav1alpha1.OllamaModel{
+				ObjectMeta: metav1.ObjectMeta{Name: "gemma3-1b", Namespace: "team-a"},
+				Spec:       ollamav1alpha1.OllamaModelSpec{Name: "gemma3", Tag: "1b"},
+			},
+		), nil, nil)
+	})
+
+	It("exports the namespace as sorted YAML manifests pinned to their digests", func() {
+		rec := get("/api/v1/export", "")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Header().Get("Content-Type")).To(Equal(contentTypeYAML))
+
+		var list ModelManifestList
+		Expect(yaml.Unmarshal(rec.Body.Bytes(), &list)).To(Succeed())
+		Expect(list.Kind).To(Equal("List"))
+		Expect(list.Items).To(HaveLen(2))
+		Expect(list.Items[0].Metadata.Name).To(Equal("llama3.2-1b"))
+		Expect(list.Items[0].Metadata.Annotations).To(BeEmpty())
+		Expect(list.Items[1].Metadata.Annotations).To(HaveKeyWithValue(ollamav1alpha1.DigestAnnotation,
+			"a80c4f17acd55265feec403c7aef86be0c25983ab279d83f3bcd3abbcb5b8b72"))
+		Expect(rec.Body.String()).NotTo(ContainSubstring("status"))
+	})
+
+	It("exports all namespaces as JSON on request", func() {
+		rec := get("/api/v1/export?allNamespaces=true", "application/json")
+		Expect(rec.Header().Get("Content-Type")).To(Equal(contentTypeJSON))
+
+		var list ModelManifestList
+		Expect(json.Unmarshal(rec.Body.Bytes(), &list)).To(Succeed())
+		Expect(list.Items).To(HaveLen(3))
+		Expect(list.Items[2].Metadata.Namespace).To(Equal("team-a"))
+	})
+})
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// modelResponseFields are the JSON field names of ModelResponse that may be
+// selected with ?fields=
+var modelResponseFields = jsonFieldNames(reflect.TypeOf(ModelResponse{}))
+
+// jsonFieldNames returns the JSON names of a struct type's fields
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// parseFields parses the ?fields= query parameter. It returns nil when all
+// fields were requested.
+func parseFields(r *http.Request) ([]string, error) {
+	value := r.URL.Query().Get("fields")
+	if value == "" {
+		return nil, nil
+	}
+
+	var fields []string
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if !modelResponseFields[field] {
+			valid := make([]string, 0, len(modelResponseFields))
+			for name := range modelResponseFields {
+				valid = append(valid, name)
+			}
+			sort.Strings(valid)
+			return nil, fmt.Errorf("unknown field %q, must be one of %s", field, strings.Join(valid, ", "))
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// projectedModelList is a model list restricted to the requested fields
+type projectedModelList struct {
+	Items []map[string]json.RawMessage `json:"items"`
+}
+
+// projectModel returns only the requested fields of a model response. Fields
+// that are empty and omitted from the full response are omitted here too.
+func projectModel(response ModelResponse, fields []string) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return nil, err
+	}
+
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+
+	projected := make(map[string]json.RawMessage, len(fields))
+	for _, field := range fields {
+		if value, ok := all[field]; ok {
+			projected[field] = value
+		}
+	}
+	return projected, nil
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+var _ = Describe("Sparse fieldsets", func() {
+	var server *Server
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		server.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	BeforeEach(func() {
+		server = NewServer(Config{Namespace: "default"}, newFakeClient(&ollamav1alpha1.OllamaModel{
+			ObjectMeta: metav1.ObjectMeta{Name: "llama3.2-1b", Namespace: "default"},
+			Spec:       ollamav1alpha1.OllamaModelSpec{Name: "llama3.2", Tag: "1b"},
+			Status: ollamav1alpha1.OllamaModelStatus{
+				State:         ollamav1alpha1.StateReady,
+				Size:          1321098329,
+				FormattedSize: "1.2 GiB",
+			},
+		}), nil, nil)
+	})
+
+	It("returns only the requested fields of each model", func() {
+		rec := get("/api/v1/models?fields=name,state,formattedSize")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+
+		var list struct {
+			Items []map[string]interface{} `json:"items"`
+		}
+		Expect(json.Unmarshal(rec.Body.Bytes(), &list)).To(Succeed())
+		Expect(list.Items).To(ConsistOf(map[string]interface{}{
+			"name":          "llama3.2-1b",
+			"state":         "Ready",
+			"formattedSize": "1.2 GiB",
+		}))
+	})
+
+	It("projects single models", func() {
+		rec := get("/api/v1/models/llama3.2-1b?fields=size")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(MatchJSON(`{"size": 1321098329}`))
+	})
+
+	It("uses a different ETag than the full representation", func() {
+		full := get("/api/v1/models/llama3.2-1b").Header().Get("ETag")
+		Expect(get("/api/v1/models/llama3.2-1b?fields=state").Header().Get("ETag")).NotTo(Equal(full))
+	})
+
+	It("rejects unknown fields", func() {
+		rec := get("/api/v1/models?fields=name,checksum")
+		Expect(rec.Code).To(Equal(http.StatusBadRequest))
+		Expect(rec.Body.String()).To(ContainSubstring(`unknown field \"checksum\"`))
+	})
+})
@@ -0,0 +1,436 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	grpcv1 "github.com/dmk/ollama-operator/api/grpc/v1"
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+	"github.com/dmk/ollama-operator/internal/audit"
+	"github.com/dmk/ollama-operator/internal/registry"
+)
+
+// watchPollInterval is how often WatchProgress checks a model for changes
+const watchPollInterval = time.Second
+
+// GRPCServer serves the model management API over gRPC
+type GRPCServer struct {
+	grpcv1.UnimplementedModelServiceServer
+
+	config   Config
+	client   client.Client
+	keyring  *Keyring
+	audit    *audit.Logger
+	registry *registry.Client
+	server   *grpc.Server
+}
+
+// NewGRPCServer creates a new gRPC API server instance
+func NewGRPCServer(config Config, k8sClient client.Client) *GRPCServer {
+	s := &GRPCServer{
+		config:   config,
+		client:   k8sClient,
+		keyring:  config.keyring(),
+		audit:    config.Auditor,
+		registry: registry.NewClient(config.RegistryURLs, nil),
+	}
+
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(s.authUnaryInterceptor),
+		grpc.ChainStreamInterceptor(s.authStreamInterceptor),
+	}
+	if config.TLS != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(config.TLS.Config("h2"))))
+	}
+	s.server = grpc.NewServer(opts...)
+	grpcv1.RegisterModelServiceServer(s.server, s)
+
+	return s
+}
+
+// Start serves the gRPC API until ctx is cancelled
+func (s *GRPCServer) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("grpc-server")
+	logger.Info("starting gRPC server", "address", s.config.GRPCBindAddress)
+
+	listener, err := net.Listen("tcp", s.config.GRPCBindAddress)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		<-ctx.Done()
+		logger.Info("shutting down gRPC server")
+		// Streams such as WatchProgress may run for as long as a pull, so
+		// they are only let finish until the shutdown timeout
+		stopped := make(chan struct{})
+		go func() {
+			s.server.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(shutdownTimeout):
+			s.server.Stop()
+		}
+	}()
+
+	return s.server.Serve(listener)
+}
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface.
+// The gRPC server doesn't need leader election.
+func (s *GRPCServer) NeedLeaderElection() bool {
+	return false
+}
+
+// ListModels lists the models in a namespace
+func (s *GRPCServer) ListModels(ctx context.Context, req *grpcv1.ListModelsRequest) (*grpcv1.ListModelsResponse, error) {
+	namespace, err := s.namespace(ctx, req.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	var modelList ollamav1alpha1.OllamaModelList
+	if err := s.client.List(ctx, &modelList, client.InNamespace(namespace)); err != nil {
+		return nil, grpcError(err)
+	}
+
+	response := &grpcv1.ListModelsResponse{
+		Items: make([]*grpcv1.Model, len(modelList.Items)),
+	}
+	for i, model := range modelList.Items {
+		response.Items[i] = convertModelToProto(model)
+	}
+	return response, nil
+}
+
+// GetModel returns a single model
+func (s *GRPCServer) GetModel(ctx context.Context, req *grpcv1.GetModelRequest) (*grpcv1.Model, error) {
+	model, err := s.getModel(ctx, req.GetNamespace(), req.GetName())
+	if err != nil {
+		return nil, err
+	}
+	return convertModelToProto(*model), nil
+}
+
+// CreateModel creates a model, taking the same fields as the HTTP API
+func (s *GRPCServer) CreateModel(ctx context.Context, req *grpcv1.CreateModelRequest) (*grpcv1.Model, error) {
+	namespace, err := s.namespace(ctx, req.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	spec := ModelRequest{
+		Name:               req.GetName(),
+		Tag:                req.GetTag(),
+		Quantization:       req.GetQuantization(),
+		ModelRef:           req.GetModelRef(),
+		Parameters:         req.GetParameters(),
+		System:             req.GetSystem(),
+		Template:           req.GetTemplate(),
+		Type:               req.GetType(),
+		ExpectedDimensions: req.ExpectedDimensions,
+	}.spec()
+	model := &ollamav1alpha1.OllamaModel{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      modelResourceName(spec.Name, spec.QualifiedTag()),
+			Namespace: namespace,
+			Labels:    s.config.modelLabels(),
+		},
+		Spec: spec,
+	}
+	if err := validateModel(model.Name, model.Spec); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := checkUnmanaged(ctx, s.client, namespace, model.Name, spec.Reference()); err != nil {
+		var managed *alreadyManagedError
+		if errors.As(err, &managed) {
+			return nil, status.Error(codes.AlreadyExists, err.Error())
+		}
+		return nil, grpcError(err)
+	}
+	if err := checkQuantization(ctx, s.registry, spec); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	annotatePrincipal(ctx, model, ollamav1alpha1.CreatedByAnnotation)
+
+	err = s.client.Create(ctx, model)
+	s.record(ctx, audit.ActionCreate, model.Namespace, model.Name, modelReference(model), err)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return convertModelToProto(*model), nil
+}
+
+// DeleteModel deletes a model
+func (s *GRPCServer) DeleteModel(ctx context.Context, req *grpcv1.DeleteModelRequest) (*grpcv1.DeleteModelResponse, error) {
+	model, err := s.getModel(ctx, req.GetNamespace(), req.GetName())
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.client.Delete(ctx, model)
+	s.record(ctx, audit.ActionDelete, model.Namespace, model.Name, modelReference(model), err)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &grpcv1.DeleteModelResponse{}, nil
+}
+
+// RefreshModel forces a model to be re-pulled
+func (s *GRPCServer) RefreshModel(ctx context.Context, req *grpcv1.RefreshModelRequest) (*grpcv1.Model, error) {
+	model, err := s.getModel(ctx, req.GetNamespace(), req.GetName())
+	if err != nil {
+		return nil, err
+	}
+	if pullInProgress(model) {
+		err := pullInProgressError(model)
+		s.record(ctx, audit.ActionRefresh, model.Namespace, model.Name, modelReference(model), err)
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	requestRefresh(model)
+	annotatePrincipal(ctx, model, ollamav1alpha1.RefreshedByAnnotation)
+	err = s.client.Update(ctx, model)
+	s.record(ctx, audit.ActionRefresh, model.Namespace, model.Name, modelReference(model), err)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return convertModelToProto(*model), nil
+}
+
+// WatchProgress streams the model whenever it changes until it is Ready or Failed
+func (s *GRPCServer) WatchProgress(req *grpcv1.WatchProgressRequest, stream grpc.ServerStreamingServer[grpcv1.Model]) error {
+	ctx := stream.Context()
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	var lastVersion string
+	for {
+		model, err := s.getModel(ctx, req.GetNamespace(), req.GetName())
+		if err != nil {
+			return err
+		}
+
+		if model.ResourceVersion != lastVersion {
+			lastVersion = model.ResourceVersion
+			if err := stream.Send(convertModelToProto(*model)); err != nil {
+				return err
+			}
+			if model.Status.State == ollamav1alpha1.StateReady || model.Status.State == ollamav1alpha1.StateFailed {
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// getModel fetches a model, mapping lookup failures to gRPC status errors
+func (s *GRPCServer) getModel(ctx context.Context, namespace, name string) (*ollamav1alpha1.OllamaModel, error) {
+	if name == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	namespace, err := s.namespace(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	model := &ollamav1alpha1.OllamaModel{}
+	if err := s.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, model); err != nil {
+		return nil, grpcError(err)
+	}
+	return model, nil
+}
+
+// namespace returns the requested namespace, falling back to the configured
+// default, or a PermissionDenied error if the operator does not watch it or
+// the key of the call may not reach it
+func (s *GRPCServer) namespace(ctx context.Context, namespace string) (string, error) {
+	if namespace == "" {
+		namespace = s.config.Namespace
+	}
+	if !s.config.namespaceWatched(namespace) {
+		return "", status.Errorf(codes.PermissionDenied, "namespace %q is not watched by the operator", namespace)
+	}
+	if !authorizeNamespace(ctx, apiGRPC, namespace) {
+		return "", status.Errorf(codes.PermissionDenied, "the key may not reach namespace %q", namespace)
+	}
+	return namespace, nil
+}
+
+// authUnaryInterceptor authenticates unary calls and records the principal in the call context
+func (s *GRPCServer) authUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	caller, err := s.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	if s.config.ReadOnly && grpcMutatingMethods[info.FullMethod] {
+		denyReadOnly(apiGRPC, caller.principal, caller.role)
+		return nil, status.Error(codes.Unimplemented, readOnlyErrorMessage)
+	}
+	ctx = context.WithValue(ctx, requestInfoKey{}, caller)
+	return handler(ctx, req)
+}
+
+// authStreamInterceptor authenticates streaming calls and records the
+// principal in the stream context
+func (s *GRPCServer) authStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	caller, err := s.authenticate(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	ctx := context.WithValue(ss.Context(), requestInfoKey{}, caller)
+	return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+}
+
+// contextStream is a server stream whose context is replaced
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the replaced context of the stream
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// grpcMutatingMethods lists the methods read-only keys may not call
+var grpcMutatingMethods = map[string]bool{
+	grpcv1.ModelService_CreateModel_FullMethodName:  true,
+	grpcv1.ModelService_DeleteModel_FullMethodName:  true,
+	grpcv1.ModelService_RefreshModel_FullMethodName: true,
+}
+
+// authenticate checks the address of the caller against the allowed networks,
+// its client certificate or x-api-key metadata against the keyring, and the
+// role of the certificate or key against the called method, returning the
+// caller's principal, role and namespaces
+func (s *GRPCServer) authenticate(ctx context.Context, method string) (*requestInfo, error) {
+	source := s.sourceIP(ctx)
+	if !s.config.sourceAllowed(source) {
+		sourceDenied.WithLabelValues(apiGRPC).Inc()
+		return nil, status.Error(codes.PermissionDenied, errSourceNotAllowed.Error())
+	}
+	if key, ok := peerCertKey(ctx); ok {
+		if !authorize(apiGRPC, key, grpcMutatingMethods[method]) {
+			return nil, status.Errorf(codes.PermissionDenied, "certificate %q is read-only", key.Name)
+		}
+		return &requestInfo{principal: key.Name, role: key.Role, namespaces: key.Namespaces}, nil
+	}
+	if !s.keyring.Enabled() {
+		return &requestInfo{principal: anonymousPrincipal, role: RoleAdmin}, nil
+	}
+
+	if remaining, locked := s.config.Lockout.Locked(source); locked {
+		authenticationFailed.WithLabelValues(apiGRPC, reasonLockedOut).Inc()
+		return nil, status.Errorf(codes.ResourceExhausted,
+			"too many failed authentications, retry in %s", remaining.Round(time.Second))
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("x-api-key")
+	if len(values) == 0 {
+		s.authenticationFailed(ctx, source, reasonMissingKey)
+		return nil, status.Error(codes.Unauthenticated, "missing API key")
+	}
+
+	key, ok := s.keyring.Authenticate(values[0])
+	if !ok {
+		s.authenticationFailed(ctx, source, reasonInvalidKey)
+		return nil, status.Error(codes.Unauthenticated, "invalid API key")
+	}
+	s.config.Lockout.Succeeded(source)
+	if !authorize(apiGRPC, key, grpcMutatingMethods[method]) {
+		return nil, status.Errorf(codes.PermissionDenied, "key %q is read-only", key.Name)
+	}
+	return &requestInfo{principal: keyPrincipal(key), role: key.Role, namespaces: key.Namespaces}, nil
+}
+
+// peerCertKey returns the identity of the verified client certificate of a call
+func peerCertKey(ctx context.Context) (APIKey, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return APIKey{}, false
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return APIKey{}, false
+	}
+	return clientCertKey(&info.State)
+}
+
+// authenticationFailed counts a call without a valid API key from source, and
+// locks the source out after too many of them
+func (s *GRPCServer) authenticationFailed(ctx context.Context, source, reason string) {
+	authenticationFailed.WithLabelValues(apiGRPC, reason).Inc()
+	if s.config.Lockout.Failed(source) {
+		lockouts.WithLabelValues(apiGRPC).Inc()
+		log.FromContext(ctx).Info("locking out source after repeated failed authentications", "source", source)
+	}
+}
+
+// record writes an audit event for a mutating call
+func (s *GRPCServer) record(ctx context.Context, action, namespace, name, model string, err error) {
+	event := audit.Event{
+		Source:    audit.SourceGRPC,
+		Action:    action,
+		Principal: principalFromContext(ctx),
+		Namespace: namespace,
+		Name:      name,
+		Model:     model,
+	}
+	event.SourceIP = s.sourceIP(ctx)
+	s.audit.Record(ctx, event.WithError(err))
+}
+
+// grpcError maps Kubernetes API errors to gRPC status errors
+func grpcError(err error) error {
+	switch {
+	case apierrors.IsNotFound(err):
+		return status.Error(codes.NotFound, err.Error())
+	case apierrors.IsAlreadyExists(err):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case apierrors.IsConflict(err):
+		return status.Error(codes.Aborted, err.Error())
+	case apierrors.IsInvalid(err), apierrors.IsBadRequest(err):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case apierrors.IsForbidden(err):
+		return status.Error(codes.PermissionDenied, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// convertModelToProto converts an OllamaModel to its gRPC representation
+func convertModelToProto(model ollamav1alpha1.OllamaModel) *grpcv1.Model {
+	response := convertModelToResponse(model)
+	return &grpcv1.Model{
+		Name:          response.Name,
+		Namespace:     response.Namespace,
+		ModelName:     response.ModelName,
+		Tag:           response.Tag,
+		State:         response.State,
+		Size:          response.Size,
+		FormattedSize: response.FormattedSize,
+		LastPullTime:  response.LastPullTime,
+		Error:         response.Error,
+	}
+}
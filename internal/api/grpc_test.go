@@ -0,0 +1,215 @@
+package api
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	grpcv1 "github.com/dmk/ollama-operator/api/grpc/v1"
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+// fakeServerStream is a server stream carrying only a context
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+var _ = Describe("gRPC Server", func() {
+	var listener *bufconn.Listener
+	var server *GRPCServer
+	var conn *grpc.ClientConn
+	var modelClient grpcv1.ModelServiceClient
+
+	BeforeEach(func() {
+		// The registry publishes the q8_0 quantizations only
+		registryServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !strings.HasSuffix(r.URL.Path, "-q8_0") {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Docker-Content-Digest", "sha256:abc")
+			_, _ = w.Write([]byte(`{"config":{"size":100},"layers":[{"size":1000}]}`))
+		}))
+		DeferCleanup(registryServer.Close)
+
+		listener = bufconn.Listen(1024 * 1024)
+		server = NewGRPCServer(Config{Namespace: "default", APIKey: "secret", RegistryURLs: []string{registryServer.URL}}, newFakeClient())
+		go func() { _ = server.server.Serve(listener) }()
+
+		var err error
+		conn, err = grpc.NewClient("passthrough:///bufnet",
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return listener.DialContext(ctx)
+			}),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+		)
+		Expect(err).NotTo(HaveOccurred())
+		modelClient = grpcv1.NewModelServiceClient(conn)
+	})
+
+	AfterEach(func() {
+		Expect(conn.Close()).To(Succeed())
+		server.server.Stop()
+	})
+
+	authed := func() context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "secret")
+	}
+
+	It("rejects calls without a valid API key", func() {
+		_, err := modelClient.ListModels(context.Background(), &grpcv1.ListModelsRequest{})
+		Expect(status.Code(err)).To(Equal(codes.Unauthenticated))
+	})
+
+	It("refuses mutating calls when read-only", func() {
+		server.config.ReadOnly = true
+		_, err := modelClient.CreateModel(authed(), &grpcv1.CreateModelRequest{Name: "phi3", Tag: "mini"})
+		Expect(status.Code(err)).To(Equal(codes.Unimplemented))
+		_, err = modelClient.ListModels(authed(), &grpcv1.ListModelsRequest{})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("creates, gets and lists models", func() {
+		created, err := modelClient.CreateModel(authed(), &grpcv1.CreateModelRequest{Name: "phi3", Tag: "mini"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(created.GetName()).To(Equal("phi3-mini"))
+		Expect(created.GetNamespace()).To(Equal("default"))
+
+		got, err := modelClient.GetModel(authed(), &grpcv1.GetModelRequest{Name: "phi3-mini"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(got.GetModelName()).To(Equal("phi3"))
+
+		list, err := modelClient.ListModels(authed(), &grpcv1.ListModelsRequest{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(list.GetItems()).To(HaveLen(1))
+
+		_, err = modelClient.CreateModel(authed(), &grpcv1.CreateModelRequest{Name: "phi3", Tag: "mini"})
+		Expect(status.Code(err)).To(Equal(codes.AlreadyExists))
+	})
+
+	It("creates models with the fields of the HTTP API", func() {
+		dimensions := int32(768)
+		created, err := modelClient.CreateModel(authed(), &grpcv1.CreateModelRequest{
+			Name:               "nomic-embed-text",
+			Tag:                "v1.5",
+			Quantization:       "q8_0",
+			Parameters:         map[string]string{"num_ctx": "8192"},
+			Type:               string(ollamav1alpha1.ModelTypeEmbedding),
+			ExpectedDimensions: &dimensions,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(created.GetName()).To(Equal("nomic-embed-text-v1.5-q8-0"))
+
+		model := &ollamav1alpha1.OllamaModel{}
+		Expect(server.client.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: created.GetName()}, model)).To(Succeed())
+		Expect(model.Spec.Quantization).To(Equal("q8_0"))
+		Expect(model.Spec.Parameters).To(HaveKeyWithValue("num_ctx", "8192"))
+		Expect(model.Spec.Type).To(Equal(ollamav1alpha1.ModelTypeEmbedding))
+		Expect(model.Spec.ExpectedDimensions).To(HaveValue(BeEquivalentTo(768)))
+		Expect(model.Annotations).To(HaveKeyWithValue(ollamav1alpha1.CreatedByAnnotation, "apikey:default"))
+	})
+
+	It("validates the fields of created models", func() {
+		_, err := modelClient.CreateModel(authed(), &grpcv1.CreateModelRequest{Name: "phi3", Tag: "mini", Quantization: "q4 K"})
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+	})
+
+	It("rejects a second model managing the same Ollama model", func() {
+		Expect(server.client.Create(context.Background(), &ollamav1alpha1.OllamaModel{
+			ObjectMeta: metav1.ObjectMeta{Name: "phi3-small", Namespace: "default"},
+			Spec:       ollamav1alpha1.OllamaModelSpec{Name: "phi3", Tag: "mini"},
+		})).To(Succeed())
+
+		_, err := modelClient.CreateModel(authed(), &grpcv1.CreateModelRequest{Name: "registry.ollama.ai/library/phi3", Tag: "mini"})
+		Expect(status.Code(err)).To(Equal(codes.AlreadyExists))
+		Expect(status.Convert(err).Message()).To(ContainSubstring("already managed by phi3-small"))
+	})
+
+	It("rejects a quantization missing from the registries", func() {
+		_, err := modelClient.CreateModel(authed(), &grpcv1.CreateModelRequest{Name: "llama3.2", Tag: "1b", Quantization: "q4_K_M"})
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		Expect(status.Convert(err).Message()).To(ContainSubstring("not found in any registry"))
+
+		_, err = modelClient.CreateModel(authed(), &grpcv1.CreateModelRequest{Name: "llama3.2", Tag: "1b", Quantization: "q8_0"})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("records the principal of streaming calls in their context", func() {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-api-key", "secret"))
+		var principal string
+		var role Role
+		err := server.authStreamInterceptor(nil, &fakeServerStream{ctx: ctx},
+			&grpc.StreamServerInfo{FullMethod: grpcv1.ModelService_WatchProgress_FullMethodName},
+			func(_ interface{}, stream grpc.ServerStream) error {
+				principal = principalFromContext(stream.Context())
+				role = roleFromContext(stream.Context())
+				return nil
+			})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(principal).To(Equal("apikey:default"))
+		Expect(role).To(Equal(RoleAdmin))
+	})
+
+	It("returns NotFound for unknown models", func() {
+		_, err := modelClient.GetModel(authed(), &grpcv1.GetModelRequest{Name: "missing"})
+		Expect(status.Code(err)).To(Equal(codes.NotFound))
+	})
+
+	It("refuses namespaces the key is not restricted to", func() {
+		keyring := NewKeyring("", true)
+		Expect(keyring.LoadSecret(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "api-keys",
+				Annotations: map[string]string{
+					APIKeyRolesAnnotation:      "ci=admin",
+					APIKeyNamespacesAnnotation: "ci=team-a",
+				},
+			},
+			Data: map[string][]byte{"ci": []byte("secret")},
+		})).To(Succeed())
+		server.keyring = keyring
+
+		_, err := modelClient.CreateModel(authed(), &grpcv1.CreateModelRequest{Namespace: "team-a", Name: "phi3", Tag: "mini"})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = modelClient.CreateModel(authed(), &grpcv1.CreateModelRequest{Namespace: "team-b", Name: "phi3", Tag: "mini"})
+		Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+		_, err = modelClient.ListModels(authed(), &grpcv1.ListModelsRequest{})
+		Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+
+		stream, err := modelClient.WatchProgress(authed(), &grpcv1.WatchProgressRequest{Namespace: "team-b", Name: "phi3-mini"})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = stream.Recv()
+		Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+	})
+
+	It("refuses namespaces the operator does not watch", func() {
+		server.config.WatchNamespaces = []string{"default"}
+		_, err := modelClient.ListModels(authed(), &grpcv1.ListModelsRequest{Namespace: "team-b"})
+		Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+		_, err = modelClient.GetModel(authed(), &grpcv1.GetModelRequest{Namespace: "team-b", Name: "phi3-mini"})
+		Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+		_, err = modelClient.ListModels(authed(), &grpcv1.ListModelsRequest{})
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
@@ -1,16 +1,22 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
@@ -18,21 +24,103 @@ import (
 
 // ModelRequest represents the payload for creating a model
 type ModelRequest struct {
-	Name string `json:"name"`
-	Tag  string `json:"tag"`
+	Name               string            `json:"name"`
+	Tag                string            `json:"tag"`
+	Quantization       string            `json:"quantization,omitempty"`
+	ModelRef           string            `json:"modelRef,omitempty"`
+	Parameters         map[string]string `json:"parameters,omitempty"`
+	System             string            `json:"system,omitempty"`
+	Template           string            `json:"template,omitempty"`
+	Type               string            `json:"type,omitempty"`
+	ExpectedDimensions *int32            `json:"expectedDimensions,omitempty"`
+}
+
+// spec returns the OllamaModel spec requested
+func (req ModelRequest) spec() ollamav1alpha1.OllamaModelSpec {
+	var spec ollamav1alpha1.OllamaModelSpec
+	req.apply(&spec)
+	return spec
+}
+
+// apply sets the fields of spec the request carries, leaving the others, such
+// as the limits or the export of a model, as they are
+func (req ModelRequest) apply(spec *ollamav1alpha1.OllamaModelSpec) {
+	spec.Name = req.Name
+	spec.Tag = req.Tag
+	spec.Quantization = req.Quantization
+	spec.ModelRef = req.ModelRef
+	spec.Parameters = req.Parameters
+	spec.System = req.System
+	spec.Template = req.Template
+	spec.Type = ollamav1alpha1.ModelType(req.Type)
+	spec.ExpectedDimensions = req.ExpectedDimensions
 }
 
 // ModelResponse represents the API response for a model
 type ModelResponse struct {
-	Name          string `json:"name"`
-	Namespace     string `json:"namespace"`
-	ModelName     string `json:"modelName"`
-	Tag           string `json:"tag"`
-	State         string `json:"state"`
-	Size          int64  `json:"size,omitempty"`
-	FormattedSize string `json:"formattedSize,omitempty"`
-	LastPullTime  string `json:"lastPullTime,omitempty"`
-	Error         string `json:"error,omitempty"`
+	Name                string              `json:"name"`
+	Namespace           string              `json:"namespace"`
+	ModelName           string              `json:"modelName"`
+	Tag                 string              `json:"tag"`
+	Quantization        string              `json:"quantization,omitempty"`
+	ModelRef            string              `json:"modelRef,omitempty"`
+	Parameters          map[string]string   `json:"parameters,omitempty"`
+	System              string              `json:"system,omitempty"`
+	Template            string              `json:"template,omitempty"`
+	Type                string              `json:"type,omitempty"`
+	ExpectedDimensions  *int32              `json:"expectedDimensions,omitempty"`
+	EmbeddingDimensions int32               `json:"embeddingDimensions,omitempty"`
+	DerivedModel        string              `json:"derivedModel,omitempty"`
+	State               string              `json:"state"`
+	Progress            *ProgressResponse   `json:"progress,omitempty"`
+	Conditions          []ConditionResponse `json:"conditions,omitempty"`
+	Digest              string              `json:"digest,omitempty"`
+	Size                int64               `json:"size,omitempty"`
+	FormattedSize       string              `json:"formattedSize,omitempty"`
+	LastPullTime        string              `json:"lastPullTime,omitempty"`
+	Error               string              `json:"error,omitempty"`
+	FailureReason       string              `json:"failureReason,omitempty"`
+	PullAttempts        int32               `json:"pullAttempts,omitempty"`
+	LastFailureTime     string              `json:"lastFailureTime,omitempty"`
+	CreatedBy           string              `json:"createdBy,omitempty"`
+	RefreshedBy         string              `json:"refreshedBy,omitempty"`
+	Endpoints           []EndpointResponse  `json:"endpoints,omitempty"`
+	OperationID         string              `json:"operationId,omitempty"`
+}
+
+// EndpointResponse represents a model on one Ollama server
+type EndpointResponse struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	State  string `json:"state,omitempty"`
+	Digest string `json:"digest,omitempty"`
+}
+
+// ConditionResponse represents a condition of a model, such as Ready or Stale
+type ConditionResponse struct {
+	Type               string `json:"type"`
+	Status             string `json:"status"`
+	Reason             string `json:"reason,omitempty"`
+	Message            string `json:"message,omitempty"`
+	LastTransitionTime string `json:"lastTransitionTime,omitempty"`
+}
+
+// ProgressResponse is the download progress of a model being pulled
+type ProgressResponse struct {
+	Percent          int32  `json:"percent"`
+	CompletedBytes   int64  `json:"completedBytes,omitempty"`
+	TotalBytes       int64  `json:"totalBytes,omitempty"`
+	BytesPerSecond   int64  `json:"bytesPerSecond,omitempty"`
+	RemainingSeconds int64  `json:"remainingSeconds,omitempty"`
+	Message          string `json:"message,omitempty"`
+}
+
+// PullInProgressResponse is the body of the 409 Conflict returned when a
+// refresh is requested for a model that is already being pulled
+type PullInProgressResponse struct {
+	ErrorResponse
+	State    string            `json:"state"`
+	Progress *ProgressResponse `json:"progress,omitempty"`
 }
 
 // ModelListResponse represents the API response for listing models
@@ -44,15 +132,53 @@ type ModelListResponse struct {
 func (s *Server) listModels(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	logger := log.FromContext(ctx).WithName("api-listModels")
+	namespace := s.namespaceFor(r)
+
+	if watch, _ := strconv.ParseBool(r.URL.Query().Get("watch")); watch {
+		s.watchModels(w, r, namespace)
+		return
+	}
 
-	// List all OllamaModel resources in the configured namespace
+	manifest, err := wantsManifest(r)
+	if err != nil {
+		sendError(w, err, http.StatusBadRequest)
+		return
+	}
+	fields, err := parseFields(r)
+	if err != nil {
+		sendError(w, err, http.StatusBadRequest)
+		return
+	}
+	if manifest && fields != nil {
+		sendError(w, fmt.Errorf("fields cannot be combined with output=%s", outputManifest), http.StatusBadRequest)
+		return
+	}
+
+	// List all OllamaModel resources in the requested namespace
 	var modelList ollamav1alpha1.OllamaModelList
-	if err := s.client.List(ctx, &modelList, client.InNamespace(s.config.Namespace)); err != nil {
+	if err := s.client.List(ctx, &modelList, client.InNamespace(namespace)); err != nil {
 		logger.Error(err, "failed to list models")
 		sendError(w, err, http.StatusInternalServerError)
 		return
 	}
 
+	if notModified(w, r, modelListETag(r, namespace, modelList.Items)) {
+		return
+	}
+
+	if manifest {
+		manifests := ModelManifestList{
+			APIVersion: "v1",
+			Kind:       "List",
+			Items:      make([]ModelManifest, len(modelList.Items)),
+		}
+		for i, model := range modelList.Items {
+			manifests.Items[i] = convertModelToManifest(model)
+		}
+		sendResponse(w, r, manifests, http.StatusOK)
+		return
+	}
+
 	// Convert to API response
 	response := ModelListResponse{
 		Items: make([]ModelResponse, len(modelList.Items)),
@@ -62,7 +188,19 @@ func (s *Server) listModels(w http.ResponseWriter, r *http.Request) {
 		response.Items[i] = convertModelToResponse(model)
 	}
 
-	sendJSON(w, response, http.StatusOK)
+	if fields != nil {
+		projected := projectedModelList{Items: make([]map[string]json.RawMessage, len(response.Items))}
+		for i, item := range response.Items {
+			if projected.Items[i], err = projectModel(item, fields); err != nil {
+				sendError(w, err, http.StatusInternalServerError)
+				return
+			}
+		}
+		sendResponse(w, r, projected, http.StatusOK)
+		return
+	}
+
+	sendResponse(w, r, response, http.StatusOK)
 }
 
 // getModel handles the GET /api/v1/models/{name} endpoint
@@ -71,10 +209,26 @@ func (s *Server) getModel(w http.ResponseWriter, r *http.Request) {
 	logger := log.FromContext(ctx).WithName("api-getModel")
 	vars := mux.Vars(r)
 	name := vars["name"]
+	namespace := s.namespaceFor(r)
+
+	manifest, err := wantsManifest(r)
+	if err != nil {
+		sendError(w, err, http.StatusBadRequest)
+		return
+	}
+	fields, err := parseFields(r)
+	if err != nil {
+		sendError(w, err, http.StatusBadRequest)
+		return
+	}
+	if manifest && fields != nil {
+		sendError(w, fmt.Errorf("fields cannot be combined with output=%s", outputManifest), http.StatusBadRequest)
+		return
+	}
 
 	// Get the model by name
 	model := &ollamav1alpha1.OllamaModel{}
-	if err := s.client.Get(ctx, types.NamespacedName{Namespace: s.config.Namespace, Name: name}, model); err != nil {
+	if err := s.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, model); err != nil {
 		if apierrors.IsNotFound(err) {
 			sendError(w, fmt.Errorf("model not found: %s", name), http.StatusNotFound)
 		} else {
@@ -84,19 +238,49 @@ func (s *Server) getModel(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if notModified(w, r, modelETag(r, model)) {
+		return
+	}
+
+	if manifest {
+		sendResponse(w, r, convertModelToManifest(*model), http.StatusOK)
+		return
+	}
+
 	response := convertModelToResponse(*model)
-	sendJSON(w, response, http.StatusOK)
+	if fields != nil {
+		projected, err := projectModel(response, fields)
+		if err != nil {
+			sendError(w, err, http.StatusInternalServerError)
+			return
+		}
+		sendResponse(w, r, projected, http.StatusOK)
+		return
+	}
+
+	sendResponse(w, r, response, http.StatusOK)
 }
 
 // createModel handles the POST /api/v1/models endpoint
 func (s *Server) createModel(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	logger := log.FromContext(ctx).WithName("api-createModel")
+	namespace := s.namespaceFor(r)
+
+	dryRun := false
+	if value := r.URL.Query().Get("dryRun"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			sendError(w, fmt.Errorf("invalid dryRun: %w", err), http.StatusBadRequest)
+			return
+		}
+		dryRun = parsed
+	}
 
 	// Parse request body
-	var req ModelRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		sendError(w, fmt.Errorf("invalid request: %w", err), http.StatusBadRequest)
+	req, err := decodeModelRequest(r)
+	if err != nil {
+		sendError(w, fmt.Errorf("invalid request: %w", err), decodeErrorStatus(err))
 		return
 	}
 
@@ -105,14 +289,20 @@ func (s *Server) createModel(w http.ResponseWriter, r *http.Request) {
 		sendError(w, fmt.Errorf("name and tag are required"), http.StatusBadRequest)
 		return
 	}
+	spec := req.spec()
+	modelName := modelResourceName(req.Name, spec.QualifiedTag())
+	if err := validateModel(modelName, spec); err != nil {
+		sendError(w, err, http.StatusUnprocessableEntity)
+		return
+	}
 
 	// Check if model already exists
-	modelName := fmt.Sprintf("%s-%s", req.Name, req.Tag)
+	setAuditTarget(ctx, namespace, modelName, spec.Reference())
 	existing := &ollamav1alpha1.OllamaModel{}
-	err := s.client.Get(ctx, types.NamespacedName{Namespace: s.config.Namespace, Name: modelName}, existing)
+	err = s.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: modelName}, existing)
 	if err == nil {
 		// Model already exists
-		sendError(w, fmt.Errorf("model already exists: %s", modelName), http.StatusConflict)
+		sendError(w, withCode(CodeAlreadyExists, fmt.Errorf("model already exists: %s", modelName)), http.StatusConflict)
 		return
 	} else if !apierrors.IsNotFound(err) {
 		// Unexpected error
@@ -121,26 +311,117 @@ func (s *Server) createModel(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !s.checkUnmanaged(w, r, namespace, modelName, spec.Reference()) {
+		return
+	}
+	if !dryRun && !s.checkQuantization(w, r, spec) {
+		return
+	}
+
 	// Create new model
 	model := &ollamav1alpha1.OllamaModel{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      modelName,
-			Namespace: s.config.Namespace,
-		},
-		Spec: ollamav1alpha1.OllamaModelSpec{
-			Name: req.Name,
-			Tag:  req.Tag,
+			Namespace: namespace,
+			Labels:    s.config.modelLabels(),
 		},
+		Spec: spec,
+	}
+	annotatePrincipal(ctx, model, ollamav1alpha1.CreatedByAnnotation)
+
+	if dryRun {
+		s.dryRunCreate(w, r, model)
+		return
 	}
 
 	if err := s.client.Create(ctx, model); err != nil {
 		logger.Error(err, "failed to create model", "name", modelName)
-		sendError(w, err, http.StatusInternalServerError)
+		status := http.StatusInternalServerError
+		if apierrors.IsInvalid(err) {
+			status = http.StatusUnprocessableEntity
+		}
+		sendError(w, err, status)
 		return
 	}
 
 	response := convertModelToResponse(*model)
-	sendJSON(w, response, http.StatusCreated)
+	response.OperationID = s.startOperation(w, OperationCreate, model)
+	sendResponse(w, r, response, http.StatusCreated)
+}
+
+// applyModel handles the PUT /api/v1/models/{name} endpoint. It creates the
+// model if it does not exist and otherwise updates its spec, so that
+// provisioning scripts can declare models without racing each other.
+func (s *Server) applyModel(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := log.FromContext(ctx).WithName("api-applyModel")
+	vars := mux.Vars(r)
+	name := vars["name"]
+	namespace := s.namespaceFor(r)
+
+	req, err := decodeModelRequest(r)
+	if err != nil {
+		sendError(w, fmt.Errorf("invalid request: %w", err), decodeErrorStatus(err))
+		return
+	}
+	if req.Name == "" || req.Tag == "" {
+		sendError(w, fmt.Errorf("name and tag are required"), http.StatusBadRequest)
+		return
+	}
+	spec := req.spec()
+	if err := validateModel(name, spec); err != nil {
+		sendError(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	setAuditTarget(ctx, namespace, name, spec.Reference())
+
+	if !s.checkUnmanaged(w, r, namespace, name, spec.Reference()) {
+		return
+	}
+	if !s.checkQuantization(w, r, spec) {
+		return
+	}
+
+	model := &ollamav1alpha1.OllamaModel{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+	}
+	var result controllerutil.OperationResult
+
+	// A concurrent create or update makes CreateOrUpdate fail; start over so the
+	// request still converges on the desired spec
+	err = retry.OnError(retry.DefaultRetry, func(err error) bool {
+		return apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err)
+	}, func() error {
+		var err error
+		result, err = controllerutil.CreateOrUpdate(ctx, s.client, model, func() error {
+			req.apply(&model.Spec)
+			// Only a model that does not exist yet gets a creator and the
+			// labels of the models created through the API
+			if model.ResourceVersion == "" {
+				model.Labels = s.config.modelLabels()
+				annotatePrincipal(ctx, model, ollamav1alpha1.CreatedByAnnotation)
+			}
+			return nil
+		})
+		return err
+	})
+	if err != nil {
+		logger.Error(err, "failed to apply model", "name", name)
+		status := http.StatusInternalServerError
+		if apierrors.IsInvalid(err) {
+			status = http.StatusUnprocessableEntity
+		}
+		sendError(w, err, status)
+		return
+	}
+
+	response := convertModelToResponse(*model)
+	if result == controllerutil.OperationResultCreated {
+		response.OperationID = s.startOperation(w, OperationCreate, model)
+		sendResponse(w, r, response, http.StatusCreated)
+		return
+	}
+	sendResponse(w, r, response, http.StatusOK)
 }
 
 // deleteModel handles the DELETE /api/v1/models/{name} endpoint
@@ -149,10 +430,11 @@ func (s *Server) deleteModel(w http.ResponseWriter, r *http.Request) {
 	logger := log.FromContext(ctx).WithName("api-deleteModel")
 	vars := mux.Vars(r)
 	name := vars["name"]
+	namespace := s.namespaceFor(r)
 
 	// Get the model to ensure it exists
 	model := &ollamav1alpha1.OllamaModel{}
-	if err := s.client.Get(ctx, types.NamespacedName{Namespace: s.config.Namespace, Name: name}, model); err != nil {
+	if err := s.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, model); err != nil {
 		if apierrors.IsNotFound(err) {
 			sendError(w, fmt.Errorf("model not found: %s", name), http.StatusNotFound)
 		} else {
@@ -162,6 +444,8 @@ func (s *Server) deleteModel(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	setAuditTarget(ctx, namespace, name, modelReference(model))
+
 	// Delete the model
 	if err := s.client.Delete(ctx, model); err != nil {
 		logger.Error(err, "failed to delete model", "name", name)
@@ -179,10 +463,11 @@ func (s *Server) refreshModel(w http.ResponseWriter, r *http.Request) {
 	logger := log.FromContext(ctx).WithName("api-refreshModel")
 	vars := mux.Vars(r)
 	name := vars["name"]
+	namespace := s.namespaceFor(r)
 
 	// Get the model
 	model := &ollamav1alpha1.OllamaModel{}
-	if err := s.client.Get(ctx, types.NamespacedName{Namespace: s.config.Namespace, Name: name}, model); err != nil {
+	if err := s.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, model); err != nil {
 		if apierrors.IsNotFound(err) {
 			sendError(w, fmt.Errorf("model not found: %s", name), http.StatusNotFound)
 		} else {
@@ -192,11 +477,22 @@ func (s *Server) refreshModel(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Add the refresh annotation
-	if model.Annotations == nil {
-		model.Annotations = make(map[string]string)
+	setAuditTarget(ctx, namespace, name, modelReference(model))
+
+	// Refreshing a model being pulled would only download it again
+	if pullInProgress(model) {
+		err := &codedError{code: CodePullInProgress, retryable: true, err: pullInProgressError(model)}
+		sendJSON(w, PullInProgressResponse{
+			ErrorResponse: newErrorResponse(w, err, http.StatusConflict),
+			State:         string(model.Status.State),
+			Progress:      convertProgress(model.Status.Progress),
+		}, http.StatusConflict)
+		return
 	}
-	model.Annotations["ollama.smithforge.dev/refresh"] = "true"
+
+	// Add the refresh annotation
+	requestRefresh(model)
+	annotatePrincipal(ctx, model, ollamav1alpha1.RefreshedByAnnotation)
 
 	// Update the model
 	if err := s.client.Update(ctx, model); err != nil {
@@ -206,25 +502,268 @@ func (s *Server) refreshModel(w http.ResponseWriter, r *http.Request) {
 	}
 
 	response := convertModelToResponse(*model)
-	sendJSON(w, response, http.StatusAccepted)
+	response.OperationID = s.startOperation(w, OperationRefresh, model)
+	sendResponse(w, r, response, http.StatusAccepted)
+}
+
+// retryModel asks the controller to pull a Failed model again, even when its
+// retries are exhausted or its failure is terminal
+func (s *Server) retryModel(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := log.FromContext(ctx).WithName("api-retryModel")
+	vars := mux.Vars(r)
+	name := vars["name"]
+	namespace := s.namespaceFor(r)
+
+	model := &ollamav1alpha1.OllamaModel{}
+	if err := s.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, model); err != nil {
+		if apierrors.IsNotFound(err) {
+			sendError(w, fmt.Errorf("model not found: %s", name), http.StatusNotFound)
+		} else {
+			logger.Error(err, "failed to get model", "name", name)
+			sendError(w, err, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	setAuditTarget(ctx, namespace, name, modelReference(model))
+
+	if model.Status.State != ollamav1alpha1.StateFailed {
+		sendError(w, fmt.Errorf("model %s is %s, only Failed models can be retried", name, model.Status.State), http.StatusConflict)
+		return
+	}
+
+	if model.Annotations == nil {
+		model.Annotations = make(map[string]string)
+	}
+	model.Annotations[ollamav1alpha1.RetryAnnotation] = "true"
+
+	if err := s.client.Update(ctx, model); err != nil {
+		logger.Error(err, "failed to update model with retry annotation", "name", name)
+		sendError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	sendResponse(w, r, convertModelToResponse(*model), http.StatusAccepted)
+}
+
+// cancelPull asks the controller to cancel the running pull or refresh of a
+// model
+func (s *Server) cancelPull(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := log.FromContext(ctx).WithName("api-cancelPull")
+	vars := mux.Vars(r)
+	name := vars["name"]
+	namespace := s.namespaceFor(r)
+
+	model := &ollamav1alpha1.OllamaModel{}
+	if err := s.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, model); err != nil {
+		if apierrors.IsNotFound(err) {
+			sendError(w, fmt.Errorf("model not found: %s", name), http.StatusNotFound)
+		} else {
+			logger.Error(err, "failed to get model", "name", name)
+			sendError(w, err, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	setAuditTarget(ctx, namespace, name, modelReference(model))
+
+	switch model.Status.State {
+	case "", ollamav1alpha1.StatePending, ollamav1alpha1.StatePulling:
+	default:
+		if !model.RefreshRequested() {
+			sendError(w, fmt.Errorf("model %s is %s, there is no pull to cancel", name, model.Status.State), http.StatusConflict)
+			return
+		}
+	}
+
+	if model.Annotations == nil {
+		model.Annotations = make(map[string]string)
+	}
+	model.Annotations[ollamav1alpha1.CancelPullAnnotation] = "true"
+
+	if err := s.client.Update(ctx, model); err != nil {
+		logger.Error(err, "failed to update model with cancel-pull annotation", "name", name)
+		sendError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	sendResponse(w, r, convertModelToResponse(*model), http.StatusAccepted)
+}
+
+// alreadyManagedError reports an Ollama model reference that another
+// OllamaModel of the namespace already manages
+type alreadyManagedError struct {
+	reference string
+	manager   string
+}
+
+func (e *alreadyManagedError) Error() string {
+	return fmt.Sprintf("model %s is already managed by %s", e.reference, e.manager)
+}
+
+// checkUnmanaged returns an alreadyManagedError if a model other than name
+// already manages the Ollama model reference in namespace
+func checkUnmanaged(ctx context.Context, c client.Client, namespace, name, reference string) error {
+	var models ollamav1alpha1.OllamaModelList
+	if err := c.List(ctx, &models, client.InNamespace(namespace),
+		client.MatchingFields{ollamav1alpha1.ModelReferenceField: ollamav1alpha1.NormalizeModelReference(reference)}); err != nil {
+		log.FromContext(ctx).Error(err, "failed to look up models managing reference", "reference", reference)
+		return err
+	}
+
+	for _, model := range models.Items {
+		if model.Name != name {
+			return &alreadyManagedError{reference: reference, manager: model.Name}
+		}
+	}
+	return nil
+}
+
+// checkUnmanaged sends a conflict and returns false if a model other than name
+// already manages the Ollama model reference in namespace
+func (s *Server) checkUnmanaged(w http.ResponseWriter, r *http.Request, namespace, name, reference string) bool {
+	err := checkUnmanaged(r.Context(), s.client, namespace, name, reference)
+	var managed *alreadyManagedError
+	switch {
+	case errors.As(err, &managed):
+		sendError(w, err, http.StatusConflict)
+		return false
+	case err != nil:
+		sendError(w, err, http.StatusInternalServerError)
+		return false
+	}
+	return true
+}
+
+// resourceNameReplacer replaces the characters of model references that
+// resource names cannot hold
+var resourceNameReplacer = strings.NewReplacer("_", "-", "/", "-", ":", "-")
+
+// modelResourceName returns the OllamaModel resource name for a model name and
+// tag. Tags such as "8b-q4_K_M" are lowercased and underscores replaced, since
+// resource names only allow lowercase letters, digits, dashes and dots. Names
+// are shortened as Ollama lists them first, so "registry.ollama.ai/library/phi3"
+// manages the same model as "phi3", while "registry.example.com/team/phi3"
+// becomes "registry.example.com-team-phi3".
+func modelResourceName(name, tag string) string {
+	name = ollamav1alpha1.ParseModelReference(name).Name()
+	return resourceNameReplacer.Replace(strings.ToLower(fmt.Sprintf("%s-%s", name, tag)))
+}
+
+// modelReference returns the Ollama model reference (name:tag) of a model
+func modelReference(model *ollamav1alpha1.OllamaModel) string {
+	return model.Spec.Reference()
+}
+
+// requestRefresh sets the annotation asking the controller to re-pull a model
+// to the current time, a value the controller has not handled yet
+func requestRefresh(model *ollamav1alpha1.OllamaModel) {
+	if model.Annotations == nil {
+		model.Annotations = make(map[string]string)
+	}
+	model.Annotations[ollamav1alpha1.RefreshAnnotation] = time.Now().UTC().Format(time.RFC3339Nano)
+}
+
+// pullInProgress reports whether a model is being pulled or about to be,
+// including a refresh the controller has not handled yet
+func pullInProgress(model *ollamav1alpha1.OllamaModel) bool {
+	switch model.Status.State {
+	case "", ollamav1alpha1.StatePending, ollamav1alpha1.StatePulling:
+		return true
+	}
+	return model.RefreshRequested()
+}
+
+// pullInProgressError describes the pull in progress of a model
+func pullInProgressError(model *ollamav1alpha1.OllamaModel) error {
+	if p := model.Status.Progress; p != nil && model.Status.State == ollamav1alpha1.StatePulling {
+		return fmt.Errorf("model %s is already being pulled (%d%% done), refresh it once the pull completes", model.Name, p.Percent)
+	}
+	return fmt.Errorf("model %s is already being pulled, refresh it once the pull completes", model.Name)
+}
+
+// convertProgress converts the download progress of a model, if any
+func convertProgress(progress *ollamav1alpha1.PullProgress) *ProgressResponse {
+	if progress == nil {
+		return nil
+	}
+	return &ProgressResponse{
+		Percent:          progress.Percent,
+		CompletedBytes:   progress.CompletedBytes,
+		TotalBytes:       progress.TotalBytes,
+		BytesPerSecond:   progress.BytesPerSecond,
+		RemainingSeconds: progress.RemainingSeconds,
+		Message:          progress.Message,
+	}
+}
+
+// annotatePrincipal records the authenticated principal of the request in an
+// annotation of model, so that models on a shared cluster can be traced back
+// to whoever created or refreshed them
+func annotatePrincipal(ctx context.Context, model *ollamav1alpha1.OllamaModel, annotation string) {
+	principal := principalFromContext(ctx)
+	if principal == "" {
+		return
+	}
+	if model.Annotations == nil {
+		model.Annotations = make(map[string]string)
+	}
+	model.Annotations[annotation] = principal
 }
 
 // convertModelToResponse converts an OllamaModel to a ModelResponse
 func convertModelToResponse(model ollamav1alpha1.OllamaModel) ModelResponse {
 	response := ModelResponse{
-		Name:          model.Name,
-		Namespace:     model.Namespace,
-		ModelName:     model.Spec.Name,
-		Tag:           model.Spec.Tag,
-		State:         string(model.Status.State),
-		Size:          model.Status.Size,
-		FormattedSize: model.Status.FormattedSize,
-		Error:         model.Status.Error,
+		Name:                model.Name,
+		Namespace:           model.Namespace,
+		ModelName:           model.Spec.Name,
+		Tag:                 model.Spec.Tag,
+		Quantization:        model.Spec.Quantization,
+		ModelRef:            model.Spec.ModelRef,
+		Parameters:          model.Spec.Parameters,
+		System:              model.Spec.System,
+		Template:            model.Spec.Template,
+		Type:                string(model.Spec.Type),
+		ExpectedDimensions:  model.Spec.ExpectedDimensions,
+		EmbeddingDimensions: model.Status.EmbeddingDimensions,
+		DerivedModel:        model.Status.DerivedModel,
+		State:               string(model.Status.State),
+		Progress:            convertProgress(model.Status.Progress),
+		Digest:              model.Status.Digest,
+		Size:                model.Status.Size,
+		FormattedSize:       model.Status.FormattedSize,
+		Error:               model.Status.Error,
+		FailureReason:       string(model.Status.FailureReason),
+		PullAttempts:        model.Status.PullAttempts,
+		CreatedBy:           model.Annotations[ollamav1alpha1.CreatedByAnnotation],
+		RefreshedBy:         model.Annotations[ollamav1alpha1.RefreshedByAnnotation],
 	}
 
 	if model.Status.LastPullTime != nil {
 		response.LastPullTime = model.Status.LastPullTime.Format(time.RFC3339)
 	}
+	if model.Status.LastFailureTime != nil {
+		response.LastFailureTime = model.Status.LastFailureTime.Format(time.RFC3339)
+	}
+	for _, condition := range model.Status.Conditions {
+		response.Conditions = append(response.Conditions, ConditionResponse{
+			Type:               condition.Type,
+			Status:             string(condition.Status),
+			Reason:             condition.Reason,
+			Message:            condition.Message,
+			LastTransitionTime: condition.LastTransitionTime.Format(time.RFC3339),
+		})
+	}
+	for _, endpoint := range model.Status.Endpoints {
+		response.Endpoints = append(response.Endpoints, EndpointResponse{
+			Name:   endpoint.Name,
+			URL:    endpoint.URL,
+			State:  string(endpoint.State),
+			Digest: endpoint.Digest,
+		})
+	}
 
 	return response
 }
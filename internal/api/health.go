@@ -0,0 +1,79 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// HealthResponse represents the API response for the health endpoint
+type HealthResponse struct {
+	Status     string                     `json:"status"`
+	Components map[string]ComponentHealth `json:"components"`
+}
+
+// ComponentHealth describes the state of a part of the operator
+type ComponentHealth struct {
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+const (
+	statusDegraded = "degraded"
+
+	leaderDetail  = "leader"
+	standbyDetail = "standby, waiting for the leader election"
+)
+
+// healthCheck handles the health endpoint. It breaks the health of the
+// operator down by component: the Kubernetes informer cache, the Ollama
+// server, and, when the controllers run in this process, the leader election
+// and the pulls in flight. It always answers 200 so that it can keep serving
+// as a liveness probe; a failing component makes the status degraded.
+func (s *Server) healthCheck(w http.ResponseWriter, r *http.Request) {
+	response := HealthResponse{
+		Status: statusOK,
+		Components: map[string]ComponentHealth{
+			"kubernetes": componentHealth(s.checkCacheSync(r.Context())),
+			"ollama":     componentHealth(s.checkOllama(r.Context())),
+		},
+	}
+	if s.config.PullQueue != nil {
+		response.Components["leader"] = s.checkLeader()
+		response.Components["pulls"] = s.checkPulls()
+	}
+
+	for _, component := range response.Components {
+		if component.Status != statusOK {
+			response.Status = statusDegraded
+		}
+	}
+
+	sendJSON(w, response, http.StatusOK)
+}
+
+// checkLeader reports whether this replica won the leader election. A standby
+// replica is healthy: it only waits to take over.
+func (s *Server) checkLeader() ComponentHealth {
+	if s.config.Elected != nil {
+		select {
+		case <-s.config.Elected:
+		default:
+			return ComponentHealth{Status: statusOK, Detail: standbyDetail}
+		}
+	}
+	return ComponentHealth{Status: statusOK, Detail: leaderDetail}
+}
+
+// checkPulls counts the pulls running and waiting for a free pull slot. Only
+// the leader pulls, so a standby replica counts none.
+func (s *Server) checkPulls() ComponentHealth {
+	running := s.config.PullQueue.Active()
+	queued := len(s.config.PullQueue.Queue())
+	return ComponentHealth{Status: statusOK, Detail: fmt.Sprintf("%d running, %d queued", running, queued)}
+}
+
+// componentHealth converts the result of a dependency check
+func componentHealth(dep DependencyStatus) ComponentHealth {
+	return ComponentHealth{Status: dep.Status, Error: dep.Error}
+}
@@ -0,0 +1,242 @@
+package api
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Role is the access level granted to an API key
+type Role string
+
+const (
+	// RoleReadOnly may only list and get resources
+	RoleReadOnly Role = "read-only"
+	// RoleAdmin may perform every operation
+	RoleAdmin Role = "admin"
+)
+
+// APIKeyRolesAnnotation maps key names to roles on an API keys Secret,
+// e.g. "ci=admin,dashboard=read-only". Unlisted keys are read-only.
+const APIKeyRolesAnnotation = "ollama.smithforge.dev/api-key-roles"
+
+// APIKeyNamespacesAnnotation restricts key names to namespaces on an API keys
+// Secret, e.g. "ci=team-a|team-b,dashboard=team-c". Unlisted keys may reach
+// every namespace.
+const APIKeyNamespacesAnnotation = "ollama.smithforge.dev/api-key-namespaces"
+
+const (
+	// defaultKeyName is the name of the key given with --api-server-key
+	defaultKeyName = "default"
+	// readOnlyKeyName is the name of the key given with --api-server-read-only-key
+	readOnlyKeyName = "read-only"
+	// anonymousPrincipal identifies requests when authentication is disabled
+	anonymousPrincipal = "anonymous"
+	// keyPrincipalPrefix prefixes the name of the key of API key principals
+	keyPrincipalPrefix = "apikey:"
+)
+
+// APIKey is a named API key and the role it grants
+type APIKey struct {
+	Name  string
+	Value string
+	Role  Role
+	// Namespaces are the namespaces the key may reach; empty allows every
+	// namespace
+	Namespaces []string
+}
+
+// Keyring holds the API keys accepted by the API servers. It is safe for
+// concurrent use and may be reloaded while serving.
+type Keyring struct {
+	mu         sync.RWMutex
+	static     []APIKey
+	dynamic    []APIKey
+	fromSecret bool
+}
+
+// NewKeyring creates a keyring. apiKey, if set, is always accepted with the
+// admin role. When fromSecret is set, authentication is required even before
+// the keys Secret has been loaded.
+func NewKeyring(apiKey string, fromSecret bool) *Keyring {
+	k := &Keyring{fromSecret: fromSecret}
+	if apiKey != "" {
+		k.static = []APIKey{{Name: defaultKeyName, Value: apiKey, Role: RoleAdmin}}
+	}
+	return k
+}
+
+// AddReadOnlyKey makes the keyring accept value with the read-only role, for
+// clients such as dashboards that only look at models
+func (k *Keyring) AddReadOnlyKey(value string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.static = append(k.static, APIKey{Name: readOnlyKeyName, Value: value, Role: RoleReadOnly})
+}
+
+// Enabled reports whether authentication is required. It is once any key is
+// configured, or a keys Secret is in use, even if it is currently empty.
+func (k *Keyring) Enabled() bool {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.fromSecret || len(k.static) > 0
+}
+
+// Authenticate returns the key matching value
+func (k *Keyring) Authenticate(value string) (APIKey, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	var match APIKey
+	found := false
+	// Compare against every key so timing does not reveal which one matched
+	for _, keys := range [][]APIKey{k.static, k.dynamic} {
+		for _, key := range keys {
+			if subtle.ConstantTimeCompare([]byte(value), []byte(key.Value)) == 1 && !found {
+				match = key
+				found = true
+			}
+		}
+	}
+	return match, found
+}
+
+// KeyNamespaces returns whether value is a valid key, and the namespaces it may
+// reach, for the inference gateway accepting the keys of the API servers
+func (k *Keyring) KeyNamespaces(value string) ([]string, bool) {
+	key, ok := k.Authenticate(value)
+	return key.Namespaces, ok
+}
+
+// LoadSecret replaces the Secret-provided keys with the contents of secret.
+// Each data entry is a key named after its entry; roles come from the
+// APIKeyRolesAnnotation and namespaces from the APIKeyNamespacesAnnotation.
+// A nil secret removes all Secret-provided keys.
+func (k *Keyring) LoadSecret(secret *corev1.Secret) error {
+	var keys []APIKey
+	var errs []string
+
+	if secret != nil {
+		roles, err := parseRoles(secret.Annotations[APIKeyRolesAnnotation])
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+		namespaces, err := parseNamespaces(secret.Annotations[APIKeyNamespacesAnnotation])
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+
+		names := make([]string, 0, len(secret.Data))
+		for name := range secret.Data {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			value := strings.TrimSpace(string(secret.Data[name]))
+			if value == "" {
+				errs = append(errs, fmt.Sprintf("key %q is empty", name))
+				continue
+			}
+			role, ok := roles[name]
+			if !ok {
+				role = RoleReadOnly
+			}
+			// A key restricted by an invalid entry would otherwise reach every namespace
+			if allowed, listed := namespaces[name]; listed && len(allowed) == 0 {
+				errs = append(errs, fmt.Sprintf("key %q is left out, its namespaces are invalid", name))
+				continue
+			}
+			keys = append(keys, APIKey{Name: name, Value: value, Role: role, Namespaces: namespaces[name]})
+		}
+	}
+
+	k.mu.Lock()
+	k.dynamic = keys
+	k.mu.Unlock()
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid API keys secret: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// keyPrincipal returns the principal name recorded for requests made with key
+func keyPrincipal(key APIKey) string {
+	return keyPrincipalPrefix + key.Name
+}
+
+// principalKey returns the name of the key of an API key principal, or the
+// principal itself for the anonymous one
+func principalKey(principal string) string {
+	return strings.TrimPrefix(principal, keyPrincipalPrefix)
+}
+
+// parseRoles parses the APIKeyRolesAnnotation value
+func parseRoles(value string) (map[string]Role, error) {
+	roles := make(map[string]Role)
+	var errs []string
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, role, ok := strings.Cut(entry, "=")
+		name, role = strings.TrimSpace(name), strings.TrimSpace(role)
+		switch {
+		case !ok || name == "":
+			errs = append(errs, fmt.Sprintf("malformed role entry %q", entry))
+		case Role(role) != RoleReadOnly && Role(role) != RoleAdmin:
+			errs = append(errs, fmt.Sprintf("unknown role %q for key %q", role, name))
+		default:
+			roles[name] = Role(role)
+		}
+	}
+
+	if len(errs) > 0 {
+		return roles, fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return roles, nil
+}
+
+// parseNamespaces parses the APIKeyNamespacesAnnotation value. Keys with an
+// invalid entry are mapped to no namespace.
+func parseNamespaces(value string) (map[string][]string, error) {
+	namespaces := make(map[string][]string)
+	var errs []string
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, list, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			errs = append(errs, fmt.Sprintf("malformed namespaces entry %q", entry))
+			continue
+		}
+		var allowed []string
+		for _, namespace := range strings.Split(list, "|") {
+			namespace = strings.TrimSpace(namespace)
+			if msgs := validation.IsDNS1123Label(namespace); len(msgs) > 0 {
+				errs = append(errs, fmt.Sprintf("invalid namespace %q for key %q: %s", namespace, name, strings.Join(msgs, ", ")))
+				allowed = nil
+				break
+			}
+			allowed = append(allowed, namespace)
+		}
+		namespaces[name] = allowed
+	}
+
+	if len(errs) > 0 {
+		return namespaces, fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return namespaces, nil
+}
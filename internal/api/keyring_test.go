@@ -0,0 +1,171 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Keyring", func() {
+	keysSecret := func(roles string, data map[string]string) *corev1.Secret {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "api-keys",
+				Annotations: map[string]string{APIKeyRolesAnnotation: roles},
+			},
+			Data: map[string][]byte{},
+		}
+		for name, value := range data {
+			secret.Data[name] = []byte(value)
+		}
+		return secret
+	}
+
+	It("loads named keys with their roles, defaulting to read-only", func() {
+		keyring := NewKeyring("", true)
+		Expect(keyring.LoadSecret(keysSecret("ci=admin", map[string]string{
+			"ci":        "ci-key\n",
+			"dashboard": "dash-key",
+		}))).To(Succeed())
+
+		key, ok := keyring.Authenticate("ci-key")
+		Expect(ok).To(BeTrue())
+		Expect(key.Name).To(Equal("ci"))
+		Expect(key.Role).To(Equal(RoleAdmin))
+
+		key, ok = keyring.Authenticate("dash-key")
+		Expect(ok).To(BeTrue())
+		Expect(key.Role).To(Equal(RoleReadOnly))
+	})
+
+	It("revokes keys removed from the secret", func() {
+		keyring := NewKeyring("", true)
+		Expect(keyring.LoadSecret(keysSecret("", map[string]string{"old": "old-key"}))).To(Succeed())
+		Expect(keyring.LoadSecret(keysSecret("", map[string]string{"new": "new-key"}))).To(Succeed())
+
+		_, ok := keyring.Authenticate("old-key")
+		Expect(ok).To(BeFalse())
+		_, ok = keyring.Authenticate("new-key")
+		Expect(ok).To(BeTrue())
+	})
+
+	It("keeps requiring authentication when the secret is deleted", func() {
+		keyring := NewKeyring("", true)
+		Expect(keyring.LoadSecret(nil)).To(Succeed())
+		Expect(keyring.Enabled()).To(BeTrue())
+		_, ok := keyring.Authenticate("")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("reports unknown roles", func() {
+		keyring := NewKeyring("", true)
+		Expect(keyring.LoadSecret(keysSecret("ci=root", map[string]string{"ci": "ci-key"}))).
+			To(MatchError(ContainSubstring(`unknown role "root"`)))
+	})
+
+	It("loads the namespaces keys are restricted to", func() {
+		keyring := NewKeyring("", true)
+		secret := keysSecret("", map[string]string{"ci": "ci-key", "dashboard": "dash-key"})
+		secret.Annotations[APIKeyNamespacesAnnotation] = "ci=team-a|team-b"
+		Expect(keyring.LoadSecret(secret)).To(Succeed())
+
+		key, ok := keyring.Authenticate("ci-key")
+		Expect(ok).To(BeTrue())
+		Expect(key.Namespaces).To(Equal([]string{"team-a", "team-b"}))
+		key, ok = keyring.Authenticate("dash-key")
+		Expect(ok).To(BeTrue())
+		Expect(key.Namespaces).To(BeEmpty())
+	})
+
+	It("leaves out keys with invalid namespaces rather than letting them reach every namespace", func() {
+		keyring := NewKeyring("", true)
+		secret := keysSecret("", map[string]string{"ci": "ci-key", "dashboard": "dash-key"})
+		secret.Annotations[APIKeyNamespacesAnnotation] = "ci=team-a|Team_B"
+		Expect(keyring.LoadSecret(secret)).To(MatchError(ContainSubstring(`invalid namespace "Team_B" for key "ci"`)))
+
+		_, ok := keyring.Authenticate("ci-key")
+		Expect(ok).To(BeFalse())
+		_, ok = keyring.Authenticate("dash-key")
+		Expect(ok).To(BeTrue())
+	})
+
+	It("refuses keys the namespaces they are not restricted to", func() {
+		keyring := NewKeyring("", true)
+		secret := keysSecret("ci=admin", map[string]string{"ci": "ci-key"})
+		secret.Annotations[APIKeyNamespacesAnnotation] = "ci=team-a"
+		Expect(keyring.LoadSecret(secret)).To(Succeed())
+		server := NewServer(Config{Namespace: "default", Keyring: keyring}, newFakeClient(), nil, nil)
+		denied := authorizationDenied.WithLabelValues(apiHTTP, "ci", string(RoleAdmin), reasonNamespace)
+		before := testutil.ToFloat64(denied)
+
+		do := func(method, path, body string) int {
+			req := httptest.NewRequest(method, path, strings.NewReader(body))
+			req.Header.Set("X-API-Key", "ci-key")
+			rec := httptest.NewRecorder()
+			server.router.ServeHTTP(rec, req)
+			return rec.Code
+		}
+
+		Expect(do(http.MethodPost, "/api/v1/namespaces/team-a/models", `{"name":"phi3","tag":"mini"}`)).To(Equal(http.StatusCreated))
+		Expect(do(http.MethodGet, "/api/v1/namespaces/team-a/models/phi3-mini", "")).To(Equal(http.StatusOK))
+		Expect(do(http.MethodGet, "/api/v1/namespaces/team-b/models", "")).To(Equal(http.StatusForbidden))
+		Expect(do(http.MethodPost, "/api/v1/namespaces/team-b/models", `{"name":"phi3","tag":"mini"}`)).To(Equal(http.StatusForbidden))
+		// The unscoped routes reach the default namespace
+		Expect(do(http.MethodGet, "/api/v1/models", "")).To(Equal(http.StatusForbidden))
+		// The admin endpoints span every namespace
+		Expect(do(http.MethodGet, "/api/v1/admin/pull-queue", "")).To(Equal(http.StatusForbidden))
+		// So does the Ollama server, which stores the models of every namespace
+		Expect(do(http.MethodGet, "/api/v1/ollama/status", "")).To(Equal(http.StatusForbidden))
+		Expect(testutil.ToFloat64(denied) - before).To(Equal(5.0))
+	})
+
+	It("rejects mutating requests from read-only keys", func() {
+		keyring := NewKeyring("", true)
+		Expect(keyring.LoadSecret(keysSecret("ci=admin", map[string]string{
+			"ci":        "ci-key",
+			"dashboard": "dash-key",
+		}))).To(Succeed())
+		server := NewServer(Config{Namespace: "default", Keyring: keyring}, newFakeClient(), nil, nil)
+
+		do := func(method, path, key, body string) int {
+			req := httptest.NewRequest(method, path, strings.NewReader(body))
+			req.Header.Set("X-API-Key", key)
+			rec := httptest.NewRecorder()
+			server.router.ServeHTTP(rec, req)
+			return rec.Code
+		}
+
+		Expect(do(http.MethodGet, "/api/v1/models", "", "")).To(Equal(http.StatusUnauthorized))
+		Expect(do(http.MethodGet, "/api/v1/models", "dash-key", "")).To(Equal(http.StatusOK))
+		Expect(do(http.MethodPost, "/api/v1/models", "dash-key", `{"name":"phi3","tag":"mini"}`)).To(Equal(http.StatusForbidden))
+		Expect(do(http.MethodPost, "/api/v1/models", "ci-key", `{"name":"phi3","tag":"mini"}`)).To(Equal(http.StatusCreated))
+	})
+
+	It("counts the requests refused to a read-only key given as a flag", func() {
+		keyring := NewKeyring("admin-key", false)
+		keyring.AddReadOnlyKey("viewer-key")
+		server := NewServer(Config{Namespace: "default", Keyring: keyring}, newFakeClient(), nil, nil)
+		denied := authorizationDenied.WithLabelValues(apiHTTP, readOnlyKeyName, string(RoleReadOnly), reasonRole)
+		before := testutil.ToFloat64(denied)
+
+		do := func(method, key string) int {
+			req := httptest.NewRequest(method, "/api/v1/models", strings.NewReader(`{"name":"phi3","tag":"mini"}`))
+			req.Header.Set("X-API-Key", key)
+			rec := httptest.NewRecorder()
+			server.router.ServeHTTP(rec, req)
+			return rec.Code
+		}
+
+		Expect(do(http.MethodGet, "viewer-key")).To(Equal(http.StatusOK))
+		Expect(do(http.MethodPost, "viewer-key")).To(Equal(http.StatusForbidden))
+		Expect(do(http.MethodPost, "admin-key")).To(Equal(http.StatusCreated))
+		Expect(testutil.ToFloat64(denied) - before).To(Equal(1.0))
+	})
+})
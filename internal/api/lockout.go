@@ -0,0 +1,112 @@
+package api
+
+import (
+	"sync"
+	"time"
+)
+
+// lockoutSweepSize is the number of tracked sources above which the sources
+// with no recent failure are forgotten
+const lockoutSweepSize = 1024
+
+// Lockout temporarily refuses the requests of the sources that failed to
+// authenticate too many times in a row, to slow down the brute forcing of API
+// keys. It is safe for concurrent use, so that the HTTP and gRPC API servers
+// share one. A nil Lockout never locks a source out.
+type Lockout struct {
+	failures int
+	duration time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	sources map[string]*lockoutSource
+}
+
+// lockoutSource tracks the failed authentications from a source
+type lockoutSource struct {
+	failures    int
+	lastFailure time.Time
+	lockedUntil time.Time
+}
+
+// NewLockout returns a Lockout refusing the requests of a source for duration
+// once it failed to authenticate failures times in a row. It returns nil,
+// which disables lockouts, when failures is 0 or less.
+func NewLockout(failures int, duration time.Duration) *Lockout {
+	if failures <= 0 {
+		return nil
+	}
+	return &Lockout{
+		failures: failures,
+		duration: duration,
+		now:      time.Now,
+		sources:  make(map[string]*lockoutSource),
+	}
+}
+
+// Locked reports whether source is locked out, and for how much longer
+func (l *Lockout) Locked(source string) (time.Duration, bool) {
+	if l == nil {
+		return 0, false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	src, ok := l.sources[source]
+	if !ok {
+		return 0, false
+	}
+	remaining := src.lockedUntil.Sub(l.now())
+	return remaining, remaining > 0
+}
+
+// Failed records a failed authentication from source, and reports whether it
+// locked the source out. Failures older than the lockout duration are
+// forgotten, so that only failures in a row lock a source out.
+func (l *Lockout) Failed(source string) bool {
+	if l == nil {
+		return false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	src, ok := l.sources[source]
+	if !ok {
+		if len(l.sources) >= lockoutSweepSize {
+			l.sweep(now)
+		}
+		src = &lockoutSource{}
+		l.sources[source] = src
+	}
+	if now.Sub(src.lastFailure) > l.duration {
+		src.failures = 0
+	}
+	src.failures++
+	src.lastFailure = now
+	if src.failures < l.failures {
+		return false
+	}
+	src.failures = 0
+	src.lockedUntil = now.Add(l.duration)
+	return true
+}
+
+// Succeeded forgets the failures of source once it authenticated
+func (l *Lockout) Succeeded(source string) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.sources, source)
+}
+
+// sweep forgets the sources that are not locked out and did not fail recently
+func (l *Lockout) sweep(now time.Time) {
+	for source, src := range l.sources {
+		if now.After(src.lockedUntil) && now.Sub(src.lastFailure) > l.duration {
+			delete(l.sources, source)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var _ = Describe("Lockout", func() {
+	var (
+		lockout *Lockout
+		now     time.Time
+	)
+
+	BeforeEach(func() {
+		now = time.Now()
+		lockout = NewLockout(3, time.Minute)
+		lockout.now = func() time.Time { return now }
+	})
+
+	It("locks a source out after failures in a row", func() {
+		Expect(lockout.Failed("10.0.0.1")).To(BeFalse())
+		Expect(lockout.Failed("10.0.0.1")).To(BeFalse())
+		Expect(lockout.Failed("10.0.0.1")).To(BeTrue())
+
+		remaining, locked := lockout.Locked("10.0.0.1")
+		Expect(locked).To(BeTrue())
+		Expect(remaining).To(Equal(time.Minute))
+		_, locked = lockout.Locked("10.0.0.2")
+		Expect(locked).To(BeFalse())
+
+		now = now.Add(time.Minute + time.Second)
+		_, locked = lockout.Locked("10.0.0.1")
+		Expect(locked).To(BeFalse())
+	})
+
+	It("forgets failures after a success or once they are old", func() {
+		lockout.Failed("10.0.0.1")
+		lockout.Failed("10.0.0.1")
+		lockout.Succeeded("10.0.0.1")
+		Expect(lockout.Failed("10.0.0.1")).To(BeFalse())
+
+		lockout.Failed("10.0.0.1")
+		now = now.Add(2 * time.Minute)
+		Expect(lockout.Failed("10.0.0.1")).To(BeFalse())
+	})
+
+	It("is disabled without a failure threshold", func() {
+		lockout := NewLockout(0, time.Minute)
+		Expect(lockout).To(BeNil())
+		Expect(lockout.Failed("10.0.0.1")).To(BeFalse())
+		_, locked := lockout.Locked("10.0.0.1")
+		Expect(locked).To(BeFalse())
+	})
+
+	It("refuses the requests of a locked out source, even with a valid key", func() {
+		server := NewServer(Config{Namespace: "default", APIKey: "secret", Lockout: lockout}, newFakeClient(), nil, nil)
+		invalid := authenticationFailed.WithLabelValues(apiHTTP, reasonInvalidKey)
+		lockedOut := authenticationFailed.WithLabelValues(apiHTTP, reasonLockedOut)
+		invalidBefore, lockedOutBefore := testutil.ToFloat64(invalid), testutil.ToFloat64(lockedOut)
+
+		get := func(key string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/models", nil)
+			req.Header.Set("X-API-Key", key)
+			rec := httptest.NewRecorder()
+			server.router.ServeHTTP(rec, req)
+			return rec
+		}
+
+		for range 3 {
+			Expect(get("guess").Code).To(Equal(http.StatusUnauthorized))
+		}
+		rec := get("secret")
+		Expect(rec.Code).To(Equal(http.StatusTooManyRequests))
+		Expect(rec.Header().Get("Retry-After")).To(Equal("60"))
+		Expect(testutil.ToFloat64(invalid) - invalidBefore).To(Equal(3.0))
+		Expect(testutil.ToFloat64(lockedOut) - lockedOutBefore).To(Equal(1.0))
+
+		now = now.Add(2 * time.Minute)
+		Expect(get("secret").Code).To(Equal(http.StatusOK))
+	})
+})
@@ -0,0 +1,137 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/dmk/ollama-operator/internal/audit"
+)
+
+const (
+	// requestIDHeader carries the request ID between clients and the API server
+	requestIDHeader = "X-Request-ID"
+	// auditIDHeader is honored by the Kubernetes API server as the audit event ID
+	auditIDHeader = "Audit-ID"
+	// maxRequestIDLength bounds client-supplied request IDs
+	maxRequestIDLength = 128
+)
+
+// requestInfoKey is the context key for the per-request requestInfo
+type requestInfoKey struct{}
+
+// requestInfo holds per-request data filled in by the middleware chain
+type requestInfo struct {
+	id        string
+	principal string
+	role      Role
+	// namespaces are the namespaces the principal may reach; empty allows
+	// every namespace
+	namespaces []string
+	// auditID is generated for every request and sent to the Kubernetes API
+	// server, whose audit log must not take IDs from clients
+	auditID string
+	audit   *audit.Event
+}
+
+// RequestIDFromContext returns the API request ID stored in ctx, if any
+func RequestIDFromContext(ctx context.Context) string {
+	if info, ok := ctx.Value(requestInfoKey{}).(*requestInfo); ok {
+		return info.id
+	}
+	return ""
+}
+
+// auditIDFromContext returns the Kubernetes audit ID of the API request
+// stored in ctx, if any
+func auditIDFromContext(ctx context.Context) string {
+	if info, ok := ctx.Value(requestInfoKey{}).(*requestInfo); ok {
+		return info.auditID
+	}
+	return ""
+}
+
+// setPrincipal records the authenticated principal of the request, its role
+// and the namespaces it may reach in ctx
+func setPrincipal(ctx context.Context, principal string, role Role, namespaces []string) {
+	if info, ok := ctx.Value(requestInfoKey{}).(*requestInfo); ok {
+		info.principal = principal
+		info.role = role
+		info.namespaces = namespaces
+	}
+}
+
+// principalFromContext returns the authenticated principal stored in ctx, if any
+func principalFromContext(ctx context.Context) string {
+	if info, ok := ctx.Value(requestInfoKey{}).(*requestInfo); ok {
+		return info.principal
+	}
+	return ""
+}
+
+// roleFromContext returns the role of the authenticated principal stored in ctx, if any
+func roleFromContext(ctx context.Context) Role {
+	if info, ok := ctx.Value(requestInfoKey{}).(*requestInfo); ok {
+		return info.role
+	}
+	return ""
+}
+
+// requestIDMiddleware assigns each request an ID and an audit ID, attaches
+// them to the request logger and echoes the ID back, then emits an access log
+// line once the request completes. The audit ID is always generated, and the
+// same as the ID unless the client supplied its own.
+func (s *Server) requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		auditID := uuid.NewString()
+		id := r.Header.Get(requestIDHeader)
+		if id == "" || len(id) > maxRequestIDLength {
+			id = auditID
+		}
+		info := &requestInfo{id: id, auditID: auditID}
+		w.Header().Set(requestIDHeader, id)
+
+		logger := log.FromContext(r.Context()).WithValues("requestID", id, "auditID", auditID)
+		ctx := context.WithValue(r.Context(), requestInfoKey{}, info)
+		ctx = log.IntoContext(ctx, logger)
+
+		rw := &responseWriter{w, http.StatusOK}
+		next.ServeHTTP(rw, r.WithContext(ctx))
+
+		logger.WithName("api-access").Info("request completed",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", rw.statusCode,
+			"latency", time.Since(start).String(),
+			"principal", info.principal,
+			"remoteAddr", r.RemoteAddr,
+			"userAgent", r.UserAgent(),
+		)
+	})
+}
+
+// RequestIDTransport wraps a Kubernetes client transport so that API calls made
+// while serving a request carry its audit ID, which the access log maps to the
+// request ID
+func RequestIDTransport(rt http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if id := auditIDFromContext(req.Context()); id != "" {
+			req = req.Clone(req.Context())
+			req.Header.Set(auditIDHeader, id)
+		}
+		return rt.RoundTrip(req)
+	})
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip implements http.RoundTripper
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
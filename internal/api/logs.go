@@ -0,0 +1,64 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get
+
+// PullLogResponse represents the API response for a model's pull log
+type PullLogResponse struct {
+	Name  string   `json:"name"`
+	Lines []string `json:"lines"`
+}
+
+// getModelLogs handles the GET /api/v1/models/{name}/logs endpoint
+func (s *Server) getModelLogs(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := log.FromContext(ctx).WithName("api-getModelLogs")
+	vars := mux.Vars(r)
+	name := vars["name"]
+	namespace := s.namespaceFor(r)
+
+	// Get the model to ensure it exists
+	model := &ollamav1alpha1.OllamaModel{}
+	if err := s.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, model); err != nil {
+		if apierrors.IsNotFound(err) {
+			sendError(w, fmt.Errorf("model not found: %s", name), http.StatusNotFound)
+		} else {
+			logger.Error(err, "failed to get model", "name", name)
+			sendError(w, err, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	response := PullLogResponse{
+		Name:  name,
+		Lines: []string{},
+	}
+
+	// A missing ConfigMap just means the model has not been pulled yet
+	configMap := &corev1.ConfigMap{}
+	key := types.NamespacedName{Namespace: namespace, Name: ollamav1alpha1.PullLogConfigMapName(name)}
+	if err := s.client.Get(ctx, key, configMap); err != nil && !apierrors.IsNotFound(err) {
+		logger.Error(err, "failed to get pull log", "name", name)
+		sendError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	if data := configMap.Data[ollamav1alpha1.PullLogKey]; data != "" {
+		response.Lines = strings.Split(data, "\n")
+	}
+
+	sendResponse(w, r, response, http.StatusOK)
+}
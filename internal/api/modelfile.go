@@ -0,0 +1,91 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	ollamaapi "github.com/ollama/ollama/api"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+// ModelDetailsResponse describes the format and architecture of a model
+type ModelDetailsResponse struct {
+	Format            string `json:"format,omitempty"`
+	Family            string `json:"family,omitempty"`
+	ParameterSize     string `json:"parameterSize,omitempty"`
+	QuantizationLevel string `json:"quantizationLevel,omitempty"`
+}
+
+// ModelfileResponse represents the API response for a model's Modelfile
+type ModelfileResponse struct {
+	Name       string               `json:"name"`
+	Model      string               `json:"model"`
+	Modelfile  string               `json:"modelfile"`
+	Parameters string               `json:"parameters,omitempty"`
+	Template   string               `json:"template,omitempty"`
+	System     string               `json:"system,omitempty"`
+	License    string               `json:"license,omitempty"`
+	Details    ModelDetailsResponse `json:"details"`
+}
+
+// getModelfile handles the GET /api/v1/models/{name}/modelfile endpoint
+func (s *Server) getModelfile(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := log.FromContext(ctx).WithName("api-getModelfile")
+	vars := mux.Vars(r)
+	name := vars["name"]
+	namespace := s.namespaceFor(r)
+
+	model := &ollamav1alpha1.OllamaModel{}
+	if err := s.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, model); err != nil {
+		if apierrors.IsNotFound(err) {
+			sendError(w, fmt.Errorf("model not found: %s", name), http.StatusNotFound)
+		} else {
+			logger.Error(err, "failed to get model", "name", name)
+			sendError(w, err, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if s.ollama == nil {
+		sendError(w, errors.New("ollama client is not configured"), http.StatusServiceUnavailable)
+		return
+	}
+
+	reference := modelReference(model)
+	show, err := s.ollama.Show(ctx, &ollamaapi.ShowRequest{Model: reference})
+	if err != nil {
+		var statusErr ollamaapi.StatusError
+		if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+			sendError(w, fmt.Errorf("model %s has not been pulled yet", reference), http.StatusNotFound)
+			return
+		}
+		logger.Error(err, "failed to show model", "model", reference)
+		sendError(w, err, http.StatusBadGateway)
+		return
+	}
+
+	response := ModelfileResponse{
+		Name:       name,
+		Model:      reference,
+		Modelfile:  show.Modelfile,
+		Parameters: show.Parameters,
+		Template:   show.Template,
+		System:     show.System,
+		License:    show.License,
+		Details: ModelDetailsResponse{
+			Format:            show.Details.Format,
+			Family:            show.Details.Family,
+			ParameterSize:     show.Details.ParameterSize,
+			QuantizationLevel: show.Details.QuantizationLevel,
+		},
+	}
+
+	sendResponse(w, r, response, http.StatusOK)
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	ollamaapi "github.com/ollama/ollama/api"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+var _ = Describe("Modelfile", func() {
+	var ollama *fakeOllama
+
+	get := func(path string) *httptest.ResponseRecorder {
+		server := NewServer(Config{Namespace: "default"}, newFakeClient(&ollamav1alpha1.OllamaModel{
+			ObjectMeta: metav1.ObjectMeta{Name: "llama3.2-1b", Namespace: "default"},
+			Spec:       ollamav1alpha1.OllamaModelSpec{Name: "llama3.2", Tag: "1b"},
+		}), ollama, nil)
+		rec := httptest.NewRecorder()
+		server.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	BeforeEach(func() {
+		ollama = &fakeOllama{}
+	})
+
+	It("returns the Modelfile and parameters from Ollama", func() {
+		ollama.shown = map[string]*ollamaapi.ShowResponse{
+			"llama3.2:1b": {
+				Modelfile:  "FROM llama3.2:1b\nPARAMETER temperature 0.2\nSYSTEM You are terse.",
+				Parameters: "temperature 0.2",
+				System:     "You are terse.",
+				License:    "LLAMA 3.2 COMMUNITY LICENSE AGREEMENT",
+				Details:    ollamaapi.ModelDetails{Family: "llama", ParameterSize: "1.2B", QuantizationLevel: "Q8_0"},
+			},
+		}
+
+		rec := get("/api/v1/models/llama3.2-1b/modelfile")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+
+		var resp ModelfileResponse
+		Expect(json.Unmarshal(rec.Body.Bytes(), &resp)).To(Succeed())
+		Expect(resp.Model).To(Equal("llama3.2:1b"))
+		Expect(resp.System).To(Equal("You are terse."))
+		Expect(resp.Parameters).To(Equal("temperature 0.2"))
+		Expect(resp.Details.ParameterSize).To(Equal("1.2B"))
+	})
+
+	It("returns 404 when the model has not been pulled", func() {
+		rec := get("/api/v1/models/llama3.2-1b/modelfile")
+		Expect(rec.Code).To(Equal(http.StatusNotFound))
+		Expect(rec.Body.String()).To(ContainSubstring("has not been pulled yet"))
+	})
+
+	It("returns 404 for unknown models", func() {
+		Expect(get("/api/v1/models/missing/modelfile").Code).To(Equal(http.StatusNotFound))
+	})
+})
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// OllamaModelInfo describes a model stored on the Ollama server
+type OllamaModelInfo struct {
+	Name       string    `json:"name"`
+	Digest     string    `json:"digest"`
+	Size       int64     `json:"size"`
+	ModifiedAt time.Time `json:"modifiedAt"`
+}
+
+// RunningModelInfo describes a model currently loaded into memory
+type RunningModelInfo struct {
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+	SizeVRAM  int64     `json:"sizeVRAM"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// OllamaStatusResponse represents the API response for the Ollama status endpoint
+type OllamaStatusResponse struct {
+	Version       string             `json:"version"`
+	Models        []OllamaModelInfo  `json:"models"`
+	RunningModels []RunningModelInfo `json:"runningModels"`
+	LoadedMemory  int64              `json:"loadedMemory"`
+	LoadedVRAM    int64              `json:"loadedVRAM"`
+}
+
+// getOllamaStatus handles the GET /api/v1/ollama/status endpoint
+func (s *Server) getOllamaStatus(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := log.FromContext(ctx).WithName("api-getOllamaStatus")
+
+	if s.ollama == nil {
+		sendError(w, errors.New("ollama client is not configured"), http.StatusServiceUnavailable)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readinessTimeout)
+	defer cancel()
+
+	version, err := s.ollama.Version(ctx)
+	if err != nil {
+		logger.Error(err, "failed to get Ollama server version")
+		sendError(w, err, http.StatusBadGateway)
+		return
+	}
+
+	list, err := s.ollama.List(ctx)
+	if err != nil {
+		logger.Error(err, "failed to list Ollama models")
+		sendError(w, err, http.StatusBadGateway)
+		return
+	}
+
+	running, err := s.ollama.ListRunning(ctx)
+	if err != nil {
+		logger.Error(err, "failed to list running Ollama models")
+		sendError(w, err, http.StatusBadGateway)
+		return
+	}
+
+	response := OllamaStatusResponse{
+		Version:       version,
+		Models:        make([]OllamaModelInfo, 0, len(list.Models)),
+		RunningModels: make([]RunningModelInfo, 0, len(running.Models)),
+	}
+	for _, model := range list.Models {
+		response.Models = append(response.Models, OllamaModelInfo{
+			Name:       model.Name,
+			Digest:     model.Digest,
+			Size:       model.Size,
+			ModifiedAt: model.ModifiedAt,
+		})
+	}
+	for _, model := range running.Models {
+		response.RunningModels = append(response.RunningModels, RunningModelInfo{
+			Name:      model.Name,
+			Size:      model.Size,
+			SizeVRAM:  model.SizeVRAM,
+			ExpiresAt: model.ExpiresAt,
+		})
+		response.LoadedMemory += model.Size
+		response.LoadedVRAM += model.SizeVRAM
+	}
+
+	sendResponse(w, r, response, http.StatusOK)
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	ollamaapi "github.com/ollama/ollama/api"
+)
+
+var _ = Describe("Ollama status", func() {
+	get := func(ollama OllamaClient) *httptest.ResponseRecorder {
+		server := NewServer(Config{Namespace: "default"}, newFakeClient(), ollama, nil)
+		rec := httptest.NewRecorder()
+		server.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/ollama/status", nil))
+		return rec
+	}
+
+	It("aggregates the version, stored models and loaded memory", func() {
+		rec := get(&fakeOllama{
+			version: "0.6.2",
+			models: []ollamaapi.ListModelResponse{
+				{Name: "llama3.2:1b", Size: 1300},
+				{Name: "gemma3:1b", Size: 800},
+			},
+			running: []ollamaapi.ProcessModelResponse{
+				{Name: "llama3.2:1b", Size: 2000, SizeVRAM: 1500},
+				{Name: "gemma3:1b", Size: 1000, SizeVRAM: 0},
+			},
+		})
+		Expect(rec.Code).To(Equal(http.StatusOK))
+
+		var resp OllamaStatusResponse
+		Expect(json.Unmarshal(rec.Body.Bytes(), &resp)).To(Succeed())
+		Expect(resp.Version).To(Equal("0.6.2"))
+		Expect(resp.Models).To(HaveLen(2))
+		Expect(resp.RunningModels).To(HaveLen(2))
+		Expect(resp.LoadedMemory).To(Equal(int64(3000)))
+		Expect(resp.LoadedVRAM).To(Equal(int64(1500)))
+	})
+
+	It("returns 502 when the Ollama server is unreachable", func() {
+		rec := get(&fakeOllama{err: errors.New("connection refused")})
+		Expect(rec.Code).To(Equal(http.StatusBadGateway))
+	})
+
+	It("returns 503 when no Ollama client is configured", func() {
+		Expect(get(nil).Code).To(Equal(http.StatusServiceUnavailable))
+	})
+})
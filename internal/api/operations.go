@@ -0,0 +1,224 @@
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+// operationTTL is how long operations can be polled after they are started
+const operationTTL = 24 * time.Hour
+
+// Operation types
+const (
+	OperationCreate  = "create"
+	OperationRefresh = "refresh"
+)
+
+// Operation states
+const (
+	OperationPending   = "pending"
+	OperationRunning   = "running"
+	OperationSucceeded = "succeeded"
+	OperationFailed    = "failed"
+)
+
+// OperationResponse represents the API response for a long-running operation
+type OperationResponse struct {
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	State     string `json:"state"`
+	Progress  string `json:"progress,omitempty"`
+	Completed int64  `json:"completed,omitempty"`
+	Total     int64  `json:"total,omitempty"`
+	// Estimate is the overall progress of the pull with the time remaining
+	// and the download rate, such as "62%, ~4m remaining at 85.0 MiB/s"
+	Estimate  string         `json:"estimate,omitempty"`
+	Error     string         `json:"error,omitempty"`
+	CreatedAt string         `json:"createdAt"`
+	Result    *ModelResponse `json:"result,omitempty"`
+}
+
+// operation is a long-running action on a model. Its ID encodes what the
+// operation needs, so that any replica of the API server can answer for it,
+// and its state is derived from the model's status.
+type operation struct {
+	Kind      string    `json:"k"`
+	Namespace string    `json:"ns"`
+	Name      string    `json:"n"`
+	UID       types.UID `json:"u"`
+	CreatedAt int64     `json:"t"`
+
+	// StartState is the model's state when the operation started, and
+	// Refresh the value of the refresh annotation a refresh set
+	StartState ollamav1alpha1.ModelState `json:"s,omitempty"`
+	Refresh    string                    `json:"r,omitempty"`
+}
+
+// newOperation returns an operation of kind on model, starting now
+func newOperation(kind string, model *ollamav1alpha1.OllamaModel) operation {
+	op := operation{
+		Kind:       kind,
+		Namespace:  model.Namespace,
+		Name:       model.Name,
+		UID:        model.UID,
+		CreatedAt:  time.Now().Unix(),
+		StartState: model.Status.State,
+	}
+	if kind == OperationRefresh {
+		op.Refresh = model.Annotations[ollamav1alpha1.RefreshAnnotation]
+	}
+	return op
+}
+
+// id encodes the operation as its ID
+func (op operation) id() string {
+	data, _ := json.Marshal(op)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// parseOperationID decodes the operation of an ID, reporting false for IDs
+// that are malformed or older than operationTTL
+func parseOperationID(id string) (operation, bool) {
+	data, err := base64.RawURLEncoding.DecodeString(id)
+	if err != nil {
+		return operation{}, false
+	}
+	var op operation
+	if err := json.Unmarshal(data, &op); err != nil || op.Kind == "" || op.Name == "" {
+		return operation{}, false
+	}
+	if time.Since(op.createdAt()) > operationTTL {
+		return operation{}, false
+	}
+	return op, true
+}
+
+// createdAt returns when the operation started
+func (op operation) createdAt() time.Time {
+	return time.Unix(op.CreatedAt, 0).UTC()
+}
+
+// operationLocation returns the URL clients poll for an operation
+func operationLocation(id string) string {
+	return "/api/v1/operations/" + id
+}
+
+// startOperation starts an operation and points the client at it
+func (s *Server) startOperation(w http.ResponseWriter, kind string, model *ollamav1alpha1.OllamaModel) string {
+	id := newOperation(kind, model).id()
+	w.Header().Set("Location", operationLocation(id))
+	return id
+}
+
+// getOperation handles the GET /api/v1/operations/{id} endpoint
+func (s *Server) getOperation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := log.FromContext(ctx).WithName("api-getOperation")
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	op, ok := parseOperationID(id)
+	if !ok {
+		sendError(w, fmt.Errorf("operation not found: %s", id), http.StatusNotFound)
+		return
+	}
+	if !authorizeNamespace(ctx, apiHTTP, op.Namespace) {
+		sendError(w, fmt.Errorf("the key may not reach namespace %q", op.Namespace), http.StatusForbidden)
+		return
+	}
+
+	response := OperationResponse{
+		ID:        id,
+		Type:      op.Kind,
+		Namespace: op.Namespace,
+		Name:      op.Name,
+		CreatedAt: op.createdAt().Format(time.RFC3339),
+	}
+
+	model := &ollamav1alpha1.OllamaModel{}
+	err := s.client.Get(ctx, types.NamespacedName{Namespace: op.Namespace, Name: op.Name}, model)
+	if err != nil && !apierrors.IsNotFound(err) {
+		logger.Error(err, "failed to get model", "name", op.Name)
+		sendError(w, err, http.StatusInternalServerError)
+		return
+	}
+	if err == nil && model.UID == op.UID {
+		result := convertModelToResponse(*model)
+		response.Result = &result
+		response.State, response.Error = op.stateFrom(model)
+	} else {
+		response.State, response.Error = OperationFailed, "model was deleted"
+	}
+
+	if response.State == OperationRunning {
+		response.Progress, response.Completed, response.Total = s.pullProgress(ctx, model)
+		if model.Status.Progress != nil {
+			response.Estimate = model.Status.Progress.Message
+		}
+	}
+
+	sendResponse(w, r, response, http.StatusOK)
+}
+
+// stateFrom derives the state of an operation from its model
+func (op operation) stateFrom(model *ollamav1alpha1.OllamaModel) (string, string) {
+	// A refresh is done once the controller has handled the refresh request
+	refreshPending := op.Kind == OperationRefresh && !op.refreshHandled(model)
+
+	switch model.Status.State {
+	case ollamav1alpha1.StatePulling:
+		return OperationRunning, ""
+	case ollamav1alpha1.StateFailed:
+		// A refresh of an already failed model has not failed until it has been retried
+		if refreshPending && op.StartState == ollamav1alpha1.StateFailed {
+			return OperationPending, ""
+		}
+		return OperationFailed, model.Status.Error
+	case ollamav1alpha1.StateReady:
+		if refreshPending {
+			return OperationPending, ""
+		}
+		return OperationSucceeded, ""
+	default:
+		return OperationPending, ""
+	}
+}
+
+// refreshHandled reports whether the controller handled the refresh request
+// of the operation, or a later one replacing it
+func (op operation) refreshHandled(model *ollamav1alpha1.OllamaModel) bool {
+	return model.Status.LastHandledRefresh == op.Refresh || !model.RefreshRequested()
+}
+
+// pullProgress returns the latest line of a model's pull log and the bytes
+// downloaded of the current layer. Progress is informational, so failures to
+// read it are ignored.
+func (s *Server) pullProgress(ctx context.Context, model *ollamav1alpha1.OllamaModel) (string, int64, int64) {
+	configMap := &corev1.ConfigMap{}
+	key := types.NamespacedName{Namespace: model.Namespace, Name: ollamav1alpha1.PullLogConfigMapName(model.Name)}
+	if err := s.client.Get(ctx, key, configMap); err != nil {
+		return "", 0, 0
+	}
+
+	lines := strings.Split(configMap.Data[ollamav1alpha1.PullLogKey], "\n")
+	var completed, total int64
+	if _, err := fmt.Sscanf(configMap.Data[ollamav1alpha1.PullProgressKey], "%d/%d", &completed, &total); err != nil {
+		completed, total = 0, 0
+	}
+	return lines[len(lines)-1], completed, total
+}
@@ -0,0 +1,146 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+var _ = Describe("Operations", func() {
+	var server *Server
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		server.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	poll := func(location string) OperationResponse {
+		rec := do(http.MethodGet, location, "")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+
+		var op OperationResponse
+		Expect(json.Unmarshal(rec.Body.Bytes(), &op)).To(Succeed())
+		return op
+	}
+
+	setState := func(name string, state ollamav1alpha1.ModelState, errMsg string) {
+		model := &ollamav1alpha1.OllamaModel{}
+		Expect(server.client.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: name}, model)).To(Succeed())
+		model.Status.State = state
+		model.Status.Error = errMsg
+		Expect(server.client.Status().Update(context.Background(), model)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		server = NewServer(Config{Namespace: "default"}, newFakeClient(
+			&ollamav1alpha1.OllamaModel{
+				ObjectMeta: metav1.ObjectMeta{Name: "llama3.2-1b", Namespace: "default"},
+				Spec:       ollamav1alpha1.OllamaModelSpec{Name: "llama3.2", Tag: "1b"},
+				Status:     ollamav1alpha1.OllamaModelStatus{State: ollamav1alpha1.StateReady},
+			},
+		), nil, nil)
+	})
+
+	It("tracks a create through the pull to completion", func() {
+		rec := do(http.MethodPost, "/api/v1/models", `{"name":"phi3","tag":"mini"}`)
+		Expect(rec.Code).To(Equal(http.StatusCreated))
+
+		var model ModelResponse
+		Expect(json.Unmarshal(rec.Body.Bytes(), &model)).To(Succeed())
+		Expect(model.OperationID).NotTo(BeEmpty())
+		location := rec.Header().Get("Location")
+		Expect(location).To(Equal("/api/v1/operations/" + model.OperationID))
+
+		op := poll(location)
+		Expect(op.Type).To(Equal(OperationCreate))
+		Expect(op.State).To(Equal(OperationPending))
+
+		setState("phi3-mini", ollamav1alpha1.StatePulling, "")
+		Expect(server.client.Create(context.Background(), &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: ollamav1alpha1.PullLogConfigMapName("phi3-mini"), Namespace: "default"},
+			Data: map[string]string{
+				ollamav1alpha1.PullLogKey:      "pulling manifest\npulling 74701a8c35f6 (1.2 GiB)",
+				ollamav1alpha1.PullProgressKey: "322122547/1288490188",
+			},
+		})).To(Succeed())
+		op = poll(location)
+		Expect(op.State).To(Equal(OperationRunning))
+		Expect(op.Progress).To(Equal("pulling 74701a8c35f6 (1.2 GiB)"))
+		Expect(op.Completed).To(Equal(int64(322122547)))
+		Expect(op.Total).To(Equal(int64(1288490188)))
+		Expect(op.Estimate).To(BeEmpty())
+
+		pulling := &ollamav1alpha1.OllamaModel{}
+		Expect(server.client.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "phi3-mini"}, pulling)).To(Succeed())
+		pulling.Status.Progress = &ollamav1alpha1.PullProgress{Percent: 62, BytesPerSecond: 89128960, RemainingSeconds: 240,
+			Message: "62%, ~4m remaining at 85.0 MiB/s"}
+		Expect(server.client.Status().Update(context.Background(), pulling)).To(Succeed())
+		op = poll(location)
+		Expect(op.Estimate).To(Equal("62%, ~4m remaining at 85.0 MiB/s"))
+		Expect(op.Result.Progress.RemainingSeconds).To(BeEquivalentTo(240))
+
+		setState("phi3-mini", ollamav1alpha1.StateReady, "")
+		op = poll(location)
+		Expect(op.State).To(Equal(OperationSucceeded))
+		Expect(op.Result.State).To(Equal(string(ollamav1alpha1.StateReady)))
+	})
+
+	It("waits for the controller to pick up a refresh", func() {
+		rec := do(http.MethodPost, "/api/v1/models/llama3.2-1b/refresh", "")
+		Expect(rec.Code).To(Equal(http.StatusAccepted))
+		location := rec.Header().Get("Location")
+
+		// Still Ready from before the refresh
+		Expect(poll(location).State).To(Equal(OperationPending))
+
+		setState("llama3.2-1b", ollamav1alpha1.StateFailed, "manifest unknown")
+		op := poll(location)
+		Expect(op.State).To(Equal(OperationFailed))
+		Expect(op.Error).To(Equal("manifest unknown"))
+
+		// Any replica answers for the operation
+		server = NewServer(Config{Namespace: "default"}, server.client, nil, nil)
+		op = poll(location)
+		Expect(op.Type).To(Equal(OperationRefresh))
+		Expect(op.State).To(Equal(OperationFailed))
+	})
+
+	It("waits for the controller to retry a refreshed failed model", func() {
+		setState("llama3.2-1b", ollamav1alpha1.StateFailed, "manifest unknown")
+		rec := do(http.MethodPost, "/api/v1/models/llama3.2-1b/refresh", "")
+		Expect(rec.Code).To(Equal(http.StatusAccepted))
+		location := rec.Header().Get("Location")
+		Expect(poll(location).State).To(Equal(OperationPending))
+
+		model := &ollamav1alpha1.OllamaModel{}
+		Expect(server.client.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "llama3.2-1b"}, model)).To(Succeed())
+		model.Status.LastHandledRefresh = model.Annotations[ollamav1alpha1.RefreshAnnotation]
+		model.Status.Error = "manifest unknown again"
+		Expect(server.client.Status().Update(context.Background(), model)).To(Succeed())
+		op := poll(location)
+		Expect(op.State).To(Equal(OperationFailed))
+		Expect(op.Error).To(Equal("manifest unknown again"))
+	})
+
+	It("returns 404 for unknown and expired operations", func() {
+		Expect(do(http.MethodGet, "/api/v1/operations/nope", "").Code).To(Equal(http.StatusNotFound))
+
+		expired := operation{Kind: OperationCreate, Namespace: "default", Name: "llama3.2-1b",
+			CreatedAt: time.Now().Add(-operationTTL - time.Minute).Unix()}
+		Expect(do(http.MethodGet, operationLocation(expired.id()), "").Code).To(Equal(http.StatusNotFound))
+	})
+})
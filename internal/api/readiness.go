@@ -0,0 +1,128 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"time"
+
+	ollamaapi "github.com/ollama/ollama/api"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// readinessTimeout bounds how long each dependency check may take
+const readinessTimeout = 2 * time.Second
+
+// OllamaClient defines the subset of the Ollama API used by the API server
+type OllamaClient interface {
+	Version(ctx context.Context) (string, error)
+	List(ctx context.Context) (*ollamaapi.ListResponse, error)
+	ListRunning(ctx context.Context) (*ollamaapi.ProcessResponse, error)
+	Delete(ctx context.Context, req *ollamaapi.DeleteRequest) error
+	Show(ctx context.Context, req *ollamaapi.ShowRequest) (*ollamaapi.ShowResponse, error)
+	Copy(ctx context.Context, req *ollamaapi.CopyRequest) error
+}
+
+// InformerCache is the subset of the manager's informer cache used by the API server
+type InformerCache interface {
+	WaitForCacheSync(ctx context.Context) bool
+	GetInformer(ctx context.Context, obj client.Object, opts ...cache.InformerGetOption) (cache.Informer, error)
+}
+
+// DependencyStatus describes the state of a single dependency
+type DependencyStatus struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ReadinessResponse represents the API response for the readiness check
+type ReadinessResponse struct {
+	Status       string                      `json:"status"`
+	Dependencies map[string]DependencyStatus `json:"dependencies"`
+}
+
+const (
+	statusOK       = "ok"
+	statusError    = "error"
+	statusReady    = "ready"
+	statusNotReady = "not ready"
+)
+
+// dependencies checks the dependencies of the API server
+func (s *Server) dependencies(ctx context.Context) map[string]DependencyStatus {
+	return map[string]DependencyStatus{
+		"kubernetes": s.checkCacheSync(ctx),
+		"ollama":     s.checkOllama(ctx),
+	}
+}
+
+// ReadyzCheck is a check of the manager's readyz endpoint failing while a
+// dependency of the API server is not ready, as GET /readiness reports. The
+// probes of a standalone API server use it, since the probe endpoint is served
+// over plain HTTP whether or not the API server uses TLS.
+func (s *Server) ReadyzCheck(req *http.Request) error {
+	dependencies := s.dependencies(req.Context())
+	for _, name := range slices.Sorted(maps.Keys(dependencies)) {
+		if dep := dependencies[name]; dep.Status != statusOK {
+			return fmt.Errorf("%s is not ready: %s", name, dep.Error)
+		}
+	}
+	return nil
+}
+
+// readinessCheck handles the readiness check endpoint
+func (s *Server) readinessCheck(w http.ResponseWriter, r *http.Request) {
+	response := ReadinessResponse{
+		Status:       statusReady,
+		Dependencies: s.dependencies(r.Context()),
+	}
+
+	status := http.StatusOK
+	for _, dep := range response.Dependencies {
+		if dep.Status != statusOK {
+			response.Status = statusNotReady
+			status = http.StatusServiceUnavailable
+		}
+	}
+
+	sendJSON(w, response, status)
+}
+
+// checkCacheSync verifies that the Kubernetes client cache has synced
+func (s *Server) checkCacheSync(ctx context.Context) DependencyStatus {
+	if s.cache == nil {
+		return DependencyStatus{Status: statusOK}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readinessTimeout)
+	defer cancel()
+
+	if !s.cache.WaitForCacheSync(ctx) {
+		return dependencyError(errors.New("informer cache not synced"))
+	}
+	return DependencyStatus{Status: statusOK}
+}
+
+// checkOllama verifies that the Ollama server answers version requests
+func (s *Server) checkOllama(ctx context.Context) DependencyStatus {
+	if s.ollama == nil {
+		return DependencyStatus{Status: statusOK}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readinessTimeout)
+	defer cancel()
+
+	if _, err := s.ollama.Version(ctx); err != nil {
+		return dependencyError(err)
+	}
+	return DependencyStatus{Status: statusOK}
+}
+
+// dependencyError returns a failed DependencyStatus for err
+func dependencyError(err error) DependencyStatus {
+	return DependencyStatus{Status: statusError, Error: err.Error()}
+}
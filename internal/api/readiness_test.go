@@ -0,0 +1,167 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	ollamaapi "github.com/ollama/ollama/api"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+)
+
+// fakeOllama is a stub OllamaClient for API server tests
+type fakeOllama struct {
+	version string
+	models  []ollamaapi.ListModelResponse
+	running []ollamaapi.ProcessModelResponse
+	shown   map[string]*ollamaapi.ShowResponse
+	deleted []string
+	copied  []ollamaapi.CopyRequest
+	err     error
+}
+
+func (f *fakeOllama) Version(ctx context.Context) (string, error) {
+	return f.version, f.err
+}
+
+func (f *fakeOllama) List(ctx context.Context) (*ollamaapi.ListResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &ollamaapi.ListResponse{Models: f.models}, nil
+}
+
+func (f *fakeOllama) Delete(ctx context.Context, req *ollamaapi.DeleteRequest) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.deleted = append(f.deleted, req.Model)
+	return nil
+}
+
+func (f *fakeOllama) Show(ctx context.Context, req *ollamaapi.ShowRequest) (*ollamaapi.ShowResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	show, ok := f.shown[req.Model]
+	if !ok {
+		return nil, ollamaapi.StatusError{StatusCode: http.StatusNotFound, ErrorMessage: "model not found"}
+	}
+	return show, nil
+}
+
+func (f *fakeOllama) Copy(ctx context.Context, req *ollamaapi.CopyRequest) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.copied = append(f.copied, *req)
+	return nil
+}
+
+func (f *fakeOllama) ListRunning(ctx context.Context) (*ollamaapi.ProcessResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &ollamaapi.ProcessResponse{Models: f.running}, nil
+}
+
+// fakeCache returns a stub InformerCache for API server tests
+func fakeCache(synced bool) *informertest.FakeInformers {
+	return &informertest.FakeInformers{Scheme: newTestScheme(), Synced: &synced}
+}
+
+var _ = Describe("Readiness check", func() {
+	check := func(ollama OllamaClient, cache InformerCache) (int, ReadinessResponse) {
+		server := NewServer(Config{Namespace: "default"}, newFakeClient(), ollama, cache)
+		rec := httptest.NewRecorder()
+		server.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readiness", nil))
+
+		var resp ReadinessResponse
+		Expect(json.Unmarshal(rec.Body.Bytes(), &resp)).To(Succeed())
+		return rec.Code, resp
+	}
+
+	It("is ready when all dependencies are healthy", func() {
+		code, resp := check(&fakeOllama{version: "0.6.2"}, fakeCache(true))
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(resp.Status).To(Equal(statusReady))
+	})
+
+	It("reports the Ollama server when it is unreachable", func() {
+		code, resp := check(&fakeOllama{err: errors.New("connection refused")}, fakeCache(true))
+		Expect(code).To(Equal(http.StatusServiceUnavailable))
+		Expect(resp.Dependencies["ollama"].Error).To(Equal("connection refused"))
+		Expect(resp.Dependencies["kubernetes"].Status).To(Equal(statusOK))
+	})
+
+	It("reports an unsynced cache", func() {
+		code, resp := check(&fakeOllama{}, fakeCache(false))
+		Expect(code).To(Equal(http.StatusServiceUnavailable))
+		Expect(resp.Dependencies["kubernetes"].Status).To(Equal(statusError))
+	})
+
+	It("fails the readyz check of the manager along", func() {
+		req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+		server := NewServer(Config{Namespace: "default"}, newFakeClient(), &fakeOllama{version: "0.6.2"}, fakeCache(true))
+		Expect(server.ReadyzCheck(req)).To(Succeed())
+
+		server = NewServer(Config{Namespace: "default"}, newFakeClient(), &fakeOllama{err: errors.New("connection refused")}, fakeCache(true))
+		Expect(server.ReadyzCheck(req)).To(MatchError("ollama is not ready: connection refused"))
+	})
+})
+
+// runningPullQueue is a pull queue with pulls running
+type runningPullQueue struct {
+	fakePullQueue
+	active int
+}
+
+func (q runningPullQueue) Active() int {
+	return q.active
+}
+
+var _ = Describe("Health check", func() {
+	check := func(config Config, ollama OllamaClient) HealthResponse {
+		config.Namespace = "default"
+		server := NewServer(config, newFakeClient(), ollama, fakeCache(true))
+		rec := httptest.NewRecorder()
+		server.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+
+		var resp HealthResponse
+		Expect(json.Unmarshal(rec.Body.Bytes(), &resp)).To(Succeed())
+		return resp
+	}
+
+	It("breaks the health down by component", func() {
+		queue := runningPullQueue{fakePullQueue: fakePullQueue{{Model: "gemma3:1b"}}, active: 2}
+		resp := check(Config{PullQueue: queue}, &fakeOllama{version: "0.6.2"})
+		Expect(resp.Status).To(Equal(statusOK))
+		Expect(resp.Components).To(HaveKeyWithValue("kubernetes", ComponentHealth{Status: statusOK}))
+		Expect(resp.Components).To(HaveKeyWithValue("leader", ComponentHealth{Status: statusOK, Detail: leaderDetail}))
+		Expect(resp.Components["pulls"].Detail).To(Equal("2 running, 1 queued"))
+	})
+
+	It("stays up but degraded when the Ollama server is unreachable", func() {
+		resp := check(Config{}, &fakeOllama{err: errors.New("connection refused")})
+		Expect(resp.Status).To(Equal(statusDegraded))
+		Expect(resp.Components["ollama"].Error).To(Equal("connection refused"))
+	})
+
+	It("reports standby replicas", func() {
+		resp := check(Config{PullQueue: fakePullQueue{}, Elected: make(chan struct{})}, &fakeOllama{})
+		Expect(resp.Status).To(Equal(statusOK))
+		Expect(resp.Components["leader"].Detail).To(Equal(standbyDetail))
+	})
+
+	It("leaves out the controllers when they run elsewhere", func() {
+		resp := check(Config{}, &fakeOllama{})
+		Expect(resp.Components).NotTo(HaveKey("leader"))
+		Expect(resp.Components).NotTo(HaveKey("pulls"))
+	})
+})
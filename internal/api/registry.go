@@ -0,0 +1,89 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+	"github.com/dmk/ollama-operator/internal/registry"
+)
+
+// registrySearchTimeout bounds a registry search, which may look up the
+// manifests of many tags, within the default write timeout of the server
+const registrySearchTimeout = 20 * time.Second
+
+// RegistrySearchResponse represents the API response for a registry search
+type RegistrySearchResponse struct {
+	Query string           `json:"query"`
+	Items []registry.Model `json:"items"`
+}
+
+// searchRegistry handles the GET /api/v1/registry/search endpoint
+func (s *Server) searchRegistry(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := log.FromContext(ctx).WithName("api-searchRegistry")
+
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		sendError(w, fmt.Errorf("query parameter q is required"), http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, registrySearchTimeout)
+	defer cancel()
+	models, err := s.registry.Search(ctx, query)
+	if err != nil {
+		logger.Error(err, "failed to search registries", "query", query)
+		sendError(w, err, http.StatusBadGateway)
+		return
+	}
+
+	response := RegistrySearchResponse{
+		Query: query,
+		Items: models,
+	}
+	if response.Items == nil {
+		response.Items = []registry.Model{}
+	}
+
+	sendResponse(w, r, response, http.StatusOK)
+}
+
+// errQuantizationNotFound is returned for a quantization of a model that no
+// configured registry publishes
+var errQuantizationNotFound = errors.New("not found in any registry")
+
+// checkQuantization verifies that the quantization requested for a model is
+// published in the registries of reg, returning errQuantizationNotFound if it
+// is not. When the registries cannot be reached, the model is accepted and its
+// pull will tell.
+func checkQuantization(ctx context.Context, reg *registry.Client, spec ollamav1alpha1.OllamaModelSpec) error {
+	if spec.Quantization == "" {
+		return nil
+	}
+
+	_, _, err := reg.Resolve(ctx, spec.Name, spec.QualifiedTag())
+	switch {
+	case errors.Is(err, registry.ErrNotFound):
+		return fmt.Errorf("quantization %s of %s:%s: %w", spec.Quantization, spec.Name, spec.Tag, errQuantizationNotFound)
+	case err != nil:
+		log.FromContext(ctx).Info("could not check quantization in registries", "model", spec.Reference(), "error", err.Error())
+	}
+	return nil
+}
+
+// checkQuantization sends 422 and returns false if the quantization requested
+// for a model is not published in the configured registries
+func (s *Server) checkQuantization(w http.ResponseWriter, r *http.Request, spec ollamav1alpha1.OllamaModelSpec) bool {
+	if err := checkQuantization(r.Context(), s.registry, spec); err != nil {
+		sendError(w, err, http.StatusUnprocessableEntity)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,166 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Registry search", func() {
+	var registryServer *httptest.Server
+	var server *Server
+
+	BeforeEach(func() {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/v2/_catalog", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+		mux.HandleFunc("/v2/library/llama3.2/tags/list", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"name":"library/llama3.2","tags":["1b","3b"]}`))
+		})
+		mux.HandleFunc("/v2/library/llama3.2/manifests/1b", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Docker-Content-Digest", "sha256:abc")
+			_, _ = w.Write([]byte(`{"config":{"size":100},"layers":[{"size":1000},{"size":24}]}`))
+		})
+		mux.HandleFunc("/v2/library/llama3.2/manifests/1b-q8_0", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Docker-Content-Digest", "sha256:def")
+			_, _ = w.Write([]byte(`{"config":{"size":100},"layers":[{"size":2000}]}`))
+		})
+		registryServer = httptest.NewServer(mux)
+
+		server = NewServer(Config{Namespace: "default", RegistryURLs: []string{registryServer.URL}}, newFakeClient(), nil, nil)
+	})
+
+	AfterEach(func() {
+		registryServer.Close()
+	})
+
+	It("returns tags and sizes for an exact model name", func() {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/registry/search?q=llama3.2", nil)
+		rec := httptest.NewRecorder()
+		server.router.ServeHTTP(rec, req)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+
+		var resp RegistrySearchResponse
+		Expect(json.Unmarshal(rec.Body.Bytes(), &resp)).To(Succeed())
+		Expect(resp.Items).To(HaveLen(1))
+		Expect(resp.Items[0].Name).To(Equal("llama3.2"))
+		Expect(resp.Items[0].Tags).To(HaveLen(2))
+		Expect(resp.Items[0].Tags[0].Digest).To(Equal("sha256:abc"))
+		Expect(resp.Items[0].Tags[0].Size).To(BeEquivalentTo(1124))
+		Expect(resp.Items[0].Tags[1].Size).To(BeZero())
+	})
+
+	It("keeps queries within the repository paths of the registry", func() {
+		var paths []string
+		registryServer.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			paths = append(paths, r.URL.EscapedPath())
+			w.WriteHeader(http.StatusNotFound)
+		})
+
+		for _, query := range []string{"..", "team/..", "llama%3F"} {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/registry/search?q="+query, nil)
+			rec := httptest.NewRecorder()
+			server.router.ServeHTTP(rec, req)
+			Expect(rec.Code).To(Equal(http.StatusOK))
+		}
+		Expect(paths).To(Equal([]string{"/v2/_catalog", "/v2/_catalog", "/v2/_catalog", "/v2/library/llama%3F/tags/list"}))
+	})
+
+	It("does not read oversized manifests", func() {
+		registryServer.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"config":{"size":100},"layers":[` + strings.Repeat(`{"size":1},`, 1<<20) + `{"size":1}]}`))
+		})
+		_, _, err := server.registry.Resolve(context.Background(), "llama3.2", "1b")
+		Expect(err).To(MatchError(ContainSubstring("manifest exceeds")))
+	})
+
+	It("rejects an empty query", func() {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/registry/search", nil)
+		rec := httptest.NewRecorder()
+		server.router.ServeHTTP(rec, req)
+		Expect(rec.Code).To(Equal(http.StatusBadRequest))
+	})
+
+	Context("dry-run create", func() {
+		create := func(body string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/models?dryRun=true", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			server.router.ServeHTTP(rec, req)
+			return rec
+		}
+
+		It("reports the model and its upstream size without creating it", func() {
+			rec := create(`{"name":"llama3.2","tag":"1b"}`)
+			Expect(rec.Code).To(Equal(http.StatusOK))
+
+			var resp DryRunResponse
+			Expect(json.Unmarshal(rec.Body.Bytes(), &resp)).To(Succeed())
+			Expect(resp.DryRun).To(BeTrue())
+			Expect(resp.Model.Name).To(Equal("llama3.2-1b"))
+			Expect(resp.Upstream.Registry).To(Equal(registryServer.URL))
+			Expect(resp.Upstream.Digest).To(Equal("sha256:abc"))
+			Expect(resp.Upstream.Size).To(BeEquivalentTo(1124))
+
+			err := server.client.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "llama3.2-1b"}, &ollamav1alpha1.OllamaModel{})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("rejects a tag missing from the registries", func() {
+			rec := create(`{"name":"llama3.2","tag":"70b"}`)
+			Expect(rec.Code).To(Equal(http.StatusUnprocessableEntity))
+			Expect(rec.Body.String()).To(ContainSubstring("llama3.2:70b was not found in any registry, check its name and tag"))
+		})
+
+		It("reports an unreachable registry without failing", func() {
+			registryServer.Close()
+			rec := create(`{"name":"llama3.2","tag":"1b"}`)
+			Expect(rec.Code).To(Equal(http.StatusOK))
+
+			var resp DryRunResponse
+			Expect(json.Unmarshal(rec.Body.Bytes(), &resp)).To(Succeed())
+			Expect(resp.Upstream.Error).NotTo(BeEmpty())
+		})
+
+		It("rejects an invalid dryRun value", func() {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/models?dryRun=maybe", strings.NewReader(`{"name":"llama3.2","tag":"1b"}`))
+			rec := httptest.NewRecorder()
+			server.router.ServeHTTP(rec, req)
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Context("quantization", func() {
+		create := func(body string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/models", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			server.router.ServeHTTP(rec, req)
+			return rec
+		}
+
+		It("creates a model for a published quantization", func() {
+			rec := create(`{"name":"llama3.2","tag":"1b","quantization":"q8_0"}`)
+			Expect(rec.Code).To(Equal(http.StatusCreated))
+
+			model := &ollamav1alpha1.OllamaModel{}
+			Expect(server.client.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "llama3.2-1b-q8-0"}, model)).To(Succeed())
+			Expect(model.Spec.Quantization).To(Equal("q8_0"))
+			Expect(model.Spec.Reference()).To(Equal("llama3.2:1b-q8_0"))
+		})
+
+		It("rejects a quantization missing from the registries", func() {
+			Expect(create(`{"name":"llama3.2","tag":"1b","quantization":"q4_K_M"}`).Code).To(Equal(http.StatusUnprocessableEntity))
+		})
+	})
+})
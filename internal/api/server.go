@@ -2,10 +2,16 @@ package api
 
 import (
 	"context"
-	"crypto/subtle"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"net"
 	"net/http"
+	"net/netip"
+	"slices"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -13,13 +19,18 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/dmk/ollama-operator/internal/audit"
+	"github.com/dmk/ollama-operator/internal/controller"
+	"github.com/dmk/ollama-operator/internal/opconfig"
+	"github.com/dmk/ollama-operator/internal/registry"
 )
 
 var (
 	apiRequestsTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "ollama_api_requests_total",
-			Help: "Total number of HTTP requests to the Ollama API server",
+			Help: "Total number of HTTP requests to the Ollama API server, by route",
 		},
 		[]string{"method", "path", "status"},
 	)
@@ -34,45 +45,206 @@ var (
 	)
 )
 
+// shutdownTimeout is how long the requests in flight are let finish once the
+// operator stops, within the manager's graceful shutdown timeout
+const shutdownTimeout = 20 * time.Second
+
 // Config holds the configuration for the API server
 type Config struct {
-	BindAddress string
-	APIKey      string
-	Namespace   string
+	BindAddress     string
+	GRPCBindAddress string
+	// APIKey is a single admin key; ignored when Keyring is set
+	APIKey       string
+	Keyring      *Keyring
+	Auditor      *audit.Logger
+	Namespace    string
+	RegistryURLs []string
+	Limits       Limits
+	// Settings is the runtime configuration, which may override Limits.MaxBodyBytes
+	Settings *opconfig.Store
+	// PullQueue holds the pulls waiting for a free pull slot; nil makes the
+	// pull queue unavailable
+	PullQueue PullQueue
+	// Elected is closed once this replica is the leader, which runs the pulls
+	// of the pull queue; nil if the replica always runs them
+	Elected <-chan struct{}
+	// Reader lists the models and snapshots of every namespace when looking
+	// for the models no resource references, which a cache scoped to some
+	// namespaces or labels does not hold; nil lists them with the client
+	Reader client.Reader
+	// WatchNamespaces are the namespaces the operator caches; requests for
+	// models in other namespaces are refused. Empty allows every namespace.
+	WatchNamespaces []string
+	// ModelLabels are set on the models created through the API, so that
+	// they match the label selector the operator watches with
+	ModelLabels map[string]string
+	// ReadOnly disables the endpoints changing resources, for installs
+	// managing models through GitOps only
+	ReadOnly bool
+	// Lockout refuses the requests of sources that failed to authenticate
+	// too many times in a row; nil disables lockouts
+	Lockout *Lockout
+	// AllowedNetworks restricts the clients of the API servers to these
+	// networks; empty allows every client
+	AllowedNetworks []netip.Prefix
+	// TrustedProxies are the networks of the reverse proxies whose
+	// X-Forwarded-For header tells the address of the client
+	TrustedProxies []netip.Prefix
+	// TLS serves the API servers over TLS and verifies client certificates;
+	// nil serves them in plain text
+	TLS *ServerTLS
+}
+
+// PullQueue lists the pulls waiting for a free pull slot and counts the
+// running ones, as controller.PullSlots does
+type PullQueue interface {
+	Queue() []controller.QueuedPull
+	Active() int
+}
+
+// Limits bounds the time and size of HTTP requests. Zero values select the
+// defaults in DefaultLimits.
+type Limits struct {
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	IdleTimeout  time.Duration
+	// MaxHeaderBytes bounds the size of request headers
+	MaxHeaderBytes int
+	// MaxBodyBytes bounds the size of request bodies
+	MaxBodyBytes int64
+}
+
+// DefaultLimits are the limits used for unset Limits fields
+var DefaultLimits = Limits{
+	ReadTimeout:    10 * time.Second,
+	WriteTimeout:   30 * time.Second,
+	IdleTimeout:    60 * time.Second,
+	MaxHeaderBytes: http.DefaultMaxHeaderBytes,
+	MaxBodyBytes:   1 << 20,
+}
+
+// withDefaults fills unset limits from DefaultLimits
+func (l Limits) withDefaults() Limits {
+	if l.ReadTimeout == 0 {
+		l.ReadTimeout = DefaultLimits.ReadTimeout
+	}
+	if l.WriteTimeout == 0 {
+		l.WriteTimeout = DefaultLimits.WriteTimeout
+	}
+	if l.IdleTimeout == 0 {
+		l.IdleTimeout = DefaultLimits.IdleTimeout
+	}
+	if l.MaxHeaderBytes == 0 {
+		l.MaxHeaderBytes = DefaultLimits.MaxHeaderBytes
+	}
+	if l.MaxBodyBytes == 0 {
+		l.MaxBodyBytes = DefaultLimits.MaxBodyBytes
+	}
+	return l
+}
+
+// modelLabels returns a copy of the labels of the models created through the
+// API, or nil if there are none
+func (c Config) modelLabels() map[string]string {
+	if len(c.ModelLabels) == 0 {
+		return nil
+	}
+	labels := make(map[string]string, len(c.ModelLabels))
+	for key, value := range c.ModelLabels {
+		labels[key] = value
+	}
+	return labels
+}
+
+// namespaceWatched reports whether the operator caches the resources of namespace
+func (c Config) namespaceWatched(namespace string) bool {
+	return len(c.WatchNamespaces) == 0 || slices.Contains(c.WatchNamespaces, namespace)
+}
+
+// reader returns the reader seeing the resources of every namespace
+func (s *Server) reader() client.Reader {
+	if s.config.Reader != nil {
+		return s.config.Reader
+	}
+	return s.client
+}
+
+// keyring returns the configured keyring, or one holding just APIKey
+func (c Config) keyring() *Keyring {
+	if c.Keyring != nil {
+		return c.Keyring
+	}
+	return NewKeyring(c.APIKey, false)
 }
 
 // Server represents the HTTP API server
 type Server struct {
 	config       Config
 	client       client.Client
+	cache        InformerCache
+	ollama       OllamaClient
+	registry     *registry.Client
+	keyring      *Keyring
+	audit        *audit.Logger
 	router       *mux.Router
 	server       *http.Server
 	shutdownChan chan struct{}
+	shutdownOnce sync.Once
 }
 
-// NewServer creates a new API server instance
-func NewServer(config Config, k8sClient client.Client) *Server {
+// NewServer creates a new API server instance. The Ollama client and cache are
+// optional; without them the endpoints that need them are unavailable.
+func NewServer(config Config, k8sClient client.Client, ollamaClient OllamaClient, cache InformerCache) *Server {
 	router := mux.NewRouter()
+	config.Limits = config.Limits.withDefaults()
 	server := &Server{
 		config:       config,
 		client:       k8sClient,
+		cache:        cache,
+		ollama:       ollamaClient,
+		registry:     registry.NewClient(config.RegistryURLs, nil),
+		keyring:      config.keyring(),
+		audit:        config.Auditor,
 		router:       router,
 		shutdownChan: make(chan struct{}),
 	}
 
 	// Setup routes
+	router.NotFoundHandler = http.HandlerFunc(notFound)
+	router.MethodNotAllowedHandler = http.HandlerFunc(methodNotAllowed)
+	router.Use(server.requestIDMiddleware)
+	router.Use(server.allowlistMiddleware)
+	router.Use(compressionMiddleware)
+	router.Use(server.bodyLimitMiddleware)
 	router.Use(server.metricsMiddleware)
 	router.Use(server.authMiddleware)
+	router.Use(server.readOnlyMiddleware)
 
 	// API v1 routes
 	apiV1 := router.PathPrefix("/api/v1").Subrouter()
 
-	// Models endpoints
-	apiV1.HandleFunc("/models", server.listModels).Methods(http.MethodGet)
-	apiV1.HandleFunc("/models", server.createModel).Methods(http.MethodPost)
-	apiV1.HandleFunc("/models/{name}", server.getModel).Methods(http.MethodGet)
-	apiV1.HandleFunc("/models/{name}", server.deleteModel).Methods(http.MethodDelete)
-	apiV1.HandleFunc("/models/{name}/refresh", server.refreshModel).Methods(http.MethodPost)
+	// Namespace-scoped models endpoints
+	server.registerModelRoutes(apiV1.PathPrefix("/namespaces/{namespace}").Subrouter())
+
+	// Models endpoints for the default namespace
+	server.registerModelRoutes(apiV1)
+
+	// Version endpoint
+	apiV1.HandleFunc("/version", server.getVersion).Methods(http.MethodGet)
+
+	// Registry endpoints
+	apiV1.HandleFunc("/registry/search", server.searchRegistry).Methods(http.MethodGet)
+
+	// Operations endpoints
+	apiV1.HandleFunc("/operations/{id}", server.getOperation).Methods(http.MethodGet)
+
+	// Admin endpoints
+	apiV1.HandleFunc("/admin/prune", server.clusterWide(server.audited(audit.ActionPrune, server.pruneModels))).Methods(http.MethodPost)
+	apiV1.HandleFunc("/admin/unmanaged", server.clusterWide(server.listUnmanaged)).Methods(http.MethodGet)
+	apiV1.HandleFunc("/admin/pull-queue", server.clusterWide(server.getPullQueue)).Methods(http.MethodGet)
+
+	// Ollama backend endpoints
+	apiV1.HandleFunc("/ollama/status", server.clusterWide(server.getOllamaStatus)).Methods(http.MethodGet)
 
 	// Health check endpoints
 	router.HandleFunc("/health", server.healthCheck).Methods(http.MethodGet)
@@ -81,34 +253,115 @@ func NewServer(config Config, k8sClient client.Client) *Server {
 	return server
 }
 
+// registerModelRoutes registers the models endpoints on the given router
+func (s *Server) registerModelRoutes(parent *mux.Router) {
+	r := parent.NewRoute().Subrouter()
+	r.Use(s.namespaceMiddleware)
+
+	r.HandleFunc("/models", s.listModels).Methods(http.MethodGet)
+	r.HandleFunc("/models", s.audited(audit.ActionCreate, s.createModel)).Methods(http.MethodPost)
+	r.HandleFunc("/models/{name}", s.getModel).Methods(http.MethodGet)
+	r.HandleFunc("/models/{name}", s.audited(audit.ActionApply, s.applyModel)).Methods(http.MethodPut)
+	r.HandleFunc("/models/{name}", s.audited(audit.ActionDelete, s.deleteModel)).Methods(http.MethodDelete)
+	r.HandleFunc("/models/{name}/refresh", s.audited(audit.ActionRefresh, s.refreshModel)).Methods(http.MethodPost)
+	r.HandleFunc("/models/{name}/retry", s.audited(audit.ActionRetry, s.retryModel)).Methods(http.MethodPost)
+	r.HandleFunc("/models/{name}/pull", s.audited(audit.ActionCancel, s.cancelPull)).Methods(http.MethodDelete)
+	r.HandleFunc("/models/{name}/copy", s.audited(audit.ActionCopy, s.copyModel)).Methods(http.MethodPost)
+	r.HandleFunc("/models/{name}/events", s.getModelEvents).Methods(http.MethodGet)
+	r.HandleFunc("/models/{name}/logs", s.getModelLogs).Methods(http.MethodGet)
+	r.HandleFunc("/models/{name}/modelfile", s.getModelfile).Methods(http.MethodGet)
+	r.HandleFunc("/stats", s.getStats).Methods(http.MethodGet)
+	r.HandleFunc("/export", s.exportModels).Methods(http.MethodGet)
+}
+
+// namespaceFor returns the namespace a request is scoped to, falling back to
+// the configured default namespace for the unscoped routes
+func (s *Server) namespaceFor(r *http.Request) string {
+	if ns := mux.Vars(r)["namespace"]; ns != "" {
+		return ns
+	}
+	return s.config.Namespace
+}
+
+// namespaceMiddleware refuses the requests for models in a namespace the
+// operator does not watch, whose lookups would fail in the cache, or the key
+// of the request may not reach
+func (s *Server) namespaceMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		namespace := s.namespaceFor(r)
+		if !s.config.namespaceWatched(namespace) {
+			sendError(w, fmt.Errorf("namespace %q is not watched by the operator", namespace), http.StatusForbidden)
+			return
+		}
+		if !authorizeNamespace(r.Context(), apiHTTP, namespace) {
+			sendError(w, fmt.Errorf("the key may not reach namespace %q", namespace), http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// clusterWide refuses the requests of keys restricted to some namespaces to
+// an endpoint spanning every namespace
+func (s *Server) clusterWide(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !authorizeNamespace(r.Context(), apiHTTP, "") {
+			sendError(w, errNamespacesForbidden, http.StatusForbidden)
+			return
+		}
+		next(w, r)
+	}
+}
+
 // Start starts the API server
 func (s *Server) Start(ctx context.Context) error {
 	logger := log.FromContext(ctx).WithName("api-server")
 	logger.Info("starting API server", "address", s.config.BindAddress)
 
+	// Streaming endpoints such as watch lift the write timeout for their own responses
 	s.server = &http.Server{
-		Addr:         s.config.BindAddress,
-		Handler:      s.router,
-		ReadTimeout:  10 * time.Second,
-		WriteTimeout: 30 * time.Second,
-		IdleTimeout:  60 * time.Second,
+		Addr:           s.config.BindAddress,
+		Handler:        s.router,
+		BaseContext:    func(net.Listener) context.Context { return log.IntoContext(context.Background(), logger) },
+		ReadTimeout:    s.config.Limits.ReadTimeout,
+		WriteTimeout:   s.config.Limits.WriteTimeout,
+		IdleTimeout:    s.config.Limits.IdleTimeout,
+		MaxHeaderBytes: s.config.Limits.MaxHeaderBytes,
 	}
 
+	served := make(chan error, 1)
 	go func() {
-		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			logger.Error(err, "API server failed to start")
-			close(s.shutdownChan)
+		if s.config.TLS != nil {
+			s.server.TLSConfig = s.config.TLS.Config()
+			served <- s.server.ListenAndServeTLS("", "")
+			return
 		}
+		served <- s.server.ListenAndServe()
 	}()
 
-	return nil
+	select {
+	case err := <-served:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		logger.Error(err, "API server failed to start")
+		return err
+	case <-ctx.Done():
+	}
+
+	// Let the requests in flight finish, past the cancellation of ctx
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
+	defer cancel()
+	return s.Shutdown(shutdownCtx)
 }
 
-// Shutdown stops the API server
+// Shutdown stops the API server. Streaming responses, such as watches, are
+// ended, and the other requests in flight are let finish until ctx is done.
 func (s *Server) Shutdown(ctx context.Context) error {
 	logger := log.FromContext(ctx).WithName("api-server")
 	logger.Info("shutting down API server")
 
+	s.shutdownOnce.Do(func() { close(s.shutdownChan) })
 	if s.server != nil {
 		return s.server.Shutdown(ctx)
 	}
@@ -134,11 +387,24 @@ func (s *Server) metricsMiddleware(next http.Handler) http.Handler {
 
 		// Record metrics
 		duration := time.Since(start).Seconds()
-		apiRequestsTotal.WithLabelValues(r.Method, r.URL.Path, fmt.Sprintf("%d", rw.statusCode)).Inc()
-		apiRequestDuration.WithLabelValues(r.Method, r.URL.Path).Observe(duration)
+		path := routePath(r)
+		apiRequestsTotal.WithLabelValues(r.Method, path, fmt.Sprintf("%d", rw.statusCode)).Inc()
+		apiRequestDuration.WithLabelValues(r.Method, path).Observe(duration)
 	})
 }
 
+// routePath returns the path template of the route serving r, such as
+// /api/v1/models/{name}, so that the metrics have a series per route rather
+// than one per model or per path a client makes up
+func routePath(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			return template
+		}
+	}
+	return "unmatched"
+}
+
 // authMiddleware handles authentication for the API
 func (s *Server) authMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -148,29 +414,82 @@ func (s *Server) authMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
-		// Check the API key if configured
-		if s.config.APIKey != "" {
-			apiKey := r.Header.Get("X-API-Key")
-			if subtle.ConstantTimeCompare([]byte(apiKey), []byte(s.config.APIKey)) != 1 {
-				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		// A verified client certificate authenticates the client in place of a key
+		if key, ok := clientCertKey(r.TLS); ok {
+			setPrincipal(r.Context(), key.Name, key.Role, key.Namespaces)
+			if !authorize(apiHTTP, key, mutatingMethod(r.Method)) {
+				sendError(w, errRoleForbidden, http.StatusForbidden)
 				return
 			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Check the API key if configured
+		if !s.keyring.Enabled() {
+			setPrincipal(r.Context(), anonymousPrincipal, RoleAdmin, nil)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		source := s.sourceIP(r)
+		if remaining, locked := s.config.Lockout.Locked(source); locked {
+			authenticationFailed.WithLabelValues(apiHTTP, reasonLockedOut).Inc()
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(remaining.Seconds()))))
+			sendError(w, errLockedOut, http.StatusTooManyRequests)
+			return
+		}
+
+		value := r.Header.Get("X-API-Key")
+		key, ok := s.keyring.Authenticate(value)
+		if !ok {
+			s.authenticationFailed(r, source, value)
+			sendError(w, errUnauthenticated, http.StatusUnauthorized)
+			return
+		}
+		s.config.Lockout.Succeeded(source)
+		setPrincipal(r.Context(), keyPrincipal(key), key.Role, key.Namespaces)
+
+		// Read-only keys may only use safe methods
+		if !authorize(apiHTTP, key, mutatingMethod(r.Method)) {
+			sendError(w, errRoleForbidden, http.StatusForbidden)
+			return
 		}
 
 		next.ServeHTTP(w, r)
 	})
 }
 
-// healthCheck handles the health check endpoint
-func (s *Server) healthCheck(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte("OK"))
+// authenticationFailed counts a request without a valid API key from source,
+// and locks the source out after too many of them
+func (s *Server) authenticationFailed(r *http.Request, source, value string) {
+	reason := reasonInvalidKey
+	if value == "" {
+		reason = reasonMissingKey
+	}
+	authenticationFailed.WithLabelValues(apiHTTP, reason).Inc()
+	if s.config.Lockout.Failed(source) {
+		lockouts.WithLabelValues(apiHTTP).Inc()
+		log.FromContext(r.Context()).Info("locking out source after repeated failed authentications", "source", source)
+	}
 }
 
-// readinessCheck handles the readiness check endpoint
-func (s *Server) readinessCheck(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte("Ready"))
+// readOnlyErrorMessage tells clients of a read-only API server where models are managed
+const readOnlyErrorMessage = "the API server is read-only: models are managed through the Kubernetes API, " +
+	"for instance by applying OllamaModel manifests from Git"
+
+// readOnlyMiddleware refuses the requests that may change resources with 405
+// Method Not Allowed when the API server is read-only
+func (s *Server) readOnlyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.config.ReadOnly && mutatingMethod(r.Method) {
+			denyReadOnly(apiHTTP, principalFromContext(r.Context()), roleFromContext(r.Context()))
+			w.Header().Set("Allow", http.MethodGet)
+			sendError(w, errors.New(readOnlyErrorMessage), http.StatusMethodNotAllowed)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
 }
 
 // responseWriter is a wrapper around http.ResponseWriter that captures the status code
@@ -185,6 +504,11 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+// Unwrap returns the wrapped ResponseWriter for http.ResponseController
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // sendJSON helper function to send JSON responses
 func sendJSON(w http.ResponseWriter, data interface{}, status int) {
 	w.Header().Set("Content-Type", "application/json")
@@ -199,6 +523,5 @@ func sendJSON(w http.ResponseWriter, data interface{}, status int) {
 
 // sendError helper function to send error responses
 func sendError(w http.ResponseWriter, err error, status int) {
-	errorRes := map[string]string{"error": err.Error()}
-	sendJSON(w, errorRes, status)
+	sendJSON(w, newErrorResponse(w, err, status), status)
 }
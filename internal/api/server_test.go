@@ -0,0 +1,516 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+var _ = Describe("API Server", func() {
+	var server *Server
+
+	newModel := func(namespace, name string) *ollamav1alpha1.OllamaModel {
+		return &ollamav1alpha1.OllamaModel{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       ollamav1alpha1.OllamaModelSpec{Name: "llama3.2", Tag: "1b"},
+		}
+	}
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		server.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	BeforeEach(func() {
+		server = NewServer(Config{Namespace: "default"}, newFakeClient(
+			newModel("default", "llama3.2-1b"),
+			newModel("team-a", "llama3.2-1b"),
+			newModel("team-a", "gemma3-1b"),
+		), nil, nil)
+	})
+
+	Context("namespace scoping", func() {
+		It("lists models in the default namespace on unscoped routes", func() {
+			rec := do(http.MethodGet, "/api/v1/models", "")
+			Expect(rec.Code).To(Equal(http.StatusOK))
+
+			var list ModelListResponse
+			Expect(json.Unmarshal(rec.Body.Bytes(), &list)).To(Succeed())
+			Expect(list.Items).To(HaveLen(1))
+			Expect(list.Items[0].Namespace).To(Equal("default"))
+		})
+
+		It("lists models in the namespace given in the path", func() {
+			rec := do(http.MethodGet, "/api/v1/namespaces/team-a/models", "")
+			Expect(rec.Code).To(Equal(http.StatusOK))
+
+			var list ModelListResponse
+			Expect(json.Unmarshal(rec.Body.Bytes(), &list)).To(Succeed())
+			Expect(list.Items).To(HaveLen(2))
+			for _, item := range list.Items {
+				Expect(item.Namespace).To(Equal("team-a"))
+			}
+		})
+
+		It("creates models in the namespace given in the path", func() {
+			rec := do(http.MethodPost, "/api/v1/namespaces/team-b/models", `{"name":"phi3","tag":"mini"}`)
+			Expect(rec.Code).To(Equal(http.StatusCreated))
+
+			model := &ollamav1alpha1.OllamaModel{}
+			Expect(server.client.Get(context.Background(), types.NamespacedName{Namespace: "team-b", Name: "phi3-mini"}, model)).To(Succeed())
+		})
+
+		It("labels created models to match the watched label selector", func() {
+			server.config.ModelLabels = map[string]string{"team": "ai-platform"}
+			rec := do(http.MethodPost, "/api/v1/namespaces/team-b/models", `{"name":"phi3","tag":"mini"}`)
+			Expect(rec.Code).To(Equal(http.StatusCreated))
+
+			model := &ollamav1alpha1.OllamaModel{}
+			Expect(server.client.Get(context.Background(), types.NamespacedName{Namespace: "team-b", Name: "phi3-mini"}, model)).To(Succeed())
+			Expect(model.Labels).To(HaveKeyWithValue("team", "ai-platform"))
+		})
+
+		It("labels models created by a PUT to match the watched label selector", func() {
+			server.config.ModelLabels = map[string]string{"team": "ai-platform"}
+			rec := do(http.MethodPut, "/api/v1/namespaces/team-b/models/phi3-mini", `{"name":"phi3","tag":"mini"}`)
+			Expect(rec.Code).To(Equal(http.StatusCreated))
+
+			model := &ollamav1alpha1.OllamaModel{}
+			Expect(server.client.Get(context.Background(), types.NamespacedName{Namespace: "team-b", Name: "phi3-mini"}, model)).To(Succeed())
+			Expect(model.Labels).To(HaveKeyWithValue("team", "ai-platform"))
+		})
+
+		It("returns 404 for a model outside the requested namespace", func() {
+			rec := do(http.MethodGet, "/api/v1/namespaces/team-b/models/llama3.2-1b", "")
+			Expect(rec.Code).To(Equal(http.StatusNotFound))
+		})
+
+		It("returns 403 for a namespace the operator does not watch", func() {
+			server.config.WatchNamespaces = []string{"default"}
+			rec := do(http.MethodGet, "/api/v1/namespaces/team-b/models", "")
+			Expect(rec.Code).To(Equal(http.StatusForbidden))
+			Expect(rec.Body.String()).To(ContainSubstring(`namespace \"team-b\" is not watched`))
+
+			Expect(do(http.MethodGet, "/api/v1/namespaces/default/models", "").Code).To(Equal(http.StatusOK))
+			Expect(do(http.MethodGet, "/api/v1/models", "").Code).To(Equal(http.StatusOK))
+			Expect(do(http.MethodGet, "/api/v1/version", "").Code).To(Equal(http.StatusOK))
+		})
+	})
+
+	Context("metrics", func() {
+		It("labels requests with their route rather than their path", func() {
+			route := apiRequestsTotal.WithLabelValues(http.MethodGet, "/api/v1/namespaces/{namespace}/models/{name}", "200")
+			before := testutil.ToFloat64(route)
+			Expect(do(http.MethodGet, "/api/v1/namespaces/team-a/models/llama3.2-1b", "").Code).To(Equal(http.StatusOK))
+			Expect(do(http.MethodGet, "/api/v1/namespaces/team-a/models/gemma3-1b", "").Code).To(Equal(http.StatusOK))
+			Expect(testutil.ToFloat64(route) - before).To(Equal(2.0))
+		})
+	})
+
+	Context("read-only mode", func() {
+		It("refuses changes with guidance and keeps serving reads", func() {
+			server.config.ReadOnly = true
+			rec := do(http.MethodPost, "/api/v1/models", `{"name":"phi3","tag":"mini"}`)
+			Expect(rec.Code).To(Equal(http.StatusMethodNotAllowed))
+			Expect(rec.Header().Get("Allow")).To(Equal(http.MethodGet))
+			Expect(rec.Body.String()).To(ContainSubstring("read-only"))
+
+			Expect(do(http.MethodDelete, "/api/v1/models/llama3.2-1b", "").Code).To(Equal(http.StatusMethodNotAllowed))
+			Expect(do(http.MethodGet, "/api/v1/models", "").Code).To(Equal(http.StatusOK))
+		})
+	})
+
+	Context("create or update", func() {
+		It("creates the model when it does not exist", func() {
+			rec := do(http.MethodPut, "/api/v1/namespaces/team-b/models/phi3", `{"name":"phi3","tag":"mini"}`)
+			Expect(rec.Code).To(Equal(http.StatusCreated))
+			Expect(rec.Header().Get("Location")).To(HavePrefix("/api/v1/operations/"))
+
+			model := &ollamav1alpha1.OllamaModel{}
+			Expect(server.client.Get(context.Background(), types.NamespacedName{Namespace: "team-b", Name: "phi3"}, model)).To(Succeed())
+			Expect(model.Spec.Tag).To(Equal("mini"))
+		})
+
+		It("updates the spec of an existing model", func() {
+			rec := do(http.MethodPut, "/api/v1/models/llama3.2-1b", `{"name":"llama3.2","tag":"3b"}`)
+			Expect(rec.Code).To(Equal(http.StatusOK))
+
+			model := &ollamav1alpha1.OllamaModel{}
+			Expect(server.client.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "llama3.2-1b"}, model)).To(Succeed())
+			Expect(model.Spec.Tag).To(Equal("3b"))
+		})
+
+		It("keeps the fields of the spec the request does not carry", func() {
+			model := &ollamav1alpha1.OllamaModel{}
+			key := types.NamespacedName{Namespace: "default", Name: "llama3.2-1b"}
+			Expect(server.client.Get(context.Background(), key, model)).To(Succeed())
+			requestsPerSecond := int32(5)
+			model.Spec.Limits = &ollamav1alpha1.InferenceLimits{RequestsPerSecond: &requestsPerSecond}
+			model.Spec.RestoreFrom = &ollamav1alpha1.SnapshotReference{Name: "llama-snapshot"}
+			model.Spec.UpdatePolicy = ollamav1alpha1.UpdatePolicyManual
+			Expect(server.client.Update(context.Background(), model)).To(Succeed())
+
+			Expect(do(http.MethodPut, "/api/v1/models/llama3.2-1b", `{"name":"llama3.2","tag":"1b","system":"Be brief."}`).Code).
+				To(Equal(http.StatusOK))
+			Expect(server.client.Get(context.Background(), key, model)).To(Succeed())
+			Expect(model.Spec.System).To(Equal("Be brief."))
+			Expect(model.Spec.Limits).NotTo(BeNil())
+			Expect(*model.Spec.Limits.RequestsPerSecond).To(BeEquivalentTo(5))
+			Expect(model.Spec.RestoreFrom).To(Equal(&ollamav1alpha1.SnapshotReference{Name: "llama-snapshot"}))
+			Expect(model.Spec.UpdatePolicy).To(Equal(ollamav1alpha1.UpdatePolicyManual))
+		})
+
+		It("is idempotent", func() {
+			Expect(do(http.MethodPut, "/api/v1/models/llama3.2-1b", `{"name":"llama3.2","tag":"1b"}`).Code).To(Equal(http.StatusOK))
+			Expect(do(http.MethodPut, "/api/v1/models/llama3.2-1b", `{"name":"llama3.2","tag":"1b"}`).Code).To(Equal(http.StatusOK))
+		})
+
+		It("requires a name and tag", func() {
+			Expect(do(http.MethodPut, "/api/v1/models/llama3.2-1b", `{"name":"llama3.2"}`).Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("rejects a second model managing the same Ollama model", func() {
+			rec := do(http.MethodPut, "/api/v1/models/llama-small", `{"name":"llama3.2","tag":"1b"}`)
+			Expect(rec.Code).To(Equal(http.StatusConflict))
+			Expect(rec.Body.String()).To(ContainSubstring("already managed by llama3.2-1b"))
+
+			// Other namespaces may manage the same model
+			Expect(do(http.MethodPut, "/api/v1/namespaces/team-b/models/llama-small", `{"name":"llama3.2","tag":"1b"}`).Code).
+				To(Equal(http.StatusCreated))
+		})
+
+		It("recognizes the same model under a qualified name", func() {
+			rec := do(http.MethodPut, "/api/v1/models/llama-small", `{"name":"registry.ollama.ai/library/llama3.2","tag":"1b"}`)
+			Expect(rec.Code).To(Equal(http.StatusConflict))
+			Expect(rec.Body.String()).To(ContainSubstring("already managed by llama3.2-1b"))
+		})
+
+		It("pulls the modelRef of a model in place of its name and tag", func() {
+			rec := do(http.MethodPost, "/api/v1/models", `{"name":"llama-hf","tag":"q4","modelRef":"hf.co/bartowski/Llama-3.2-1B-Instruct-GGUF:Q4_K_M"}`)
+			Expect(rec.Code).To(Equal(http.StatusCreated))
+
+			model := &ollamav1alpha1.OllamaModel{}
+			Expect(server.client.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "llama-hf-q4"}, model)).To(Succeed())
+			Expect(model.Spec.Reference()).To(Equal("hf.co/bartowski/Llama-3.2-1B-Instruct-GGUF:Q4_K_M"))
+		})
+
+		It("names user-namespaced models after the user and the model", func() {
+			rec := do(http.MethodPost, "/api/v1/models", `{"name":"username/custom-model","tag":"v2"}`)
+			Expect(rec.Code).To(Equal(http.StatusCreated))
+
+			model := &ollamav1alpha1.OllamaModel{}
+			Expect(server.client.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "username-custom-model-v2"}, model)).
+				To(Succeed())
+			Expect(model.Spec.Reference()).To(Equal("username/custom-model:v2"))
+		})
+	})
+
+	Context("retry", func() {
+		It("asks the controller to pull a Failed model again", func() {
+			ctx := context.Background()
+			model := &ollamav1alpha1.OllamaModel{}
+			key := types.NamespacedName{Namespace: "default", Name: "llama3.2-1b"}
+			Expect(server.client.Get(ctx, key, model)).To(Succeed())
+			model.Status.State = ollamav1alpha1.StateFailed
+			Expect(server.client.Status().Update(ctx, model)).To(Succeed())
+
+			rec := do(http.MethodPost, "/api/v1/models/llama3.2-1b/retry", "")
+			Expect(rec.Code).To(Equal(http.StatusAccepted))
+
+			Expect(server.client.Get(ctx, key, model)).To(Succeed())
+			Expect(model.Annotations).To(HaveKeyWithValue(ollamav1alpha1.RetryAnnotation, "true"))
+		})
+
+		It("only retries Failed models", func() {
+			Expect(do(http.MethodPost, "/api/v1/models/llama3.2-1b/retry", "").Code).To(Equal(http.StatusConflict))
+			Expect(do(http.MethodPost, "/api/v1/models/missing/retry", "").Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Context("model status", func() {
+		It("returns the progress, conditions and digest of a model", func() {
+			model := newModel("default", "phi3-mini")
+			model.Status = ollamav1alpha1.OllamaModelStatus{
+				State:    ollamav1alpha1.StatePulling,
+				Digest:   "8eeb52dfb3bb",
+				Progress: &ollamav1alpha1.PullProgress{Percent: 42, CompletedBytes: 420, TotalBytes: 1000},
+				Conditions: []metav1.Condition{{
+					Type:               "Ready",
+					Status:             metav1.ConditionFalse,
+					Reason:             "Pulling",
+					Message:            "Pulling phi3:mini",
+					LastTransitionTime: metav1.NewTime(time.Date(2025, 3, 25, 19, 4, 53, 0, time.UTC)),
+				}},
+			}
+			server = NewServer(Config{Namespace: "default"}, newFakeClient(model), nil, nil)
+
+			rec := do(http.MethodGet, "/api/v1/models/phi3-mini", "")
+			Expect(rec.Code).To(Equal(http.StatusOK))
+
+			var resp ModelResponse
+			Expect(json.Unmarshal(rec.Body.Bytes(), &resp)).To(Succeed())
+			Expect(resp.Digest).To(Equal("8eeb52dfb3bb"))
+			Expect(resp.Progress).To(Equal(&ProgressResponse{Percent: 42, CompletedBytes: 420, TotalBytes: 1000}))
+			Expect(resp.Conditions).To(ConsistOf(ConditionResponse{
+				Type:               "Ready",
+				Status:             "False",
+				Reason:             "Pulling",
+				Message:            "Pulling phi3:mini",
+				LastTransitionTime: "2025-03-25T19:04:53Z",
+			}))
+		})
+	})
+
+	Context("refresh", func() {
+		setState := func(state ollamav1alpha1.ModelState, progress *ollamav1alpha1.PullProgress) {
+			ctx := context.Background()
+			model := &ollamav1alpha1.OllamaModel{}
+			key := types.NamespacedName{Namespace: "default", Name: "llama3.2-1b"}
+			Expect(server.client.Get(ctx, key, model)).To(Succeed())
+			model.Status.State = state
+			model.Status.Progress = progress
+			Expect(server.client.Status().Update(ctx, model)).To(Succeed())
+		}
+
+		It("rejects a refresh while the model is being pulled", func() {
+			setState(ollamav1alpha1.StatePulling, &ollamav1alpha1.PullProgress{Percent: 42, CompletedBytes: 420, TotalBytes: 1000})
+
+			rec := do(http.MethodPost, "/api/v1/models/llama3.2-1b/refresh", "")
+			Expect(rec.Code).To(Equal(http.StatusConflict))
+
+			var resp PullInProgressResponse
+			Expect(json.Unmarshal(rec.Body.Bytes(), &resp)).To(Succeed())
+			Expect(resp.State).To(Equal(string(ollamav1alpha1.StatePulling)))
+			Expect(resp.Progress).NotTo(BeNil())
+			Expect(resp.Progress.Percent).To(Equal(int32(42)))
+
+			model := &ollamav1alpha1.OllamaModel{}
+			Expect(server.client.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "llama3.2-1b"}, model)).To(Succeed())
+			Expect(model.Annotations).NotTo(HaveKey(ollamav1alpha1.RefreshAnnotation))
+		})
+
+		It("rejects a second refresh the controller has not handled yet", func() {
+			setState(ollamav1alpha1.StateReady, nil)
+
+			Expect(do(http.MethodPost, "/api/v1/models/llama3.2-1b/refresh", "").Code).To(Equal(http.StatusAccepted))
+			Expect(do(http.MethodPost, "/api/v1/models/llama3.2-1b/refresh", "").Code).To(Equal(http.StatusConflict))
+		})
+	})
+
+	Context("pull cancellation", func() {
+		It("asks the controller to cancel a running pull", func() {
+			ctx := context.Background()
+			model := &ollamav1alpha1.OllamaModel{}
+			key := types.NamespacedName{Namespace: "default", Name: "llama3.2-1b"}
+			Expect(server.client.Get(ctx, key, model)).To(Succeed())
+			model.Status.State = ollamav1alpha1.StatePulling
+			Expect(server.client.Status().Update(ctx, model)).To(Succeed())
+
+			rec := do(http.MethodDelete, "/api/v1/models/llama3.2-1b/pull", "")
+			Expect(rec.Code).To(Equal(http.StatusAccepted))
+
+			Expect(server.client.Get(ctx, key, model)).To(Succeed())
+			Expect(model.Annotations).To(HaveKeyWithValue(ollamav1alpha1.CancelPullAnnotation, "true"))
+		})
+
+		It("rejects models that are not being pulled", func() {
+			ctx := context.Background()
+			model := &ollamav1alpha1.OllamaModel{}
+			key := types.NamespacedName{Namespace: "default", Name: "llama3.2-1b"}
+			Expect(server.client.Get(ctx, key, model)).To(Succeed())
+			model.Status.State = ollamav1alpha1.StateReady
+			Expect(server.client.Status().Update(ctx, model)).To(Succeed())
+
+			Expect(do(http.MethodDelete, "/api/v1/models/llama3.2-1b/pull", "").Code).To(Equal(http.StatusConflict))
+		})
+	})
+
+	Context("model events", func() {
+		It("returns the events involving the model, oldest first", func() {
+			model := newModel("default", "phi3-mini")
+			model.UID = "phi3-uid"
+			newEvent := func(name, reason string, uid types.UID, at time.Time) *corev1.Event {
+				return &corev1.Event{
+					ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "default"},
+					InvolvedObject: corev1.ObjectReference{Kind: "OllamaModel", Name: "phi3-mini", UID: uid},
+					Type:           corev1.EventTypeWarning,
+					Reason:         reason,
+					LastTimestamp:  metav1.NewTime(at),
+				}
+			}
+			now := time.Now()
+			server = NewServer(Config{Namespace: "default"}, newFakeClient(
+				model,
+				newEvent("e1", "RefreshFailed", "phi3-uid", now),
+				newEvent("e2", "RefreshStarted", "phi3-uid", now.Add(-time.Minute)),
+				newEvent("e3", "Unrelated", "other-uid", now),
+			), nil, nil)
+
+			rec := do(http.MethodGet, "/api/v1/models/phi3-mini/events", "")
+			Expect(rec.Code).To(Equal(http.StatusOK))
+
+			var list EventListResponse
+			Expect(json.Unmarshal(rec.Body.Bytes(), &list)).To(Succeed())
+			Expect(list.Items).To(HaveLen(2))
+			Expect(list.Items[0].Reason).To(Equal("RefreshStarted"))
+			Expect(list.Items[1].Reason).To(Equal("RefreshFailed"))
+		})
+	})
+
+	Context("conditional requests", func() {
+		get := func(path, etag string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			if etag != "" {
+				req.Header.Set("If-None-Match", etag)
+			}
+			rec := httptest.NewRecorder()
+			server.router.ServeHTTP(rec, req)
+			return rec
+		}
+
+		It("returns 304 for an unchanged model", func() {
+			rec := get("/api/v1/models/llama3.2-1b", "")
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			etag := rec.Header().Get("ETag")
+			Expect(etag).NotTo(BeEmpty())
+
+			rec = get("/api/v1/models/llama3.2-1b", etag)
+			Expect(rec.Code).To(Equal(http.StatusNotModified))
+			Expect(rec.Body.Len()).To(BeZero())
+		})
+
+		It("returns the list again once a model changes", func() {
+			rec := get("/api/v1/namespaces/team-a/models", "")
+			etag := rec.Header().Get("ETag")
+			Expect(get("/api/v1/namespaces/team-a/models", etag).Code).To(Equal(http.StatusNotModified))
+
+			model := &ollamav1alpha1.OllamaModel{}
+			key := types.NamespacedName{Namespace: "team-a", Name: "gemma3-1b"}
+			Expect(server.client.Get(context.Background(), key, model)).To(Succeed())
+			model.Status.State = ollamav1alpha1.StateReady
+			Expect(server.client.Status().Update(context.Background(), model)).To(Succeed())
+
+			rec = get("/api/v1/namespaces/team-a/models", etag)
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Header().Get("ETag")).NotTo(Equal(etag))
+		})
+
+		It("does not share ETags between namespaces", func() {
+			etag := get("/api/v1/models", "").Header().Get("ETag")
+			Expect(get("/api/v1/namespaces/team-a/models", etag).Code).To(Equal(http.StatusOK))
+		})
+	})
+
+	Context("request IDs", func() {
+		It("generates a request ID when none is supplied", func() {
+			rec := do(http.MethodGet, "/api/v1/models", "")
+			Expect(rec.Header().Get("X-Request-ID")).NotTo(BeEmpty())
+		})
+
+		It("propagates the client's request ID", func() {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/models", nil)
+			req.Header.Set("X-Request-ID", "abc-123")
+			rec := httptest.NewRecorder()
+			server.router.ServeHTTP(rec, req)
+			Expect(rec.Header().Get("X-Request-ID")).To(Equal("abc-123"))
+		})
+
+		It("tags Kubernetes API calls with the audit ID of the request", func() {
+			var auditID string
+			transport := RequestIDTransport(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				auditID = req.Header.Get("Audit-ID")
+				return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+			}))
+
+			ctx := context.WithValue(context.Background(), requestInfoKey{}, &requestInfo{id: "abc-123", auditID: "def-456"})
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://kubernetes/api", nil)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = transport.RoundTrip(req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(auditID).To(Equal("def-456"))
+		})
+
+		It("generates the audit ID rather than trusting the client's request ID", func() {
+			var requestID, auditID string
+			handler := server.requestIDMiddleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				requestID = RequestIDFromContext(r.Context())
+				auditID = auditIDFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/models", nil)
+			req.Header.Set("X-Request-ID", "abc-123")
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+			Expect(requestID).To(Equal("abc-123"))
+			Expect(uuid.Validate(auditID)).To(Succeed())
+
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/models", nil))
+			Expect(requestID).To(Equal(auditID))
+		})
+	})
+
+	Context("principals", func() {
+		BeforeEach(func() {
+			keyring := NewKeyring("", true)
+			Expect(keyring.LoadSecret(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "api-keys",
+					Annotations: map[string]string{APIKeyRolesAnnotation: "ci=admin,ops=admin"},
+				},
+				Data: map[string][]byte{"ci": []byte("ci-key"), "ops": []byte("ops-key")},
+			})).To(Succeed())
+			server = NewServer(Config{Namespace: "default", Keyring: keyring}, newFakeClient(), nil, nil)
+		})
+
+		doAs := func(key, method, path, body string) ModelResponse {
+			req := httptest.NewRequest(method, path, strings.NewReader(body))
+			req.Header.Set("X-API-Key", key)
+			rec := httptest.NewRecorder()
+			server.router.ServeHTTP(rec, req)
+			Expect(rec.Code).To(BeNumerically("<", http.StatusBadRequest))
+
+			var resp ModelResponse
+			Expect(json.Unmarshal(rec.Body.Bytes(), &resp)).To(Succeed())
+			return resp
+		}
+
+		It("records who created and refreshed a model", func() {
+			resp := doAs("ci-key", http.MethodPost, "/api/v1/models", `{"name":"phi3","tag":"mini"}`)
+			Expect(resp.CreatedBy).To(Equal("apikey:ci"))
+
+			model := &ollamav1alpha1.OllamaModel{}
+			key := types.NamespacedName{Namespace: "default", Name: "phi3-mini"}
+			Expect(server.client.Get(context.Background(), key, model)).To(Succeed())
+			model.Status.State = ollamav1alpha1.StateReady
+			Expect(server.client.Status().Update(context.Background(), model)).To(Succeed())
+
+			resp = doAs("ops-key", http.MethodPost, "/api/v1/models/phi3-mini/refresh", "")
+			Expect(resp.CreatedBy).To(Equal("apikey:ci"))
+			Expect(resp.RefreshedBy).To(Equal("apikey:ops"))
+
+			Expect(server.client.Get(context.Background(), key, model)).To(Succeed())
+			Expect(model.Annotations).To(HaveKeyWithValue(ollamav1alpha1.CreatedByAnnotation, "apikey:ci"))
+		})
+
+		It("keeps the creator when a model is updated", func() {
+			doAs("ci-key", http.MethodPut, "/api/v1/models/phi3", `{"name":"phi3","tag":"mini"}`)
+			resp := doAs("ops-key", http.MethodPut, "/api/v1/models/phi3", `{"name":"phi3","tag":"medium"}`)
+			Expect(resp.CreatedBy).To(Equal("apikey:ci"))
+		})
+	})
+})
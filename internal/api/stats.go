@@ -0,0 +1,80 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+// statsTopN bounds the largest models and failures listed in stats
+const statsTopN = 5
+
+// StatsResponse represents the API response for the stats endpoint
+type StatsResponse struct {
+	Namespace     string          `json:"namespace"`
+	TotalModels   int             `json:"totalModels"`
+	ByState       map[string]int  `json:"byState"`
+	Pulling       int             `json:"pulling"`
+	TotalBytes    int64           `json:"totalBytes"`
+	LargestModels []ModelResponse `json:"largestModels"`
+	Failures      []ModelResponse `json:"failures"`
+}
+
+// getStats handles the GET /api/v1/stats endpoint
+func (s *Server) getStats(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := log.FromContext(ctx).WithName("api-getStats")
+	namespace := s.namespaceFor(r)
+
+	var modelList ollamav1alpha1.OllamaModelList
+	if err := s.client.List(ctx, &modelList, client.InNamespace(namespace)); err != nil {
+		logger.Error(err, "failed to list models")
+		sendError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	response := StatsResponse{
+		Namespace:   namespace,
+		TotalModels: len(modelList.Items),
+		ByState: map[string]int{
+			string(ollamav1alpha1.StatePending):  0,
+			string(ollamav1alpha1.StatePulling):  0,
+			string(ollamav1alpha1.StateReady):    0,
+			string(ollamav1alpha1.StateFailed):   0,
+			string(ollamav1alpha1.StateDeleting): 0,
+		},
+		LargestModels: []ModelResponse{},
+		Failures:      []ModelResponse{},
+	}
+
+	models := modelList.Items
+	for _, model := range models {
+		state := model.Status.State
+		if state == "" {
+			state = ollamav1alpha1.StatePending
+		}
+		response.ByState[string(state)]++
+		response.TotalBytes += model.Status.Size
+
+		if state == ollamav1alpha1.StateFailed && len(response.Failures) < statsTopN {
+			response.Failures = append(response.Failures, convertModelToResponse(model))
+		}
+	}
+	response.Pulling = response.ByState[string(ollamav1alpha1.StatePulling)]
+
+	sort.SliceStable(models, func(i, j int) bool {
+		return models[i].Status.Size > models[j].Status.Size
+	})
+	for _, model := range models {
+		if model.Status.Size == 0 || len(response.LargestModels) == statsTopN {
+			break
+		}
+		response.LargestModels = append(response.LargestModels, convertModelToResponse(model))
+	}
+
+	sendResponse(w, r, response, http.StatusOK)
+}
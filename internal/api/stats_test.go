@@ -0,0 +1,50 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+var _ = Describe("Stats", func() {
+	newModel := func(namespace, name string, state ollamav1alpha1.ModelState, size int64) *ollamav1alpha1.OllamaModel {
+		return &ollamav1alpha1.OllamaModel{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       ollamav1alpha1.OllamaModelSpec{Name: name, Tag: "latest"},
+			Status:     ollamav1alpha1.OllamaModelStatus{State: state, Size: size},
+		}
+	}
+
+	It("aggregates models in the namespace", func() {
+		server := NewServer(Config{Namespace: "default"}, newFakeClient(
+			newModel("default", "small", ollamav1alpha1.StateReady, 100),
+			newModel("default", "large", ollamav1alpha1.StateReady, 300),
+			newModel("default", "pulling", ollamav1alpha1.StatePulling, 0),
+			newModel("default", "broken", ollamav1alpha1.StateFailed, 0),
+			newModel("default", "new", "", 0),
+			newModel("team-a", "elsewhere", ollamav1alpha1.StateReady, 1000),
+		), nil, nil)
+
+		rec := httptest.NewRecorder()
+		server.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/stats", nil))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+
+		var stats StatsResponse
+		Expect(json.Unmarshal(rec.Body.Bytes(), &stats)).To(Succeed())
+		Expect(stats.TotalModels).To(Equal(5))
+		Expect(stats.ByState).To(Equal(map[string]int{"Pending": 1, "Pulling": 1, "Ready": 2, "Failed": 1, "Deleting": 0}))
+		Expect(stats.Pulling).To(Equal(1))
+		Expect(stats.TotalBytes).To(Equal(int64(400)))
+		Expect(stats.LargestModels).To(HaveLen(2))
+		Expect(stats.LargestModels[0].Name).To(Equal("large"))
+		Expect(stats.Failures).To(HaveLen(1))
+		Expect(stats.Failures[0].Name).To(Equal("broken"))
+	})
+})
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+func TestAPI(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "API Suite")
+}
+
+// newTestScheme returns a scheme with the core and OllamaModel types registered
+func newTestScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	Expect(ollamav1alpha1.AddToScheme(scheme)).To(Succeed())
+	return scheme
+}
+
+// newFakeClient returns a fake Kubernetes client seeded with the given objects
+func newFakeClient(objs ...client.Object) client.Client {
+	return fake.NewClientBuilder().
+		WithScheme(newTestScheme()).
+		WithObjects(objs...).
+		WithStatusSubresource(&ollamav1alpha1.OllamaModel{}).
+		WithIndex(&corev1.Event{}, "involvedObject.uid", func(o client.Object) []string {
+			return []string{string(o.(*corev1.Event).InvolvedObject.UID)}
+		}).
+		WithIndex(&ollamav1alpha1.OllamaModel{}, ollamav1alpha1.ModelReferenceField, func(o client.Object) []string {
+			model := o.(*ollamav1alpha1.OllamaModel)
+			return []string{ollamav1alpha1.NormalizeModelReference(model.Spec.Reference())}
+		}).
+		Build()
+}
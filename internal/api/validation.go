@@ -0,0 +1,32 @@
+package api
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+// validateModel checks the name and tag of a model request, and the name of
+// the OllamaModel managing it, so that malformed names are rejected with the
+// fields at fault instead of failing at pull time. The fields are named as in
+// the request body.
+func validateModel(resourceName string, spec ollamav1alpha1.OllamaModelSpec) error {
+	errs := spec.ValidateReference(nil)
+	if len(errs) == 0 {
+		for _, msg := range validation.IsDNS1123Subdomain(resourceName) {
+			errs = append(errs, field.Invalid(field.NewPath("name"), resourceName, "makes an invalid resource name: "+msg))
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+
+	details := make([]ErrorDetail, 0, len(errs))
+	for _, err := range errs {
+		details = append(details, ErrorDetail{Field: err.Field, Message: err.ErrorBody()})
+	}
+	return validationError(fmt.Errorf("invalid model: %w", errs.ToAggregate()), details...)
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+var _ = Describe("Model validation", func() {
+	validate := func(name, tag string) error {
+		spec := ollamav1alpha1.OllamaModelSpec{Name: name, Tag: tag}
+		return validateModel(modelResourceName(name, tag), spec)
+	}
+
+	It("accepts the names and tags of the Ollama library", func() {
+		Expect(validate("llama3.2", "1b")).To(Succeed())
+		Expect(validate("qwen2.5-coder", "7b-instruct-q4_K_M")).To(Succeed())
+		Expect(validate("nomic-embed-text", "latest")).To(Succeed())
+	})
+
+	It("rejects malformed names and tags", func() {
+		Expect(validate("llama 3", "1b")).NotTo(Succeed())
+		Expect(validate("llama3", "1b:latest")).NotTo(Succeed())
+		Expect(validate(".hidden", "1b")).NotTo(Succeed())
+		Expect(validate("llama3", strings.Repeat("a", ollamav1alpha1.MaxModelPartLength+1))).NotTo(Succeed())
+	})
+
+	It("accepts names qualified with a registry host", func() {
+		Expect(validate("registry.example.com/team/model", "1b")).To(Succeed())
+		Expect(validate("registry.example.com:5000/team/model", "latest")).To(Succeed())
+		Expect(validate("registry.ollama.ai/library/llama3.2", "1b")).To(Succeed())
+
+		Expect(validate("registry.example.com/team/model/extra", "1b")).NotTo(Succeed())
+		Expect(validate("registry example.com/team/model", "1b")).NotTo(Succeed())
+		Expect(validate("registry.example.com/te.am/model", "1b")).NotTo(Succeed())
+	})
+
+	It("names the resources of qualified models after their shortest name", func() {
+		Expect(modelResourceName("llama3.2", "8b-q4_K_M")).To(Equal("llama3.2-8b-q4-k-m"))
+		Expect(modelResourceName("registry.ollama.ai/library/llama3.2", "1b")).To(Equal("llama3.2-1b"))
+		Expect(modelResourceName("registry.example.com:5000/team/model", "1b")).
+			To(Equal("registry.example.com-5000-team-model-1b"))
+	})
+
+	It("passes a modelRef through verbatim", func() {
+		spec := ollamav1alpha1.OllamaModelSpec{Name: "llama-hf", Tag: "q4", ModelRef: "hf.co/bartowski/Llama-3.2-1B-Instruct-GGUF:Q4_K_M"}
+		Expect(validateModel("llama-hf-q4", spec)).To(Succeed())
+		Expect(spec.Reference()).To(Equal("hf.co/bartowski/Llama-3.2-1B-Instruct-GGUF:Q4_K_M"))
+
+		spec.ModelRef = "hf.co/bartowski/Llama 3.2"
+		Expect(validateModel("llama-hf-q4", spec)).NotTo(Succeed())
+		spec.ModelRef, spec.Quantization = "hf.co/bartowski/Llama-3.2-1B-Instruct-GGUF", "q8_0"
+		Expect(validateModel("llama-hf-q4", spec)).NotTo(Succeed())
+	})
+
+	It("rejects the fields that cannot be combined", func() {
+		dimensions := int32(768)
+		spec := ollamav1alpha1.OllamaModelSpec{Name: "nomic-embed-text", Tag: "latest", ExpectedDimensions: &dimensions}
+		Expect(validateModel("nomic-embed-text-latest", spec)).NotTo(Succeed())
+		spec.Type = ollamav1alpha1.ModelTypeEmbedding
+		Expect(validateModel("nomic-embed-text-latest", spec)).To(Succeed())
+
+		spec = ollamav1alpha1.OllamaModelSpec{Name: "llama3.2", Tag: "1b",
+			RestoreFrom: &ollamav1alpha1.SnapshotReference{Name: "llama-snapshot"}, UpdatePolicy: ollamav1alpha1.UpdatePolicyAuto}
+		Expect(validateModel("llama3.2-1b", spec)).NotTo(Succeed())
+		spec.UpdatePolicy = ollamav1alpha1.UpdatePolicyManual
+		Expect(validateModel("llama3.2-1b", spec)).To(Succeed())
+	})
+
+	It("rejects names that make an invalid resource name", func() {
+		Expect(validate("_llama3", "1b")).NotTo(Succeed())
+	})
+
+	It("returns the fields at fault", func() {
+		server := NewServer(Config{Namespace: "default"}, newFakeClient(), nil, nil)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/models", strings.NewReader(`{"name":"llama 3","tag":"1b?"}`))
+		rec := httptest.NewRecorder()
+		server.router.ServeHTTP(rec, req)
+		Expect(rec.Code).To(Equal(http.StatusUnprocessableEntity))
+
+		var resp ErrorResponse
+		Expect(json.Unmarshal(rec.Body.Bytes(), &resp)).To(Succeed())
+		Expect(resp.Code).To(Equal(CodeValidationFailed))
+		Expect(resp.Details).To(HaveLen(2))
+		Expect(resp.Details[0].Field).To(Equal("name"))
+		Expect(resp.Details[1].Field).To(Equal("tag"))
+	})
+})
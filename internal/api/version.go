@@ -0,0 +1,49 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+	"github.com/dmk/ollama-operator/internal/version"
+)
+
+// supportedAPIVersions lists the HTTP and Kubernetes API versions served by the operator
+var supportedAPIVersions = []string{"v1", ollamav1alpha1.GroupVersion.String()}
+
+// VersionResponse represents the API response for the version endpoint
+type VersionResponse struct {
+	version.Info
+	APIVersions   []string `json:"apiVersions"`
+	OllamaVersion string   `json:"ollamaVersion,omitempty"`
+	OllamaError   string   `json:"ollamaError,omitempty"`
+}
+
+// getVersion handles the GET /api/v1/version endpoint
+func (s *Server) getVersion(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := log.FromContext(ctx).WithName("api-getVersion")
+
+	response := VersionResponse{
+		Info:        version.Get(),
+		APIVersions: supportedAPIVersions,
+	}
+
+	// The Ollama version is best effort so the endpoint stays useful when the backend is down
+	if s.ollama != nil {
+		ctx, cancel := context.WithTimeout(ctx, readinessTimeout)
+		defer cancel()
+
+		ollamaVersion, err := s.ollama.Version(ctx)
+		if err != nil {
+			logger.Error(err, "failed to get Ollama server version")
+			response.OllamaError = err.Error()
+		} else {
+			response.OllamaVersion = ollamaVersion
+		}
+	}
+
+	sendResponse(w, r, response, http.StatusOK)
+}
@@ -0,0 +1,145 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+const (
+	// watchBufferSize is the number of events buffered for a slow watch client
+	// before its stream is closed
+	watchBufferSize = 100
+
+	// maxWatchTimeout bounds how long a watch stream stays open; clients reconnect after it
+	maxWatchTimeout = 30 * time.Minute
+
+	contentTypeNDJSON = "application/x-ndjson"
+)
+
+// Watch event types
+const (
+	WatchAdded    = "ADDED"
+	WatchModified = "MODIFIED"
+	WatchDeleted  = "DELETED"
+)
+
+// WatchEvent is a single line of a model watch stream
+type WatchEvent struct {
+	Type   string        `json:"type"`
+	Object ModelResponse `json:"object"`
+}
+
+// watchModels streams model changes in a namespace as newline-delimited JSON.
+// It serves GET /api/v1/models?watch=true; existing models are sent first as
+// ADDED events.
+func (s *Server) watchModels(w http.ResponseWriter, r *http.Request, namespace string) {
+	ctx := r.Context()
+	logger := log.FromContext(ctx).WithName("api-watchModels")
+
+	timeout := maxWatchTimeout
+	if value := r.URL.Query().Get("timeoutSeconds"); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil || seconds <= 0 {
+			sendError(w, fmt.Errorf("invalid timeoutSeconds: %q", value), http.StatusBadRequest)
+			return
+		}
+		timeout = min(time.Duration(seconds)*time.Second, maxWatchTimeout)
+	}
+
+	if s.cache == nil {
+		sendError(w, errors.New("watch is not available without an informer cache"), http.StatusServiceUnavailable)
+		return
+	}
+
+	informer, err := s.cache.GetInformer(ctx, &ollamav1alpha1.OllamaModel{})
+	if err != nil {
+		logger.Error(err, "failed to get model informer")
+		sendError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	events := make(chan WatchEvent, watchBufferSize)
+	overflow := make(chan struct{})
+	done := make(chan struct{})
+	defer close(done)
+
+	send := func(eventType string, obj interface{}) {
+		if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+		model, ok := obj.(*ollamav1alpha1.OllamaModel)
+		if !ok || model.Namespace != namespace {
+			return
+		}
+
+		select {
+		case events <- WatchEvent{Type: eventType, Object: convertModelToResponse(*model)}:
+		case <-done:
+		default:
+			// The client is not keeping up; end the stream so it can resync
+			select {
+			case <-overflow:
+			default:
+				close(overflow)
+			}
+		}
+	}
+
+	registration, err := informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { send(WatchAdded, obj) },
+		UpdateFunc: func(_, obj interface{}) { send(WatchModified, obj) },
+		DeleteFunc: func(obj interface{}) { send(WatchDeleted, obj) },
+	})
+	if err != nil {
+		logger.Error(err, "failed to register watch handler")
+		sendError(w, err, http.StatusInternalServerError)
+		return
+	}
+	defer func() {
+		if err := informer.RemoveEventHandler(registration); err != nil {
+			logger.Error(err, "failed to remove watch handler")
+		}
+	}()
+
+	// Streams outlive the server's write timeout
+	controller := http.NewResponseController(w)
+	if err := controller.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		logger.Error(err, "failed to clear write deadline")
+	}
+
+	w.Header().Set("Content-Type", contentTypeNDJSON)
+	w.WriteHeader(http.StatusOK)
+	controller.Flush()
+
+	encoder := json.NewEncoder(w)
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case event := <-events:
+			if err := encoder.Encode(event); err != nil {
+				return
+			}
+			controller.Flush()
+		case <-overflow:
+			logger.Info("closing watch stream of slow client")
+			return
+		case <-timer.C:
+			return
+		case <-ctx.Done():
+			return
+		case <-s.shutdownChan:
+			return
+		}
+	}
+}
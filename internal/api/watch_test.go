@@ -0,0 +1,87 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllertest"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+var _ = Describe("Watch", func() {
+	var (
+		httpServer *httptest.Server
+		informer   *controllertest.FakeInformer
+	)
+
+	newModel := func(namespace, name string, state ollamav1alpha1.ModelState) *ollamav1alpha1.OllamaModel {
+		return &ollamav1alpha1.OllamaModel{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       ollamav1alpha1.OllamaModelSpec{Name: "llama3.2", Tag: "1b"},
+			Status:     ollamav1alpha1.OllamaModelStatus{State: state},
+		}
+	}
+
+	BeforeEach(func() {
+		cache := fakeCache(true)
+		var err error
+		informer, err = cache.FakeInformerFor(context.Background(), &ollamav1alpha1.OllamaModel{})
+		Expect(err).NotTo(HaveOccurred())
+
+		server := NewServer(Config{Namespace: "default"}, newFakeClient(), nil, cache)
+		httpServer = httptest.NewServer(server.router)
+		DeferCleanup(httpServer.Close)
+	})
+
+	It("streams changes to models in the namespace as newline-delimited JSON", func() {
+		resp, err := http.Get(httpServer.URL + "/api/v1/models?watch=true")
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(resp.Header.Get("Content-Type")).To(Equal(contentTypeNDJSON))
+
+		pulling := newModel("default", "llama3.2-1b", ollamav1alpha1.StatePulling)
+		ready := newModel("default", "llama3.2-1b", ollamav1alpha1.StateReady)
+		informer.Add(pulling)
+		informer.Add(newModel("team-a", "llama3.2-1b", ollamav1alpha1.StatePulling))
+		informer.Update(pulling, ready)
+		informer.Delete(ready)
+
+		lines := bufio.NewScanner(resp.Body)
+		next := func() WatchEvent {
+			Expect(lines.Scan()).To(BeTrue())
+			var event WatchEvent
+			Expect(json.Unmarshal(lines.Bytes(), &event)).To(Succeed())
+			return event
+		}
+
+		event := next()
+		Expect(event.Type).To(Equal(WatchAdded))
+		Expect(event.Object.State).To(Equal("Pulling"))
+
+		event = next()
+		Expect(event.Type).To(Equal(WatchModified))
+		Expect(event.Object.Namespace).To(Equal("default"))
+		Expect(event.Object.State).To(Equal("Ready"))
+
+		Expect(next().Type).To(Equal(WatchDeleted))
+	})
+
+	It("ends the stream after the requested timeout", func() {
+		resp, err := http.Get(httpServer.URL + "/api/v1/models?watch=true&timeoutSeconds=1")
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+
+		lines := bufio.NewScanner(resp.Body)
+		Expect(lines.Scan()).To(BeFalse())
+		Expect(lines.Err()).NotTo(HaveOccurred())
+	})
+})
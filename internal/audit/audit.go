@@ -0,0 +1,171 @@
+// Package audit records mutating operations on OllamaModels to audit sinks.
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Sources of audited operations
+const (
+	SourceAPI        = "api"
+	SourceGRPC       = "grpc"
+	SourceController = "controller"
+)
+
+// Actions recorded in the audit log
+const (
+	ActionCreate  = "create"
+	ActionApply   = "apply"
+	ActionDelete  = "delete"
+	ActionRefresh = "refresh"
+	ActionRetry   = "retry"
+	ActionCancel  = "cancel"
+	ActionCopy    = "copy"
+	ActionPull    = "pull"
+	ActionPrune   = "prune"
+	ActionPromote = "promote"
+)
+
+// Outcomes of audited operations
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// ControllerPrincipal identifies operations initiated by the operator itself
+const ControllerPrincipal = "system:ollama-operator"
+
+// Event is a single audit record
+type Event struct {
+	Time      time.Time `json:"time"`
+	Source    string    `json:"source"`
+	Action    string    `json:"action"`
+	Principal string    `json:"principal"`
+	// Requester is the API principal on whose behalf the controller acted
+	Requester string `json:"requester,omitempty"`
+	SourceIP  string `json:"sourceIP,omitempty"`
+	RequestID string `json:"requestID,omitempty"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Model     string `json:"model,omitempty"`
+	Outcome   string `json:"outcome"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Sink receives audit events
+type Sink interface {
+	Write(ctx context.Context, event Event) error
+}
+
+// Logger fans audit events out to its sinks. A nil Logger discards events.
+type Logger struct {
+	sinks []Sink
+}
+
+// NewLogger creates an audit logger writing to the given sinks
+func NewLogger(sinks ...Sink) *Logger {
+	return &Logger{sinks: sinks}
+}
+
+// Record writes event to every sink, filling in the time if unset. Sink
+// failures are logged rather than returned so auditing never blocks an operation.
+func (l *Logger) Record(ctx context.Context, event Event) {
+	if l == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+	if event.Outcome == "" {
+		event.Outcome = OutcomeSuccess
+	}
+
+	for _, sink := range l.sinks {
+		if err := sink.Write(ctx, event); err != nil {
+			log.FromContext(ctx).Error(err, "failed to write audit event", "action", event.Action, "name", event.Name)
+		}
+	}
+}
+
+// WithError sets the outcome of event from err
+func (e Event) WithError(err error) Event {
+	if err != nil {
+		e.Outcome = OutcomeFailure
+		e.Error = err.Error()
+	}
+	return e
+}
+
+// WriterSink writes events as JSON lines to an io.Writer such as stdout or a file
+type WriterSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterSink creates a sink writing JSON lines to w
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{w: w}
+}
+
+// Write implements Sink
+func (s *WriterSink) Write(_ context.Context, event Event) error {
+	line, err := json.Marshal(struct {
+		Audit Event `json:"audit"`
+	}{event})
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(append(line, '\n'))
+	return err
+}
+
+// WebhookSink posts events as JSON to an external collector
+type WebhookSink struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewWebhookSink creates a sink posting events to url
+func NewWebhookSink(url string, httpClient *http.Client) *WebhookSink {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &WebhookSink{url: url, httpClient: httpClient}
+}
+
+// Write implements Sink
+func (s *WebhookSink) Write(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("audit webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
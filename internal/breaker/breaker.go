@@ -0,0 +1,251 @@
+// Package breaker guards the Ollama client with a circuit breaker. After a
+// number of consecutive failures to reach the Ollama server, calls fail fast
+// for a cooldown period instead of each of them timing out, after which a
+// single trial call decides whether the server is back.
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/ollama/ollama/api"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// State is the state of a circuit breaker
+type State int
+
+// Circuit breaker states
+const (
+	// Closed lets calls through
+	Closed State = iota
+	// HalfOpen lets a single trial call through after the cooldown
+	HalfOpen
+	// Open fails calls without calling the Ollama server
+	Open
+)
+
+func (s State) String() string {
+	switch s {
+	case HalfOpen:
+		return "half-open"
+	case Open:
+		return "open"
+	default:
+		return "closed"
+	}
+}
+
+var stateGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "ollama_circuit_breaker_state",
+	Help: "State of the circuit breaker around the Ollama server (0 closed, 1 half-open, 2 open)",
+})
+
+func init() {
+	metrics.Registry.MustRegister(stateGauge)
+}
+
+// ErrOpen is matched by errors returned while the circuit is open
+var ErrOpen = errors.New("ollama circuit breaker is open")
+
+// OpenError is returned instead of calling the Ollama server while the circuit is open
+type OpenError struct {
+	// RetryAfter is the time until the next trial call is let through
+	RetryAfter time.Duration
+	// Cause is the failure that opened the circuit
+	Cause error
+}
+
+func (e *OpenError) Error() string {
+	return fmt.Sprintf("%v, retrying in %s: %v", ErrOpen, e.RetryAfter.Round(time.Second), e.Cause)
+}
+
+// Is makes OpenError match ErrOpen
+func (e *OpenError) Is(target error) bool {
+	return target == ErrOpen
+}
+
+// Upstream is the Ollama client guarded by the breaker
+type Upstream interface {
+	Delete(ctx context.Context, req *api.DeleteRequest) error
+	Show(ctx context.Context, req *api.ShowRequest) (*api.ShowResponse, error)
+	Pull(ctx context.Context, req *api.PullRequest, fn api.PullProgressFunc) error
+	Create(ctx context.Context, req *api.CreateRequest, fn api.CreateProgressFunc) error
+	Copy(ctx context.Context, req *api.CopyRequest) error
+	Embed(ctx context.Context, req *api.EmbedRequest) (*api.EmbedResponse, error)
+	List(ctx context.Context) (*api.ListResponse, error)
+}
+
+// Client is an Ollama client guarded by a circuit breaker
+type Client struct {
+	upstream  Upstream
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu        sync.Mutex
+	state     State
+	failures  int
+	openedAt  time.Time
+	lastError error
+	trial     bool
+}
+
+// New guards upstream with a breaker that opens after threshold consecutive
+// failures and stays open for cooldown
+func New(upstream Upstream, threshold int, cooldown time.Duration) *Client {
+	stateGauge.Set(float64(Closed))
+	return &Client{upstream: upstream, threshold: threshold, cooldown: cooldown, now: time.Now}
+}
+
+// State returns the current state of the breaker
+func (c *Client) State() State {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.state == Open && c.now().Sub(c.openedAt) >= c.cooldown {
+		return HalfOpen
+	}
+	return c.state
+}
+
+// Show calls Show on the Ollama server unless the circuit is open
+func (c *Client) Show(ctx context.Context, req *api.ShowRequest) (*api.ShowResponse, error) {
+	if err := c.allow(); err != nil {
+		return nil, err
+	}
+	resp, err := c.upstream.Show(ctx, req)
+	c.record(err)
+	return resp, err
+}
+
+// List calls List on the Ollama server unless the circuit is open
+func (c *Client) List(ctx context.Context) (*api.ListResponse, error) {
+	if err := c.allow(); err != nil {
+		return nil, err
+	}
+	resp, err := c.upstream.List(ctx)
+	c.record(err)
+	return resp, err
+}
+
+// Pull calls Pull on the Ollama server unless the circuit is open
+func (c *Client) Pull(ctx context.Context, req *api.PullRequest, fn api.PullProgressFunc) error {
+	if err := c.allow(); err != nil {
+		return err
+	}
+	err := c.upstream.Pull(ctx, req, fn)
+	c.record(err)
+	return err
+}
+
+// Create calls Create on the Ollama server unless the circuit is open
+func (c *Client) Create(ctx context.Context, req *api.CreateRequest, fn api.CreateProgressFunc) error {
+	if err := c.allow(); err != nil {
+		return err
+	}
+	err := c.upstream.Create(ctx, req, fn)
+	c.record(err)
+	return err
+}
+
+// Copy calls Copy on the Ollama server unless the circuit is open
+func (c *Client) Copy(ctx context.Context, req *api.CopyRequest) error {
+	if err := c.allow(); err != nil {
+		return err
+	}
+	err := c.upstream.Copy(ctx, req)
+	c.record(err)
+	return err
+}
+
+// Embed calls Embed on the Ollama server unless the circuit is open
+func (c *Client) Embed(ctx context.Context, req *api.EmbedRequest) (*api.EmbedResponse, error) {
+	if err := c.allow(); err != nil {
+		return nil, err
+	}
+	resp, err := c.upstream.Embed(ctx, req)
+	c.record(err)
+	return resp, err
+}
+
+// Delete calls Delete on the Ollama server unless the circuit is open
+func (c *Client) Delete(ctx context.Context, req *api.DeleteRequest) error {
+	if err := c.allow(); err != nil {
+		return err
+	}
+	err := c.upstream.Delete(ctx, req)
+	c.record(err)
+	return err
+}
+
+// allow returns an *OpenError if a call may not go through. Once the cooldown
+// has passed, a single trial call is let through.
+func (c *Client) allow() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch c.state {
+	case Closed:
+		return nil
+	case Open:
+		if wait := c.cooldown - c.now().Sub(c.openedAt); wait > 0 {
+			return &OpenError{RetryAfter: wait, Cause: c.lastError}
+		}
+		c.setState(HalfOpen)
+	}
+
+	if c.trial {
+		return &OpenError{RetryAfter: c.cooldown, Cause: c.lastError}
+	}
+	c.trial = true
+	return nil
+}
+
+// record updates the breaker with the outcome of a call
+func (c *Client) record(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.trial = false
+
+	if !unavailable(err) {
+		c.failures = 0
+		c.setState(Closed)
+		return
+	}
+
+	c.failures++
+	c.lastError = err
+	if c.state == HalfOpen || c.failures >= c.threshold {
+		c.openedAt = c.now()
+		c.setState(Open)
+	}
+}
+
+// setState changes the state and updates the state metric
+func (c *Client) setState(state State) {
+	c.state = state
+	stateGauge.Set(float64(state))
+}
+
+// unavailable reports whether err means that the Ollama server could not be
+// reached or failed, as opposed to rejecting the request, such as for a model
+// that does not exist
+func unavailable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var statusErr api.StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500
+	}
+	var urlErr *url.Error
+	var netErr net.Error
+	return errors.As(err, &urlErr) || errors.As(err, &netErr)
+}
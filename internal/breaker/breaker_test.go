@@ -0,0 +1,129 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/ollama/ollama/api"
+)
+
+// flakyOllama fails every call with err, counting the calls that reach it
+type flakyOllama struct {
+	calls int
+	err   error
+}
+
+func (f *flakyOllama) Delete(context.Context, *api.DeleteRequest) error {
+	f.calls++
+	return f.err
+}
+
+func (f *flakyOllama) Pull(context.Context, *api.PullRequest, api.PullProgressFunc) error {
+	f.calls++
+	return f.err
+}
+
+func (f *flakyOllama) Create(context.Context, *api.CreateRequest, api.CreateProgressFunc) error {
+	f.calls++
+	return f.err
+}
+
+func (f *flakyOllama) Copy(context.Context, *api.CopyRequest) error {
+	f.calls++
+	return f.err
+}
+
+func (f *flakyOllama) Embed(context.Context, *api.EmbedRequest) (*api.EmbedResponse, error) {
+	f.calls++
+	return &api.EmbedResponse{}, f.err
+}
+
+func (f *flakyOllama) Show(context.Context, *api.ShowRequest) (*api.ShowResponse, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &api.ShowResponse{}, nil
+}
+
+func (f *flakyOllama) List(context.Context) (*api.ListResponse, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &api.ListResponse{}, nil
+}
+
+var _ = Describe("Client", func() {
+	var (
+		upstream *flakyOllama
+		guarded  *Client
+		now      time.Time
+		ctx      = context.Background()
+		down     = &url.Error{Op: "Post", URL: "http://ollama:11434/api/show", Err: errors.New("connection refused")}
+	)
+
+	BeforeEach(func() {
+		upstream = &flakyOllama{err: down}
+		guarded = New(upstream, 3, 30*time.Second)
+		now = time.Date(2025, 3, 26, 9, 0, 0, 0, time.UTC)
+		guarded.now = func() time.Time { return now }
+	})
+
+	show := func() error {
+		_, err := guarded.Show(ctx, &api.ShowRequest{Name: "llama3.2:1b"})
+		return err
+	}
+
+	It("opens after consecutive failures and then fails fast", func() {
+		for i := 0; i < 3; i++ {
+			Expect(show()).To(MatchError(down))
+		}
+		Expect(guarded.State()).To(Equal(Open))
+
+		err := show()
+		Expect(err).To(MatchError(ErrOpen))
+		var openErr *OpenError
+		Expect(errors.As(err, &openErr)).To(BeTrue())
+		Expect(openErr.RetryAfter).To(Equal(30 * time.Second))
+		Expect(upstream.calls).To(Equal(3))
+	})
+
+	It("closes again when the trial call after the cooldown succeeds", func() {
+		for i := 0; i < 3; i++ {
+			_ = show()
+		}
+		now = now.Add(30 * time.Second)
+		Expect(guarded.State()).To(Equal(HalfOpen))
+
+		upstream.err = nil
+		Expect(show()).To(Succeed())
+		Expect(guarded.State()).To(Equal(Closed))
+	})
+
+	It("opens again right away when the trial call fails", func() {
+		for i := 0; i < 3; i++ {
+			_ = show()
+		}
+		now = now.Add(30 * time.Second)
+
+		Expect(show()).To(MatchError(down))
+		Expect(guarded.State()).To(Equal(Open))
+		Expect(show()).To(MatchError(ErrOpen))
+	})
+
+	It("does not count requests rejected by the Ollama server", func() {
+		upstream.err = api.StatusError{StatusCode: http.StatusNotFound, ErrorMessage: "model not found"}
+		for i := 0; i < 5; i++ {
+			Expect(show()).NotTo(MatchError(ErrOpen))
+		}
+		Expect(guarded.State()).To(Equal(Closed))
+		Expect(upstream.calls).To(Equal(5))
+	})
+})
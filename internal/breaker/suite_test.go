@@ -0,0 +1,14 @@
+package breaker
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestBreaker(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Circuit Breaker Suite")
+}
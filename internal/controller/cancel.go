@@ -0,0 +1,116 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ollama/ollama/api"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ollamamodel "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+// errPullCancelled is returned by pull when the pull was cancelled with the
+// cancel-pull annotation
+var errPullCancelled = errors.New("pull cancelled")
+
+// cancelCheckInterval is how often a running pull looks for the cancel-pull
+// annotation. A model is not reconciled while it is pulled, so the annotation
+// is looked up in the cache rather than waited for as an event.
+const cancelCheckInterval = 2 * time.Second
+
+// cancelRequested reports whether the cancel-pull annotation is set on a model
+func cancelRequested(ollamaModel *ollamamodel.OllamaModel) bool {
+	return ollamaModel.Annotations[ollamamodel.CancelPullAnnotation] == "true"
+}
+
+// watchCancel cancels a pull with errPullCancelled once the cancel-pull
+// annotation is set on its model, until ctx is done
+func (r *OllamaModelReconciler) watchCancel(ctx context.Context, key client.ObjectKey, cancel context.CancelCauseFunc) {
+	ticker := time.NewTicker(cancelCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		ollamaModel := &ollamamodel.OllamaModel{}
+		if err := r.Get(ctx, key, ollamaModel); err != nil {
+			continue
+		}
+		if cancelRequested(ollamaModel) {
+			cancel(errPullCancelled)
+			return
+		}
+	}
+}
+
+// pullCancelled settles a model whose pull was cancelled. The annotation
+// asking for the cancellation is removed and any refresh requested marked as
+// handled, then the model goes back to Pending when it is still stored on the
+// Ollama server, such as after a cancelled refresh, and to Failed with the
+// Cancelled reason otherwise, so that it is not pulled again until a retry is
+// requested.
+func (r *OllamaModelReconciler) pullCancelled(ctx context.Context, ollamaModel *ollamamodel.OllamaModel, modelName string) (ctrl.Result, error) {
+	log.FromContext(ctx).Info("pull cancelled", "name", ollamaModel.Name, "model", modelName)
+
+	// Updating the object replaces its status with the stored one, so the
+	// annotations are changed before the status
+	delete(ollamaModel.Annotations, ollamamodel.CancelPullAnnotation)
+	if err := r.Update(ctx, ollamaModel); err != nil {
+		return ctrl.Result{RequeueAfter: time.Second * 5}, err
+	}
+
+	r.Recorder.Event(ollamaModel, "Warning", "PullCancelled", fmt.Sprintf("Cancelled the pull of model %s", modelName))
+
+	status := &ollamaModel.Status
+	if ollamaModel.RefreshRequested() {
+		status.LastHandledRefresh = ollamaModel.Annotations[ollamamodel.RefreshAnnotation]
+	}
+	if _, err := r.Ollama.Show(ctx, &api.ShowRequest{Name: modelName}); err == nil {
+		status.State = ollamamodel.StatePending
+		status.Error = ""
+	} else {
+		status.State = ollamamodel.StateFailed
+		status.Error = errPullCancelled.Error()
+		status.FailureReason = ollamamodel.FailureCancelled
+		status.LastFailureTime = &metav1.Time{Time: time.Now()}
+	}
+	if err := r.updateStatus(ctx, ollamaModel); err != nil {
+		return ctrl.Result{RequeueAfter: time.Second * 5}, err
+	}
+	if status.State == ollamamodel.StatePending {
+		return ctrl.Result{Requeue: true}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// dropCancelRequest removes a cancel-pull annotation set on a model that is
+// neither pulled nor waiting to be, so that it does not cancel a later pull
+func (r *OllamaModelReconciler) dropCancelRequest(ctx context.Context, ollamaModel *ollamamodel.OllamaModel) error {
+	delete(ollamaModel.Annotations, ollamamodel.CancelPullAnnotation)
+	return r.Update(ctx, ollamaModel)
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/ollama/ollama/api"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+// blockingPull is an Ollama client whose pulls run until they are cancelled
+type blockingPull struct {
+	OllamaClient
+}
+
+func (blockingPull) Pull(ctx context.Context, _ *api.PullRequest, _ api.PullProgressFunc) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+var _ = Describe("Pull cancellation", func() {
+	It("cancels a running pull once the cancel-pull annotation is set", func() {
+		model := &ollamav1alpha1.OllamaModel{
+			ObjectMeta: metav1.ObjectMeta{Name: "cancel-test", Namespace: "default"},
+			Spec:       ollamav1alpha1.OllamaModelSpec{Name: "llama3.1", Tag: "70b"},
+		}
+		Expect(k8sClient.Create(ctx, model)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, model)
+
+		r := &OllamaModelReconciler{
+			Client:   k8sClient,
+			Scheme:   k8sClient.Scheme(),
+			Ollama:   blockingPull{},
+			Recorder: record.NewFakeRecorder(10),
+		}
+		pulled := make(chan error, 1)
+		go func() {
+			pulled <- r.pull(ctx, model, &api.PullRequest{Name: model.Spec.Reference()}, func(api.ProgressResponse) error { return nil })
+		}()
+		Consistently(pulled, 3*time.Second).ShouldNot(Receive())
+
+		annotated := model.DeepCopy()
+		annotated.Annotations = map[string]string{ollamav1alpha1.CancelPullAnnotation: "true"}
+		Expect(k8sClient.Update(ctx, annotated)).To(Succeed())
+
+		Eventually(pulled, 5*time.Second).Should(Receive(MatchError(errPullCancelled)))
+	})
+})
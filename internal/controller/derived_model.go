@@ -0,0 +1,143 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ollama/ollama/api"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ollamamodel "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+// derivedModelName returns the name of the Ollama model created with the
+// parameters, system prompt and template of a model, which is unique to the
+// OllamaModel
+func derivedModelName(ollamaModel *ollamamodel.OllamaModel) string {
+	return fmt.Sprintf("%s/%s:latest", ollamaModel.Namespace, ollamaModel.Name)
+}
+
+// needsDerivedModel reports whether a spec customizes the pulled model
+func needsDerivedModel(spec ollamamodel.OllamaModelSpec) bool {
+	return len(spec.Parameters) > 0 || spec.System != "" || spec.Template != ""
+}
+
+// derivedModelHash returns a short hash identifying the parameters, system
+// prompt and template of a spec
+func derivedModelHash(spec ollamamodel.OllamaModelSpec) string {
+	keys := make([]string, 0, len(spec.Parameters))
+	for key := range spec.Parameters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	hash := sha256.New()
+	for _, key := range keys {
+		fmt.Fprintf(hash, "%s=%q\n", key, spec.Parameters[key])
+	}
+	fmt.Fprintf(hash, "system=%q\ntemplate=%q\n", spec.System, spec.Template)
+	return hex.EncodeToString(hash.Sum(nil))[:16]
+}
+
+// formatParameters converts parameters to the typed values the Ollama create
+// API expects, rejecting unknown parameters and malformed values the same way
+// PARAMETER lines of a Modelfile are
+func formatParameters(parameters map[string]string) (map[string]interface{}, error) {
+	values := make(map[string][]string, len(parameters))
+	for key, value := range parameters {
+		values[key] = strings.Split(strings.TrimSuffix(value, "\n"), "\n")
+	}
+	return api.FormatParams(values)
+}
+
+// applyDerivedModel makes the derived model of a Ready model match the
+// parameters, system prompt and template in its spec: it is created from the
+// pulled model when they changed or it went missing, and deleted once they are
+// all removed. It reports whether the status changed.
+func (r *OllamaModelReconciler) applyDerivedModel(ctx context.Context, ollamaModel *ollamamodel.OllamaModel, modelName string) (bool, error) {
+	spec := ollamaModel.Spec
+	status := &ollamaModel.Status
+
+	if !needsDerivedModel(spec) {
+		if status.DerivedModel == "" {
+			return false, nil
+		}
+		err := r.Ollama.Delete(ctx, &api.DeleteRequest{Model: status.DerivedModel})
+		if err != nil && !strings.Contains(err.Error(), "model not found") {
+			return false, fmt.Errorf("deleting model %s: %w", status.DerivedModel, err)
+		}
+		r.Recorder.Event(ollamaModel, "Normal", "DerivedModelRemoved",
+			fmt.Sprintf("Deleted model %s, the parameters, system prompt and template were removed", status.DerivedModel))
+		status.DerivedModel, status.DerivedModelHash = "", ""
+		return true, nil
+	}
+
+	derived, hash := derivedModelName(ollamaModel), derivedModelHash(spec)
+	if status.DerivedModel == derived && status.DerivedModelHash == hash {
+		if _, err := r.Ollama.Show(ctx, &api.ShowRequest{Model: derived}); err == nil {
+			return false, nil
+		}
+	}
+
+	req := &api.CreateRequest{Model: derived, From: modelName, System: spec.System, Template: spec.Template}
+	if len(spec.Parameters) > 0 {
+		formatted, err := formatParameters(spec.Parameters)
+		if err != nil {
+			return false, fmt.Errorf("invalid parameters: %w", err)
+		}
+		req.Parameters = formatted
+	}
+	if err := r.Ollama.Create(ctx, req, func(api.ProgressResponse) error { return nil }); err != nil {
+		return false, fmt.Errorf("creating model %s: %w", derived, err)
+	}
+	r.Recorder.Event(ollamaModel, "Normal", "DerivedModelCreated",
+		fmt.Sprintf("Created model %s from %s with the spec's %s", derived, modelName, customizations(spec)))
+	status.DerivedModel, status.DerivedModelHash = derived, hash
+	return true, nil
+}
+
+// customizations lists what a spec customizes in the derived model, for events
+func customizations(spec ollamamodel.OllamaModelSpec) string {
+	var parts []string
+	if len(spec.Parameters) > 0 {
+		parts = append(parts, "parameters")
+	}
+	if spec.System != "" {
+		parts = append(parts, "system prompt")
+	}
+	if spec.Template != "" {
+		parts = append(parts, "template")
+	}
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	return strings.Join(parts[:len(parts)-1], ", ") + " and " + parts[len(parts)-1]
+}
+
+// derivedModelFailed marks a model whose derived model could not be created as
+// failed, and reports whether it newly failed
+func (r *OllamaModelReconciler) derivedModelFailed(ctx context.Context, ollamaModel *ollamamodel.OllamaModel, modelName string, err error) bool {
+	log.FromContext(ctx).Error(err, "failed to create derived model", "name", ollamaModel.Name, "model", modelName)
+	r.Recorder.Event(ollamaModel, "Warning", "DerivedModelFailed", err.Error())
+	return checkFailed(ollamaModel, ollamamodel.FailureDerivedModel, err)
+}
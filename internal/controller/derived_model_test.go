@@ -0,0 +1,78 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+var _ = Describe("Derived models", func() {
+	It("hashes parameters independently of their order", func() {
+		a := derivedModelHash(ollamav1alpha1.OllamaModelSpec{Parameters: map[string]string{"num_ctx": "8192", "temperature": "0.2"}})
+		b := derivedModelHash(ollamav1alpha1.OllamaModelSpec{Parameters: map[string]string{"temperature": "0.2", "num_ctx": "8192"}})
+		Expect(a).To(Equal(b))
+		Expect(a).NotTo(Equal(derivedModelHash(ollamav1alpha1.OllamaModelSpec{Parameters: map[string]string{"num_ctx": "4096", "temperature": "0.2"}})))
+	})
+
+	It("changes the hash with the system prompt and template", func() {
+		spec := ollamav1alpha1.OllamaModelSpec{System: "You are a helpful assistant."}
+		hashes := map[string]bool{derivedModelHash(spec): true}
+		spec.System = "You are a terse assistant."
+		hashes[derivedModelHash(spec)] = true
+		spec.Template = "{{ .System }}\n{{ .Prompt }}"
+		hashes[derivedModelHash(spec)] = true
+		Expect(hashes).To(HaveLen(3))
+	})
+
+	It("needs a derived model only when the spec customizes the pulled one", func() {
+		Expect(needsDerivedModel(ollamav1alpha1.OllamaModelSpec{Name: "llama3.2", Tag: "1b"})).To(BeFalse())
+		Expect(needsDerivedModel(ollamav1alpha1.OllamaModelSpec{System: "Be brief."})).To(BeTrue())
+		Expect(needsDerivedModel(ollamav1alpha1.OllamaModelSpec{Template: "{{ .Prompt }}"})).To(BeTrue())
+		Expect(needsDerivedModel(ollamav1alpha1.OllamaModelSpec{Parameters: map[string]string{"num_ctx": "8192"}})).To(BeTrue())
+	})
+
+	It("describes the customizations in events", func() {
+		Expect(customizations(ollamav1alpha1.OllamaModelSpec{System: "Be brief."})).To(Equal("system prompt"))
+		Expect(customizations(ollamav1alpha1.OllamaModelSpec{
+			Parameters: map[string]string{"num_ctx": "8192"},
+			System:     "Be brief.",
+			Template:   "{{ .Prompt }}",
+		})).To(Equal("parameters, system prompt and template"))
+	})
+
+	It("converts parameters to typed values", func() {
+		formatted, err := formatParameters(map[string]string{
+			"num_ctx":     "8192",
+			"temperature": "0.2",
+			"stop":        "<|im_end|>\n<|im_start|>\n",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(formatted).To(HaveKeyWithValue("num_ctx", BeEquivalentTo(8192)))
+		Expect(formatted).To(HaveKeyWithValue("temperature", BeNumerically("~", 0.2, 0.001)))
+		Expect(formatted).To(HaveKeyWithValue("stop", Equal([]string{"<|im_end|>", "<|im_start|>"})))
+	})
+
+	It("rejects unknown parameters and malformed values", func() {
+		_, err := formatParameters(map[string]string{"context_size": "8192"})
+		Expect(err).To(HaveOccurred())
+		_, err = formatParameters(map[string]string{"num_ctx": "large"})
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,80 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ollama/ollama/api"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ollamamodel "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+// embeddingProbe is the text embedded to measure the dimension of an embedding model
+const embeddingProbe = "ollama-operator dimension check"
+
+// checkEmbedding measures the dimension of the vectors an embedding model
+// produces and checks it against the expected one. It is measured again after
+// every pull, since a new version of a tag may change it, and otherwise only
+// while it is unknown. It reports whether the status changed.
+func (r *OllamaModelReconciler) checkEmbedding(ctx context.Context, ollamaModel *ollamamodel.OllamaModel, modelName string, pulled bool) (bool, error) {
+	spec := ollamaModel.Spec
+	status := &ollamaModel.Status
+
+	if spec.Type != ollamamodel.ModelTypeEmbedding {
+		if status.EmbeddingDimensions == 0 {
+			return false, nil
+		}
+		status.EmbeddingDimensions = 0
+		return true, nil
+	}
+
+	changed := false
+	if pulled || status.EmbeddingDimensions == 0 {
+		resp, err := r.Ollama.Embed(ctx, &api.EmbedRequest{Model: modelName, Input: embeddingProbe})
+		if err != nil {
+			return false, fmt.Errorf("embedding with %s: %w", modelName, err)
+		}
+		if len(resp.Embeddings) == 0 || len(resp.Embeddings[0]) == 0 {
+			return false, fmt.Errorf("%s returned no embedding, it may not be an embedding model", modelName)
+		}
+
+		dimensions := int32(len(resp.Embeddings[0]))
+		if previous := status.EmbeddingDimensions; previous != 0 && previous != dimensions {
+			r.Recorder.Event(ollamaModel, "Warning", "DimensionsChanged",
+				fmt.Sprintf("%s now produces embeddings of dimension %d instead of %d", modelName, dimensions, previous))
+		}
+		changed = status.EmbeddingDimensions != dimensions
+		status.EmbeddingDimensions = dimensions
+	}
+
+	if expected := spec.ExpectedDimensions; expected != nil && *expected != status.EmbeddingDimensions {
+		return changed, fmt.Errorf("%s produces embeddings of dimension %d, expected %d",
+			modelName, status.EmbeddingDimensions, *expected)
+	}
+	return changed, nil
+}
+
+// embeddingFailed marks an embedding model that failed its check as failed,
+// and reports whether it newly failed
+func (r *OllamaModelReconciler) embeddingFailed(ctx context.Context, ollamaModel *ollamamodel.OllamaModel, modelName string, err error) bool {
+	log.FromContext(ctx).Error(err, "embedding model check failed", "name", ollamaModel.Name, "model", modelName)
+	r.Recorder.Event(ollamaModel, "Warning", "EmbeddingCheckFailed", err.Error())
+	return checkFailed(ollamaModel, ollamamodel.FailureEmbeddingCheck, err)
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ollama/ollama/api"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+// embeddingOllama is an Ollama client producing embeddings of a fixed dimension
+type embeddingOllama struct {
+	OllamaClient
+	dimensions int
+	err        error
+	calls      int
+}
+
+func (f *embeddingOllama) Embed(context.Context, *api.EmbedRequest) (*api.EmbedResponse, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &api.EmbedResponse{Embeddings: [][]float32{make([]float32, f.dimensions)}}, nil
+}
+
+var _ = Describe("Embedding models", func() {
+	ctx := context.Background()
+	model := func(expected *int32) *ollamav1alpha1.OllamaModel {
+		return &ollamav1alpha1.OllamaModel{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "embedding", Name: "nomic-embed-text"},
+			Spec: ollamav1alpha1.OllamaModelSpec{
+				Name:               "nomic-embed-text",
+				Tag:                "latest",
+				Type:               ollamav1alpha1.ModelTypeEmbedding,
+				ExpectedDimensions: expected,
+			},
+		}
+	}
+
+	It("records the dimension and measures it again only after a pull", func() {
+		ollama := &embeddingOllama{dimensions: 768}
+		r := &OllamaModelReconciler{Ollama: ollama, Recorder: record.NewFakeRecorder(10)}
+		m := model(ptr.To[int32](768))
+
+		changed, err := r.checkEmbedding(ctx, m, "nomic-embed-text:latest", true)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(m.Status.EmbeddingDimensions).To(BeEquivalentTo(768))
+
+		changed, err = r.checkEmbedding(ctx, m, "nomic-embed-text:latest", false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).To(BeFalse())
+		Expect(ollama.calls).To(Equal(1))
+	})
+
+	It("fails on a dimension other than the expected one", func() {
+		recorder := record.NewFakeRecorder(10)
+		r := &OllamaModelReconciler{Ollama: &embeddingOllama{dimensions: 1024}, Recorder: recorder}
+		m := model(ptr.To[int32](768))
+		m.Status.EmbeddingDimensions = 768
+
+		_, err := r.checkEmbedding(ctx, m, "nomic-embed-text:latest", true)
+		Expect(err).To(MatchError(ContainSubstring("dimension 1024, expected 768")))
+		Expect(m.Status.EmbeddingDimensions).To(BeEquivalentTo(1024))
+		Expect(recorder.Events).To(Receive(ContainSubstring("DimensionsChanged")))
+	})
+
+	It("fails when the model produces no embedding", func() {
+		r := &OllamaModelReconciler{Ollama: &embeddingOllama{err: errors.New("does not support embeddings")}}
+		_, err := r.checkEmbedding(ctx, model(nil), "llama3.2:1b", true)
+		Expect(err).To(MatchError(ContainSubstring("does not support embeddings")))
+	})
+
+	It("leaves generation models alone", func() {
+		ollama := &embeddingOllama{}
+		r := &OllamaModelReconciler{Ollama: ollama}
+		m := model(nil)
+		m.Spec.Type = ""
+		m.Status.EmbeddingDimensions = 768
+
+		changed, err := r.checkEmbedding(ctx, m, "llama3.2:1b", true)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(m.Status.EmbeddingDimensions).To(BeZero())
+		Expect(ollama.calls).To(BeZero())
+	})
+})
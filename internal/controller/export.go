@@ -0,0 +1,248 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ollamamodel "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+// DefaultExportImage is the image of the Jobs exporting and pre-warming model blobs
+const DefaultExportImage = "ollama/ollama:0.6.2"
+
+const (
+	// pullJobMountPath is where pull Jobs mount the claim, used as OLLAMA_MODELS
+	pullJobMountPath = "/models"
+	// pullJobTTL is how long finished pull Jobs are kept. A failed Job is
+	// retried once it is gone.
+	pullJobTTL = int32(3600)
+	// minExportClaimSize is the smallest claim requested for a model's blobs
+	minExportClaimSize = int64(1 << 30)
+)
+
+// pullScript pulls the models in $MODELS into the claim with a throwaway Ollama server
+const pullScript = `set -e
+ollama serve &
+until ollama list >/dev/null 2>&1; do sleep 1; done
+for model in $MODELS; do ollama pull "$model"; done
+`
+
+// syncScript pulls the models into the claim, then removes any other model
+// left over from a previous export or model selection
+const syncScript = pullScript + `printf '%s\n' $MODELS > /tmp/models
+ollama list | tail -n +2 | awk '{print $1}' | grep -vxF -f /tmp/models | xargs -r -n1 ollama rm
+`
+
+// pullJobName returns the name of a Job pulling models for owner, short
+// enough to be used as a label value in its pods
+func pullJobName(owner, purpose, id string) string {
+	name := owner
+	if limit := 63 - len(purpose) - len(id) - 2; len(name) > limit {
+		name = strings.TrimRight(name[:limit], "-.")
+	}
+	return name + "-" + purpose + "-" + id
+}
+
+// exportJobName returns the name of the Job exporting a digest of a model
+func exportJobName(ollamaModel *ollamamodel.OllamaModel, digest string) string {
+	digest = strings.TrimPrefix(digest, "sha256:")
+	if len(digest) > 12 {
+		digest = digest[:12]
+	}
+	return pullJobName(ollamaModel.Name, "export", digest)
+}
+
+// exportClaimSize returns the capacity requested for the claim of a model
+func exportClaimSize(ollamaModel *ollamamodel.OllamaModel) resource.Quantity {
+	if size := ollamaModel.Spec.Export.Size; size != nil {
+		return *size
+	}
+	size := ollamaModel.Status.Size + ollamaModel.Status.Size/5
+	if size < minExportClaimSize {
+		size = minExportClaimSize
+	}
+	return *resource.NewQuantity(size, resource.BinarySI)
+}
+
+// reconcileExport copies the blobs of a Ready model to its claim with a Job
+// whenever its digest changes, and deletes the claim once the export is
+// removed from the spec. It reports whether the status changed. Export
+// failures are reported in the status but don't fail the model, which remains
+// usable through Ollama.
+func (r *OllamaModelReconciler) reconcileExport(ctx context.Context, ollamaModel *ollamamodel.OllamaModel, modelName string) (bool, error) {
+	status := &ollamaModel.Status
+	claimName := ollamamodel.BlobsClaimName(ollamaModel.Name)
+
+	if ollamaModel.Spec.Export == nil {
+		if status.Export == nil {
+			return false, nil
+		}
+		claim := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: claimName, Namespace: ollamaModel.Namespace}}
+		if err := r.Delete(ctx, claim); client.IgnoreNotFound(err) != nil {
+			return false, fmt.Errorf("deleting claim %s: %w", claimName, err)
+		}
+		r.Recorder.Event(ollamaModel, "Normal", "ExportRemoved", fmt.Sprintf("Deleted claim %s", claimName))
+		status.Export = nil
+		return true, nil
+	}
+
+	if err := r.ensureExportClaim(ctx, ollamaModel, claimName); err != nil {
+		return false, err
+	}
+
+	// The Job is named after the digest, so wait until it is known
+	digest := status.Digest
+	if digest == "" {
+		return false, nil
+	}
+	if status.Export != nil && status.Export.ClaimName == claimName && status.Export.Digest == digest && status.Export.Job == "" {
+		return false, nil
+	}
+	previous := ollamamodel.ExportStatus{}
+	if status.Export != nil {
+		previous = *status.Export
+	}
+	export := ollamamodel.ExportStatus{ClaimName: claimName, Digest: previous.Digest, Job: exportJobName(ollamaModel, digest)}
+
+	job := &batchv1.Job{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: ollamaModel.Namespace, Name: export.Job}, job)
+	switch {
+	case apierrors.IsNotFound(err):
+		if err := r.createExportJob(ctx, ollamaModel, modelName, claimName, export.Job); err != nil {
+			return false, err
+		}
+		r.Recorder.Event(ollamaModel, "Normal", "ExportStarted",
+			fmt.Sprintf("Exporting %s to claim %s with Job %s", modelName, claimName, export.Job))
+	case err != nil:
+		return false, fmt.Errorf("getting export job %s: %w", export.Job, err)
+	case job.Status.Succeeded > 0:
+		export.Digest, export.Job = digest, ""
+		r.Recorder.Event(ollamaModel, "Normal", "Exported", fmt.Sprintf("Exported %s to claim %s", modelName, claimName))
+	default:
+		export.Error = jobFailure(job)
+		if export.Error != "" && export.Error != previous.Error {
+			r.Recorder.Event(ollamaModel, "Warning", "ExportFailed", export.Error)
+		}
+	}
+
+	status.Export = &export
+	return export != previous, nil
+}
+
+// ensureExportClaim creates the claim the blobs of a model are exported to
+func (r *OllamaModelReconciler) ensureExportClaim(ctx context.Context, ollamaModel *ollamamodel.OllamaModel, claimName string) error {
+	claim := &corev1.PersistentVolumeClaim{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: ollamaModel.Namespace, Name: claimName}, claim)
+	if !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	spec := ollamaModel.Spec.Export
+	accessModes := spec.AccessModes
+	if len(accessModes) == 0 {
+		accessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}
+	}
+	claim = &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: claimName, Namespace: ollamaModel.Namespace},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      accessModes,
+			StorageClassName: spec.StorageClassName,
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: exportClaimSize(ollamaModel)},
+			},
+		},
+	}
+	if err := controllerutil.SetControllerReference(ollamaModel, claim, r.Scheme); err != nil {
+		return err
+	}
+	if err := r.Create(ctx, claim); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("creating claim %s: %w", claimName, err)
+	}
+	log.FromContext(ctx).Info("created claim for exported blobs", "name", ollamaModel.Name, "claim", claimName)
+	return nil
+}
+
+// createExportJob creates the Job pulling a model into its claim
+func (r *OllamaModelReconciler) createExportJob(ctx context.Context, ollamaModel *ollamamodel.OllamaModel, modelName, claimName, jobName string) error {
+	job := newPullJob(ollamaModel.Namespace, jobName, claimName, r.ExportImage, syncScript, []string{modelName})
+	if err := controllerutil.SetControllerReference(ollamaModel, job, r.Scheme); err != nil {
+		return err
+	}
+	if err := r.Create(ctx, job); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("creating export job %s: %w", jobName, err)
+	}
+	return nil
+}
+
+// newPullJob returns a Job running script with a claim mounted as the Ollama
+// models directory and the models to pull in $MODELS
+func newPullJob(namespace, name, claimName, image, script string, models []string) *batchv1.Job {
+	if image == "" {
+		image = DefaultExportImage
+	}
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            ptr.To[int32](2),
+			TTLSecondsAfterFinished: ptr.To(pullJobTTL),
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{{
+						Name:    "pull",
+						Image:   image,
+						Command: []string{"/bin/sh", "-c", script},
+						Env: []corev1.EnvVar{
+							{Name: "MODELS", Value: strings.Join(models, " ")},
+							{Name: "OLLAMA_MODELS", Value: pullJobMountPath},
+						},
+						VolumeMounts: []corev1.VolumeMount{{Name: "models", MountPath: pullJobMountPath}},
+					}},
+					Volumes: []corev1.Volume{{
+						Name: "models",
+						VolumeSource: corev1.VolumeSource{
+							PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claimName},
+						},
+					}},
+				},
+			},
+		},
+	}
+}
+
+// jobFailure returns the reason a Job failed, or "" unless it failed
+func jobFailure(job *batchv1.Job) string {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			return fmt.Sprintf("Job %s failed: %s", job.Name, condition.Message)
+		}
+	}
+	return ""
+}
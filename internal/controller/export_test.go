@@ -0,0 +1,102 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+var _ = Describe("Export", func() {
+	const digest = "8eeb52dfb3bb9aefdf9d1ef24b3bdbcfbe82238798c4b918278320b6fcef18fe"
+
+	It("names export jobs after the digest within the label length limit", func() {
+		model := &ollamav1alpha1.OllamaModel{ObjectMeta: metav1.ObjectMeta{Name: "llama3.2-1b"}}
+		Expect(exportJobName(model, "sha256:"+digest)).To(Equal("llama3.2-1b-export-8eeb52dfb3bb"))
+
+		model.Name = strings.Repeat("a", 80)
+		name := exportJobName(model, digest)
+		Expect(len(name)).To(BeNumerically("<=", 63))
+		Expect(name).To(HaveSuffix("-export-8eeb52dfb3bb"))
+	})
+
+	It("sizes the claim after the model unless configured", func() {
+		model := &ollamav1alpha1.OllamaModel{
+			Spec:   ollamav1alpha1.OllamaModelSpec{Export: &ollamav1alpha1.ModelExport{}},
+			Status: ollamav1alpha1.OllamaModelStatus{Size: 10 << 30},
+		}
+		size := exportClaimSize(model)
+		Expect(size.Value()).To(BeEquivalentTo(12 << 30))
+
+		model.Status.Size = 1 << 20
+		size = exportClaimSize(model)
+		Expect(size.Value()).To(BeEquivalentTo(minExportClaimSize))
+
+		model.Spec.Export.Size = resource.NewQuantity(50<<30, resource.BinarySI)
+		size = exportClaimSize(model)
+		Expect(size.Value()).To(BeEquivalentTo(50 << 30))
+	})
+
+	It("creates the claim and the export job of a Ready model", func() {
+		ctx := context.Background()
+		model := &ollamav1alpha1.OllamaModel{
+			ObjectMeta: metav1.ObjectMeta{Name: "export-test", Namespace: "default"},
+			Spec: ollamav1alpha1.OllamaModelSpec{
+				Name:   "llama3.2",
+				Tag:    "1b",
+				Export: &ollamav1alpha1.ModelExport{},
+			},
+		}
+		Expect(k8sClient.Create(ctx, model)).To(Succeed())
+		DeferCleanup(func() { Expect(k8sClient.Delete(ctx, model)).To(Succeed()) })
+		model.Status = ollamav1alpha1.OllamaModelStatus{State: ollamav1alpha1.StateReady, Digest: digest, Size: 1 << 30}
+
+		r := &OllamaModelReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), Recorder: record.NewFakeRecorder(10)}
+		changed, err := r.reconcileExport(ctx, model, "llama3.2:1b")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(model.Status.Export).To(Equal(&ollamav1alpha1.ExportStatus{
+			ClaimName: "export-test-blobs",
+			Job:       "export-test-export-8eeb52dfb3bb",
+		}))
+
+		claim := &corev1.PersistentVolumeClaim{}
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "export-test-blobs"}, claim)).To(Succeed())
+		Expect(claim.Spec.AccessModes).To(ConsistOf(corev1.ReadWriteOnce))
+		Expect(metav1.IsControlledBy(claim, model)).To(BeTrue())
+
+		job := &batchv1.Job{}
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "export-test-export-8eeb52dfb3bb"}, job)).To(Succeed())
+		Expect(job.Spec.Template.Spec.Containers[0].Image).To(Equal(DefaultExportImage))
+		Expect(job.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "MODELS", Value: "llama3.2:1b"}))
+
+		changed, err = r.reconcileExport(ctx, model, "llama3.2:1b")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).To(BeFalse())
+	})
+})
@@ -0,0 +1,204 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ollama/ollama/api"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	ollamamodel "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+// Delays before a failed pull is retried, doubled on each consecutive failure
+const (
+	retryBaseDelay = 30 * time.Second
+	retryMaxDelay  = time.Hour
+)
+
+// pullFailed records a failed pull in the status of a model: it moves to
+// Failed, counts the attempt and classifies the error
+func pullFailed(ollamaModel *ollamamodel.OllamaModel, err error) {
+	now := metav1.Now()
+	status := &ollamaModel.Status
+	status.State = ollamamodel.StateFailed
+	status.Error = err.Error()
+	status.FailureReason = classifyPullError(err)
+	status.PullAttempts++
+	status.LastFailureTime = &now
+}
+
+// checkFailed records in the status of a pulled model that it failed a check
+// made once pulled: it moves to Failed and counts the attempt like a failed
+// pull, so that the check is retried with the same growing delay. It reports
+// whether the model newly failed, rather than failing again for the same
+// reason, so that the failure is only notified once.
+func checkFailed(ollamaModel *ollamamodel.OllamaModel, reason ollamamodel.FailureReason, err error) bool {
+	now := metav1.Now()
+	status := &ollamaModel.Status
+	repeated := status.FailureReason == reason
+	if !repeated {
+		status.PullAttempts = 0
+	}
+	status.State = ollamamodel.StateFailed
+	status.Error = err.Error()
+	status.FailureReason = reason
+	status.PullAttempts++
+	status.LastFailureTime = &now
+	return !repeated
+}
+
+// resetPullFailures forgets the failed pulls of a model
+func resetPullFailures(ollamaModel *ollamamodel.OllamaModel) {
+	ollamaModel.Status.FailureReason = ""
+	ollamaModel.Status.PullAttempts = 0
+}
+
+// gaveUp returns why a failed pull is no longer retried, as the reason of the
+// Terminal condition, or an empty string while it is
+func gaveUp(ollamaModel *ollamamodel.OllamaModel) string {
+	status := ollamaModel.Status
+	if status.FailureReason.Terminal() {
+		return string(status.FailureReason)
+	}
+	if policy := ollamaModel.Spec.RetryPolicy; policy != nil && policy.MaxAttempts != nil &&
+		status.FailureReason != "" && status.PullAttempts >= *policy.MaxAttempts {
+		return ollamamodel.ReasonRetriesExhausted
+	}
+	return ""
+}
+
+// failedResult requeues a model whose pull failed once its retry delay has
+// passed, or not at all when the controller gave up on it. No error is
+// returned, so that the delay is not overridden by the work queue's backoff.
+func failedResult(ollamaModel *ollamamodel.OllamaModel) ctrl.Result {
+	if gaveUp(ollamaModel) != "" {
+		return ctrl.Result{}
+	}
+	return ctrl.Result{RequeueAfter: retryDelay(ollamaModel.Status.PullAttempts)}
+}
+
+// retryDelay returns how long to wait after the given number of consecutive
+// failed pulls before pulling again
+func retryDelay(attempts int32) time.Duration {
+	delay := retryBaseDelay
+	for i := int32(1); i < attempts && delay < retryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, retryMaxDelay)
+}
+
+// retryFailedPull decides what to do with a model whose last pull failed. A
+// changed spec is pulled right away, a model the controller gave up on is left
+// alone, and other failures are pulled again once their retry delay has
+// passed. It returns the time left to wait, and moves the model back to
+// Pending when it should be pulled.
+func retryFailedPull(ollamaModel *ollamamodel.OllamaModel, now time.Time) (wait time.Duration, retry bool) {
+	status := &ollamaModel.Status
+	switch {
+	case status.ObservedGeneration != ollamaModel.Generation:
+		// The progress belongs to the model the spec named before
+		resetPullFailures(ollamaModel)
+		status.Progress = nil
+	case gaveUp(ollamaModel) != "":
+		return 0, false
+	case status.LastFailureTime != nil:
+		if wait := status.LastFailureTime.Add(retryDelay(status.PullAttempts)).Sub(now); wait > 0 {
+			return wait, false
+		}
+	}
+	status.State = ollamamodel.StatePending
+	status.Error = ""
+	return 0, true
+}
+
+// acceptRetry handles the retry annotation of a model: it removes the
+// annotation and moves a Failed model back to Pending with its failed pulls
+// forgotten. It reports whether a retry was requested.
+func (r *OllamaModelReconciler) acceptRetry(ctx context.Context, ollamaModel *ollamamodel.OllamaModel) (bool, error) {
+	if ollamaModel.Annotations[ollamamodel.RetryAnnotation] != "true" {
+		return false, nil
+	}
+
+	// Updating the object replaces its status with the stored one, so the
+	// annotation is removed before the status is changed
+	delete(ollamaModel.Annotations, ollamamodel.RetryAnnotation)
+	if err := r.Update(ctx, ollamaModel); err != nil {
+		return false, err
+	}
+
+	if ollamaModel.Status.State == ollamamodel.StateFailed {
+		r.Recorder.Event(ollamaModel, "Normal", "RetryRequested",
+			fmt.Sprintf("Retrying after %d failed pulls", ollamaModel.Status.PullAttempts))
+		ollamaModel.Status.State = ollamamodel.StatePending
+		ollamaModel.Status.Error = ""
+	}
+	resetPullFailures(ollamaModel)
+	return true, nil
+}
+
+// classifyPullError tells a model that does not exist or may not be pulled
+// apart from a registry or network outage. Ollama passes registry errors on
+// as messages, so those are matched on their text.
+func classifyPullError(err error) ollamamodel.FailureReason {
+	var statusErr api.StatusError
+	if errors.As(err, &statusErr) {
+		switch statusErr.StatusCode {
+		case http.StatusNotFound:
+			return ollamamodel.FailureNotFound
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return ollamamodel.FailureUnauthorized
+		case http.StatusGatewayTimeout, http.StatusBadGateway, http.StatusServiceUnavailable:
+			return ollamamodel.FailureNetworkTimeout
+		}
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, syscall.ECONNREFUSED) ||
+		(errors.As(err, &netErr) && netErr.Timeout()) {
+		return ollamamodel.FailureNetworkTimeout
+	}
+	if errors.Is(err, syscall.ENOSPC) {
+		return ollamamodel.FailureOutOfSpace
+	}
+
+	message := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(message, "no space left on device"):
+		return ollamamodel.FailureOutOfSpace
+	case strings.Contains(message, "file does not exist"), strings.Contains(message, "manifest unknown"),
+		strings.Contains(message, "not found"):
+		return ollamamodel.FailureNotFound
+	case strings.Contains(message, "unauthorized"), strings.Contains(message, "forbidden"),
+		strings.Contains(message, "denied"):
+		return ollamamodel.FailureUnauthorized
+	case strings.Contains(message, "timeout"), strings.Contains(message, "timed out"),
+		strings.Contains(message, "connection refused"), strings.Contains(message, "connection reset"),
+		strings.Contains(message, "no such host"), strings.Contains(message, "eof"):
+		return ollamamodel.FailureNetworkTimeout
+	}
+	return ollamamodel.FailureUnknown
+}
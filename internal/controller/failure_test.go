@@ -0,0 +1,149 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/ollama/ollama/api"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/utils/ptr"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+var _ = Describe("Pull failures", func() {
+	DescribeTable("classifies pull errors",
+		func(err error, reason ollamav1alpha1.FailureReason) {
+			Expect(classifyPullError(err)).To(Equal(reason))
+		},
+		Entry("missing manifest", errors.New("pull model manifest: file does not exist"), ollamav1alpha1.FailureNotFound),
+		Entry("404 from Ollama", api.StatusError{StatusCode: http.StatusNotFound}, ollamav1alpha1.FailureNotFound),
+		Entry("401 from Ollama", api.StatusError{StatusCode: http.StatusUnauthorized}, ollamav1alpha1.FailureUnauthorized),
+		Entry("registry denial", errors.New("pull access denied"), ollamav1alpha1.FailureUnauthorized),
+		Entry("deadline", fmt.Errorf("pulling: %w", context.DeadlineExceeded), ollamav1alpha1.FailureNetworkTimeout),
+		Entry("registry timeout", errors.New("dial tcp 1.2.3.4:443: i/o timeout"), ollamav1alpha1.FailureNetworkTimeout),
+		Entry("full disk", fmt.Errorf("write: %w", syscall.ENOSPC), ollamav1alpha1.FailureOutOfSpace),
+		Entry("full disk message", errors.New("write /root/.ollama/models/blobs: no space left on device"), ollamav1alpha1.FailureOutOfSpace),
+		Entry("anything else", errors.New("invalid model format"), ollamav1alpha1.FailureUnknown),
+	)
+
+	It("counts consecutive failures until they are reset", func() {
+		model := &ollamav1alpha1.OllamaModel{}
+		pullFailed(model, errors.New("pull model manifest: file does not exist"))
+		pullFailed(model, errors.New("pull model manifest: file does not exist"))
+		Expect(model.Status.State).To(Equal(ollamav1alpha1.StateFailed))
+		Expect(model.Status.PullAttempts).To(Equal(int32(2)))
+		Expect(model.Status.FailureReason).To(Equal(ollamav1alpha1.FailureNotFound))
+		Expect(model.Status.LastFailureTime).NotTo(BeNil())
+
+		resetPullFailures(model)
+		Expect(model.Status.PullAttempts).To(BeZero())
+		Expect(model.Status.FailureReason).To(BeEmpty())
+		Expect(model.Status.LastFailureTime).NotTo(BeNil())
+	})
+
+	It("backs off exponentially up to an hour", func() {
+		Expect(retryDelay(1)).To(Equal(30 * time.Second))
+		Expect(retryDelay(2)).To(Equal(time.Minute))
+		Expect(retryDelay(4)).To(Equal(4 * time.Minute))
+		Expect(retryDelay(100)).To(Equal(time.Hour))
+	})
+
+	It("retries transient failures once their delay has passed", func() {
+		model := &ollamav1alpha1.OllamaModel{}
+		pullFailed(model, errors.New("dial tcp: i/o timeout"))
+		failedAt := model.Status.LastFailureTime.Time
+
+		wait, retry := retryFailedPull(model, failedAt.Add(10*time.Second))
+		Expect(retry).To(BeFalse())
+		Expect(wait).To(Equal(20 * time.Second))
+		Expect(failedResult(model).RequeueAfter).To(Equal(30 * time.Second))
+
+		_, retry = retryFailedPull(model, failedAt.Add(time.Minute))
+		Expect(retry).To(BeTrue())
+		Expect(model.Status.State).To(Equal(ollamav1alpha1.StatePending))
+		Expect(model.Status.PullAttempts).To(Equal(int32(1)))
+	})
+
+	It("stops retrying terminal failures until the spec changes", func() {
+		model := &ollamav1alpha1.OllamaModel{}
+		pullFailed(model, errors.New("pull model manifest: file does not exist"))
+		setStateConditions(model)
+		Expect(meta.IsStatusConditionTrue(model.Status.Conditions, ollamav1alpha1.ConditionTerminal)).To(BeTrue())
+		Expect(failedResult(model).RequeueAfter).To(BeZero())
+
+		wait, retry := retryFailedPull(model, time.Now().Add(24*time.Hour))
+		Expect(retry).To(BeFalse())
+		Expect(wait).To(BeZero())
+
+		model.Generation++
+		_, retry = retryFailedPull(model, time.Now())
+		Expect(retry).To(BeTrue())
+		Expect(model.Status.PullAttempts).To(BeZero())
+		setStateConditions(model)
+		Expect(meta.FindStatusCondition(model.Status.Conditions, ollamav1alpha1.ConditionTerminal)).To(BeNil())
+	})
+
+	It("gives up once the retry policy is exhausted", func() {
+		model := &ollamav1alpha1.OllamaModel{}
+		model.Spec.RetryPolicy = &ollamav1alpha1.RetryPolicy{MaxAttempts: ptr.To(int32(2))}
+		pullFailed(model, errors.New("dial tcp: i/o timeout"))
+		Expect(failedResult(model).RequeueAfter).To(Equal(30 * time.Second))
+
+		pullFailed(model, errors.New("dial tcp: i/o timeout"))
+		Expect(failedResult(model).RequeueAfter).To(BeZero())
+		_, retry := retryFailedPull(model, time.Now().Add(24*time.Hour))
+		Expect(retry).To(BeFalse())
+
+		setStateConditions(model)
+		terminal := meta.FindStatusCondition(model.Status.Conditions, ollamav1alpha1.ConditionTerminal)
+		Expect(terminal).NotTo(BeNil())
+		Expect(terminal.Reason).To(Equal(ollamav1alpha1.ReasonRetriesExhausted))
+		Expect(terminal.Message).To(HavePrefix("Gave up after 2 failed pulls"))
+	})
+
+	It("reports a failed check as new only the first time", func() {
+		model := &ollamav1alpha1.OllamaModel{}
+		model.Status.State = ollamav1alpha1.StateReady
+		Expect(checkFailed(model, ollamav1alpha1.FailureDerivedModel, errors.New("creating model"))).To(BeTrue())
+		Expect(model.Status.State).To(Equal(ollamav1alpha1.StateFailed))
+		Expect(model.Status.FailureReason).To(Equal(ollamav1alpha1.FailureDerivedModel))
+		Expect(failedResult(model).RequeueAfter).To(Equal(30 * time.Second))
+
+		// The check is not made again before its retry delay
+		_, retry := retryFailedPull(model, model.Status.LastFailureTime.Add(10*time.Second))
+		Expect(retry).To(BeFalse())
+		_, retry = retryFailedPull(model, model.Status.LastFailureTime.Add(time.Minute))
+		Expect(retry).To(BeTrue())
+
+		Expect(checkFailed(model, ollamav1alpha1.FailureDerivedModel, errors.New("creating model"))).To(BeFalse())
+		Expect(model.Status.PullAttempts).To(Equal(int32(2)))
+		Expect(failedResult(model).RequeueAfter).To(Equal(time.Minute))
+
+		// Another failure is new, and counted from the start
+		Expect(checkFailed(model, ollamav1alpha1.FailureEmbeddingCheck, errors.New("no embedding"))).To(BeTrue())
+		Expect(model.Status.PullAttempts).To(Equal(int32(1)))
+	})
+})
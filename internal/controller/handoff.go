@@ -0,0 +1,67 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ollamamodel "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+// startPull moves a model to Pulling and records this replica as the holder
+// of the pull. Progress left by an interrupted pull, because its replica
+// stopped or the pull failed, is recorded as the point the pull resumes from:
+// Ollama keeps the layers downloaded so far and only fetches the rest.
+func (r *OllamaModelReconciler) startPull(ollamaModel *ollamamodel.OllamaModel, modelName string, now time.Time) {
+	status := &ollamaModel.Status
+	status.ResumedFrom = nil
+	if status.Progress != nil && status.Progress.CompletedBytes > 0 {
+		status.ResumedFrom = status.Progress.DeepCopy()
+		r.Recorder.Event(ollamaModel, "Normal", "PullResumed",
+			fmt.Sprintf("Resuming the pull of model %s from %d%%", modelName, status.Progress.Percent))
+	}
+	status.State = ollamamodel.StatePulling
+	status.PullHolder = r.Identity
+	status.PullStartTime = &metav1.Time{Time: now}
+	status.PullInterruptedTime = nil
+}
+
+// takeOverPull moves a model left Pulling, but not stored on the Ollama
+// server, back to Pending so that it is pulled again. A model is not
+// reconciled while this replica pulls it, so the pull was interrupted: its
+// replica stopped or lost the leadership before the pull finished. Ollama
+// keeps the layers downloaded so far, so pulling again resumes the download.
+func (r *OllamaModelReconciler) takeOverPull(ctx context.Context, ollamaModel *ollamamodel.OllamaModel, modelName string) {
+	status := &ollamaModel.Status
+	holder := status.PullHolder
+	if holder == "" {
+		holder = "an unknown replica"
+	}
+	log.FromContext(ctx).Info("taking over interrupted pull", "name", ollamaModel.Name, "model", modelName, "holder", status.PullHolder)
+	r.Recorder.Event(ollamaModel, "Normal", "PullTakenOver",
+		fmt.Sprintf("Pulling model %s again, its pull by %s was interrupted", modelName, holder))
+
+	status.State = ollamamodel.StatePending
+	status.PullHolder = ""
+	status.PullStartTime = nil
+	status.PullInterruptedTime = nil
+}
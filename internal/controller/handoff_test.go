@@ -0,0 +1,64 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/tools/record"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+var _ = Describe("Pull handoff", func() {
+	It("pulls a model left Pulling by another replica again", func() {
+		recorder := record.NewFakeRecorder(1)
+		previous := &OllamaModelReconciler{Identity: "ollama-operator-5d8f7-abcde"}
+		current := &OllamaModelReconciler{Identity: "ollama-operator-5d8f7-fghij", Recorder: recorder}
+
+		model := &ollamav1alpha1.OllamaModel{}
+		previous.startPull(model, "llama3.1:70b", time.Now())
+		Expect(model.Status.State).To(Equal(ollamav1alpha1.StatePulling))
+		Expect(model.Status.PullHolder).To(Equal("ollama-operator-5d8f7-abcde"))
+		Expect(model.Status.PullStartTime).NotTo(BeNil())
+
+		current.takeOverPull(context.Background(), model, "llama3.1:70b")
+		Expect(model.Status.State).To(Equal(ollamav1alpha1.StatePending))
+		Expect(model.Status.PullHolder).To(BeEmpty())
+		Expect(model.Status.PullStartTime).To(BeNil())
+		Expect(recorder.Events).To(Receive(ContainSubstring("PullTakenOver")))
+
+		current.startPull(model, "llama3.1:70b", time.Now())
+		Expect(model.Status.PullHolder).To(Equal("ollama-operator-5d8f7-fghij"))
+		Expect(model.Status.ResumedFrom).To(BeNil())
+	})
+
+	It("resumes from the progress of an interrupted pull", func() {
+		recorder := record.NewFakeRecorder(1)
+		r := &OllamaModelReconciler{Recorder: recorder}
+		model := &ollamav1alpha1.OllamaModel{}
+		model.Status.State = ollamav1alpha1.StateFailed
+		model.Status.Progress = &ollamav1alpha1.PullProgress{Percent: 40, CompletedBytes: 16 << 30, TotalBytes: 40 << 30}
+
+		r.startPull(model, "llama3.1:70b", time.Now())
+		Expect(model.Status.ResumedFrom).To(Equal(&ollamav1alpha1.PullProgress{Percent: 40, CompletedBytes: 16 << 30, TotalBytes: 40 << 30}))
+		Expect(recorder.Events).To(Receive(ContainSubstring("from 40%")))
+	})
+})
@@ -0,0 +1,126 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ollama/ollama/api"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ollamamodel "github.com/dmk/ollama-operator/api/v1alpha1"
+	"github.com/dmk/ollama-operator/internal/audit"
+)
+
+// integrityCheckDue reports whether a Ready model is due for an integrity check
+func (r *OllamaModelReconciler) integrityCheckDue(ollamaModel *ollamamodel.OllamaModel, now time.Time) bool {
+	if r.IntegrityCheckInterval <= 0 || ollamaModel.Status.State != ollamamodel.StateReady {
+		return false
+	}
+	checked := ollamaModel.Status.LastIntegrityCheckTime
+	return checked == nil || !now.Before(checked.Add(r.IntegrityCheckInterval))
+}
+
+// integrityProblem describes what is wrong with a model stored on the Ollama
+// server, or returns "" when it is intact. showErr is the error of showing the
+// model, which fails when its manifest or one of its blobs is missing or
+// truncated, and stored the models listed by the server, whose digest is the
+// hash of the manifest as currently stored.
+func integrityProblem(ollamaModel *ollamamodel.OllamaModel, modelName string, showErr error, stored []api.ListModelResponse) string {
+	if showErr != nil {
+		return fmt.Sprintf("%s cannot be read from the Ollama server: %v", modelName, showErr)
+	}
+	for _, model := range stored {
+		if !ollamamodel.SameModel(model.Name, modelName) {
+			continue
+		}
+		if digestChanged(ollamaModel, model.Digest) {
+			return fmt.Sprintf("%s is stored with digest %s instead of %s", modelName, model.Digest, ollamaModel.Status.Digest)
+		}
+		return ""
+	}
+	return fmt.Sprintf("%s is not listed by the Ollama server", modelName)
+}
+
+// checkIntegrity verifies a Ready model stored on the Ollama server and
+// records the outcome in its Corrupted condition. A model found corrupted whose
+// spec sets repullOnCorruption is deleted from the server, which also removes
+// its damaged blobs, and left Pending to be pulled again; checkIntegrity
+// reports whether it was. A model still corrupted once pulled again is not
+// deleted again, so that a damaged blob kept by the server for another model
+// does not make it pulled over and over. A model missing from the server is
+// left to the reconcile, which pulls it again anyway.
+func (r *OllamaModelReconciler) checkIntegrity(ctx context.Context, ollamaModel *ollamamodel.OllamaModel, modelName string, showErr error) (bool, error) {
+	log := log.FromContext(ctx)
+
+	var statusErr api.StatusError
+	if errors.As(showErr, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	listResp, err := r.Ollama.List(ctx)
+	if err != nil {
+		return false, fmt.Errorf("listing models to verify %s: %w", modelName, err)
+	}
+
+	now := metav1.Now()
+	ollamaModel.Status.LastIntegrityCheckTime = &now
+	condition := metav1.Condition{
+		Type:               ollamamodel.ConditionCorrupted,
+		Status:             metav1.ConditionFalse,
+		Reason:             "Verified",
+		Message:            "The stored model is readable and matches its digest",
+		ObservedGeneration: ollamaModel.Generation,
+	}
+	problem := integrityProblem(ollamaModel, modelName, showErr, listResp.Models)
+	wasCorrupted := meta.IsStatusConditionTrue(ollamaModel.Status.Conditions, ollamamodel.ConditionCorrupted)
+	if problem != "" {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "IntegrityCheckFailed"
+		condition.Message = problem
+		log.Info("stored model is corrupted", "name", ollamaModel.Name, "model", modelName, "problem", problem)
+		r.Recorder.Event(ollamaModel, "Warning", "Corrupted", problem)
+	}
+	meta.SetStatusCondition(&ollamaModel.Status.Conditions, condition)
+
+	repull := problem != "" && !wasCorrupted && ollamaModel.Spec.RepullOnCorruption
+	if repull {
+		err := r.Ollama.Delete(ctx, &api.DeleteRequest{Name: modelName})
+		r.recordAudit(ctx, audit.ActionDelete, ollamaModel, modelName, err)
+		if err != nil {
+			// Leave the status alone, so that the check runs again on retry
+			r.Recorder.Event(ollamaModel, "Warning", "DeleteFailed",
+				fmt.Sprintf("Failed to delete corrupted model %s from Ollama: %v", modelName, err))
+			return false, fmt.Errorf("deleting corrupted model %s: %w", modelName, err)
+		}
+		r.Recorder.Event(ollamaModel, "Normal", "Repulling",
+			fmt.Sprintf("Deleted corrupted model %s from Ollama to pull it again", modelName))
+		ollamaModel.Status.State = ollamamodel.StatePending
+		// Verify the model again as soon as it is pulled
+		ollamaModel.Status.LastIntegrityCheckTime = nil
+	}
+
+	if err := r.updateStatus(ctx, ollamaModel); err != nil {
+		return false, err
+	}
+	return repull, nil
+}
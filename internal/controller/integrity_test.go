@@ -0,0 +1,130 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ollama/ollama/api"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+// integrityOllama is an Ollama client storing a single model with a given digest
+type integrityOllama struct {
+	OllamaClient
+	digest  string
+	deleted []string
+}
+
+func (f *integrityOllama) List(context.Context) (*api.ListResponse, error) {
+	return &api.ListResponse{Models: []api.ListModelResponse{{Name: "llama3.2:1b", Digest: f.digest}}}, nil
+}
+
+func (f *integrityOllama) Delete(_ context.Context, req *api.DeleteRequest) error {
+	f.deleted = append(f.deleted, req.Name)
+	return nil
+}
+
+var _ = Describe("Integrity checks", func() {
+	ctx := context.Background()
+	digest := strings.Repeat("a", 64)
+	now := time.Now()
+
+	It("finds unreadable, missing and replaced models", func() {
+		m := &ollamav1alpha1.OllamaModel{Status: ollamav1alpha1.OllamaModelStatus{Digest: digest}}
+		stored := []api.ListModelResponse{{Name: "llama3.2:1b", Digest: digest}}
+
+		Expect(integrityProblem(m, "llama3.2:1b", nil, stored)).To(BeEmpty())
+		Expect(integrityProblem(m, "llama3.2:1b", errors.New("unexpected EOF"), stored)).To(ContainSubstring("cannot be read"))
+		Expect(integrityProblem(m, "llama3.2:3b", nil, stored)).To(ContainSubstring("not listed"))
+		stored[0].Digest = strings.Repeat("b", 64)
+		Expect(integrityProblem(m, "llama3.2:1b", nil, stored)).To(ContainSubstring("instead of " + digest))
+	})
+
+	It("checks Ready models every interval", func() {
+		r := &OllamaModelReconciler{IntegrityCheckInterval: time.Hour}
+		m := &ollamav1alpha1.OllamaModel{Status: ollamav1alpha1.OllamaModelStatus{State: ollamav1alpha1.StateReady}}
+		Expect(r.integrityCheckDue(m, now)).To(BeTrue())
+		Expect(r.nextCheck(m, 0, now)).To(Equal(time.Second))
+
+		checked := metav1.NewTime(now.Add(-20 * time.Minute))
+		m.Status.LastIntegrityCheckTime = &checked
+		Expect(r.integrityCheckDue(m, now)).To(BeFalse())
+		Expect(r.nextCheck(m, 0, now)).To(Equal(40 * time.Minute))
+		Expect(r.nextCheck(m, 10*time.Minute, now)).To(Equal(10 * time.Minute))
+		Expect(r.integrityCheckDue(m, now.Add(40*time.Minute))).To(BeTrue())
+
+		m.Status.State = ollamav1alpha1.StatePulling
+		Expect(r.integrityCheckDue(m, now.Add(time.Hour))).To(BeFalse())
+
+		r.IntegrityCheckInterval = 0
+		Expect(r.nextCheck(m, 10*time.Minute, now)).To(Equal(10 * time.Minute))
+	})
+
+	It("reports a corrupted model and pulls it again when asked to", func() {
+		model := &ollamav1alpha1.OllamaModel{
+			ObjectMeta: metav1.ObjectMeta{Name: "integrity-llama", Namespace: "default"},
+			Spec:       ollamav1alpha1.OllamaModelSpec{Name: "llama3.2", Tag: "1b"},
+		}
+		Expect(k8sClient.Create(ctx, model)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, model)
+		model.Status.State = ollamav1alpha1.StateReady
+		model.Status.Digest = digest
+		Expect(k8sClient.Status().Update(ctx, model)).To(Succeed())
+
+		ollama := &integrityOllama{digest: digest}
+		recorder := record.NewFakeRecorder(10)
+		r := &OllamaModelReconciler{Client: k8sClient, Ollama: ollama, Recorder: recorder, IntegrityCheckInterval: time.Hour}
+
+		repull, err := r.checkIntegrity(ctx, model, "llama3.2:1b", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(repull).To(BeFalse())
+		Expect(meta.IsStatusConditionFalse(model.Status.Conditions, ollamav1alpha1.ConditionCorrupted)).To(BeTrue())
+		Expect(model.Status.LastIntegrityCheckTime).NotTo(BeNil())
+
+		ollama.digest = strings.Repeat("b", 64)
+		model.Spec.RepullOnCorruption = true
+		repull, err = r.checkIntegrity(ctx, model, "llama3.2:1b", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(repull).To(BeTrue())
+		Expect(recorder.Events).To(Receive(ContainSubstring("Corrupted")))
+		Expect(ollama.deleted).To(Equal([]string{"llama3.2:1b"}))
+
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(model), model)).To(Succeed())
+		Expect(model.Status.State).To(Equal(ollamav1alpha1.StatePending))
+		Expect(model.Status.LastIntegrityCheckTime).To(BeNil())
+		Expect(meta.IsStatusConditionTrue(model.Status.Conditions, ollamav1alpha1.ConditionCorrupted)).To(BeTrue())
+
+		// A model still corrupted once pulled again is only reported
+		model.Spec.RepullOnCorruption = true
+		model.Status.State = ollamav1alpha1.StateReady
+		repull, err = r.checkIntegrity(ctx, model, "llama3.2:1b", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(repull).To(BeFalse())
+		Expect(ollama.deleted).To(HaveLen(1))
+	})
+})
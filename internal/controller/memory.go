@@ -0,0 +1,148 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ollamamodel "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+// registryLookupTimeout bounds the registry lookups made while reconciling a
+// model, so that a registry that doesn't answer cannot hold a worker
+const registryLookupTimeout = 10 * time.Second
+
+// memoryOverhead is the share of the weights added to the memory requirement
+// of a model for its KV cache and runtime buffers at the default context size
+const memoryOverhead = 0.2
+
+// defaultQuantizationBits is the bits per weight of Q4_K_M, the quantization
+// of library tags that don't name one
+const defaultQuantizationBits = 4.5
+
+// quantizationBits is the average number of bits per weight of quantization families
+var quantizationBits = map[string]float64{
+	"q2": 2.6, "q3": 3.4, "q4": 4.5, "q5": 5.5, "q6": 6.6, "q8": 8.5,
+	"f16": 16, "fp16": 16, "bf16": 16, "f32": 32,
+}
+
+var (
+	// parameterCountPattern matches the parameter count leading a tag, such as
+	// "8b", "1.5b", "270m" or "8x7b" for mixtures of experts
+	parameterCountPattern = regexp.MustCompile(`^(?:(\d+)x)?(\d+(?:\.\d+)?)([bm])(?:-|$)`)
+	// quantizationPattern matches the quantization named in a tag
+	quantizationPattern = regexp.MustCompile(`(?:^|-)(q\d|bf16|fp16|f16|f32)`)
+)
+
+// weightsFromTag estimates the size of the weights of a model from the
+// parameter count and quantization in its tag (e.g., "8b-instruct-q8_0")
+func weightsFromTag(tag string) (int64, bool) {
+	tag = strings.ToLower(tag)
+	match := parameterCountPattern.FindStringSubmatch(tag)
+	if match == nil {
+		return 0, false
+	}
+
+	parameters, err := strconv.ParseFloat(match[2], 64)
+	if err != nil {
+		return 0, false
+	}
+	if match[1] != "" {
+		experts, _ := strconv.ParseFloat(match[1], 64)
+		parameters *= experts
+	}
+	if match[3] == "b" {
+		parameters *= 1e9
+	} else {
+		parameters *= 1e6
+	}
+
+	bits := defaultQuantizationBits
+	if quantization := quantizationPattern.FindStringSubmatch(tag); quantization != nil {
+		bits = quantizationBits[quantization[1]]
+	}
+	return int64(math.Ceil(parameters * bits / 8)), true
+}
+
+// estimateMemory estimates the memory needed to load a model with weights of the given size
+func estimateMemory(weights int64) int64 {
+	return weights + int64(float64(weights)*memoryOverhead)
+}
+
+// checkMemory estimates the memory a model needs before it is pulled, from the
+// size of its manifest in the registry or else from its tag, and sets the
+// WontFit condition by comparing it with the memory available to the Ollama
+// server. Models whose requirement can't be estimated are let through. It
+// reports whether the model fits and whether the condition changed.
+func (r *OllamaModelReconciler) checkMemory(ctx context.Context, ollamaModel *ollamamodel.OllamaModel, modelName string) (fits bool, changed bool) {
+	if r.AvailableMemory <= 0 {
+		return true, meta.RemoveStatusCondition(&ollamaModel.Status.Conditions, ollamamodel.ConditionWontFit)
+	}
+	log := log.FromContext(ctx)
+	reference := ollamaModel.Spec.ParsedReference()
+
+	var weights int64
+	if r.Registry != nil {
+		lookupCtx, cancel := context.WithTimeout(ctx, registryLookupTimeout)
+		_, manifest, err := r.Registry.Resolve(lookupCtx, reference.Name(), reference.Tag)
+		cancel()
+		if err == nil {
+			weights = manifest.Size
+		} else {
+			log.V(1).Info("failed to resolve the model in the registry, estimating its size from the tag", "model", modelName, "error", err.Error())
+		}
+	}
+	if weights == 0 {
+		var ok bool
+		if weights, ok = weightsFromTag(reference.Tag); !ok {
+			log.V(1).Info("unable to estimate the memory requirement of the model", "model", modelName)
+			return true, false
+		}
+	}
+
+	required := estimateMemory(weights)
+	condition := metav1.Condition{
+		Type:               ollamamodel.ConditionWontFit,
+		Status:             metav1.ConditionFalse,
+		Reason:             "Fits",
+		Message:            fmt.Sprintf("The model needs about %s of the %s available", formatBytes(required), formatBytes(r.AvailableMemory)),
+		ObservedGeneration: ollamaModel.Generation,
+	}
+	fits = required <= r.AvailableMemory
+	if !fits {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "InsufficientMemory"
+		condition.Message = fmt.Sprintf("The model needs about %s, but only %s is available to the Ollama server",
+			formatBytes(required), formatBytes(r.AvailableMemory))
+	}
+
+	changed = meta.SetStatusCondition(&ollamaModel.Status.Conditions, condition)
+	if changed && !fits {
+		r.Recorder.Event(ollamaModel, "Warning", "WontFit", condition.Message)
+	}
+	return fits, changed
+}
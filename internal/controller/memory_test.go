@@ -0,0 +1,86 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+var _ = Describe("Memory requirements", func() {
+	const gb = 1e9
+
+	DescribeTable("estimates the weights from the tag",
+		func(tag string, expected float64) {
+			weights, ok := weightsFromTag(tag)
+			Expect(ok).To(BeTrue())
+			Expect(float64(weights)).To(BeNumerically("~", expected, expected*0.01))
+		},
+		Entry("library default quantization", "8b", 4.5*gb),
+		Entry("explicit quantization", "8b-instruct-q8_0", 8.5*gb),
+		Entry("half precision", "1.5b-fp16", 3*gb),
+		Entry("millions of parameters", "270m", 0.152*gb),
+		Entry("mixture of experts", "8x7b-instruct-v0.1-q4_K_M", 31.5*gb),
+	)
+
+	It("can't estimate tags without a parameter count", func() {
+		_, ok := weightsFromTag("latest")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("sets the WontFit condition for models exceeding the available memory", func() {
+		recorder := record.NewFakeRecorder(10)
+		r := &OllamaModelReconciler{AvailableMemory: 16 * gb, Recorder: recorder}
+		model := &ollamav1alpha1.OllamaModel{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "memory", Name: "llama3.1-70b"},
+			Spec:       ollamav1alpha1.OllamaModelSpec{Name: "llama3.1", Tag: "70b"},
+		}
+
+		fits, changed := r.checkMemory(context.Background(), model, "llama3.1:70b")
+		Expect(fits).To(BeFalse())
+		Expect(changed).To(BeTrue())
+		Expect(meta.IsStatusConditionTrue(model.Status.Conditions, ollamav1alpha1.ConditionWontFit)).To(BeTrue())
+		Expect(recorder.Events).To(Receive(ContainSubstring("WontFit")))
+
+		fits, changed = r.checkMemory(context.Background(), model, "llama3.1:70b")
+		Expect(fits).To(BeFalse())
+		Expect(changed).To(BeFalse())
+		Expect(recorder.Events).NotTo(Receive())
+
+		model.Spec.Tag = "8b"
+		fits, changed = r.checkMemory(context.Background(), model, "llama3.1:8b")
+		Expect(fits).To(BeTrue())
+		Expect(changed).To(BeTrue())
+		Expect(meta.IsStatusConditionFalse(model.Status.Conditions, ollamav1alpha1.ConditionWontFit)).To(BeTrue())
+	})
+
+	It("lets every model through without a memory limit", func() {
+		r := &OllamaModelReconciler{}
+		model := &ollamav1alpha1.OllamaModel{Spec: ollamav1alpha1.OllamaModelSpec{Name: "llama3.1", Tag: "405b"}}
+		fits, changed := r.checkMemory(context.Background(), model, "llama3.1:405b")
+		Expect(fits).To(BeTrue())
+		Expect(changed).To(BeFalse())
+		Expect(model.Status.Conditions).To(BeEmpty())
+	})
+})
@@ -0,0 +1,256 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	ollamamodel "github.com/dmk/ollama-operator/api/v1alpha1"
+	"github.com/ollama/ollama/api"
+)
+
+// pullRateInterval is the window over which the download rate is measured
+const pullRateInterval = 5 * time.Second
+
+var (
+	pullBytesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ollama_model_pull_bytes_total",
+		Help: "Bytes downloaded while pulling models",
+	}, []string{"namespace", "name", "model"})
+
+	pullRate = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ollama_model_pull_rate_bytes_per_second",
+		Help: "Current download rate of models being pulled",
+	}, []string{"namespace", "name", "model"})
+
+	updateAvailable = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ollama_model_update_available",
+		Help: "Whether the registry serves a new version of the model, 1 if it does and 0 otherwise",
+	}, []string{"namespace", "name", "model"})
+
+	timeToReady = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ollama_model_time_to_ready_seconds",
+		Help:    "Time from the creation of OllamaModels to their first Ready state, by the size of the model",
+		Buckets: prometheus.ExponentialBuckets(10, 2, 12),
+	}, []string{"size"})
+)
+
+// sizeBuckets are the upper bounds of the size buckets labeling the time to
+// ready, so that the models of a size can be held to their own objective
+var sizeBuckets = []struct {
+	label string
+	bytes int64
+}{
+	{"lt1GiB", 1 << 30},
+	{"1-4GiB", 4 << 30},
+	{"4-16GiB", 16 << 30},
+	{"16-64GiB", 64 << 30},
+}
+
+// sizeBucket returns the size bucket of a model of size bytes
+func sizeBucket(size int64) string {
+	if size <= 0 {
+		return "unknown"
+	}
+	for _, bucket := range sizeBuckets {
+		if size < bucket.bytes {
+			return bucket.label
+		}
+	}
+	return "gte64GiB"
+}
+
+// recordFirstReady sets the first Ready time of a Ready model that has none,
+// and reports whether the model became Ready for the first time at now, so
+// that its time to ready is observed. The first Ready time is kept in the
+// status, so that each model is timed only once, even across restarts of the
+// operator. A model already pulled, at previousPull, was Ready before its
+// first Ready time was recorded, such as before an upgrade of the operator:
+// it gets the time of that pull and is not timed, as the time since its
+// creation would not tell how long it took to become Ready.
+func recordFirstReady(status *ollamamodel.OllamaModelStatus, previousPull *metav1.Time, now metav1.Time) bool {
+	if status.State != ollamamodel.StateReady || status.FirstReadyTime != nil {
+		return false
+	}
+	if previousPull != nil {
+		status.FirstReadyTime = previousPull.DeepCopy()
+		return false
+	}
+	status.FirstReadyTime = &now
+	return true
+}
+
+// observeTimeToReady records the time a model took from its creation to its
+// first Ready state
+func observeTimeToReady(ollamaModel *ollamamodel.OllamaModel) {
+	if ollamaModel.Status.FirstReadyTime == nil {
+		return
+	}
+	elapsed := ollamaModel.Status.FirstReadyTime.Sub(ollamaModel.CreationTimestamp.Time)
+	timeToReady.WithLabelValues(sizeBucket(ollamaModel.Status.Size)).Observe(max(elapsed.Seconds(), 0))
+}
+
+// modelAges exports the time since each model was last pulled
+var modelAges = &modelAgeCollector{
+	desc: prometheus.NewDesc("ollama_model_age_seconds", "Seconds since the model was last pulled",
+		[]string{"namespace", "name", "model"}, nil),
+	pulls: make(map[types.NamespacedName]modelPull),
+}
+
+// pullQueue exports the pulls waiting for a free pull slot
+var pullQueue = &pullQueueCollector{
+	length: prometheus.NewDesc("ollama_pull_queue_length", "Number of pulls waiting for a free pull slot", nil, nil),
+	wait: prometheus.NewDesc("ollama_pull_queue_wait_seconds", "Seconds a queued pull has been waiting for a free pull slot",
+		[]string{"kind", "namespace", "name", "model"}, nil),
+	pulls: make(map[*QueuedPull]struct{}),
+}
+
+func init() {
+	metrics.Registry.MustRegister(pullBytesTotal, pullRate, updateAvailable, timeToReady, modelAges, pullQueue)
+}
+
+// pullMetrics exports the bytes downloaded and the download rate of a pull
+type pullMetrics struct {
+	labels    prometheus.Labels
+	layers    map[string]int64
+	sampled   int64
+	sampledAt time.Time
+}
+
+// newPullMetrics starts measuring a pull of modelName for ollamaModel
+func newPullMetrics(ollamaModel *ollamamodel.OllamaModel, modelName string, now time.Time) *pullMetrics {
+	return &pullMetrics{
+		labels:    prometheus.Labels{"namespace": ollamaModel.Namespace, "name": ollamaModel.Name, "model": modelName},
+		layers:    make(map[string]int64),
+		sampledAt: now,
+	}
+}
+
+// observe records a progress update. The first update of a layer only sets its
+// baseline, so that parts downloaded by an earlier, interrupted pull are not
+// counted again when Ollama resumes it.
+func (m *pullMetrics) observe(resp api.ProgressResponse, now time.Time) {
+	if resp.Digest == "" {
+		return
+	}
+	previous, seen := m.layers[resp.Digest]
+	m.layers[resp.Digest] = resp.Completed
+	if seen && resp.Completed > previous {
+		pulled := resp.Completed - previous
+		pullBytesTotal.With(m.labels).Add(float64(pulled))
+		m.sampled += pulled
+	}
+
+	if elapsed := now.Sub(m.sampledAt); elapsed >= pullRateInterval {
+		pullRate.With(m.labels).Set(float64(m.sampled) / elapsed.Seconds())
+		m.sampled, m.sampledAt = 0, now
+	}
+}
+
+// done removes the download rate of the finished pull
+func (m *pullMetrics) done() {
+	pullRate.Delete(m.labels)
+}
+
+// modelPull is the last pull of a model
+type modelPull struct {
+	model string
+	at    time.Time
+}
+
+// modelAgeCollector computes the age of the models' last pulls when metrics are
+// scraped, so that the ages keep growing between reconciles
+type modelAgeCollector struct {
+	desc  *prometheus.Desc
+	mu    sync.Mutex
+	pulls map[types.NamespacedName]modelPull
+}
+
+// Describe implements prometheus.Collector
+func (c *modelAgeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect implements prometheus.Collector
+func (c *modelAgeCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, pull := range c.pulls {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, time.Since(pull.at).Seconds(),
+			key.Namespace, key.Name, pull.model)
+	}
+}
+
+// set records when a model was last pulled
+func (c *modelAgeCollector) set(key types.NamespacedName, modelName string, at time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pulls[key] = modelPull{model: modelName, at: at}
+}
+
+// forget stops exporting the age of a deleted model
+func (c *modelAgeCollector) forget(key types.NamespacedName) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.pulls, key)
+}
+
+// pullQueueCollector computes the wait of the queued pulls when metrics are
+// scraped, so that it keeps growing while they wait
+type pullQueueCollector struct {
+	length *prometheus.Desc
+	wait   *prometheus.Desc
+	mu     sync.Mutex
+	pulls  map[*QueuedPull]struct{}
+}
+
+// Describe implements prometheus.Collector
+func (c *pullQueueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.length
+	ch <- c.wait
+}
+
+// Collect implements prometheus.Collector
+func (c *pullQueueCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch <- prometheus.MustNewConstMetric(c.length, prometheus.GaugeValue, float64(len(c.pulls)))
+	for pull := range c.pulls {
+		ch <- prometheus.MustNewConstMetric(c.wait, prometheus.GaugeValue, time.Since(pull.Since).Seconds(),
+			pull.Kind, pull.Namespace, pull.Name, pull.Model)
+	}
+}
+
+// add starts exporting a queued pull
+func (c *pullQueueCollector) add(pull *QueuedPull) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pulls[pull] = struct{}{}
+}
+
+// remove stops exporting a pull that left the queue
+func (c *pullQueueCollector) remove(pull *QueuedPull) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.pulls, pull)
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	"github.com/ollama/ollama/api"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+var _ = Describe("Pull metrics", func() {
+	It("counts downloaded bytes from a baseline and measures the rate", func() {
+		model := &ollamav1alpha1.OllamaModel{ObjectMeta: metav1.ObjectMeta{Namespace: "metrics", Name: "llama3.2-1b"}}
+		start := time.Now()
+		m := newPullMetrics(model, "llama3.2:1b", start)
+		defer m.done()
+
+		m.observe(api.ProgressResponse{Digest: "sha256:a", Completed: 500, Total: 1000}, start)
+		m.observe(api.ProgressResponse{Digest: "sha256:a", Completed: 800, Total: 1000}, start.Add(time.Second))
+		m.observe(api.ProgressResponse{Digest: "sha256:a", Completed: 1000, Total: 1000}, start.Add(pullRateInterval))
+
+		Expect(testutil.ToFloat64(pullBytesTotal.With(m.labels))).To(BeEquivalentTo(500))
+		Expect(testutil.ToFloat64(pullRate.With(m.labels))).To(BeNumerically("~", 100))
+	})
+})
+
+var _ = Describe("Time to ready", func() {
+	It("buckets models by size", func() {
+		Expect(sizeBucket(0)).To(Equal("unknown"))
+		Expect(sizeBucket(800 << 20)).To(Equal("lt1GiB"))
+		Expect(sizeBucket(1 << 30)).To(Equal("1-4GiB"))
+		Expect(sizeBucket(4920 << 20)).To(Equal("4-16GiB"))
+		Expect(sizeBucket(40 << 30)).To(Equal("16-64GiB"))
+		Expect(sizeBucket(243 << 30)).To(Equal("gte64GiB"))
+	})
+
+	It("observes the time from creation to the first Ready state", func() {
+		created := time.Now().Add(-10 * time.Minute)
+		model := &ollamav1alpha1.OllamaModel{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "metrics", Name: "llama3.1-70b", CreationTimestamp: metav1.Time{Time: created}},
+			Status:     ollamav1alpha1.OllamaModelStatus{Size: 40 << 30},
+		}
+		histogram := func() *dto.Histogram {
+			metric := &dto.Metric{}
+			Expect(timeToReady.WithLabelValues("16-64GiB").(prometheus.Metric).Write(metric)).To(Succeed())
+			return metric.GetHistogram()
+		}
+		count := histogram().GetSampleCount()
+		observeTimeToReady(model)
+		Expect(histogram().GetSampleCount()).To(Equal(count))
+
+		model.Status.FirstReadyTime = &metav1.Time{Time: created.Add(8 * time.Minute)}
+		sum := histogram().GetSampleSum()
+		observeTimeToReady(model)
+		Expect(histogram().GetSampleCount()).To(Equal(count + 1))
+		Expect(histogram().GetSampleSum() - sum).To(BeNumerically("~", 480, 1))
+	})
+
+	It("only times the first transition to Ready", func() {
+		now := metav1.Now()
+		status := &ollamav1alpha1.OllamaModelStatus{State: ollamav1alpha1.StatePulling}
+		Expect(recordFirstReady(status, nil, now)).To(BeFalse())
+		Expect(status.FirstReadyTime).To(BeNil())
+
+		status.State = ollamav1alpha1.StateReady
+		Expect(recordFirstReady(status, nil, now)).To(BeTrue())
+		Expect(status.FirstReadyTime).To(Equal(&now))
+		Expect(recordFirstReady(status, nil, metav1.Now())).To(BeFalse())
+		Expect(status.FirstReadyTime).To(Equal(&now))
+	})
+
+	It("backfills models Ready before the first Ready time was recorded without timing them", func() {
+		pulled := metav1.NewTime(time.Now().Add(-30 * 24 * time.Hour))
+		status := &ollamav1alpha1.OllamaModelStatus{State: ollamav1alpha1.StateReady}
+		Expect(recordFirstReady(status, &pulled, metav1.Now())).To(BeFalse())
+		Expect(status.FirstReadyTime.Time).To(Equal(pulled.Time))
+	})
+})
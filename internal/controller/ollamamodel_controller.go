@@ -18,19 +18,33 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
+	"golang.org/x/time/rate"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	ollamamodel "github.com/dmk/ollama-operator/api/v1alpha1"
+	"github.com/dmk/ollama-operator/internal/audit"
+	"github.com/dmk/ollama-operator/internal/breaker"
+	"github.com/dmk/ollama-operator/internal/notify"
+	"github.com/dmk/ollama-operator/internal/opconfig"
+	"github.com/dmk/ollama-operator/internal/registry"
 	"github.com/ollama/ollama/api"
 )
 
@@ -39,6 +53,9 @@ type OllamaClient interface {
 	Delete(ctx context.Context, req *api.DeleteRequest) error
 	Show(ctx context.Context, req *api.ShowRequest) (*api.ShowResponse, error)
 	Pull(ctx context.Context, req *api.PullRequest, fn api.PullProgressFunc) error
+	Create(ctx context.Context, req *api.CreateRequest, fn api.CreateProgressFunc) error
+	Copy(ctx context.Context, req *api.CopyRequest) error
+	Embed(ctx context.Context, req *api.EmbedRequest) (*api.EmbedResponse, error)
 	List(ctx context.Context) (*api.ListResponse, error)
 }
 
@@ -48,13 +65,69 @@ type OllamaModelReconciler struct {
 	Scheme   *runtime.Scheme
 	Ollama   OllamaClient
 	Recorder record.EventRecorder
+	Audit    *audit.Logger
+	Notifier *notify.Notifier
+	Alerter  *notify.Alerter
+	// StuckPullThreshold is how long a pull may run before an alert is sent; 0 disables the alert
+	StuckPullThreshold time.Duration
+	// StaleThreshold is how long after its last pull a Ready model is reported
+	// as stale; 0 disables the Stale condition
+	StaleThreshold time.Duration
+	// UpdateCheckInterval is how often the registry is checked for a new
+	// version of Ready models, reported by their NewVersionAvailable
+	// condition without pulling it; 0 disables the check
+	UpdateCheckInterval time.Duration
+	// IntegrityCheckInterval is how often Ready models are verified on the
+	// Ollama server and their Corrupted condition set; 0 disables the check
+	IntegrityCheckInterval time.Duration
+	// FinalizerTimeout is how long a deleted model waits for its removal from
+	// Ollama before it is let go regardless; 0 waits forever
+	FinalizerTimeout time.Duration
+	// AvailableMemory is the memory available to the Ollama server in bytes.
+	// Models estimated to need more are not pulled; 0 disables the check.
+	AvailableMemory int64
+	// Registry resolves model manifests to estimate their memory requirement;
+	// nil estimates it from the tag alone
+	Registry *registry.Client
+	// ExportImage is the image of the Jobs exporting model blobs; empty uses DefaultExportImage
+	ExportImage string
+	// Settings is the runtime configuration; nil uses the zero Settings
+	Settings *opconfig.Store
+	// TagsPoller, if set, reconciles models changed on the Ollama server
+	// between resyncs
+	TagsPoller *TagsPoller
+	// OllamaURL is the address of the Ollama server reported in the endpoints
+	// of the models' status; empty leaves the endpoints out
+	OllamaURL string
+	// Identity names this replica in the status of the models it pulls, so
+	// that the replica taking over after a leader failover can tell
+	Identity string
+	// Pulls caps the models pulled at the same time along with the other
+	// controllers pulling models; nil caps the pulls of this reconciler alone
+	Pulls *PullSlots
+	// APIReader lists the models of every namespace when deciding whether a
+	// deleted model is still managed by another, which a cache scoped to some
+	// namespaces or labels does not hold; nil lists them through the cache
+	APIReader client.Reader
+
+	pulls  PullSlots
+	shared sharedPulls
 }
 
 const ollamaModelFinalizer = "ollama.smithforge.dev/finalizer"
 
+// Defaults of the work queue's per-model exponential backoff, matching controller-runtime
+const (
+	defaultRateLimiterBaseDelay = 5 * time.Millisecond
+	defaultRateLimiterMaxDelay  = 1000 * time.Second
+)
+
 // +kubebuilder:rbac:groups=ollama.smithforge.dev,resources=ollamamodels,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=ollama.smithforge.dev,resources=ollamamodels/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=ollama.smithforge.dev,resources=ollamamodels/finalizers,verbs=update
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -67,7 +140,7 @@ func (r *OllamaModelReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	}
 
 	// Construct the full model name (e.g., "llama2:7b")
-	modelName := fmt.Sprintf("%s:%s", ollamaModel.Spec.Name, ollamaModel.Spec.Tag)
+	modelName := ollamaModel.Spec.Reference()
 
 	// Check if the model is being deleted
 	if !ollamaModel.DeletionTimestamp.IsZero() {
@@ -83,71 +156,392 @@ func (r *OllamaModelReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 			// If update fails, retry after a short delay
 			return ctrl.Result{RequeueAfter: time.Second * 5}, err
 		}
-		return ctrl.Result{}, nil
+		// Continue rather than waiting for the update event, which the
+		// predicates filter out
 	}
 
 	log.Info("reconciling OllamaModel", "name", ollamaModel.Name, "model", modelName)
 
-	// Check for refresh annotation
-	if val, exists := ollamaModel.Annotations["ollama.smithforge.dev/refresh"]; exists && val == "true" {
-		log.Info("refresh annotation detected, forcing model refresh", "name", ollamaModel.Name, "model", modelName)
-		return r.refreshModel(ctx, ollamaModel, modelName)
+	// A cancelled pull or refresh is settled before anything else is done
+	if cancelRequested(ollamaModel) {
+		switch ollamaModel.Status.State {
+		case "", ollamamodel.StatePending, ollamamodel.StatePulling:
+			return r.pullCancelled(ctx, ollamaModel, modelName)
+		}
+		if ollamaModel.RefreshRequested() {
+			return r.pullCancelled(ctx, ollamaModel, modelName)
+		}
+		if err := r.dropCancelRequest(ctx, ollamaModel); err != nil {
+			return ctrl.Result{RequeueAfter: time.Second * 5}, err
+		}
+	}
+
+	// A refresh annotation set to a value not handled yet requests a refresh
+	if ollamaModel.RefreshRequested() {
+		if !refreshSatisfied(ollamaModel) {
+			log.Info("refresh annotation detected, forcing model refresh", "name", ollamaModel.Name, "model", modelName,
+				"value", ollamaModel.Annotations[ollamamodel.RefreshAnnotation])
+			return r.refreshModel(ctx, ollamaModel, modelName)
+		}
+		// The refresh was requested while the model was being pulled, by a
+		// pull that has finished since: pulling again would download nothing new
+		r.Recorder.Event(ollamaModel, "Normal", "RefreshSkipped",
+			fmt.Sprintf("Model %s was pulled after the refresh was requested", modelName))
+		ollamaModel.Status.LastHandledRefresh = ollamaModel.Annotations[ollamamodel.RefreshAnnotation]
+		if err := r.updateStatus(ctx, ollamaModel); err != nil {
+			return ctrl.Result{RequeueAfter: time.Second * 5}, err
+		}
 	}
 
 	// Initialize status if needed
 	if ollamaModel.Status.State == "" {
 		log.Info("initializing model status", "name", ollamaModel.Name)
 		ollamaModel.Status.State = ollamamodel.StatePending
-		if err := r.Status().Update(ctx, ollamaModel); err != nil {
+		if err := r.updateStatus(ctx, ollamaModel); err != nil {
 			// If update fails, retry after a short delay
 			return ctrl.Result{RequeueAfter: time.Second * 5}, err
 		}
-		return ctrl.Result{}, nil
+	}
+
+	// A requested retry pulls the model again whatever its failures
+	retryRequested, err := r.acceptRetry(ctx, ollamaModel)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: time.Second * 5}, err
+	}
+	if retryRequested {
+		log.Info("retry requested", "name", ollamaModel.Name, "model", modelName)
+	}
+
+	// Failed pulls are retried with a growing delay, unless the controller
+	// gave up on them, until the spec changes or a retry is requested
+	if ollamaModel.Status.State == ollamamodel.StateFailed && ollamaModel.Status.FailureReason != "" {
+		wait, retry := retryFailedPull(ollamaModel, time.Now())
+		if !retry {
+			return ctrl.Result{RequeueAfter: wait}, nil
+		}
+		log.Info("retrying failed pull", "name", ollamaModel.Name, "model", modelName, "attempts", ollamaModel.Status.PullAttempts)
 	}
 
 	// Check if model exists in Ollama
 	showReq := &api.ShowRequest{Name: modelName}
-	_, err := r.Ollama.Show(ctx, showReq)
+	_, err = r.Ollama.Show(ctx, showReq)
+	var openErr *breaker.OpenError
+	if errors.As(err, &openErr) {
+		return r.waitForOllama(ctx, ollamaModel, openErr)
+	}
+	if meta.IsStatusConditionFalse(ollamaModel.Status.Conditions, ollamamodel.ConditionOllamaAvailable) {
+		r.setOllamaAvailable(ollamaModel, metav1.ConditionTrue, "Reachable", "The Ollama server is reachable")
+		if err := r.updateStatus(ctx, ollamaModel); err != nil {
+			return ctrl.Result{RequeueAfter: time.Second * 5}, err
+		}
+	}
+	// Ready models are verified every integrity check interval; a corrupted
+	// one may be deleted to be pulled again
+	if r.integrityCheckDue(ollamaModel, time.Now()) {
+		repull, checkErr := r.checkIntegrity(ctx, ollamaModel, modelName, err)
+		if checkErr != nil {
+			return ctrl.Result{}, checkErr
+		}
+		if repull {
+			return ctrl.Result{Requeue: true}, nil
+		}
+	}
 	if err != nil {
+		// A Ready model missing from the Ollama server was deleted behind the
+		// operator's back, so pull it again
+		var statusErr api.StatusError
+		if ollamaModel.Status.State == ollamamodel.StateReady && errors.As(err, &statusErr) &&
+			statusErr.StatusCode == http.StatusNotFound {
+			log.Info("model missing from the Ollama server, pulling it again", "name", ollamaModel.Name, "model", modelName)
+			ollamaModel.Status.State = ollamamodel.StatePending
+		}
+
+		// A model left Pulling was being pulled by a replica that stopped
+		// before the pull finished, so pull it again
+		if ollamaModel.Status.State == ollamamodel.StatePulling {
+			r.takeOverPull(ctx, ollamaModel, modelName)
+		}
+
 		// Model doesn't exist, start pulling
 		if ollamaModel.Status.State == ollamamodel.StatePending {
+			// Don't pull models that would not fit in memory, which would only
+			// fail once loaded for the first inference
+			if fits, changed := r.checkMemory(ctx, ollamaModel, modelName); !fits {
+				log.Info("model won't fit in the memory of the Ollama server, not pulling it", "name", ollamaModel.Name, "model", modelName)
+				if changed {
+					if err := r.updateStatus(ctx, ollamaModel); err != nil {
+						return ctrl.Result{RequeueAfter: time.Second * 5}, err
+					}
+				}
+				return ctrl.Result{RequeueAfter: r.Settings.Get().ResyncInterval}, nil
+			}
+
+			if ollamaModel.Spec.RestoreFrom != nil {
+				return r.restoreModel(ctx, ollamaModel, modelName)
+			}
+
 			log.Info("starting model pull", "name", ollamaModel.Name, "model", modelName)
-			ollamaModel.Status.State = ollamamodel.StatePulling
-			if err := r.Status().Update(ctx, ollamaModel); err != nil {
+			r.startPull(ollamaModel, modelName, time.Now())
+			if err := r.updateStatus(ctx, ollamaModel); err != nil {
 				// If update fails, retry after a short delay
 				return ctrl.Result{RequeueAfter: time.Second * 5}, err
 			}
 
 			// Actually pull the model
+			pl := &pullLog{}
+			pl.add("pulling model %s", modelName)
+			r.savePullLog(ctx, ollamaModel, pl)
+
+			progress := &pullProgress{}
 			pullReq := &api.PullRequest{Name: modelName}
-			err := r.Ollama.Pull(ctx, pullReq, func(resp api.ProgressResponse) error {
+			err := r.pull(ctx, ollamaModel, pullReq, func(resp api.ProgressResponse) error {
 				log.Info("pull progress", "model", modelName, "status", resp.Status, "completed", resp.Completed)
+				pl.progress(resp)
+				if pl.due() {
+					r.savePullLog(ctx, ollamaModel, pl)
+				}
+				now := time.Now()
+				progress.update(resp, now)
+				r.reportProgress(ctx, ollamaModel, modelName, progress, now)
 				return nil
 			})
+			if errors.Is(err, errPullCancelled) {
+				pl.add("pull cancelled")
+				r.savePullLog(ctx, ollamaModel, pl)
+				r.recordAudit(ctx, audit.ActionPull, ollamaModel, modelName, err)
+				return r.pullCancelled(ctx, ollamaModel, modelName)
+			}
+			if shuttingDown(ctx, err) {
+				return r.pullInterrupted(ctx, ollamaModel, modelName, pl, progress)
+			}
 			if err != nil {
 				log.Error(err, "failed to pull model", "model", modelName)
-				ollamaModel.Status.State = ollamamodel.StateFailed
-				ollamaModel.Status.Error = err.Error()
-				if updateErr := r.Status().Update(ctx, ollamaModel); updateErr != nil {
+				pl.add("pull failed: %v", err)
+				r.savePullLog(ctx, ollamaModel, pl)
+				r.recordAudit(ctx, audit.ActionPull, ollamaModel, modelName, err)
+				if p := progress.stopped(); p.CompletedBytes > 0 {
+					ollamaModel.Status.Progress = p
+				}
+				pullFailed(ollamaModel, err)
+				if updateErr := r.updateStatus(ctx, ollamaModel); updateErr != nil {
 					// If update fails, retry after a short delay
 					return ctrl.Result{RequeueAfter: time.Second * 5}, updateErr
 				}
-				// Return error to trigger retry
-				return ctrl.Result{RequeueAfter: time.Second * 30}, err
+				r.notify(ctx, notify.EventModelFailed, ollamaModel, modelName)
+				return failedResult(ollamaModel), nil
 			}
 
 			log.Info("model pull completed successfully", "name", ollamaModel.Name, "model", modelName)
+			pl.add("pull completed")
+			r.savePullLog(ctx, ollamaModel, pl)
+			r.recordAudit(ctx, audit.ActionPull, ollamaModel, modelName, nil)
 			return r.updateModelDetails(ctx, ollamaModel, modelName)
 		}
 	} else {
-		// Model exists, update to ready if not already
-		if ollamaModel.Status.State != ollamamodel.StateReady {
+		// Model exists, update to ready if not already, and refresh the details
+		// when the spec changed to another model that exists already
+		if ollamaModel.Status.State != ollamamodel.StateReady ||
+			ollamaModel.Status.ObservedGeneration != ollamaModel.Generation {
+			// The model found may not be the one the snapshot captured
+			if ollamaModel.Spec.RestoreFrom != nil {
+				return r.restoreModel(ctx, ollamaModel, modelName)
+			}
 			log.Info("model already exists, marking as ready", "name", ollamaModel.Name, "model", modelName)
 			return r.updateModelDetails(ctx, ollamaModel, modelName)
 		}
+		// With integrity checks, a model stored with another digest is
+		// reported as corrupted rather than adopted
+		if r.IntegrityCheckInterval <= 0 && r.storedDigestChanged(ctx, ollamaModel, modelName) {
+			log.Info("model replaced on the Ollama server, updating its details", "name", ollamaModel.Name, "model", modelName)
+			return r.updateModelDetails(ctx, ollamaModel, modelName)
+		}
 	}
 
-	return ctrl.Result{}, nil
+	if ollamaModel.Status.State != ollamamodel.StateReady {
+		return ctrl.Result{RequeueAfter: r.Settings.Get().ResyncInterval}, nil
+	}
+
+	// Check Ready models again later, so that models deleted from the Ollama
+	// server behind the operator's back and models becoming stale are noticed
+	changed, untilStale := r.staleness(ollamaModel, modelName, time.Now())
+	if r.IntegrityCheckInterval <= 0 && meta.RemoveStatusCondition(&ollamaModel.Status.Conditions, ollamamodel.ConditionCorrupted) {
+		changed = true
+	}
+	updateChecked := r.updateCheckDue(ollamaModel, time.Now())
+	if updateChecked {
+		r.checkForUpdate(ctx, ollamaModel, modelName)
+		changed = true
+	}
+	if r.setNewVersionAvailable(ollamaModel, modelName) {
+		changed = true
+	}
+	// A new version is only pulled right after the check finding it, so that a
+	// failed refresh waits for the next check rather than being retried
+	if updateChecked && autoUpdate(ollamaModel) && r.inUpdateWindow(ctx, ollamaModel, modelName, time.Now()) {
+		log.Info("new version available, refreshing model", "name", ollamaModel.Name, "model", modelName,
+			"digest", ollamaModel.Status.LatestDigest)
+		r.Recorder.Event(ollamaModel, "Normal", "AutoUpdate",
+			fmt.Sprintf("Refreshing %s to digest %s following its Auto update policy", modelName, ollamaModel.Status.LatestDigest))
+		return r.refreshModel(ctx, ollamaModel, modelName)
+	}
+	embeddingChanged, err := r.checkEmbedding(ctx, ollamaModel, modelName, false)
+	derivedChanged, newlyFailed := false, false
+	if err != nil {
+		newlyFailed = r.embeddingFailed(ctx, ollamaModel, modelName, err)
+	} else if derivedChanged, err = r.applyDerivedModel(ctx, ollamaModel, modelName); err != nil {
+		newlyFailed = r.derivedModelFailed(ctx, ollamaModel, modelName, err)
+	}
+	var exportChanged bool
+	var exportErr error
+	if err == nil {
+		if exportChanged, exportErr = r.reconcileExport(ctx, ollamaModel, modelName); exportErr != nil {
+			log.Error(exportErr, "failed to export model blobs", "name", ollamaModel.Name, "model", modelName)
+		}
+	}
+	if changed || embeddingChanged || derivedChanged || exportChanged || err != nil {
+		if err := r.updateStatus(ctx, ollamaModel); err != nil {
+			return ctrl.Result{RequeueAfter: time.Second * 5}, err
+		}
+	}
+	if err != nil {
+		if newlyFailed {
+			r.notify(ctx, notify.EventModelFailed, ollamaModel, modelName)
+		}
+		return failedResult(ollamaModel), nil
+	}
+	if exportErr != nil {
+		return ctrl.Result{}, exportErr
+	}
+	return r.readyResult(r.nextCheck(ollamaModel, untilStale, time.Now())), nil
+}
+
+// pull pulls a model once a pull slot is free, so that no more than the
+// configured number of models are pulled at the same time. An alert is sent
+// when the pull runs for longer than the stuck pull threshold, and download
+// metrics are exported while it runs. A quantization that does not exist is
+// reported as such, and a pull cancelled with the cancel-pull annotation
+// returns errPullCancelled. A model already being pulled for another
+// OllamaModel is not pulled again: the pull follows the running one instead.
+func (r *OllamaModelReconciler) pull(ctx context.Context, ollamaModel *ollamamodel.OllamaModel, req *api.PullRequest, fn api.PullProgressFunc) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	go r.watchCancel(ctx, client.ObjectKeyFromObject(ollamaModel), cancel)
+
+	for {
+		shared, lead := r.shared.join(req.Name, client.ObjectKeyFromObject(ollamaModel).String())
+		if lead {
+			err := r.leadPull(ctx, ollamaModel, req, func(resp api.ProgressResponse) error {
+				shared.progress(resp)
+				return fn(resp)
+			})
+			r.shared.finish(req.Name, shared, err)
+			return err
+		}
+		retry, err := r.followPull(ctx, ollamaModel, req.Name, shared, fn)
+		if !retry {
+			return err
+		}
+	}
+}
+
+// followPull follows the pull of modelName led by another OllamaModel,
+// passing its progress to fn, and returns its outcome. A cancelled pull is
+// only cancelled for the model that led it, so retry is true when the
+// follower must pull the model itself.
+func (r *OllamaModelReconciler) followPull(ctx context.Context, ollamaModel *ollamamodel.OllamaModel, modelName string, pull *sharedPull, fn api.PullProgressFunc) (retry bool, err error) {
+	log.FromContext(ctx).Info("following the pull of another model", "name", ollamaModel.Name, "model", modelName, "leader", pull.leader)
+	r.Recorder.Event(ollamaModel, "Normal", "PullShared",
+		fmt.Sprintf("Following the pull of model %s for %s instead of pulling it again", modelName, pull.leader))
+
+	updates := pull.follow()
+	defer pull.unfollow(updates)
+	for {
+		select {
+		case <-ctx.Done():
+			if errors.Is(context.Cause(ctx), errPullCancelled) {
+				return false, errPullCancelled
+			}
+			return false, ctx.Err()
+		case resp := <-updates:
+			if err := fn(resp); err != nil {
+				return false, err
+			}
+		case <-pull.done:
+			if errors.Is(pull.err, errPullCancelled) {
+				return true, nil
+			}
+			return false, pull.err
+		}
+	}
+}
+
+// leadPull runs a pull followed by the other models, once a pull slot is free
+func (r *OllamaModelReconciler) leadPull(ctx context.Context, ollamaModel *ollamamodel.OllamaModel, req *api.PullRequest, fn api.PullProgressFunc) error {
+	slots := r.Pulls
+	if slots == nil {
+		slots = &r.pulls
+	}
+	queued := QueuedPull{Kind: "OllamaModel", Namespace: ollamaModel.Namespace, Name: ollamaModel.Name, Model: req.Name}
+	if err := slots.acquire(ctx, queued, func() int { return r.Settings.Get().MaxConcurrentPulls }); err != nil {
+		if errors.Is(context.Cause(ctx), errPullCancelled) {
+			return errPullCancelled
+		}
+		return err
+	}
+	defer slots.release()
+
+	if r.StuckPullThreshold > 0 {
+		alert := notify.Alert{
+			Kind:      notify.AlertPullStuck,
+			Namespace: ollamaModel.Namespace,
+			Name:      ollamaModel.Name,
+			Model:     req.Name,
+			Duration:  r.StuckPullThreshold.String(),
+		}
+		stuck := time.AfterFunc(r.StuckPullThreshold, func() { r.Alerter.Alert(ctx, alert) })
+		defer stuck.Stop()
+	}
+
+	metrics := newPullMetrics(ollamaModel, req.Name, time.Now())
+	defer metrics.done()
+	err := r.Ollama.Pull(ctx, req, func(resp api.ProgressResponse) error {
+		metrics.observe(resp, time.Now())
+		return fn(resp)
+	})
+	if err != nil && errors.Is(context.Cause(ctx), errPullCancelled) {
+		return errPullCancelled
+	}
+
+	// Ollama reports unknown tags as a missing manifest file
+	if spec := ollamaModel.Spec; err != nil && spec.Quantization != "" && strings.Contains(err.Error(), "file does not exist") {
+		return fmt.Errorf("quantization %s of %s:%s is not available: %w", spec.Quantization, spec.Name, spec.Tag, err)
+	}
+	return err
+}
+
+// waitForOllama records that the Ollama server is unavailable and requeues the
+// model once the circuit breaker lets calls through again. No error is returned
+// so that an outage does not flood the logs with one error per model.
+func (r *OllamaModelReconciler) waitForOllama(ctx context.Context, ollamaModel *ollamamodel.OllamaModel, openErr *breaker.OpenError) (ctrl.Result, error) {
+	log.FromContext(ctx).V(1).Info("ollama server unavailable, waiting", "name", ollamaModel.Name, "retryAfter", openErr.RetryAfter)
+
+	if r.setOllamaAvailable(ollamaModel, metav1.ConditionFalse, "CircuitOpen", openErr.Error()) {
+		if err := r.updateStatus(ctx, ollamaModel); err != nil {
+			return ctrl.Result{RequeueAfter: time.Second * 5}, err
+		}
+	}
+	return ctrl.Result{RequeueAfter: openErr.RetryAfter}, nil
+}
+
+// setOllamaAvailable sets the OllamaAvailable condition and reports whether it changed
+func (r *OllamaModelReconciler) setOllamaAvailable(ollamaModel *ollamamodel.OllamaModel, status metav1.ConditionStatus, reason, message string) bool {
+	return meta.SetStatusCondition(&ollamaModel.Status.Conditions, metav1.Condition{
+		Type:               ollamamodel.ConditionOllamaAvailable,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: ollamaModel.Generation,
+	})
 }
 
 // updateModelDetails updates the OllamaModel details including state, digest, and size
@@ -156,7 +550,9 @@ func (r *OllamaModelReconciler) updateModelDetails(ctx context.Context, ollamaMo
 
 	// Update state to ready
 	now := metav1.Now()
+	previousPull := ollamaModel.Status.LastPullTime
 	ollamaModel.Status.State = ollamamodel.StateReady
+	ollamaModel.Status.Error = ""
 	ollamaModel.Status.LastPullTime = &now
 
 	// Get model details
@@ -179,7 +575,11 @@ func (r *OllamaModelReconciler) updateModelDetails(ctx context.Context, ollamaMo
 			// Find the model in the list
 			for _, model := range listResp.Models {
 				// Check if this is our model
-				if model.Name == modelName {
+				if ollamamodel.SameModel(model.Name, modelName) {
+					// Prefer the manifest digest reported by Ollama
+					if model.Digest != "" {
+						ollamaModel.Status.Digest = model.Digest
+					}
 					// Update the size from the list response
 					ollamaModel.Status.Size = model.Size
 					// Set the formatted size
@@ -193,10 +593,39 @@ func (r *OllamaModelReconciler) updateModelDetails(ctx context.Context, ollamaMo
 		}
 	}
 
+	_, untilStale := r.staleness(ollamaModel, modelName, now.Time)
+	r.setNewVersionAvailable(ollamaModel, modelName)
+	// The failures of a check are kept until it passes, so that a model
+	// failing it again is neither notified again nor checked before its
+	// retry delay
+	newlyFailed := false
+	if _, err := r.checkEmbedding(ctx, ollamaModel, modelName, true); err != nil {
+		newlyFailed = r.embeddingFailed(ctx, ollamaModel, modelName, err)
+	} else if _, err := r.applyDerivedModel(ctx, ollamaModel, modelName); err != nil {
+		newlyFailed = r.derivedModelFailed(ctx, ollamaModel, modelName, err)
+	} else {
+		resetPullFailures(ollamaModel)
+	}
+	var exportErr error
+	if ollamaModel.Status.State == ollamamodel.StateReady {
+		// The model is usable regardless, so a failed export is only retried
+		if _, exportErr = r.reconcileExport(ctx, ollamaModel, modelName); exportErr != nil {
+			log.Error(exportErr, "failed to export model blobs", "name", ollamaModel.Name, "model", modelName)
+		}
+	}
+
+	if pinned := ollamaModel.Annotations[ollamamodel.DigestAnnotation]; pinned != "" &&
+		ollamaModel.Status.Digest != "" && pinned != ollamaModel.Status.Digest {
+		r.Recorder.Event(ollamaModel, "Warning", "DigestMismatch",
+			fmt.Sprintf("Pulled %s with digest %s, but the model is pinned to %s", modelName, ollamaModel.Status.Digest, pinned))
+	}
+
+	firstReady := recordFirstReady(&ollamaModel.Status, previousPull, now)
+
 	// Use exponential backoff for status updates
 	maxRetries := 3
 	for i := 0; i < maxRetries; i++ {
-		if err := r.Status().Update(ctx, ollamaModel); err != nil {
+		if err := r.updateStatus(ctx, ollamaModel); err != nil {
 			if i == maxRetries-1 {
 				return ctrl.Result{}, err
 			}
@@ -206,8 +635,23 @@ func (r *OllamaModelReconciler) updateModelDetails(ctx context.Context, ollamaMo
 		}
 		break
 	}
+	// The status is saved; a model whose status could not be saved is timed
+	// when it is next found Ready, with its first Ready time still unset
+	if firstReady {
+		observeTimeToReady(ollamaModel)
+	}
 
-	return ctrl.Result{}, nil
+	if ollamaModel.Status.State == ollamamodel.StateFailed {
+		if newlyFailed {
+			r.notify(ctx, notify.EventModelFailed, ollamaModel, modelName)
+		}
+		return failedResult(ollamaModel), nil
+	}
+	r.notify(ctx, notify.EventModelReady, ollamaModel, modelName)
+	if exportErr != nil {
+		return ctrl.Result{}, exportErr
+	}
+	return r.readyResult(r.nextCheck(ollamaModel, untilStale, time.Now())), nil
 }
 
 // formatBytes converts bytes to a human-readable string (e.g., "4.2 GiB")
@@ -249,59 +693,207 @@ func formatBytes(bytes int64) string {
 	return fmt.Sprintf("%.1f %s", value, unit)
 }
 
-// handleDeletion handles the deletion of a model when the OllamaModel resource is deleted
+// handleDeletion deletes the model from Ollama before letting the OllamaModel
+// resource go. A failed delete is retried with the work queue's backoff until
+// it succeeds, the finalizer timeout passes or the force-delete annotation is
+// set; in the latter two cases the model may be left behind in Ollama. The
+// model is in the Deleting state meanwhile, and every attempt and its outcome
+// is recorded as an event.
 func (r *OllamaModelReconciler) handleDeletion(ctx context.Context, ollamaModel *ollamamodel.OllamaModel, modelName string) (ctrl.Result, error) {
 	log := log.FromContext(ctx)
 
-	// Check if the finalizer exists
-	if controllerutil.ContainsFinalizer(ollamaModel, ollamaModelFinalizer) {
-		// Delete the model from Ollama with retries
-		maxRetries := 3
-		var deleteErr error
-		for i := 0; i < maxRetries; i++ {
-			deleteReq := &api.DeleteRequest{Name: modelName}
-			deleteErr = r.Ollama.Delete(ctx, deleteReq)
-			if deleteErr == nil {
-				break
-			}
-			// If model not found, that's fine - it's already deleted
-			if strings.Contains(deleteErr.Error(), "model not found") {
-				deleteErr = nil
-				break
-			}
-			// Wait with exponential backoff before retrying
-			time.Sleep(time.Second * time.Duration(1<<uint(i)))
+	if !controllerutil.ContainsFinalizer(ollamaModel, ollamaModelFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	if ollamaModel.Status.State != ollamamodel.StateDeleting {
+		ollamaModel.Status.State = ollamamodel.StateDeleting
+		ollamaModel.Status.Error = ""
+		if err := r.updateStatus(ctx, ollamaModel); err != nil {
+			return ctrl.Result{}, err
 		}
+	}
 
-		if deleteErr != nil {
-			log.Error(deleteErr, "failed to delete model from Ollama after retries", "model", modelName)
-			// We don't return an error here as we still want to allow deletion of the resource
-			// even if the model deletion fails
-		} else {
-			log.Info("successfully deleted model from Ollama", "model", modelName)
+	// Keep the model in Ollama while another OllamaModel still manages it
+	sharedWith, err := r.sharedWith(ctx, ollamaModel, modelName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// The derived model belongs to this OllamaModel alone, even when the pulled
+	// model is shared. Failing to delete it does not hold up the deletion.
+	force := ollamaModel.Annotations[ollamamodel.ForceDeleteAnnotation] == "true"
+	if derived := ollamaModel.Status.DerivedModel; derived != "" && !force {
+		err := r.Ollama.Delete(ctx, &api.DeleteRequest{Model: derived})
+		if err != nil && !strings.Contains(err.Error(), "model not found") {
+			log.Error(err, "failed to delete derived model from Ollama", "model", derived)
+			r.Recorder.Event(ollamaModel, "Warning", "DeleteFailed",
+				fmt.Sprintf("Failed to delete model %s from Ollama: %v", derived, err))
 		}
+	}
 
-		// Remove the finalizer to allow the resource to be deleted
-		controllerutil.RemoveFinalizer(ollamaModel, ollamaModelFinalizer)
-		if err := r.Update(ctx, ollamaModel); err != nil {
-			// If update fails, retry after a short delay
-			return ctrl.Result{RequeueAfter: time.Second * 5}, err
+	switch {
+	case sharedWith != "":
+		log.Info("keeping model in Ollama, it is still managed by another OllamaModel", "model", modelName, "managedBy", sharedWith)
+		r.Recorder.Event(ollamaModel, "Normal", "DeleteSkipped",
+			fmt.Sprintf("Kept model %s in Ollama, it is still managed by %s", modelName, sharedWith))
+	case force:
+		log.Info("force-delete annotation set, not deleting model from Ollama", "model", modelName)
+		r.Recorder.Event(ollamaModel, "Warning", "ForceDeleted",
+			fmt.Sprintf("Removed without deleting model %s from Ollama", modelName))
+	default:
+		r.Recorder.Event(ollamaModel, "Normal", "Deleting", fmt.Sprintf("Deleting model %s from Ollama", modelName))
+		deleteErr := r.Ollama.Delete(ctx, &api.DeleteRequest{Name: modelName})
+		absent := deleteErr != nil && strings.Contains(deleteErr.Error(), "model not found")
+		if absent {
+			deleteErr = nil
+		}
+		r.recordAudit(ctx, audit.ActionDelete, ollamaModel, modelName, deleteErr)
+
+		switch {
+		case absent:
+			log.Info("model was already absent from Ollama", "model", modelName)
+			r.Recorder.Event(ollamaModel, "Normal", "AlreadyAbsent", fmt.Sprintf("Model %s was already absent from Ollama", modelName))
+		case deleteErr == nil:
+			log.Info("successfully deleted model from Ollama", "model", modelName)
+			r.Recorder.Event(ollamaModel, "Normal", "Deleted", fmt.Sprintf("Deleted model %s from Ollama", modelName))
+		case r.FinalizerTimeout == 0 || time.Since(ollamaModel.DeletionTimestamp.Time) < r.FinalizerTimeout:
+			log.Error(deleteErr, "failed to delete model from Ollama, retrying", "model", modelName)
+			r.Recorder.Event(ollamaModel, "Warning", "DeleteFailed",
+				fmt.Sprintf("Failed to delete model %s from Ollama, retrying: %v", modelName, deleteErr))
+			ollamaModel.Status.Error = deleteErr.Error()
+			if err := r.updateStatus(ctx, ollamaModel); err != nil {
+				log.Error(err, "failed to update status", "name", ollamaModel.Name)
+			}
+			var openErr *breaker.OpenError
+			if errors.As(deleteErr, &openErr) {
+				return ctrl.Result{RequeueAfter: openErr.RetryAfter}, nil
+			}
+			return ctrl.Result{}, deleteErr
+		default:
+			log.Error(deleteErr, "giving up deleting model from Ollama after the finalizer timeout", "model", modelName, "timeout", r.FinalizerTimeout)
+			r.Recorder.Event(ollamaModel, "Warning", "FinalizerTimeout",
+				fmt.Sprintf("Gave up deleting model %s from Ollama after %s, it may be left behind: %v", modelName, r.FinalizerTimeout, deleteErr))
 		}
 	}
 
+	// Remove the finalizer to allow the resource to be deleted
+	controllerutil.RemoveFinalizer(ollamaModel, ollamaModelFinalizer)
+	if err := r.Update(ctx, ollamaModel); err != nil {
+		// If update fails, retry after a short delay
+		return ctrl.Result{RequeueAfter: time.Second * 5}, err
+	}
+	modelAges.forget(client.ObjectKeyFromObject(ollamaModel))
+	forgetUpdate(client.ObjectKeyFromObject(ollamaModel))
+	r.notify(ctx, notify.EventModelDeleted, ollamaModel, modelName)
+
 	return ctrl.Result{}, nil
 }
 
+// sharedWith returns the namespace/name of another OllamaModel that manages the
+// same Ollama model and is not being deleted, or "" if there is none
+func (r *OllamaModelReconciler) sharedWith(ctx context.Context, ollamaModel *ollamamodel.OllamaModel, modelName string) (string, error) {
+	var models ollamamodel.OllamaModelList
+	// The Ollama server is shared by every namespace, including those the
+	// cache leaves out. The API server has no index, so the models are
+	// matched below.
+	if r.APIReader != nil {
+		if err := r.APIReader.List(ctx, &models); err != nil {
+			return "", err
+		}
+	} else if err := r.List(ctx, &models, client.MatchingFields{ollamamodel.ModelReferenceField: ollamamodel.NormalizeModelReference(modelName)}); err != nil {
+		return "", err
+	}
+	for _, other := range models.Items {
+		if other.UID != ollamaModel.UID && other.DeletionTimestamp.IsZero() && ollamamodel.SameModel(other.Spec.Reference(), modelName) {
+			return other.Namespace + "/" + other.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// recordAudit records an audit event for an action the controller performed against Ollama
+func (r *OllamaModelReconciler) recordAudit(ctx context.Context, action string, ollamaModel *ollamamodel.OllamaModel, modelName string, err error) {
+	r.Audit.Record(ctx, audit.Event{
+		Source:    audit.SourceController,
+		Action:    action,
+		Principal: audit.ControllerPrincipal,
+		Requester: requester(action, ollamaModel),
+		Namespace: ollamaModel.Namespace,
+		Name:      ollamaModel.Name,
+		Model:     modelName,
+	}.WithError(err))
+}
+
+// requester returns the API principal that asked for an action on a model, if any
+func requester(action string, ollamaModel *ollamamodel.OllamaModel) string {
+	switch action {
+	case audit.ActionRefresh:
+		return ollamaModel.Annotations[ollamamodel.RefreshedByAnnotation]
+	case audit.ActionPull:
+		return ollamaModel.Annotations[ollamamodel.CreatedByAnnotation]
+	default:
+		return ""
+	}
+}
+
+// notify sends a model state transition to the configured webhooks, and
+// alerts about models that failed
+func (r *OllamaModelReconciler) notify(ctx context.Context, eventType string, ollamaModel *ollamamodel.OllamaModel, modelName string) {
+	event := notify.Event{
+		Type:      eventType,
+		Namespace: ollamaModel.Namespace,
+		Name:      ollamaModel.Name,
+		Model:     modelName,
+		State:     string(ollamaModel.Status.State),
+		Digest:    ollamaModel.Status.Digest,
+		Size:      ollamaModel.Status.Size,
+	}
+	if eventType == notify.EventModelFailed {
+		event.Error = ollamaModel.Status.Error
+		r.Alerter.Alert(ctx, notify.Alert{
+			Kind:      notify.AlertModelFailed,
+			Namespace: ollamaModel.Namespace,
+			Name:      ollamaModel.Name,
+			Model:     modelName,
+			Error:     ollamaModel.Status.Error,
+		})
+	}
+	r.Notifier.Notify(ctx, event)
+}
+
+// refreshSatisfied reports whether the refresh requested on a Ready model was
+// already satisfied by a pull finished after it was requested, such as a
+// refresh requested while the model was being pulled. Only refresh requests
+// set to a timestamp, as the API sets them, can be compared to the pull.
+func refreshSatisfied(ollamaModel *ollamamodel.OllamaModel) bool {
+	pulledAt := ollamaModel.Status.LastPullTime
+	if ollamaModel.Status.State != ollamamodel.StateReady || pulledAt == nil {
+		return false
+	}
+	requestedAt, err := time.Parse(time.RFC3339Nano, ollamaModel.Annotations[ollamamodel.RefreshAnnotation])
+	if err != nil {
+		return false
+	}
+	return !pulledAt.Time.Before(requestedAt)
+}
+
 // refreshModel forces a model to be re-pulled and updates its status
 func (r *OllamaModelReconciler) refreshModel(ctx context.Context, ollamaModel *ollamamodel.OllamaModel, modelName string) (ctrl.Result, error) {
 	log := log.FromContext(ctx)
+	request := ollamaModel.Annotations[ollamamodel.RefreshAnnotation]
+
+	// A refresh left Pulling was interrupted the same way as a pull
+	if ollamaModel.Status.State == ollamamodel.StatePulling {
+		r.takeOverPull(ctx, ollamaModel, modelName)
+	}
 
 	// Record event for refresh start
 	r.Recorder.Event(ollamaModel, "Normal", "RefreshStarted", fmt.Sprintf("Starting refresh of model %s", modelName))
 
 	// Set state to pulling to indicate a refresh is in progress
-	ollamaModel.Status.State = ollamamodel.StatePulling
-	if err := r.Status().Update(ctx, ollamaModel); err != nil {
+	r.startPull(ollamaModel, modelName, time.Now())
+	if err := r.updateStatus(ctx, ollamaModel); err != nil {
 		// If update fails, retry after a short delay
 		return ctrl.Result{RequeueAfter: time.Second * 5}, err
 	}
@@ -309,63 +901,157 @@ func (r *OllamaModelReconciler) refreshModel(ctx context.Context, ollamaModel *o
 	// Pull the model with retries
 	maxRetries := 3
 	var pullErr error
+	pl := &pullLog{}
+	progress := &pullProgress{}
 	for i := 0; i < maxRetries; i++ {
+		// A restored model is refreshed from its snapshot, not the registry
+		if ollamaModel.Spec.RestoreFrom != nil {
+			pl.add("restoring model %s from snapshot %s", modelName, ollamaModel.Spec.RestoreFrom.Name)
+			if pullErr = r.copySnapshot(ctx, ollamaModel, modelName); pullErr == nil || shuttingDown(ctx, pullErr) {
+				break
+			}
+			pl.add("attempt %d failed: %v", i+1, pullErr)
+			time.Sleep(time.Second * time.Duration(1<<uint(i)))
+			continue
+		}
+		pl.add("refreshing model %s (attempt %d/%d)", modelName, i+1, maxRetries)
+		r.savePullLog(ctx, ollamaModel, pl)
+
 		pullReq := &api.PullRequest{Name: modelName}
-		pullErr = r.Ollama.Pull(ctx, pullReq, func(resp api.ProgressResponse) error {
+		pullErr = r.pull(ctx, ollamaModel, pullReq, func(resp api.ProgressResponse) error {
 			log.Info("refresh progress", "model", modelName, "status", resp.Status, "completed", resp.Completed)
+			pl.progress(resp)
+			if pl.due() {
+				r.savePullLog(ctx, ollamaModel, pl)
+			}
+			now := time.Now()
+			progress.update(resp, now)
+			r.reportProgress(ctx, ollamaModel, modelName, progress, now)
 			return nil
 		})
-		if pullErr == nil {
+		if pullErr == nil || errors.Is(pullErr, errPullCancelled) || shuttingDown(ctx, pullErr) {
 			break
 		}
+		pl.add("attempt %d failed: %v", i+1, pullErr)
+		pl.lastStatus = ""
 		// Wait with exponential backoff before retrying
 		time.Sleep(time.Second * time.Duration(1<<uint(i)))
 	}
 
+	if errors.Is(pullErr, errPullCancelled) {
+		pl.add("refresh cancelled")
+		r.savePullLog(ctx, ollamaModel, pl)
+		r.recordAudit(ctx, audit.ActionRefresh, ollamaModel, modelName, pullErr)
+		return r.pullCancelled(ctx, ollamaModel, modelName)
+	}
+	if shuttingDown(ctx, pullErr) {
+		return r.pullInterrupted(ctx, ollamaModel, modelName, pl, progress)
+	}
 	if pullErr != nil {
 		log.Error(pullErr, "failed to refresh model after retries", "model", modelName)
-		ollamaModel.Status.State = ollamamodel.StateFailed
-		ollamaModel.Status.Error = pullErr.Error()
+		pl.add("refresh failed after %d attempts", maxRetries)
+		r.savePullLog(ctx, ollamaModel, pl)
+		r.recordAudit(ctx, audit.ActionRefresh, ollamaModel, modelName, pullErr)
+		if p := progress.stopped(); p.CompletedBytes > 0 {
+			ollamaModel.Status.Progress = p
+		}
+		pullFailed(ollamaModel, pullErr)
+		ollamaModel.Status.LastHandledRefresh = request
 
 		// Record event for refresh failure
 		r.Recorder.Event(ollamaModel, "Warning", "RefreshFailed",
 			fmt.Sprintf("Failed to refresh model %s: %v", modelName, pullErr))
 
-		if updateErr := r.Status().Update(ctx, ollamaModel); updateErr != nil {
+		if updateErr := r.updateStatus(ctx, ollamaModel); updateErr != nil {
 			// If update fails, retry after a short delay
 			return ctrl.Result{RequeueAfter: time.Second * 5}, updateErr
 		}
-		return ctrl.Result{RequeueAfter: time.Second * 30}, pullErr
+		r.notify(ctx, notify.EventModelFailed, ollamaModel, modelName)
+		return failedResult(ollamaModel), nil
 	}
 
-	// Update the model details
+	pl.add("refresh completed")
+	r.savePullLog(ctx, ollamaModel, pl)
+	r.recordAudit(ctx, audit.ActionRefresh, ollamaModel, modelName, nil)
+
+	// Update the model details, recording the refresh request as handled
+	ollamaModel.Status.LastHandledRefresh = request
 	result, err := r.updateModelDetails(ctx, ollamaModel, modelName)
 	if err != nil {
 		return result, err
 	}
 
-	// Update the annotation to indicate the refresh is complete
-	if ollamaModel.Annotations == nil {
-		ollamaModel.Annotations = make(map[string]string)
-	}
-	ollamaModel.Annotations["ollama.smithforge.dev/refresh"] = fmt.Sprintf("completed-%s", time.Now().Format(time.RFC3339))
-	if err := r.Update(ctx, ollamaModel); err != nil {
-		// If update fails, retry after a short delay
-		return ctrl.Result{RequeueAfter: time.Second * 5}, err
-	}
-
 	// Record event for successful refresh
 	r.Recorder.Event(ollamaModel, "Normal", "RefreshCompleted",
 		fmt.Sprintf("Successfully refreshed model %s (size: %s)", modelName, ollamaModel.Status.FormattedSize))
 
 	log.Info("model refresh completed successfully", "name", ollamaModel.Name, "model", modelName)
-	return ctrl.Result{}, nil
+	return result, nil
+}
+
+// Options tunes the throughput of the controller. Zero values select the
+// controller-runtime defaults.
+type Options struct {
+	// MaxConcurrentReconciles is the number of models reconciled in parallel
+	MaxConcurrentReconciles int
+	// RateLimiterBaseDelay is the requeue delay after a model's first failure,
+	// doubled on each further failure
+	RateLimiterBaseDelay time.Duration
+	// RateLimiterMaxDelay caps the requeue delay of a failing model
+	RateLimiterMaxDelay time.Duration
+}
+
+// rateLimiter returns the work queue rate limiter for the options, or nil for the default
+func (o Options) rateLimiter() workqueue.TypedRateLimiter[reconcile.Request] {
+	if o.RateLimiterBaseDelay == 0 && o.RateLimiterMaxDelay == 0 {
+		return nil
+	}
+
+	baseDelay, maxDelay := o.RateLimiterBaseDelay, o.RateLimiterMaxDelay
+	if baseDelay == 0 {
+		baseDelay = defaultRateLimiterBaseDelay
+	}
+	if maxDelay == 0 {
+		maxDelay = defaultRateLimiterMaxDelay
+	}
+
+	// Keep the overall bucket of the default limiter, which bounds retries across all models
+	return workqueue.NewTypedMaxOfRateLimiter(
+		workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](baseDelay, maxDelay),
+		&workqueue.TypedBucketRateLimiter[reconcile.Request]{Limiter: rate.NewLimiter(rate.Limit(10), 100)},
+	)
+}
+
+// indexModelReference indexes an OllamaModel by the Ollama model it manages
+func indexModelReference(obj client.Object) []string {
+	model, ok := obj.(*ollamamodel.OllamaModel)
+	if !ok {
+		return nil
+	}
+	return []string{ollamamodel.NormalizeModelReference(model.Spec.Reference())}
 }
 
 // SetupWithManager sets up the controller with the Manager.
-func (r *OllamaModelReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&ollamamodel.OllamaModel{}).
-		Named("ollamamodel").
+func (r *OllamaModelReconciler) SetupWithManager(mgr ctrl.Manager, opts Options) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &ollamamodel.OllamaModel{},
+		ollamamodel.ModelReferenceField, indexModelReference); err != nil {
+		return err
+	}
+
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&ollamamodel.OllamaModel{}, builder.WithPredicates(reconcilePredicate())).
+		Owns(&batchv1.Job{})
+	if r.TagsPoller != nil {
+		if err := mgr.Add(r.TagsPoller); err != nil {
+			return err
+		}
+		b = b.WatchesRawSource(r.TagsPoller.Source())
+	}
+
+	return b.Named("ollamamodel").
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: opts.MaxConcurrentReconciles,
+			RateLimiter:             opts.rateLimiter(),
+		}).
 		Complete(r)
 }
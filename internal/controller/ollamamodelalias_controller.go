@@ -0,0 +1,197 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	ollamamodel "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+// OllamaModelAliasReconciler reports which Ollama model an OllamaModelAlias
+// resolves to and whether it can serve requests. The inference gateway
+// resolves aliases itself, so repointing one takes effect without waiting for
+// the status.
+type OllamaModelAliasReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=ollama.smithforge.dev,resources=ollamamodelaliases,verbs=get;list;watch
+// +kubebuilder:rbac:groups=ollama.smithforge.dev,resources=ollamamodelaliases/status,verbs=get;update;patch
+
+// Reconcile resolves an alias to its model, or to the share of the requests
+// each model of its split receives
+func (r *OllamaModelAliasReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	alias := &ollamamodel.OllamaModelAlias{}
+	if err := r.Get(ctx, req.NamespacedName, alias); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	original := alias.Status.DeepCopy()
+	status := &alias.Status
+	status.ObservedGeneration = alias.Generation
+
+	switch {
+	case !alias.Spec.Valid():
+		status.Reference, status.Split = "", nil
+		r.setReady(alias, metav1.ConditionFalse, ollamamodel.ReasonInvalidSpec, "Exactly one of model and split must be set")
+	case len(alias.Spec.Split) > 0:
+		if err := r.resolveSplit(ctx, alias); err != nil {
+			return ctrl.Result{}, err
+		}
+	default:
+		if err := r.resolveModel(ctx, alias); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if equality.Semantic.DeepEqual(original, status) {
+		return ctrl.Result{}, nil
+	}
+	log.FromContext(ctx).Info("resolved model alias", "name", alias.Name, "model", alias.Spec.Model, "reference", status.Reference)
+	return ctrl.Result{}, r.Status().Update(ctx, alias)
+}
+
+// resolveModel resolves an alias pointing at a single model
+func (r *OllamaModelAliasReconciler) resolveModel(ctx context.Context, alias *ollamamodel.OllamaModelAlias) error {
+	status := &alias.Status
+	status.Split = nil
+
+	model := &ollamamodel.OllamaModel{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: alias.Namespace, Name: alias.Spec.Model}, model)
+	switch {
+	case apierrors.IsNotFound(err):
+		status.Reference = ""
+		r.setReady(alias, metav1.ConditionFalse, ollamamodel.ReasonModelNotFound,
+			fmt.Sprintf("OllamaModel %s does not exist", alias.Spec.Model))
+	case err != nil:
+		return err
+	default:
+		status.Reference = model.ServedModel()
+		if model.Status.State == ollamamodel.StateReady {
+			r.setReady(alias, metav1.ConditionTrue, ollamamodel.ReasonResolved,
+				fmt.Sprintf("Requests go to model %s", status.Reference))
+		} else {
+			r.setReady(alias, metav1.ConditionFalse, ollamamodel.ReasonModelNotReady,
+				fmt.Sprintf("OllamaModel %s is %s", alias.Spec.Model, model.Status.State))
+		}
+	}
+	return nil
+}
+
+// resolveSplit resolves an alias splitting its requests over several models,
+// reporting the share of the requests each receives. The weights of the
+// models that are not Ready go to the others, like the gateway does.
+func (r *OllamaModelAliasReconciler) resolveSplit(ctx context.Context, alias *ollamamodel.OllamaModelAlias) error {
+	status := &alias.Status
+	status.Reference = ""
+	status.Split = make([]ollamamodel.AliasBranchStatus, len(alias.Spec.Split))
+
+	weights := make([]int32, len(alias.Spec.Split))
+	var total int32
+	missing := 0
+	for i, branch := range alias.Spec.Split {
+		status.Split[i].Model = branch.Model
+		model := &ollamamodel.OllamaModel{}
+		if err := r.Get(ctx, client.ObjectKey{Namespace: alias.Namespace, Name: branch.Model}, model); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return err
+			}
+			missing++
+			continue
+		}
+		status.Split[i].Reference = model.ServedModel()
+		if model.Status.State == ollamamodel.StateReady {
+			weights[i] = branch.Weight
+			total += branch.Weight
+		}
+	}
+
+	if total == 0 {
+		reason := ollamamodel.ReasonModelNotReady
+		if missing == len(alias.Spec.Split) {
+			reason = ollamamodel.ReasonModelNotFound
+		}
+		r.setReady(alias, metav1.ConditionFalse, reason, "No model of the split with a weight is Ready")
+		return nil
+	}
+	shares := make([]string, 0, len(weights))
+	for i, weight := range weights {
+		status.Split[i].Percent = weight * 100 / total
+		if weight > 0 {
+			shares = append(shares, fmt.Sprintf("%s (%d%%)", status.Split[i].Reference, status.Split[i].Percent))
+		}
+	}
+	r.setReady(alias, metav1.ConditionTrue, ollamamodel.ReasonResolved,
+		fmt.Sprintf("Requests go to models %s", strings.Join(shares, ", ")))
+	return nil
+}
+
+// setReady sets the Ready condition of an alias
+func (r *OllamaModelAliasReconciler) setReady(alias *ollamamodel.OllamaModelAlias, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&alias.Status.Conditions, metav1.Condition{
+		Type:               ollamamodel.ConditionReady,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: alias.Generation,
+	})
+}
+
+// aliasesForModel maps a model to the aliases of its namespace pointing at it,
+// alone or in a split
+func (r *OllamaModelAliasReconciler) aliasesForModel(ctx context.Context, obj client.Object) []reconcile.Request {
+	var aliases ollamamodel.OllamaModelAliasList
+	if err := r.List(ctx, &aliases, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list model aliases", "namespace", obj.GetNamespace())
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range aliases.Items {
+		for _, branch := range aliases.Items[i].Spec.Branches() {
+			if branch.Model == obj.GetName() {
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&aliases.Items[i])})
+				break
+			}
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager
+func (r *OllamaModelAliasReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&ollamamodel.OllamaModelAlias{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&ollamamodel.OllamaModel{}, handler.EnqueueRequestsFromMapFunc(r.aliasesForModel)).
+		Named("ollamamodelalias").
+		Complete(r)
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+var _ = Describe("OllamaModelAlias Controller", func() {
+	It("resolves an alias to the model it points at", func() {
+		ctx := context.Background()
+		model := &ollamav1alpha1.OllamaModel{
+			ObjectMeta: metav1.ObjectMeta{Name: "alias-llama", Namespace: "default"},
+			Spec:       ollamav1alpha1.OllamaModelSpec{Name: "llama3.1", Tag: "8b"},
+		}
+		Expect(k8sClient.Create(ctx, model)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, model)
+
+		alias := &ollamav1alpha1.OllamaModelAlias{
+			ObjectMeta: metav1.ObjectMeta{Name: "assistant-prod", Namespace: "default"},
+			Spec:       ollamav1alpha1.OllamaModelAliasSpec{Model: "alias-llama"},
+		}
+		Expect(k8sClient.Create(ctx, alias)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, alias)
+
+		r := &OllamaModelAliasReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+		reconcileAlias := func() *ollamav1alpha1.OllamaModelAlias {
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(alias)})
+			Expect(err).NotTo(HaveOccurred())
+			resolved := &ollamav1alpha1.OllamaModelAlias{}
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(alias), resolved)).To(Succeed())
+			return resolved
+		}
+
+		// The model is not Ready yet
+		resolved := reconcileAlias()
+		Expect(resolved.Status.Reference).To(Equal("llama3.1:8b"))
+		ready := meta.FindStatusCondition(resolved.Status.Conditions, ollamav1alpha1.ConditionReady)
+		Expect(ready).NotTo(BeNil())
+		Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+		Expect(ready.Reason).To(Equal(ollamav1alpha1.ReasonModelNotReady))
+
+		model.Status.State = ollamav1alpha1.StateReady
+		model.Status.DerivedModel = "llama3.1:8b-default-alias-llama"
+		Expect(k8sClient.Status().Update(ctx, model)).To(Succeed())
+		resolved = reconcileAlias()
+		Expect(resolved.Status.Reference).To(Equal("llama3.1:8b-default-alias-llama"))
+		Expect(meta.IsStatusConditionTrue(resolved.Status.Conditions, ollamav1alpha1.ConditionReady)).To(BeTrue())
+
+		Expect(r.aliasesForModel(ctx, model)).To(HaveLen(1))
+
+		// Repointing the alias at a model that does not exist
+		resolved.Spec.Model = "missing"
+		Expect(k8sClient.Update(ctx, resolved)).To(Succeed())
+		resolved = reconcileAlias()
+		Expect(resolved.Status.Reference).To(BeEmpty())
+		ready = meta.FindStatusCondition(resolved.Status.Conditions, ollamav1alpha1.ConditionReady)
+		Expect(ready.Reason).To(Equal(ollamav1alpha1.ReasonModelNotFound))
+		Expect(resolved.Status.ObservedGeneration).To(Equal(resolved.Generation))
+	})
+
+	It("reports the share of the requests each model of a split receives", func() {
+		ctx := context.Background()
+		for _, m := range []struct {
+			name, tag string
+			state     ollamav1alpha1.ModelState
+		}{
+			{"split-stable", "8b", ollamav1alpha1.StateReady},
+			{"split-tuned", "3b", ollamav1alpha1.StateReady},
+			{"split-pending", "1b", ollamav1alpha1.StatePulling},
+		} {
+			model := &ollamav1alpha1.OllamaModel{
+				ObjectMeta: metav1.ObjectMeta{Name: m.name, Namespace: "default"},
+				Spec:       ollamav1alpha1.OllamaModelSpec{Name: "llama3.2", Tag: m.tag},
+			}
+			Expect(k8sClient.Create(ctx, model)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, model)
+			model.Status.State = m.state
+			Expect(k8sClient.Status().Update(ctx, model)).To(Succeed())
+		}
+
+		alias := &ollamav1alpha1.OllamaModelAlias{
+			ObjectMeta: metav1.ObjectMeta{Name: "assistant-canary", Namespace: "default"},
+			Spec: ollamav1alpha1.OllamaModelAliasSpec{Split: []ollamav1alpha1.AliasBranch{
+				{Model: "split-stable", Weight: 60},
+				{Model: "split-tuned", Weight: 20},
+				{Model: "split-pending", Weight: 20},
+			}},
+		}
+		Expect(k8sClient.Create(ctx, alias)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, alias)
+
+		r := &OllamaModelAliasReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(alias)})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(alias), alias)).To(Succeed())
+		Expect(alias.Status.Reference).To(BeEmpty())
+		Expect(alias.Status.Split).To(Equal([]ollamav1alpha1.AliasBranchStatus{
+			{Model: "split-stable", Reference: "llama3.2:8b", Percent: 75},
+			{Model: "split-tuned", Reference: "llama3.2:3b", Percent: 25},
+			{Model: "split-pending", Reference: "llama3.2:1b", Percent: 0},
+		}))
+		Expect(meta.IsStatusConditionTrue(alias.Status.Conditions, ollamav1alpha1.ConditionReady)).To(BeTrue())
+		Expect(r.aliasesForModel(ctx, &ollamav1alpha1.OllamaModel{
+			ObjectMeta: metav1.ObjectMeta{Name: "split-tuned", Namespace: "default"},
+		})).To(HaveLen(1))
+	})
+})
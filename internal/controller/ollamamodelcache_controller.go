@@ -0,0 +1,252 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	ollamamodel "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+// OllamaModelCacheReconciler keeps the volume of an OllamaModelCache in sync
+// with the models it selects. The models are pulled by a single Job for all
+// the Ollama servers mounting the volume, so that each is downloaded once.
+type OllamaModelCacheReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	// Image is the image of the Jobs pulling models into caches; empty uses DefaultExportImage
+	Image string
+}
+
+// +kubebuilder:rbac:groups=ollama.smithforge.dev,resources=ollamamodelcaches,verbs=get;list;watch
+// +kubebuilder:rbac:groups=ollama.smithforge.dev,resources=ollamamodelcaches/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create
+
+// Reconcile creates the volume of a cache and runs a Job whenever the
+// selected models differ from the ones it holds
+func (r *OllamaModelCacheReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	cache := &ollamamodel.OllamaModelCache{}
+	if err := r.Get(ctx, req.NamespacedName, cache); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !cache.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+	original := cache.Status.DeepCopy()
+	status := &cache.Status
+	status.ObservedGeneration = cache.Generation
+
+	status.ClaimName = ollamamodel.ModelCacheClaimName(cache.Name)
+	if err := r.ensureClaim(ctx, cache, status.ClaimName); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	models, err := r.selectedModels(ctx, cache)
+	if err != nil {
+		r.setReady(cache, metav1.ConditionFalse, "InvalidSelector", err.Error())
+		return ctrl.Result{}, r.updateStatus(ctx, cache, original)
+	}
+
+	// Wait for a running Job to finish before starting another, so that no two
+	// Ollama instances write to the volume at the same time
+	jobName := cacheJobName(cache.Name, models)
+	if status.Job != "" && status.Job != jobName {
+		running, err := r.jobRunning(ctx, cache.Namespace, status.Job)
+		if err != nil || running {
+			return ctrl.Result{}, err
+		}
+		status.Job = ""
+	}
+
+	if status.Job == "" && slices.Equal(models, status.Models) {
+		r.setReady(cache, metav1.ConditionTrue, "Synced", fmt.Sprintf("The cache holds %d models", len(models)))
+		return ctrl.Result{}, r.updateStatus(ctx, cache, original)
+	}
+
+	job := &batchv1.Job{}
+	err = r.Get(ctx, client.ObjectKey{Namespace: cache.Namespace, Name: jobName}, job)
+	switch {
+	case apierrors.IsNotFound(err):
+		job = newPullJob(cache.Namespace, jobName, status.ClaimName, r.Image, syncScript, models)
+		if err := controllerutil.SetControllerReference(cache, job, r.Scheme); err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.Create(ctx, job); err != nil && !apierrors.IsAlreadyExists(err) {
+			return ctrl.Result{}, fmt.Errorf("creating cache job %s: %w", jobName, err)
+		}
+		log.Info("syncing model cache", "name", cache.Name, "job", jobName, "models", models)
+		r.Recorder.Event(cache, "Normal", "Syncing", fmt.Sprintf("Pulling %d models with Job %s", len(models), jobName))
+		status.Job = jobName
+		r.setReady(cache, metav1.ConditionFalse, "Pulling", fmt.Sprintf("Job %s is pulling the selected models", jobName))
+	case err != nil:
+		return ctrl.Result{}, err
+	case job.Status.Succeeded > 0:
+		log.Info("synced model cache", "name", cache.Name, "models", models)
+		r.Recorder.Event(cache, "Normal", "Synced", fmt.Sprintf("The cache holds %d models", len(models)))
+		status.Models, status.Job = models, ""
+		r.setReady(cache, metav1.ConditionTrue, "Synced", fmt.Sprintf("The cache holds %d models", len(models)))
+	default:
+		status.Job = jobName
+		// The Job is retried once its TTL removes it
+		if failure := jobFailure(job); failure != "" {
+			if r.setReady(cache, metav1.ConditionFalse, "PullFailed", failure) {
+				r.Recorder.Event(cache, "Warning", "SyncFailed", failure)
+			}
+		}
+	}
+	return ctrl.Result{}, r.updateStatus(ctx, cache, original)
+}
+
+// ensureClaim creates the ReadWriteMany volume of a cache
+func (r *OllamaModelCacheReconciler) ensureClaim(ctx context.Context, cache *ollamamodel.OllamaModelCache, claimName string) error {
+	claim := &corev1.PersistentVolumeClaim{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: cache.Namespace, Name: claimName}, claim)
+	if !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	claim = &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: claimName, Namespace: cache.Namespace},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+			StorageClassName: cache.Spec.StorageClassName,
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: cache.Spec.Size},
+			},
+		},
+	}
+	if err := controllerutil.SetControllerReference(cache, claim, r.Scheme); err != nil {
+		return err
+	}
+	if err := r.Create(ctx, claim); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("creating claim %s: %w", claimName, err)
+	}
+	return nil
+}
+
+// selectedModels returns the sorted references of the models a cache selects
+func (r *OllamaModelCacheReconciler) selectedModels(ctx context.Context, cache *ollamamodel.OllamaModelCache) ([]string, error) {
+	selector := labels.Everything()
+	if cache.Spec.ModelSelector != nil {
+		var err error
+		if selector, err = metav1.LabelSelectorAsSelector(cache.Spec.ModelSelector); err != nil {
+			return nil, fmt.Errorf("invalid model selector: %w", err)
+		}
+	}
+
+	var list ollamamodel.OllamaModelList
+	if err := r.List(ctx, &list, client.InNamespace(cache.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, err
+	}
+	models := make([]string, 0, len(list.Items))
+	for _, model := range list.Items {
+		reference := model.Spec.Reference()
+		sameModel := func(other string) bool { return ollamamodel.SameModel(other, reference) }
+		if model.DeletionTimestamp.IsZero() && !slices.ContainsFunc(models, sameModel) {
+			models = append(models, reference)
+		}
+	}
+	sort.Strings(models)
+	return models, nil
+}
+
+// jobRunning reports whether a Job exists and has not finished yet
+func (r *OllamaModelCacheReconciler) jobRunning(ctx context.Context, namespace, name string) (bool, error) {
+	job := &batchv1.Job{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, job); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	return job.Status.Succeeded == 0 && jobFailure(job) == "", nil
+}
+
+// setReady sets the Ready condition of a cache and reports whether it changed
+func (r *OllamaModelCacheReconciler) setReady(cache *ollamamodel.OllamaModelCache, status metav1.ConditionStatus, reason, message string) bool {
+	return meta.SetStatusCondition(&cache.Status.Conditions, metav1.Condition{
+		Type:               ollamamodel.ConditionReady,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: cache.Generation,
+	})
+}
+
+// updateStatus writes the status of a cache unless it is unchanged
+func (r *OllamaModelCacheReconciler) updateStatus(ctx context.Context, cache *ollamamodel.OllamaModelCache, original *ollamamodel.OllamaModelCacheStatus) error {
+	if equality.Semantic.DeepEqual(original, &cache.Status) {
+		return nil
+	}
+	return r.Status().Update(ctx, cache)
+}
+
+// cacheJobName returns the name of the Job syncing a cache with a set of models
+func cacheJobName(cacheName string, models []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(models, "\n")))
+	return pullJobName(cacheName, "sync", hex.EncodeToString(sum[:])[:8])
+}
+
+// cachesForModel maps a model to the caches of its namespace
+func (r *OllamaModelCacheReconciler) cachesForModel(ctx context.Context, obj client.Object) []reconcile.Request {
+	var caches ollamamodel.OllamaModelCacheList
+	if err := r.List(ctx, &caches, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list model caches", "namespace", obj.GetNamespace())
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(caches.Items))
+	for i := range caches.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&caches.Items[i])})
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager
+func (r *OllamaModelCacheReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&ollamamodel.OllamaModelCache{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Owns(&batchv1.Job{}).
+		Watches(&ollamamodel.OllamaModel{}, handler.EnqueueRequestsFromMapFunc(r.cachesForModel),
+			builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{}))).
+		Named("ollamamodelcache").
+		Complete(r)
+}
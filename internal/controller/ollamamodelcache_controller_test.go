@@ -0,0 +1,93 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+var _ = Describe("OllamaModelCache Controller", func() {
+	It("pulls the selected models into a ReadWriteMany claim", func() {
+		ctx := context.Background()
+		for _, model := range []*ollamav1alpha1.OllamaModel{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "cache-llama", Namespace: "default", Labels: map[string]string{"cache": "shared"}},
+				Spec:       ollamav1alpha1.OllamaModelSpec{Name: "llama3.2", Tag: "1b"},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "cache-gemma", Namespace: "default"},
+				Spec:       ollamav1alpha1.OllamaModelSpec{Name: "gemma3", Tag: "1b"},
+			},
+		} {
+			Expect(k8sClient.Create(ctx, model)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, model)
+		}
+
+		cache := &ollamav1alpha1.OllamaModelCache{
+			ObjectMeta: metav1.ObjectMeta{Name: "shared", Namespace: "default"},
+			Spec: ollamav1alpha1.OllamaModelCacheSpec{
+				Size:          resource.MustParse("20Gi"),
+				ModelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"cache": "shared"}},
+			},
+		}
+		Expect(k8sClient.Create(ctx, cache)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, cache)
+
+		r := &OllamaModelCacheReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), Recorder: record.NewFakeRecorder(10)}
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cache)})
+		Expect(err).NotTo(HaveOccurred())
+
+		claim := &corev1.PersistentVolumeClaim{}
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "shared-models"}, claim)).To(Succeed())
+		Expect(claim.Spec.AccessModes).To(ConsistOf(corev1.ReadWriteMany))
+		Expect(metav1.IsControlledBy(claim, cache)).To(BeTrue())
+
+		jobName := cacheJobName(cache.Name, []string{"llama3.2:1b"})
+		job := &batchv1.Job{}
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: jobName}, job)).To(Succeed())
+		Expect(job.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "MODELS", Value: "llama3.2:1b"}))
+
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(cache), cache)).To(Succeed())
+		Expect(cache.Status.Job).To(Equal(jobName))
+		Expect(meta.IsStatusConditionFalse(cache.Status.Conditions, ollamav1alpha1.ConditionReady)).To(BeTrue())
+
+		By("recording the models once the job succeeds")
+		now := metav1.Now()
+		job.Status.StartTime, job.Status.Succeeded = &now, 1
+		Expect(k8sClient.Status().Update(ctx, job)).To(Succeed())
+		_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cache)})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(cache), cache)).To(Succeed())
+		Expect(cache.Status.Models).To(Equal([]string{"llama3.2:1b"}))
+		Expect(cache.Status.Job).To(BeEmpty())
+		Expect(meta.IsStatusConditionTrue(cache.Status.Conditions, ollamav1alpha1.ConditionReady)).To(BeTrue())
+	})
+})
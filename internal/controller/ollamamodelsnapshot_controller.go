@@ -0,0 +1,214 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ollama/ollama/api"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	ollamamodel "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+// snapshotFinalizer deletes the frozen copy of a snapshot from Ollama
+const snapshotFinalizer = "ollama.smithforge.dev/snapshot-finalizer"
+
+// OllamaModelSnapshotReconciler captures the state of an OllamaModel once it
+// is Ready, and freezes a copy of it when asked to
+type OllamaModelSnapshotReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Ollama   OllamaClient
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=ollama.smithforge.dev,resources=ollamamodelsnapshots,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=ollama.smithforge.dev,resources=ollamamodelsnapshots/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=ollama.smithforge.dev,resources=ollamamodelsnapshots/finalizers,verbs=update
+
+// Reconcile captures a snapshot the first time its model is Ready. A captured
+// snapshot is left alone until it is deleted.
+func (r *OllamaModelSnapshotReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	snapshot := &ollamamodel.OllamaModelSnapshot{}
+	if err := r.Get(ctx, req.NamespacedName, snapshot); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !snapshot.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.deleteFrozenModel(ctx, snapshot)
+	}
+	if snapshot.Status.Captured() {
+		return ctrl.Result{}, nil
+	}
+
+	// The finalizer is added before the copy is made, so that it is never left behind
+	if snapshot.Spec.FrozenTag != "" && !controllerutil.ContainsFinalizer(snapshot, snapshotFinalizer) {
+		controllerutil.AddFinalizer(snapshot, snapshotFinalizer)
+		if err := r.Update(ctx, snapshot); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	model := &ollamamodel.OllamaModel{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: snapshot.Namespace, Name: snapshot.Spec.Model}, model)
+	switch {
+	case apierrors.IsNotFound(err):
+		return ctrl.Result{}, r.setReady(ctx, snapshot, metav1.ConditionFalse, ollamamodel.ReasonModelNotFound,
+			fmt.Sprintf("OllamaModel %s does not exist", snapshot.Spec.Model))
+	case err != nil:
+		return ctrl.Result{}, err
+	case model.Status.State != ollamamodel.StateReady:
+		return ctrl.Result{}, r.setReady(ctx, snapshot, metav1.ConditionFalse, ollamamodel.ReasonModelNotReady,
+			fmt.Sprintf("OllamaModel %s is %s", snapshot.Spec.Model, model.Status.State))
+	}
+
+	if err := r.capture(ctx, snapshot, model); err != nil {
+		log.Error(err, "failed to capture model snapshot", "name", snapshot.Name, "model", snapshot.Spec.Model)
+		r.Recorder.Event(snapshot, "Warning", "CaptureFailed", fmt.Sprintf("Failed to capture model %s: %v", snapshot.Spec.Model, err))
+		if updateErr := r.setReady(ctx, snapshot, metav1.ConditionFalse, ollamamodel.ReasonCaptureFailed, err.Error()); updateErr != nil {
+			return ctrl.Result{}, updateErr
+		}
+		return ctrl.Result{}, err
+	}
+
+	log.Info("captured model snapshot", "name", snapshot.Name, "model", snapshot.Status.Model, "digest", snapshot.Status.Digest)
+	message := fmt.Sprintf("Captured model %s", snapshot.Status.Model)
+	if snapshot.Status.FrozenModel != "" {
+		message += fmt.Sprintf(", frozen as %s", snapshot.Status.FrozenModel)
+	}
+	r.Recorder.Event(snapshot, "Normal", "Captured", message)
+	return ctrl.Result{}, r.setReady(ctx, snapshot, metav1.ConditionTrue, ollamamodel.ReasonCaptured, message)
+}
+
+// capture records the state of the model served for an OllamaModel in the
+// status of a snapshot, and copies it under the frozen tag
+func (r *OllamaModelSnapshotReconciler) capture(ctx context.Context, snapshot *ollamamodel.OllamaModelSnapshot, model *ollamamodel.OllamaModel) error {
+	reference := model.ServedModel()
+	show, err := r.Ollama.Show(ctx, &api.ShowRequest{Model: reference})
+	if err != nil {
+		return fmt.Errorf("failed to show model %s: %w", reference, err)
+	}
+	list, err := r.Ollama.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list models: %w", err)
+	}
+	digest := ""
+	for _, stored := range list.Models {
+		if ollamamodel.SameModel(stored.Name, reference) {
+			digest = stored.Digest
+			break
+		}
+	}
+	if digest == "" {
+		return fmt.Errorf("model %s is not on the Ollama server", reference)
+	}
+
+	frozen := ""
+	if snapshot.Spec.FrozenTag != "" {
+		frozen = fmt.Sprintf("%s:%s", model.Spec.ParsedReference().Name(), snapshot.Spec.FrozenTag)
+		if err := r.Ollama.Copy(ctx, &api.CopyRequest{Source: reference, Destination: frozen}); err != nil {
+			return fmt.Errorf("failed to copy model %s to %s: %w", reference, frozen, err)
+		}
+	}
+
+	snapshot.Status = ollamamodel.OllamaModelSnapshotStatus{
+		Model:       reference,
+		Digest:      digest,
+		Modelfile:   show.Modelfile,
+		Parameters:  show.Parameters,
+		Template:    show.Template,
+		System:      show.System,
+		License:     show.License,
+		FrozenModel: frozen,
+		CaptureTime: &metav1.Time{Time: time.Now()},
+		Conditions:  snapshot.Status.Conditions,
+	}
+	return nil
+}
+
+// deleteFrozenModel deletes the frozen copy of a deleted snapshot from Ollama
+// and releases the snapshot
+func (r *OllamaModelSnapshotReconciler) deleteFrozenModel(ctx context.Context, snapshot *ollamamodel.OllamaModelSnapshot) error {
+	if !controllerutil.ContainsFinalizer(snapshot, snapshotFinalizer) {
+		return nil
+	}
+	if frozen := snapshot.Status.FrozenModel; frozen != "" {
+		err := r.Ollama.Delete(ctx, &api.DeleteRequest{Model: frozen})
+		if err != nil && !strings.Contains(err.Error(), "model not found") {
+			r.Recorder.Event(snapshot, "Warning", "DeleteFailed", fmt.Sprintf("Failed to delete model %s from Ollama: %v", frozen, err))
+			return err
+		}
+		log.FromContext(ctx).Info("deleted frozen model", "name", snapshot.Name, "model", frozen)
+	}
+	controllerutil.RemoveFinalizer(snapshot, snapshotFinalizer)
+	return r.Update(ctx, snapshot)
+}
+
+// setReady sets the Ready condition of a snapshot and writes its status
+func (r *OllamaModelSnapshotReconciler) setReady(ctx context.Context, snapshot *ollamamodel.OllamaModelSnapshot, status metav1.ConditionStatus, reason, message string) error {
+	if !meta.SetStatusCondition(&snapshot.Status.Conditions, metav1.Condition{
+		Type:               ollamamodel.ConditionReady,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: snapshot.Generation,
+	}) {
+		return nil
+	}
+	return r.Status().Update(ctx, snapshot)
+}
+
+// snapshotsForModel maps a model to the snapshots of its namespace waiting to
+// capture it
+func (r *OllamaModelSnapshotReconciler) snapshotsForModel(ctx context.Context, obj client.Object) []reconcile.Request {
+	var snapshots ollamamodel.OllamaModelSnapshotList
+	if err := r.List(ctx, &snapshots, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list model snapshots", "namespace", obj.GetNamespace())
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range snapshots.Items {
+		if snapshots.Items[i].Spec.Model == obj.GetName() && !snapshots.Items[i].Status.Captured() {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&snapshots.Items[i])})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager
+func (r *OllamaModelSnapshotReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&ollamamodel.OllamaModelSnapshot{}).
+		Watches(&ollamamodel.OllamaModel{}, handler.EnqueueRequestsFromMapFunc(r.snapshotsForModel)).
+		Named("ollamamodelsnapshot").
+		Complete(r)
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/ollama/ollama/api"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+// snapshotOllama is an Ollama client serving a single model and recording the
+// copies and deletes made
+type snapshotOllama struct {
+	OllamaClient
+	copies  []api.CopyRequest
+	deletes []string
+}
+
+func (f *snapshotOllama) Show(context.Context, *api.ShowRequest) (*api.ShowResponse, error) {
+	return &api.ShowResponse{
+		Modelfile:  "FROM llama3.2:1b\nPARAMETER temperature 0.2",
+		Parameters: "temperature 0.2",
+		License:    "LLAMA 3.2 COMMUNITY LICENSE AGREEMENT",
+	}, nil
+}
+
+func (f *snapshotOllama) List(context.Context) (*api.ListResponse, error) {
+	return &api.ListResponse{Models: []api.ListModelResponse{{Name: "llama3.2:1b", Digest: "sha256:a80c4f17acd5"}}}, nil
+}
+
+func (f *snapshotOllama) Copy(_ context.Context, req *api.CopyRequest) error {
+	f.copies = append(f.copies, *req)
+	return nil
+}
+
+func (f *snapshotOllama) Delete(_ context.Context, req *api.DeleteRequest) error {
+	f.deletes = append(f.deletes, req.Model)
+	return nil
+}
+
+var _ = Describe("OllamaModelSnapshot Controller", func() {
+	ctx := context.Background()
+
+	var (
+		model  *ollamav1alpha1.OllamaModel
+		ollama *snapshotOllama
+		r      *OllamaModelSnapshotReconciler
+	)
+
+	BeforeEach(func() {
+		model = &ollamav1alpha1.OllamaModel{
+			ObjectMeta: metav1.ObjectMeta{Name: "snapshot-llama", Namespace: "default"},
+			Spec:       ollamav1alpha1.OllamaModelSpec{Name: "llama3.2", Tag: "1b"},
+		}
+		Expect(k8sClient.Create(ctx, model)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, model)
+
+		ollama = &snapshotOllama{}
+		r = &OllamaModelSnapshotReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), Ollama: ollama, Recorder: record.NewFakeRecorder(10)}
+	})
+
+	newSnapshot := func(frozenTag string) *ollamav1alpha1.OllamaModelSnapshot {
+		snapshot := &ollamav1alpha1.OllamaModelSnapshot{
+			ObjectMeta: metav1.ObjectMeta{Name: "snapshot-exp", Namespace: "default"},
+			Spec:       ollamav1alpha1.OllamaModelSnapshotSpec{Model: model.Name, FrozenTag: frozenTag},
+		}
+		Expect(k8sClient.Create(ctx, snapshot)).To(Succeed())
+		return snapshot
+	}
+
+	reconcile := func(snapshot *ollamav1alpha1.OllamaModelSnapshot) {
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(snapshot)})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	It("waits for the model to be Ready", func() {
+		snapshot := newSnapshot("")
+		DeferCleanup(k8sClient.Delete, ctx, snapshot)
+
+		reconcile(snapshot)
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(snapshot), snapshot)).To(Succeed())
+		Expect(snapshot.Status.Captured()).To(BeFalse())
+		condition := meta.FindStatusCondition(snapshot.Status.Conditions, ollamav1alpha1.ConditionReady)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Reason).To(Equal(ollamav1alpha1.ReasonModelNotReady))
+	})
+
+	It("captures a Ready model, freezes a copy of it and deletes the copy along with the snapshot", func() {
+		model.Status.State = ollamav1alpha1.StateReady
+		Expect(k8sClient.Status().Update(ctx, model)).To(Succeed())
+
+		snapshot := newSnapshot("exp-42")
+		reconcile(snapshot)
+
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(snapshot), snapshot)).To(Succeed())
+		Expect(snapshot.Status.Model).To(Equal("llama3.2:1b"))
+		Expect(snapshot.Status.Digest).To(Equal("sha256:a80c4f17acd5"))
+		Expect(snapshot.Status.Parameters).To(Equal("temperature 0.2"))
+		Expect(snapshot.Status.License).To(ContainSubstring("LLAMA 3.2"))
+		Expect(snapshot.Status.FrozenModel).To(Equal("llama3.2:exp-42"))
+		Expect(meta.IsStatusConditionTrue(snapshot.Status.Conditions, ollamav1alpha1.ConditionReady)).To(BeTrue())
+		Expect(ollama.copies).To(Equal([]api.CopyRequest{{Source: "llama3.2:1b", Destination: "llama3.2:exp-42"}}))
+
+		// A captured snapshot is not captured again
+		reconcile(snapshot)
+		Expect(ollama.copies).To(HaveLen(1))
+
+		Expect(k8sClient.Delete(ctx, snapshot)).To(Succeed())
+		reconcile(snapshot)
+		Expect(ollama.deletes).To(Equal([]string{"llama3.2:exp-42"}))
+		err := k8sClient.Get(ctx, client.ObjectKeyFromObject(snapshot), snapshot)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("restores models from the frozen copy of a snapshot", func() {
+		model.Status.State = ollamav1alpha1.StateReady
+		Expect(k8sClient.Status().Update(ctx, model)).To(Succeed())
+		snapshot := newSnapshot("exp-42")
+		reconcile(snapshot)
+		DeferCleanup(func() {
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, snapshot))).To(Succeed())
+			reconcile(snapshot)
+		})
+
+		restored := &ollamav1alpha1.OllamaModel{
+			ObjectMeta: metav1.ObjectMeta{Name: "restored-llama", Namespace: "default"},
+			Spec: ollamav1alpha1.OllamaModelSpec{
+				Name:        "llama3.2",
+				Tag:         "restored",
+				RestoreFrom: &ollamav1alpha1.SnapshotReference{Name: snapshot.Name},
+			},
+		}
+		models := &OllamaModelReconciler{Client: k8sClient, Ollama: ollama}
+		Expect(models.copySnapshot(ctx, restored, restored.Spec.Reference())).To(Succeed())
+		Expect(ollama.copies).To(ContainElement(api.CopyRequest{Source: "llama3.2:exp-42", Destination: "llama3.2:restored"}))
+
+		restored.Spec.RestoreFrom.Name = "missing"
+		Expect(models.copySnapshot(ctx, restored, restored.Spec.Reference())).To(MatchError(ContainSubstring("missing")))
+	})
+})
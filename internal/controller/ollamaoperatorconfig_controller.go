@@ -0,0 +1,105 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	ollamamodel "github.com/dmk/ollama-operator/api/v1alpha1"
+	"github.com/dmk/ollama-operator/internal/opconfig"
+)
+
+// OperatorConfigReconciler applies the OllamaOperatorConfig with the configured
+// name to the runtime Settings. The other OllamaOperatorConfigs are ignored.
+type OperatorConfigReconciler struct {
+	client.Client
+	// Name is the name of the OllamaOperatorConfig to apply
+	Name     string
+	Settings *opconfig.Store
+	// ApplyOnly applies the configuration without reporting it in the status,
+	// for processes such as standalone API servers that may not write it
+	ApplyOnly bool
+}
+
+// +kubebuilder:rbac:groups=ollama.smithforge.dev,resources=ollamaoperatorconfigs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=ollama.smithforge.dev,resources=ollamaoperatorconfigs/status,verbs=get;update;patch
+
+// Reconcile applies the OllamaOperatorConfig, or restores the command-line
+// defaults when it is deleted
+func (r *OperatorConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	config := &ollamamodel.OllamaOperatorConfig{}
+	if err := r.Get(ctx, req.NamespacedName, config); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, err
+		}
+		log.Info("operator config not found, using command-line defaults", "name", req.Name)
+		r.Settings.Apply(nil)
+		return ctrl.Result{}, nil
+	}
+
+	settings := r.Settings.Apply(&config.Spec)
+	log.Info("applied operator config", "name", config.Name,
+		"maxConcurrentPulls", settings.MaxConcurrentPulls, "resyncInterval", settings.ResyncInterval,
+		"pruneMode", settings.PruneMode, "maxBodyBytes", settings.MaxBodyBytes)
+
+	// Every replica applies the config, but one status update is enough
+	if r.ApplyOnly || config.Status.ObservedGeneration == config.Generation {
+		return ctrl.Result{}, nil
+	}
+	config.Status.ObservedGeneration = config.Generation
+	meta.SetStatusCondition(&config.Status.Conditions, metav1.Condition{
+		Type:               ollamamodel.ConditionApplied,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Applied",
+		Message:            fmt.Sprintf("Generation %d is applied", config.Generation),
+		ObservedGeneration: config.Generation,
+	})
+	if err := r.Status().Update(ctx, config); err != nil {
+		// The settings are applied already, only the status is stale
+		return ctrl.Result{RequeueAfter: time.Second * 5}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager. Every replica
+// applies the configuration, since the API server runs on all of them.
+func (r *OperatorConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	named := predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return obj.GetName() == r.Name
+	})
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&ollamamodel.OllamaOperatorConfig{},
+			builder.WithPredicates(named, predicate.GenerationChangedPredicate{})).
+		Named("ollamaoperatorconfig").
+		WithOptions(controller.Options{NeedLeaderElection: ptr.To(false)}).
+		Complete(r)
+}
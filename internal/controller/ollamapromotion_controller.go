@@ -0,0 +1,254 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ollama/ollama/api"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	ollamamodel "github.com/dmk/ollama-operator/api/v1alpha1"
+	"github.com/dmk/ollama-operator/internal/audit"
+)
+
+// OllamaPromotionReconciler promotes an OllamaModel to another namespace once
+// the promotion is approved and the model is Ready. All namespaces share the
+// Ollama server, so the promoted model is the stored model the source manages,
+// pinned to its digest.
+type OllamaPromotionReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Ollama   OllamaClient
+	Recorder record.EventRecorder
+	Audit    *audit.Logger
+}
+
+// +kubebuilder:rbac:groups=ollama.smithforge.dev,resources=ollamapromotions,verbs=get;list;watch
+// +kubebuilder:rbac:groups=ollama.smithforge.dev,resources=ollamapromotions/status,verbs=get;update;patch
+
+// Reconcile carries out a promotion once. A promoted promotion is left alone,
+// so promoting a new version of the source takes a new promotion.
+func (r *OllamaPromotionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	promotion := &ollamamodel.OllamaPromotion{}
+	if err := r.Get(ctx, req.NamespacedName, promotion); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if promotion.Status.Promoted() {
+		return ctrl.Result{}, nil
+	}
+	if promotion.Spec.Approval.ApprovedBy == "" {
+		return ctrl.Result{}, r.setReady(ctx, promotion, metav1.ConditionFalse, ollamamodel.ReasonAwaitingApproval,
+			"Waiting for approval.approvedBy to be set")
+	}
+
+	sourceKey := promotionSource(promotion)
+	if sourceKey == promotionTarget(promotion, sourceKey) {
+		return ctrl.Result{}, r.setReady(ctx, promotion, metav1.ConditionFalse, ollamamodel.ReasonInvalidSpec,
+			"The source and the target are the same OllamaModel")
+	}
+	source := &ollamamodel.OllamaModel{}
+	err := r.Get(ctx, sourceKey, source)
+	switch {
+	case apierrors.IsNotFound(err):
+		return ctrl.Result{}, r.setReady(ctx, promotion, metav1.ConditionFalse, ollamamodel.ReasonModelNotFound,
+			fmt.Sprintf("OllamaModel %s does not exist", sourceKey))
+	case err != nil:
+		return ctrl.Result{}, err
+	case source.Status.State != ollamamodel.StateReady || source.Status.Digest == "":
+		return ctrl.Result{}, r.setReady(ctx, promotion, metav1.ConditionFalse, ollamamodel.ReasonModelNotReady,
+			fmt.Sprintf("OllamaModel %s is %s", sourceKey, source.Status.State))
+	}
+
+	target, err := r.promote(ctx, promotion, source)
+	r.Audit.Record(ctx, audit.Event{
+		Source:    audit.SourceController,
+		Action:    audit.ActionPromote,
+		Principal: audit.ControllerPrincipal,
+		Requester: promotion.Spec.Approval.ApprovedBy,
+		Namespace: promotion.Namespace,
+		Name:      promotionTarget(promotion, sourceKey).Name,
+		Model:     target,
+	}.WithError(err))
+	if err != nil {
+		log.FromContext(ctx).Error(err, "failed to promote model", "promotion", promotion.Name, "source", sourceKey)
+		r.Recorder.Event(promotion, "Warning", "PromotionFailed", fmt.Sprintf("Failed to promote %s: %v", sourceKey, err))
+		if updateErr := r.setReady(ctx, promotion, metav1.ConditionFalse, ollamamodel.ReasonPromotionFailed, err.Error()); updateErr != nil {
+			return ctrl.Result{}, updateErr
+		}
+		return ctrl.Result{}, err
+	}
+
+	status := &promotion.Status
+	status.Model = target
+	status.Digest = source.Status.Digest
+	status.PromotedBy = promotion.Spec.Approval.ApprovedBy
+	status.PromotionTime = &metav1.Time{Time: time.Now()}
+	message := fmt.Sprintf("Promoted %s as %s with digest %s, approved by %s", sourceKey, target, status.Digest, status.PromotedBy)
+	log.FromContext(ctx).Info("promoted model", "promotion", promotion.Name, "source", sourceKey, "model", target, "digest", status.Digest)
+	r.Recorder.Event(promotion, "Normal", "Promoted", message)
+	return ctrl.Result{}, r.setReady(ctx, promotion, metav1.ConditionTrue, ollamamodel.ReasonPromoted, message)
+}
+
+// promote creates or updates the target model of a promotion, pinned to the
+// digest of the source model, and returns the Ollama model it manages. The
+// stored model is checked to have that digest first, so that a tag that moved
+// since the source was pulled is not promoted.
+func (r *OllamaPromotionReconciler) promote(ctx context.Context, promotion *ollamamodel.OllamaPromotion, source *ollamamodel.OllamaModel) (string, error) {
+	spec := source.Spec
+	if tag := promotion.Spec.CopyToTag; tag != "" {
+		copied := fmt.Sprintf("%s:%s", spec.ParsedReference().Name(), tag)
+		if err := r.Ollama.Copy(ctx, &api.CopyRequest{Source: spec.Reference(), Destination: copied}); err != nil {
+			return copied, fmt.Errorf("failed to copy model %s to %s: %w", spec.Reference(), copied, err)
+		}
+		if spec.ModelRef != "" {
+			spec.ModelRef = copied
+		}
+		spec.Tag, spec.Quantization = tag, ""
+	}
+	reference := spec.Reference()
+
+	list, err := r.Ollama.List(ctx)
+	if err != nil {
+		return reference, fmt.Errorf("failed to list models: %w", err)
+	}
+	stored := ""
+	for _, model := range list.Models {
+		if ollamamodel.SameModel(model.Name, reference) {
+			stored = model.Digest
+			break
+		}
+	}
+	if stored != source.Status.Digest {
+		return reference, fmt.Errorf("model %s has digest %q on the Ollama server, not the digest %s of the source",
+			reference, stored, source.Status.Digest)
+	}
+
+	key := promotionTarget(promotion, client.ObjectKeyFromObject(source))
+	target := &ollamamodel.OllamaModel{}
+	err = r.Get(ctx, key, target)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return reference, err
+	}
+	exists := err == nil
+	target.Name, target.Namespace = key.Name, key.Namespace
+	promotedSpec(&target.Spec, spec)
+	if target.Annotations == nil {
+		target.Annotations = map[string]string{}
+	}
+	target.Annotations[ollamamodel.DigestAnnotation] = source.Status.Digest
+	target.Annotations[ollamamodel.PromotedFromAnnotation] = client.ObjectKeyFromObject(promotion).String()
+	if exists {
+		return reference, r.Update(ctx, target)
+	}
+	// A new target inherits the labels of the promotion, so that it matches the
+	// label selector the operator may watch with as the promotion does
+	for key, value := range promotion.Labels {
+		if target.Labels == nil {
+			target.Labels = map[string]string{}
+		}
+		target.Labels[key] = value
+	}
+	return reference, r.Create(ctx, target)
+}
+
+// promotedSpec sets the fields of a target model's spec that determine the
+// model it serves to those of the source. The other fields, such as limits
+// and exports, are left as the target environment set them.
+func promotedSpec(target *ollamamodel.OllamaModelSpec, source ollamamodel.OllamaModelSpec) {
+	target.Name = source.Name
+	target.Tag = source.Tag
+	target.Quantization = source.Quantization
+	target.Parameters = source.Parameters
+	target.System = source.System
+	target.Template = source.Template
+	target.Type = source.Type
+	target.ExpectedDimensions = source.ExpectedDimensions
+	// The model is on the Ollama server already, and snapshots belong to the
+	// namespace of the source
+	target.RestoreFrom = nil
+}
+
+// promotionSource returns the key of the source model of a promotion
+func promotionSource(promotion *ollamamodel.OllamaPromotion) client.ObjectKey {
+	namespace := promotion.Spec.Source.Namespace
+	if namespace == "" {
+		namespace = promotion.Namespace
+	}
+	return client.ObjectKey{Namespace: namespace, Name: promotion.Spec.Source.Name}
+}
+
+// promotionTarget returns the key of the target model of a promotion
+func promotionTarget(promotion *ollamamodel.OllamaPromotion, source client.ObjectKey) client.ObjectKey {
+	name := promotion.Spec.Target
+	if name == "" {
+		name = source.Name
+	}
+	return client.ObjectKey{Namespace: promotion.Namespace, Name: name}
+}
+
+// setReady sets the Ready condition of a promotion and writes its status
+func (r *OllamaPromotionReconciler) setReady(ctx context.Context, promotion *ollamamodel.OllamaPromotion, status metav1.ConditionStatus, reason, message string) error {
+	if !meta.SetStatusCondition(&promotion.Status.Conditions, metav1.Condition{
+		Type:               ollamamodel.ConditionReady,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: promotion.Generation,
+	}) {
+		return nil
+	}
+	return r.Status().Update(ctx, promotion)
+}
+
+// promotionsForModel maps a model to the promotions of any namespace waiting
+// to promote it
+func (r *OllamaPromotionReconciler) promotionsForModel(ctx context.Context, obj client.Object) []reconcile.Request {
+	var promotions ollamamodel.OllamaPromotionList
+	if err := r.List(ctx, &promotions); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list promotions")
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range promotions.Items {
+		promotion := &promotions.Items[i]
+		if !promotion.Status.Promoted() && promotionSource(promotion) == client.ObjectKeyFromObject(obj) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(promotion)})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager
+func (r *OllamaPromotionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&ollamamodel.OllamaPromotion{}).
+		Watches(&ollamamodel.OllamaModel{}, handler.EnqueueRequestsFromMapFunc(r.promotionsForModel)).
+		Named("ollamapromotion").
+		Complete(r)
+}
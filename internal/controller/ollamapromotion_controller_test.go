@@ -0,0 +1,114 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+var _ = Describe("OllamaPromotion Controller", func() {
+	ctx := context.Background()
+
+	var (
+		source *ollamav1alpha1.OllamaModel
+		r      *OllamaPromotionReconciler
+	)
+
+	BeforeEach(func() {
+		source = &ollamav1alpha1.OllamaModel{
+			ObjectMeta: metav1.ObjectMeta{Name: "staging-llama", Namespace: "default"},
+			Spec: ollamav1alpha1.OllamaModelSpec{
+				Name:       "llama3.2",
+				Tag:        "1b",
+				Parameters: map[string]string{"temperature": "0.2"},
+			},
+		}
+		Expect(k8sClient.Create(ctx, source)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, source)
+		source.Status.State = ollamav1alpha1.StateReady
+		source.Status.Digest = "sha256:a80c4f17acd5"
+		Expect(k8sClient.Status().Update(ctx, source)).To(Succeed())
+
+		r = &OllamaPromotionReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), Ollama: &snapshotOllama{}, Recorder: record.NewFakeRecorder(10)}
+	})
+
+	newPromotion := func(approvedBy string) *ollamav1alpha1.OllamaPromotion {
+		promotion := &ollamav1alpha1.OllamaPromotion{
+			ObjectMeta: metav1.ObjectMeta{Name: "promote-llama", Namespace: "default"},
+			Spec: ollamav1alpha1.OllamaPromotionSpec{
+				Source:   ollamav1alpha1.PromotionSource{Name: source.Name},
+				Target:   "production-llama",
+				Approval: ollamav1alpha1.PromotionApproval{ApprovedBy: approvedBy},
+			},
+		}
+		Expect(k8sClient.Create(ctx, promotion)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, promotion)
+		return promotion
+	}
+
+	readyReason := func(promotion *ollamav1alpha1.OllamaPromotion) string {
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(promotion), promotion)).To(Succeed())
+		condition := meta.FindStatusCondition(promotion.Status.Conditions, ollamav1alpha1.ConditionReady)
+		Expect(condition).NotTo(BeNil())
+		return condition.Reason
+	}
+
+	It("waits for approval", func() {
+		promotion := newPromotion("")
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(promotion)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(readyReason(promotion)).To(Equal(ollamav1alpha1.ReasonAwaitingApproval))
+	})
+
+	It("creates the target model pinned to the digest of the source", func() {
+		promotion := newPromotion("jane@example.com")
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(promotion)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(readyReason(promotion)).To(Equal(ollamav1alpha1.ReasonPromoted))
+		Expect(promotion.Status.Model).To(Equal("llama3.2:1b"))
+		Expect(promotion.Status.PromotedBy).To(Equal("jane@example.com"))
+
+		target := &ollamav1alpha1.OllamaModel{}
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "production-llama"}, target)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, target)
+		Expect(target.Spec.Reference()).To(Equal("llama3.2:1b"))
+		Expect(target.Spec.Parameters).To(HaveKeyWithValue("temperature", "0.2"))
+		Expect(target.Annotations).To(HaveKeyWithValue(ollamav1alpha1.DigestAnnotation, "sha256:a80c4f17acd5"))
+		Expect(target.Annotations).To(HaveKeyWithValue(ollamav1alpha1.PromotedFromAnnotation, "default/promote-llama"))
+	})
+
+	It("does not promote a model whose tag moved since the source was pulled", func() {
+		source.Status.Digest = "sha256:0b1f2a"
+		Expect(k8sClient.Status().Update(ctx, source)).To(Succeed())
+
+		promotion := newPromotion("jane@example.com")
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(promotion)})
+		Expect(err).To(MatchError(ContainSubstring("not the digest sha256:0b1f2a")))
+		Expect(readyReason(promotion)).To(Equal(ollamav1alpha1.ReasonPromotionFailed))
+		Expect(promotion.Status.Promoted()).To(BeFalse())
+	})
+})
@@ -0,0 +1,203 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ollama/ollama/api"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	ollamamodel "github.com/dmk/ollama-operator/api/v1alpha1"
+	"github.com/dmk/ollama-operator/internal/opconfig"
+)
+
+// pullJobRetryDelay is the delay before the first retry of a failed pull job,
+// doubled for every further retry
+const pullJobRetryDelay = 10 * time.Second
+
+// OllamaPullJobReconciler pulls the model of an OllamaPullJob once. Unlike
+// OllamaModels, the model is not checked, refreshed or removed afterwards.
+type OllamaPullJobReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Ollama   OllamaClient
+	Recorder record.EventRecorder
+	// Settings is the runtime configuration; nil uses the zero Settings
+	Settings *opconfig.Store
+	// Pulls caps the models pulled at the same time along with the other
+	// controllers pulling models; nil caps the pulls of this reconciler alone
+	Pulls *PullSlots
+
+	pulls PullSlots
+}
+
+// +kubebuilder:rbac:groups=ollama.smithforge.dev,resources=ollamapulljobs,verbs=get;list;watch;delete
+// +kubebuilder:rbac:groups=ollama.smithforge.dev,resources=ollamapulljobs/status,verbs=get;update;patch
+
+// Reconcile runs the pull of a job until it succeeds or exhausts its retries,
+// then deletes the job once its TTL passes
+func (r *OllamaPullJobReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	job := &ollamamodel.OllamaPullJob{}
+	if err := r.Get(ctx, req.NamespacedName, job); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !job.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+	status := &job.Status
+	if status.Finished() {
+		return r.expire(ctx, job)
+	}
+
+	// The spec is read once, so that the job pulls what it started with
+	if status.Model == "" {
+		status.Model = job.Spec.Reference()
+	}
+	if status.StartTime == nil {
+		status.StartTime = &metav1.Time{Time: time.Now()}
+	}
+	status.Phase = ollamamodel.PullJobRunning
+	if err := r.Status().Update(ctx, job); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	log.Info("pulling model", "job", job.Name, "model", status.Model, "attempt", status.Attempts+1)
+	r.Recorder.Event(job, "Normal", "PullStarted", fmt.Sprintf("Pulling model %s", status.Model))
+	err := r.pull(ctx, job)
+	if ctx.Err() != nil {
+		// The operator is shutting down; the next leader pulls again
+		return ctrl.Result{}, ctx.Err()
+	}
+
+	if err == nil {
+		log.Info("pulled model", "job", job.Name, "model", status.Model)
+		r.Recorder.Event(job, "Normal", "Pulled", fmt.Sprintf("Pulled model %s", status.Model))
+		status.Phase = ollamamodel.PullJobSucceeded
+		status.Error = ""
+		status.CompletionTime = &metav1.Time{Time: time.Now()}
+		r.setCondition(job, ollamamodel.ConditionComplete, "Pulled", fmt.Sprintf("Pulled model %s", status.Model))
+		if err := r.Status().Update(ctx, job); err != nil {
+			return ctrl.Result{}, err
+		}
+		return r.expire(ctx, job)
+	}
+
+	log.Error(err, "failed to pull model", "job", job.Name, "model", status.Model)
+	r.Recorder.Event(job, "Warning", "PullFailed", fmt.Sprintf("Failed to pull model %s: %v", status.Model, err))
+	status.Attempts++
+	status.Error = err.Error()
+	backoffLimit := int32(ollamamodel.DefaultPullJobBackoffLimit)
+	if job.Spec.BackoffLimit != nil {
+		backoffLimit = *job.Spec.BackoffLimit
+	}
+	if status.Attempts <= backoffLimit {
+		status.Phase = ollamamodel.PullJobPending
+		if err := r.Status().Update(ctx, job); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: pullJobRetryDelay << (status.Attempts - 1)}, nil
+	}
+
+	status.Phase = ollamamodel.PullJobFailed
+	status.CompletionTime = &metav1.Time{Time: time.Now()}
+	r.setCondition(job, ollamamodel.ConditionFailed, "BackoffLimitExceeded",
+		fmt.Sprintf("Gave up after %d failed pulls: %v", status.Attempts, err))
+	if err := r.Status().Update(ctx, job); err != nil {
+		return ctrl.Result{}, err
+	}
+	return r.expire(ctx, job)
+}
+
+// pull pulls the model of a job, writing its progress to the status as it
+// goes and leaving the final progress in it. Progress is informational, so
+// failures to write it are only logged.
+func (r *OllamaPullJobReconciler) pull(ctx context.Context, job *ollamamodel.OllamaPullJob) error {
+	slots := r.Pulls
+	if slots == nil {
+		slots = &r.pulls
+	}
+	queued := QueuedPull{Kind: "OllamaPullJob", Namespace: job.Namespace, Name: job.Name, Model: job.Status.Model}
+	if err := slots.acquire(ctx, queued, func() int { return r.Settings.Get().MaxConcurrentPulls }); err != nil {
+		return err
+	}
+	defer slots.release()
+
+	progress := &pullProgress{}
+	defer func() {
+		if completed, _ := progress.bytes(); completed > 0 {
+			job.Status.Progress = progress.stopped()
+		}
+	}()
+	return r.Ollama.Pull(ctx, &api.PullRequest{Name: job.Status.Model}, func(resp api.ProgressResponse) error {
+		now := time.Now()
+		progress.update(resp, now)
+		if progress.due(now) {
+			progress.saved(now)
+			base := job.DeepCopy()
+			job.Status.Progress = progress.status()
+			if err := r.Status().Patch(ctx, job, client.MergeFrom(base)); err != nil {
+				log.FromContext(ctx).Error(err, "failed to save pull progress", "job", job.Name)
+			}
+		}
+		return nil
+	})
+}
+
+// expire deletes a finished job once its TTL has passed, or requeues it until then
+func (r *OllamaPullJobReconciler) expire(ctx context.Context, job *ollamamodel.OllamaPullJob) (ctrl.Result, error) {
+	if job.Spec.TTLSecondsAfterFinished == nil || job.Status.CompletionTime == nil {
+		return ctrl.Result{}, nil
+	}
+	ttl := time.Duration(*job.Spec.TTLSecondsAfterFinished) * time.Second
+	if remaining := time.Until(job.Status.CompletionTime.Add(ttl)); remaining > 0 {
+		return ctrl.Result{RequeueAfter: remaining}, nil
+	}
+	log.FromContext(ctx).Info("deleting finished pull job", "job", job.Name)
+	return ctrl.Result{}, client.IgnoreNotFound(r.Delete(ctx, job))
+}
+
+// setCondition sets a condition of a job to True
+func (r *OllamaPullJobReconciler) setCondition(job *ollamamodel.OllamaPullJob, conditionType, reason, message string) {
+	meta.SetStatusCondition(&job.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: job.Generation,
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager
+func (r *OllamaPullJobReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&ollamamodel.OllamaPullJob{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Named("ollamapulljob").
+		Complete(r)
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ollama/ollama/api"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+// scriptedPull is an Ollama client whose pulls report progress, then return err
+type scriptedPull struct {
+	OllamaClient
+	err error
+}
+
+func (p scriptedPull) Pull(_ context.Context, _ *api.PullRequest, fn api.PullProgressFunc) error {
+	if err := fn(api.ProgressResponse{Status: "pulling", Digest: "sha256:1", Total: 100, Completed: 100}); err != nil {
+		return err
+	}
+	return p.err
+}
+
+var _ = Describe("OllamaPullJob Controller", func() {
+	ctx := context.Background()
+
+	newJob := func(name string, backoffLimit int32) *ollamav1alpha1.OllamaPullJob {
+		job := &ollamav1alpha1.OllamaPullJob{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       ollamav1alpha1.OllamaPullJobSpec{Name: "llama3.2", Tag: "1b", BackoffLimit: &backoffLimit},
+		}
+		Expect(k8sClient.Create(ctx, job)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, job)
+		return job
+	}
+
+	It("completes once the model is pulled", func() {
+		job := newJob("pulljob-succeeds", 0)
+		r := &OllamaPullJobReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), Ollama: scriptedPull{}, Recorder: record.NewFakeRecorder(10)}
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: job.Name, Namespace: job.Namespace}})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: job.Name, Namespace: job.Namespace}, job)).To(Succeed())
+		Expect(job.Status.Phase).To(Equal(ollamav1alpha1.PullJobSucceeded))
+		Expect(job.Status.Model).To(Equal("llama3.2:1b"))
+		Expect(job.Status.Progress).NotTo(BeNil())
+		Expect(job.Status.Progress.Percent).To(BeEquivalentTo(100))
+		Expect(job.Status.CompletionTime).NotTo(BeNil())
+		Expect(meta.IsStatusConditionTrue(job.Status.Conditions, ollamav1alpha1.ConditionComplete)).To(BeTrue())
+	})
+
+	It("retries failed pulls, then fails once the backoff limit is exceeded", func() {
+		job := newJob("pulljob-fails", 1)
+		r := &OllamaPullJobReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), Ollama: scriptedPull{err: errors.New("manifest unknown")}, Recorder: record.NewFakeRecorder(10)}
+		req := ctrl.Request{NamespacedName: types.NamespacedName{Name: job.Name, Namespace: job.Namespace}}
+
+		result, err := r.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(pullJobRetryDelay))
+		Expect(k8sClient.Get(ctx, req.NamespacedName, job)).To(Succeed())
+		Expect(job.Status.Phase).To(Equal(ollamav1alpha1.PullJobPending))
+		Expect(job.Status.Attempts).To(BeEquivalentTo(1))
+
+		_, err = r.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(k8sClient.Get(ctx, req.NamespacedName, job)).To(Succeed())
+		Expect(job.Status.Phase).To(Equal(ollamav1alpha1.PullJobFailed))
+		Expect(job.Status.Error).To(ContainSubstring("manifest unknown"))
+		Expect(meta.IsStatusConditionTrue(job.Status.Conditions, ollamav1alpha1.ConditionFailed)).To(BeTrue())
+	})
+})
@@ -0,0 +1,307 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	ollamamodel "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+// OllamaScheduleReconciler creates the OllamaModels of a schedule when one of
+// its windows opens and deletes them when it closes, which pulls the models
+// and removes them from the Ollama server in turn
+type OllamaScheduleReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=ollama.smithforge.dev,resources=ollamaschedules,verbs=get;list;watch
+// +kubebuilder:rbac:groups=ollama.smithforge.dev,resources=ollamaschedules/status,verbs=get;update;patch
+
+// Reconcile keeps the models of a schedule while it is in a window and
+// requeues it for its next transition
+func (r *OllamaScheduleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	schedule := &ollamamodel.OllamaSchedule{}
+	if err := r.Get(ctx, req.NamespacedName, schedule); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !schedule.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+	original := schedule.Status.DeepCopy()
+	status := &schedule.Status
+	status.ObservedGeneration = schedule.Generation
+
+	now := time.Now()
+	active, next, err := scheduleState(schedule.Spec, now)
+	if err != nil {
+		r.setActive(schedule, metav1.ConditionFalse, "InvalidSchedule", err.Error())
+		return ctrl.Result{}, r.updateScheduleStatus(ctx, schedule, original)
+	}
+
+	var owned ollamamodel.OllamaModelList
+	if err := r.List(ctx, &owned, client.InNamespace(schedule.Namespace), client.MatchingLabels{ollamamodel.ScheduleLabel: schedule.Name}); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	var kept []string
+	if active {
+		for _, template := range schedule.Spec.Models {
+			ok, err := r.ensureScheduledModel(ctx, schedule, template)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if ok {
+				kept = append(kept, template.Name)
+			}
+		}
+	}
+	for i := range owned.Items {
+		model := &owned.Items[i]
+		if slices.Contains(kept, model.Name) || !metav1.IsControlledBy(model, schedule) || !model.DeletionTimestamp.IsZero() {
+			continue
+		}
+		log.FromContext(ctx).Info("removing scheduled model", "schedule", schedule.Name, "name", model.Name)
+		if err := r.Delete(ctx, model); client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, fmt.Errorf("deleting model %s: %w", model.Name, err)
+		}
+	}
+	sort.Strings(kept)
+
+	status.Active = active
+	status.Models = kept
+	status.NextTransition = &metav1.Time{Time: next}
+	if active {
+		r.setActive(schedule, metav1.ConditionTrue, "InWindow",
+			fmt.Sprintf("Keeping %d models until %s", len(kept), next.Format(time.RFC3339)))
+	} else {
+		r.setActive(schedule, metav1.ConditionFalse, "OutsideWindow",
+			fmt.Sprintf("Models are removed until %s", next.Format(time.RFC3339)))
+	}
+	if original.Active != active && original.NextTransition != nil {
+		if active {
+			r.Recorder.Event(schedule, "Normal", "WindowOpened", fmt.Sprintf("Creating %d models", len(kept)))
+		} else {
+			r.Recorder.Event(schedule, "Normal", "WindowClosed", "Removing the models of the schedule")
+		}
+	}
+
+	if err := r.updateScheduleStatus(ctx, schedule, original); err != nil {
+		return ctrl.Result{}, err
+	}
+	// A second of margin makes sure the transition has passed when requeued
+	return ctrl.Result{RequeueAfter: next.Sub(now) + time.Second}, nil
+}
+
+// ensureScheduledModel creates or updates the OllamaModel of a template of a
+// schedule. It reports false, with a warning event, when a model of the same
+// name that the schedule does not own is in the way.
+func (r *OllamaScheduleReconciler) ensureScheduledModel(ctx context.Context, schedule *ollamamodel.OllamaSchedule, template ollamamodel.ScheduledModel) (bool, error) {
+	model := &ollamamodel.OllamaModel{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: schedule.Namespace, Name: template.Name}, model)
+	switch {
+	case apierrors.IsNotFound(err):
+		// The models inherit the labels of the schedule, so that they match the
+		// label selector the operator may watch with as the schedule does
+		labels := map[string]string{}
+		for key, value := range schedule.Labels {
+			labels[key] = value
+		}
+		labels[ollamamodel.ScheduleLabel] = schedule.Name
+		model = &ollamamodel.OllamaModel{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      template.Name,
+				Namespace: schedule.Namespace,
+				Labels:    labels,
+			},
+			Spec: *template.Spec.DeepCopy(),
+		}
+		if err := controllerutil.SetControllerReference(schedule, model, r.Scheme); err != nil {
+			return false, err
+		}
+		if err := r.Create(ctx, model); err != nil {
+			return false, fmt.Errorf("creating model %s: %w", template.Name, err)
+		}
+		log.FromContext(ctx).Info("created scheduled model", "schedule", schedule.Name, "name", template.Name)
+		return true, nil
+	case err != nil:
+		return false, err
+	case !metav1.IsControlledBy(model, schedule):
+		r.Recorder.Event(schedule, "Warning", "ModelConflict",
+			fmt.Sprintf("OllamaModel %s exists and is not managed by the schedule", template.Name))
+		return false, nil
+	case !model.DeletionTimestamp.IsZero():
+		// Recreated once the previous one is removed from the Ollama server
+		return false, nil
+	case !equality.Semantic.DeepEqual(model.Spec, template.Spec):
+		model.Spec = *template.Spec.DeepCopy()
+		if err := r.Update(ctx, model); err != nil {
+			return false, fmt.Errorf("updating model %s: %w", template.Name, err)
+		}
+	}
+	return true, nil
+}
+
+// setActive sets the Active condition of a schedule
+func (r *OllamaScheduleReconciler) setActive(schedule *ollamamodel.OllamaSchedule, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&schedule.Status.Conditions, metav1.Condition{
+		Type:               ollamamodel.ConditionActive,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: schedule.Generation,
+	})
+}
+
+// updateScheduleStatus writes the status of a schedule unless it is unchanged
+func (r *OllamaScheduleReconciler) updateScheduleStatus(ctx context.Context, schedule *ollamamodel.OllamaSchedule, original *ollamamodel.OllamaScheduleStatus) error {
+	if equality.Semantic.DeepEqual(original, &schedule.Status) {
+		return nil
+	}
+	return r.Status().Update(ctx, schedule)
+}
+
+// scheduleState reports whether a schedule is in one of its windows at now,
+// and when that next changes
+func scheduleState(spec ollamamodel.OllamaScheduleSpec, now time.Time) (bool, time.Time, error) {
+	return windowState(spec.TimeZone, spec.Windows, now)
+}
+
+// windowState reports whether now is in one of the daily windows of the time
+// zone, and when that next changes
+func windowState(timeZone string, windows []ollamamodel.ScheduleWindow, now time.Time) (bool, time.Time, error) {
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return false, time.Time{}, fmt.Errorf("invalid time zone %q: %w", timeZone, err)
+	}
+	now = now.In(location)
+
+	// The windows opening from yesterday, which may still be open, until a
+	// week from now
+	var periods [][2]time.Time
+	year, month, day := now.Date()
+	for offset := -1; offset <= 7; offset++ {
+		date := time.Date(year, month, day+offset, 0, 0, 0, 0, location)
+		for _, window := range windows {
+			if len(window.Days) > 0 && !slices.Contains(window.Days, weekday(date.Weekday())) {
+				continue
+			}
+			start, err := clockTime(date, window.Start)
+			if err != nil {
+				return false, time.Time{}, err
+			}
+			end, err := clockTime(date, window.End)
+			if err != nil {
+				return false, time.Time{}, err
+			}
+			if !end.After(start) {
+				end = end.AddDate(0, 0, 1)
+			}
+			periods = append(periods, [2]time.Time{start, end})
+		}
+	}
+	if len(periods) == 0 {
+		return false, time.Time{}, fmt.Errorf("no window opens on any day")
+	}
+
+	activeAt := func(t time.Time) bool {
+		for _, period := range periods {
+			if !t.Before(period[0]) && t.Before(period[1]) {
+				return true
+			}
+		}
+		return false
+	}
+	var boundaries []time.Time
+	for _, period := range periods {
+		for _, boundary := range period {
+			if boundary.After(now) {
+				boundaries = append(boundaries, boundary)
+			}
+		}
+	}
+	sort.Slice(boundaries, func(i, j int) bool { return boundaries[i].Before(boundaries[j]) })
+
+	active := activeAt(now)
+	for _, boundary := range boundaries {
+		if activeAt(boundary) != active {
+			return active, boundary, nil
+		}
+	}
+	// Windows covering the whole week never close; look again in a week
+	return active, now.AddDate(0, 0, 7), nil
+}
+
+// clockTime returns the time of day given as HH:MM on the date
+func clockTime(date time.Time, clock string) (time.Time, error) {
+	hours, minutes, ok := cutClock(clock)
+	if !ok {
+		return time.Time{}, fmt.Errorf("invalid time of day %q, expected HH:MM", clock)
+	}
+	year, month, day := date.Date()
+	return time.Date(year, month, day, hours, minutes, 0, 0, date.Location()), nil
+}
+
+// cutClock splits a time of day given as HH:MM
+func cutClock(clock string) (int, int, bool) {
+	if len(clock) != 5 || clock[2] != ':' {
+		return 0, 0, false
+	}
+	hours, err := strconv.Atoi(clock[:2])
+	if err != nil || hours < 0 || hours > 23 {
+		return 0, 0, false
+	}
+	minutes, err := strconv.Atoi(clock[3:])
+	if err != nil || minutes < 0 || minutes > 59 {
+		return 0, 0, false
+	}
+	return hours, minutes, true
+}
+
+// weekday returns the abbreviation of a day of the week used by schedules
+func weekday(day time.Weekday) ollamamodel.Weekday {
+	return ollamamodel.Weekday(day.String()[:3])
+}
+
+// SetupWithManager sets up the controller with the Manager
+func (r *OllamaScheduleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&ollamamodel.OllamaSchedule{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Owns(&ollamamodel.OllamaModel{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Named("ollamaschedule").
+		Complete(r)
+}
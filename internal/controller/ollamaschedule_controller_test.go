@@ -0,0 +1,128 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+var _ = Describe("OllamaSchedule Controller", func() {
+	Context("scheduleState", func() {
+		berlin, _ := time.LoadLocation("Europe/Berlin")
+		// A Wednesday
+		at := func(hour, minute int) time.Time { return time.Date(2025, 3, 12, hour, minute, 0, 0, berlin) }
+		nightly := ollamav1alpha1.OllamaScheduleSpec{
+			TimeZone: "Europe/Berlin",
+			Windows:  []ollamav1alpha1.ScheduleWindow{{Start: "22:00", End: "06:00"}},
+		}
+
+		It("opens windows running past midnight on the evening they start", func() {
+			active, next, err := scheduleState(nightly, at(12, 0))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(active).To(BeFalse())
+			Expect(next).To(BeTemporally("==", at(22, 0)))
+
+			active, next, err = scheduleState(nightly, at(3, 0))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(active).To(BeTrue())
+			Expect(next).To(BeTemporally("==", at(6, 0)))
+		})
+
+		It("only opens windows on their days", func() {
+			weekend := ollamav1alpha1.OllamaScheduleSpec{
+				Windows: []ollamav1alpha1.ScheduleWindow{{Start: "00:00", End: "00:00", Days: []ollamav1alpha1.Weekday{"Sat", "Sun"}}},
+			}
+			active, next, err := scheduleState(weekend, time.Date(2025, 3, 12, 12, 0, 0, 0, time.UTC))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(active).To(BeFalse())
+			Expect(next).To(BeTemporally("==", time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)))
+
+			// Back-to-back windows close once both are over
+			active, next, err = scheduleState(weekend, time.Date(2025, 3, 15, 12, 0, 0, 0, time.UTC))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(active).To(BeTrue())
+			Expect(next).To(BeTemporally("==", time.Date(2025, 3, 17, 0, 0, 0, 0, time.UTC)))
+		})
+
+		It("rejects unknown time zones", func() {
+			_, _, err := scheduleState(ollamav1alpha1.OllamaScheduleSpec{TimeZone: "Mars/Olympus", Windows: nightly.Windows}, at(12, 0))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	It("creates the models of a schedule during its windows and removes them outside", func() {
+		ctx := context.Background()
+		now := time.Now().UTC()
+		clock := func(t time.Time) string { return t.Format("15:04") }
+
+		schedule := &ollamav1alpha1.OllamaSchedule{
+			ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "default"},
+			Spec: ollamav1alpha1.OllamaScheduleSpec{
+				Windows: []ollamav1alpha1.ScheduleWindow{{Start: clock(now.Add(-time.Hour)), End: clock(now.Add(time.Hour))}},
+				Models: []ollamav1alpha1.ScheduledModel{{
+					Name: "nightly-llama",
+					Spec: ollamav1alpha1.OllamaModelSpec{Name: "llama3.1", Tag: "70b"},
+				}},
+			},
+		}
+		Expect(k8sClient.Create(ctx, schedule)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, schedule)
+
+		r := &OllamaScheduleReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), Recorder: record.NewFakeRecorder(10)}
+		result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(schedule)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeNumerically("~", time.Hour, 2*time.Minute))
+
+		model := &ollamav1alpha1.OllamaModel{}
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "nightly-llama"}, model)).To(Succeed())
+		Expect(metav1.IsControlledBy(model, schedule)).To(BeTrue())
+		Expect(model.Labels).To(HaveKeyWithValue(ollamav1alpha1.ScheduleLabel, "nightly"))
+		Expect(model.Spec.Reference()).To(Equal("llama3.1:70b"))
+
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(schedule), schedule)).To(Succeed())
+		Expect(schedule.Status.Active).To(BeTrue())
+		Expect(schedule.Status.Models).To(Equal([]string{"nightly-llama"}))
+
+		// Moving the window later closes it
+		schedule.Spec.Windows[0] = ollamav1alpha1.ScheduleWindow{Start: clock(now.Add(time.Hour)), End: clock(now.Add(2 * time.Hour))}
+		Expect(k8sClient.Update(ctx, schedule)).To(Succeed())
+		_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(schedule)})
+		Expect(err).NotTo(HaveOccurred())
+
+		err = k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "nightly-llama"}, model)
+		if err == nil {
+			// Held by the finalizer until removed from the Ollama server
+			Expect(model.DeletionTimestamp.IsZero()).To(BeFalse())
+		} else {
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		}
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(schedule), schedule)).To(Succeed())
+		Expect(schedule.Status.Active).To(BeFalse())
+		Expect(schedule.Status.Models).To(BeEmpty())
+	})
+})
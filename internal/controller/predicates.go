@@ -0,0 +1,68 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	ollamamodel "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+// reconcilePredicate filters out updates that need no reconcile, most notably
+// the status and annotation updates the controller writes itself. Spec changes
+// and deletions bump the generation; refresh, retry and cancel requests,
+// digest pins and forced deletions are annotation changes and are let through
+// explicitly.
+func reconcilePredicate() predicate.Predicate {
+	return predicate.Or(predicate.GenerationChangedPredicate{}, annotationPredicate())
+}
+
+// annotationPredicate accepts updates that set the refresh annotation to a new
+// value, change the pinned digest or force a deletion, as well as periodic
+// resyncs
+func annotationPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc:  func(event.CreateEvent) bool { return false },
+		DeleteFunc:  func(event.DeleteEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.ObjectOld == nil || e.ObjectNew == nil {
+				return false
+			}
+			// Resyncs deliver unchanged objects; keep them so that models removed
+			// from Ollama behind the operator's back are noticed
+			if e.ObjectOld.GetResourceVersion() == e.ObjectNew.GetResourceVersion() {
+				return true
+			}
+			oldAnnotations, newAnnotations := e.ObjectOld.GetAnnotations(), e.ObjectNew.GetAnnotations()
+			if refresh := newAnnotations[ollamamodel.RefreshAnnotation]; refresh != "" && refresh != oldAnnotations[ollamamodel.RefreshAnnotation] {
+				return true
+			}
+			if newAnnotations[ollamamodel.ForceDeleteAnnotation] == "true" && oldAnnotations[ollamamodel.ForceDeleteAnnotation] != "true" {
+				return true
+			}
+			if newAnnotations[ollamamodel.RetryAnnotation] == "true" && oldAnnotations[ollamamodel.RetryAnnotation] != "true" {
+				return true
+			}
+			if newAnnotations[ollamamodel.CancelPullAnnotation] == "true" && oldAnnotations[ollamamodel.CancelPullAnnotation] != "true" {
+				return true
+			}
+			return newAnnotations[ollamamodel.DigestAnnotation] != oldAnnotations[ollamamodel.DigestAnnotation]
+		},
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+var _ = Describe("Reconcile predicate", func() {
+	model := func(generation int64, resourceVersion string, annotations map[string]string) *ollamav1alpha1.OllamaModel {
+		return &ollamav1alpha1.OllamaModel{ObjectMeta: metav1.ObjectMeta{
+			Name:            "llama3.2-1b",
+			Generation:      generation,
+			ResourceVersion: resourceVersion,
+			Annotations:     annotations,
+		}}
+	}
+
+	update := func(oldObj, newObj *ollamav1alpha1.OllamaModel) bool {
+		return reconcilePredicate().Update(event.UpdateEvent{ObjectOld: oldObj, ObjectNew: newObj})
+	}
+
+	It("skips status and bookkeeping updates", func() {
+		Expect(update(model(1, "1", nil), model(1, "2", nil))).To(BeFalse())
+		Expect(update(model(1, "1", map[string]string{ollamav1alpha1.RefreshAnnotation: "2025-03-25T19:04:53Z"}),
+			model(1, "2", nil))).To(BeFalse())
+	})
+
+	It("reconciles spec changes, refresh requests, digest pins, forced deletions and resyncs", func() {
+		Expect(update(model(1, "1", nil), model(2, "2", nil))).To(BeTrue())
+		Expect(update(model(1, "1", nil), model(1, "2", map[string]string{ollamav1alpha1.RefreshAnnotation: "true"}))).To(BeTrue())
+		Expect(update(model(1, "1", map[string]string{ollamav1alpha1.RefreshAnnotation: "2025-03-25T19:04:53Z"}),
+			model(1, "2", map[string]string{ollamav1alpha1.RefreshAnnotation: "2025-03-26T08:00:00Z"}))).To(BeTrue())
+		Expect(update(model(1, "1", nil), model(1, "2", map[string]string{ollamav1alpha1.DigestAnnotation: "a80c4f17"}))).To(BeTrue())
+		Expect(update(model(1, "1", nil), model(1, "2", map[string]string{ollamav1alpha1.ForceDeleteAnnotation: "true"}))).To(BeTrue())
+		Expect(update(model(1, "1", nil), model(1, "1", nil))).To(BeTrue())
+	})
+
+	It("requests a refresh for annotation values not handled yet", func() {
+		m := model(1, "1", map[string]string{ollamav1alpha1.RefreshAnnotation: "2025-03-25T19:04:53Z"})
+		Expect(m.RefreshRequested()).To(BeTrue())
+		m.Status.LastHandledRefresh = "2025-03-25T19:04:53Z"
+		Expect(m.RefreshRequested()).To(BeFalse())
+
+		// Values left behind by earlier versions were handled already
+		m.Annotations[ollamav1alpha1.RefreshAnnotation] = "completed-2025-03-25T19:04:53Z"
+		Expect(m.RefreshRequested()).To(BeFalse())
+	})
+
+	It("treats refreshes requested before the last pull as satisfied", func() {
+		requested := time.Date(2025, 3, 25, 19, 4, 53, 0, time.UTC)
+		m := model(1, "1", map[string]string{ollamav1alpha1.RefreshAnnotation: requested.Format(time.RFC3339Nano)})
+		m.Status.State = ollamav1alpha1.StateReady
+		Expect(refreshSatisfied(m)).To(BeFalse())
+
+		m.Status.LastPullTime = &metav1.Time{Time: requested.Add(time.Minute)}
+		Expect(refreshSatisfied(m)).To(BeTrue())
+
+		m.Status.LastPullTime = &metav1.Time{Time: requested.Add(-time.Minute)}
+		Expect(refreshSatisfied(m)).To(BeFalse())
+
+		// Values other than timestamps cannot be compared to the pull
+		m.Annotations[ollamav1alpha1.RefreshAnnotation] = "true"
+		Expect(refreshSatisfied(m)).To(BeFalse())
+	})
+})
@@ -0,0 +1,181 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	ollamamodel "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+// PrewarmReconciler pulls the models marked for pre-warming into new Ollama
+// volumes, the PersistentVolumeClaims labeled with PrewarmLabel, with a Job.
+// Each claim is pre-warmed once; the models pulled are recorded in its
+// PrewarmedAnnotation, which replicas can wait for before serving.
+type PrewarmReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	// Image is the image of the pre-warm Jobs; empty uses DefaultExportImage
+	Image string
+}
+
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;delete
+
+// Reconcile pre-warms a labeled claim that has not been pre-warmed yet
+func (r *PrewarmReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	claim := &corev1.PersistentVolumeClaim{}
+	if err := r.Get(ctx, req.NamespacedName, claim); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !needsPrewarm(claim) {
+		return ctrl.Result{}, nil
+	}
+
+	models, err := r.prewarmModels(ctx)
+	if err != nil || len(models) == 0 {
+		// Marking a model for pre-warming requeues the claims waiting for one
+		return ctrl.Result{}, err
+	}
+
+	jobName := prewarmJobName(claim.Name, models)
+	job := &batchv1.Job{}
+	err = r.Get(ctx, client.ObjectKey{Namespace: claim.Namespace, Name: jobName}, job)
+	switch {
+	case apierrors.IsNotFound(err):
+		job = newPullJob(claim.Namespace, jobName, claim.Name, r.Image, pullScript, models)
+		if err := controllerutil.SetControllerReference(claim, job, r.Scheme); err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.Create(ctx, job); err != nil && !apierrors.IsAlreadyExists(err) {
+			return ctrl.Result{}, fmt.Errorf("creating prewarm job %s: %w", jobName, err)
+		}
+		log.Info("pre-warming claim", "claim", claim.Name, "job", jobName, "models", models)
+		r.Recorder.Event(claim, "Normal", "PrewarmStarted",
+			fmt.Sprintf("Pulling %s with Job %s", strings.Join(models, ", "), jobName))
+	case err != nil:
+		return ctrl.Result{}, err
+	case job.Status.Succeeded > 0:
+		patch := client.MergeFrom(claim.DeepCopy())
+		if claim.Annotations == nil {
+			claim.Annotations = make(map[string]string)
+		}
+		claim.Annotations[ollamamodel.PrewarmedAnnotation] = strings.Join(models, ",")
+		if err := r.Patch(ctx, claim, patch); err != nil {
+			return ctrl.Result{}, err
+		}
+		log.Info("pre-warmed claim", "claim", claim.Name, "models", models)
+		r.Recorder.Event(claim, "Normal", "Prewarmed", fmt.Sprintf("Pulled %s", strings.Join(models, ", ")))
+	default:
+		// The Job is retried once its TTL removes it
+		if failure := jobFailure(job); failure != "" {
+			r.Recorder.Event(claim, "Warning", "PrewarmFailed", failure)
+		}
+	}
+	return ctrl.Result{}, nil
+}
+
+// needsPrewarm reports whether a claim is labeled for pre-warming and has not been pre-warmed yet
+func needsPrewarm(claim *corev1.PersistentVolumeClaim) bool {
+	_, prewarmed := claim.Annotations[ollamamodel.PrewarmedAnnotation]
+	return claim.Labels[ollamamodel.PrewarmLabel] == "true" && !prewarmed && claim.DeletionTimestamp.IsZero()
+}
+
+// prewarmModels returns the sorted references of the models marked for pre-warming in all namespaces
+func (r *PrewarmReconciler) prewarmModels(ctx context.Context) ([]string, error) {
+	var list ollamamodel.OllamaModelList
+	if err := r.List(ctx, &list); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var models []string
+	for _, model := range list.Items {
+		reference := model.Spec.Reference()
+		if model.Spec.Prewarm && model.DeletionTimestamp.IsZero() && !seen[reference] {
+			seen[reference] = true
+			models = append(models, reference)
+		}
+	}
+	sort.Strings(models)
+	return models, nil
+}
+
+// prewarmJobName returns the name of the Job pre-warming a claim with a set
+// of models, so that a change to the set starts a new Job
+func prewarmJobName(claimName string, models []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(models, "\n")))
+	return pullJobName(claimName, "prewarm", hex.EncodeToString(sum[:])[:8])
+}
+
+// claimsToPrewarm maps a model marked for pre-warming to the claims waiting to be pre-warmed
+func (r *PrewarmReconciler) claimsToPrewarm(ctx context.Context, obj client.Object) []reconcile.Request {
+	model, ok := obj.(*ollamamodel.OllamaModel)
+	if !ok || !model.Spec.Prewarm {
+		return nil
+	}
+
+	var claims corev1.PersistentVolumeClaimList
+	if err := r.List(ctx, &claims, client.MatchingLabels{ollamamodel.PrewarmLabel: "true"}); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list claims to pre-warm")
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range claims.Items {
+		if needsPrewarm(&claims.Items[i]) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&claims.Items[i])})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager
+func (r *PrewarmReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	labeled := predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return obj.GetLabels()[ollamamodel.PrewarmLabel] == "true"
+	})
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&corev1.PersistentVolumeClaim{}, builder.WithPredicates(labeled)).
+		Owns(&batchv1.Job{}).
+		Watches(&ollamamodel.OllamaModel{}, handler.EnqueueRequestsFromMapFunc(r.claimsToPrewarm),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Named("prewarm").
+		Complete(r)
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+var _ = Describe("Prewarm", func() {
+	It("pre-warms labeled claims only once", func() {
+		claim := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{ollamav1alpha1.PrewarmLabel: "true"},
+		}}
+		Expect(needsPrewarm(claim)).To(BeTrue())
+
+		claim.Annotations = map[string]string{ollamav1alpha1.PrewarmedAnnotation: "llama3.2:1b"}
+		Expect(needsPrewarm(claim)).To(BeFalse())
+		Expect(needsPrewarm(&corev1.PersistentVolumeClaim{})).To(BeFalse())
+	})
+
+	It("names jobs after the set of models", func() {
+		a := prewarmJobName("ollama-data-0", []string{"gemma3:1b", "llama3.2:1b"})
+		Expect(a).To(HavePrefix("ollama-data-0-prewarm-"))
+		Expect(a).To(Equal(prewarmJobName("ollama-data-0", []string{"gemma3:1b", "llama3.2:1b"})))
+		Expect(a).NotTo(Equal(prewarmJobName("ollama-data-0", []string{"llama3.2:1b"})))
+	})
+
+	It("creates a job pulling the models marked for pre-warming into a new claim", func() {
+		ctx := context.Background()
+		for _, model := range []*ollamav1alpha1.OllamaModel{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "prewarm-llama", Namespace: "default"},
+				Spec:       ollamav1alpha1.OllamaModelSpec{Name: "llama3.2", Tag: "1b", Prewarm: true},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "prewarm-gemma", Namespace: "default"},
+				Spec:       ollamav1alpha1.OllamaModelSpec{Name: "gemma3", Tag: "1b"},
+			},
+		} {
+			Expect(k8sClient.Create(ctx, model)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, model)
+		}
+
+		claim := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "ollama-data-0",
+				Namespace: "default",
+				Labels:    map[string]string{ollamav1alpha1.PrewarmLabel: "true"},
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				Resources: corev1.VolumeResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, claim)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, claim)
+
+		r := &PrewarmReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), Recorder: record.NewFakeRecorder(10)}
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(claim)})
+		Expect(err).NotTo(HaveOccurred())
+
+		job := &batchv1.Job{}
+		key := client.ObjectKey{Namespace: "default", Name: prewarmJobName(claim.Name, []string{"llama3.2:1b"})}
+		Expect(k8sClient.Get(ctx, key, job)).To(Succeed())
+		Expect(job.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "MODELS", Value: "llama3.2:1b"}))
+		Expect(metav1.IsControlledBy(job, claim)).To(BeTrue())
+	})
+})
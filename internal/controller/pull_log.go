@@ -0,0 +1,113 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ollamamodel "github.com/dmk/ollama-operator/api/v1alpha1"
+	"github.com/ollama/ollama/api"
+)
+
+const (
+	// pullLogMaxLines bounds the number of lines kept in a model's pull log
+	pullLogMaxLines = 200
+
+	// pullLogSaveInterval is how often the pull log is saved while downloading
+	pullLogSaveInterval = 2 * time.Second
+)
+
+// pullLog collects the status lines, errors and retries of a single pull attempt
+// along with the download progress of the current layer
+type pullLog struct {
+	lines      []string
+	lastStatus string
+	completed  int64
+	total      int64
+	savedAt    time.Time
+}
+
+// add appends a timestamped line to the log, dropping the oldest lines once full
+func (l *pullLog) add(format string, args ...interface{}) {
+	line := fmt.Sprintf("%s %s", time.Now().UTC().Format(time.RFC3339), fmt.Sprintf(format, args...))
+	l.lines = append(l.lines, line)
+	if len(l.lines) > pullLogMaxLines {
+		l.lines = l.lines[len(l.lines)-pullLogMaxLines:]
+	}
+}
+
+// progress records a pull progress update, skipping repeated statuses so that
+// per-chunk download updates don't flood the log
+func (l *pullLog) progress(resp api.ProgressResponse) {
+	l.completed, l.total = resp.Completed, resp.Total
+	if resp.Status == l.lastStatus {
+		return
+	}
+	l.lastStatus = resp.Status
+	if resp.Total > 0 {
+		l.add("%s (%s)", resp.Status, formatBytes(resp.Total))
+		return
+	}
+	l.add("%s", resp.Status)
+}
+
+// due reports whether enough time has passed since the log was last saved to
+// save the download progress again
+func (l *pullLog) due() bool {
+	return time.Since(l.savedAt) >= pullLogSaveInterval
+}
+
+// String returns the log contents
+func (l *pullLog) String() string {
+	return strings.Join(l.lines, "\n")
+}
+
+// savePullLog writes the pull log to the model's pull log ConfigMap. Failures are
+// only logged since the pull log is informational.
+func (r *OllamaModelReconciler) savePullLog(ctx context.Context, ollamaModel *ollamamodel.OllamaModel, pl *pullLog) {
+	pl.savedAt = time.Now()
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ollamamodel.PullLogConfigMapName(ollamaModel.Name),
+			Namespace: ollamaModel.Namespace,
+		},
+	}
+
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, configMap, func() error {
+		if configMap.Data == nil {
+			configMap.Data = make(map[string]string)
+		}
+		configMap.Data[ollamamodel.PullLogKey] = pl.String()
+		if pl.total > 0 {
+			configMap.Data[ollamamodel.PullProgressKey] = fmt.Sprintf("%d/%d", pl.completed, pl.total)
+		} else {
+			delete(configMap.Data, ollamamodel.PullProgressKey)
+		}
+		return controllerutil.SetControllerReference(ollamaModel, configMap, r.Scheme)
+	})
+	if err != nil {
+		log.FromContext(ctx).Error(err, "failed to save pull log", "name", ollamaModel.Name)
+	}
+}
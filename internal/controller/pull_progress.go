@@ -0,0 +1,257 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ollamamodel "github.com/dmk/ollama-operator/api/v1alpha1"
+	"github.com/ollama/ollama/api"
+)
+
+// Progress is written to the status once it moved by progressStatusStep percent
+// and progressStatusInterval passed since the last write, whichever is later,
+// so that fast downloads don't turn into a stream of status writes
+const (
+	progressStatusStep     = 5
+	progressStatusInterval = 15 * time.Second
+)
+
+// progressMilestones are the percentages at which an event is recorded
+var progressMilestones = []int32{25, 50, 75, 100}
+
+// progressEventInterval is how long a pull goes without an event before its
+// progress is recorded again, so that slow pulls keep telling how they are
+// doing between milestones
+const progressEventInterval = 5 * time.Minute
+
+// rateSmoothing is the weight of the latest sample in the moving average of
+// the download rate, measured over pullRateInterval, so that the time
+// remaining doesn't jump with every burst of the download
+const rateSmoothing = 0.3
+
+// layerProgress is the download progress of a single layer
+type layerProgress struct {
+	completed int64
+	total     int64
+}
+
+// pullProgress tracks the download progress of a pull across its layers and
+// decides when it is worth reporting
+type pullProgress struct {
+	layers       map[string]layerProgress
+	percent      int32
+	savedPercent int32
+	savedAt      time.Time
+	milestone    int
+	startedAt    time.Time
+	eventAt      time.Time
+
+	// rate is the smoothed download rate in bytes per second, measured from
+	// the bytes downloaded at sampledAt
+	rate         float64
+	sampledBytes int64
+	sampledAt    time.Time
+}
+
+// update records a progress update received at now. Updates without a layer,
+// such as "pulling manifest", carry no download progress and are ignored.
+func (p *pullProgress) update(resp api.ProgressResponse, now time.Time) {
+	if resp.Digest == "" || resp.Total <= 0 {
+		return
+	}
+	if p.layers == nil {
+		p.layers = make(map[string]layerProgress)
+	}
+	p.layers[resp.Digest] = layerProgress{completed: resp.Completed, total: resp.Total}
+	if p.startedAt.IsZero() {
+		p.startedAt = now
+	}
+
+	// Layers are discovered as the pull goes, so the share completed can drop
+	// when a new one starts; report the highest share seen instead
+	completed, total := p.bytes()
+	if percent := int32(completed * 100 / total); percent > p.percent {
+		p.percent = percent
+	}
+	p.measure(completed, now)
+}
+
+// measure updates the download rate with the bytes downloaded by now
+func (p *pullProgress) measure(completed int64, now time.Time) {
+	if p.sampledAt.IsZero() {
+		p.sampledBytes, p.sampledAt = completed, now
+		return
+	}
+	elapsed := now.Sub(p.sampledAt)
+	if elapsed < pullRateInterval {
+		return
+	}
+	rate := max(float64(completed-p.sampledBytes)/elapsed.Seconds(), 0)
+	if p.rate == 0 {
+		p.rate = rate
+	} else {
+		p.rate = rateSmoothing*rate + (1-rateSmoothing)*p.rate
+	}
+	p.sampledBytes, p.sampledAt = completed, now
+}
+
+// remaining returns the estimated time left to download the layers seen so
+// far at the current rate, and false until the rate is known or once the
+// download is done. Layers are discovered as the pull goes, so the estimate
+// may grow when one starts.
+func (p *pullProgress) remaining() (time.Duration, bool) {
+	completed, total := p.bytes()
+	if p.rate <= 0 || completed >= total {
+		return 0, false
+	}
+	return time.Duration(float64(total-completed) / p.rate * float64(time.Second)), true
+}
+
+// estimate describes the time remaining and the download rate, such as
+// "~4m remaining at 85.0 MiB/s", or returns "" while they are unknown
+func (p *pullProgress) estimate() string {
+	remaining, ok := p.remaining()
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("%s remaining at %s/s", formatRemaining(remaining), formatBytes(int64(p.rate)))
+}
+
+// formatRemaining rounds a time remaining to a readable estimate, such as
+// "~45s", "~4m" or "~1h20m"
+func formatRemaining(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("~%ds", max(int(d.Round(5*time.Second).Seconds()), 5))
+	case d < time.Hour:
+		return fmt.Sprintf("~%dm", int(d.Round(time.Minute).Minutes()))
+	}
+	d = d.Round(time.Minute)
+	return fmt.Sprintf("~%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// bytes returns the bytes downloaded and the total size of the layers seen so far
+func (p *pullProgress) bytes() (int64, int64) {
+	var completed, total int64
+	for _, layer := range p.layers {
+		completed += layer.completed
+		total += layer.total
+	}
+	return completed, total
+}
+
+// due reports whether the progress should be written to the status again
+func (p *pullProgress) due(now time.Time) bool {
+	return p.percent-p.savedPercent >= progressStatusStep && now.Sub(p.savedAt) >= progressStatusInterval
+}
+
+// saved records that the progress was written to the status
+func (p *pullProgress) saved(now time.Time) {
+	p.savedPercent, p.savedAt = p.percent, now
+}
+
+// eventDue reports whether progressEventInterval passed since the last event
+// of the pull, or since the download started
+func (p *pullProgress) eventDue(now time.Time) bool {
+	last := p.eventAt
+	if last.IsZero() {
+		last = p.startedAt
+	}
+	return !last.IsZero() && now.Sub(last) >= progressEventInterval
+}
+
+// milestones returns the milestones reached since the last call
+func (p *pullProgress) milestones() []int32 {
+	var reached []int32
+	for p.milestone < len(progressMilestones) && p.percent >= progressMilestones[p.milestone] {
+		reached = append(reached, progressMilestones[p.milestone])
+		p.milestone++
+	}
+	return reached
+}
+
+// status returns the progress as reported in the status of a model
+func (p *pullProgress) status() *ollamamodel.PullProgress {
+	completed, total := p.bytes()
+	status := &ollamamodel.PullProgress{
+		Percent:        p.percent,
+		CompletedBytes: completed,
+		TotalBytes:     total,
+		BytesPerSecond: int64(p.rate),
+		Message:        fmt.Sprintf("%d%%", p.percent),
+	}
+	if remaining, ok := p.remaining(); ok {
+		status.RemainingSeconds = int64(remaining.Round(time.Second).Seconds())
+		status.Message += ", " + p.estimate()
+	}
+	return status
+}
+
+// stopped returns the progress of a pull that is no longer running, without
+// its rate and time remaining
+func (p *pullProgress) stopped() *ollamamodel.PullProgress {
+	completed, total := p.bytes()
+	return &ollamamodel.PullProgress{Percent: p.percent, CompletedBytes: completed, TotalBytes: total}
+}
+
+// progressEvent describes the progress of a pull at percent in an event,
+// with the time remaining once it is known
+func progressEvent(modelName string, percent int32, estimate string) string {
+	message := fmt.Sprintf("Downloaded %d%% of model %s", percent, modelName)
+	if estimate != "" {
+		message += ", " + estimate
+	}
+	return message
+}
+
+// reportProgress records an event for each milestone a pull reached, or its
+// progress when it went on for progressEventInterval without one, and writes
+// its progress to the status of the model when due. The events and the status
+// tell the time remaining once the download rate is known. The status is
+// patched so that concurrent metadata changes don't make the write conflict;
+// failures are only logged since progress is informational.
+func (r *OllamaModelReconciler) reportProgress(ctx context.Context, ollamaModel *ollamamodel.OllamaModel, modelName string, progress *pullProgress, now time.Time) {
+	estimate := progress.estimate()
+	milestones := progress.milestones()
+	for _, milestone := range milestones {
+		r.Recorder.Event(ollamaModel, "Normal", "PullProgress", progressEvent(modelName, milestone, estimate))
+	}
+	switch {
+	case len(milestones) > 0:
+		progress.eventAt = now
+	case progress.eventDue(now):
+		r.Recorder.Event(ollamaModel, "Normal", "PullProgress", progressEvent(modelName, progress.percent, estimate))
+		progress.eventAt = now
+	}
+
+	if !progress.due(now) {
+		return
+	}
+	progress.saved(now)
+
+	base := ollamaModel.DeepCopy()
+	ollamaModel.Status.Progress = progress.status()
+	if err := r.Status().Patch(ctx, ollamaModel, client.MergeFrom(base)); err != nil {
+		log.FromContext(ctx).Error(err, "failed to save pull progress", "name", ollamaModel.Name)
+	}
+}
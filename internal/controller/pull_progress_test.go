@@ -0,0 +1,120 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	"github.com/ollama/ollama/api"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Pull progress", func() {
+	layer := func(digest string, completed, total int64) api.ProgressResponse {
+		return api.ProgressResponse{Status: "pulling " + digest, Digest: digest, Completed: completed, Total: total}
+	}
+
+	It("sums the layers and never goes back", func() {
+		var p pullProgress
+		start := time.Now()
+		p.update(api.ProgressResponse{Status: "pulling manifest"}, start)
+		Expect(p.percent).To(BeZero())
+
+		p.update(layer("sha256:a", 900, 1000), start)
+		Expect(p.percent).To(BeEquivalentTo(90))
+
+		p.update(layer("sha256:b", 0, 1000), start)
+		Expect(p.percent).To(BeEquivalentTo(90))
+		Expect(p.status().CompletedBytes).To(BeEquivalentTo(900))
+		Expect(p.status().TotalBytes).To(BeEquivalentTo(2000))
+	})
+
+	It("is due once both the step and the interval passed", func() {
+		var p pullProgress
+		start := time.Now()
+		p.update(layer("sha256:a", 3, 100), start)
+		Expect(p.due(start)).To(BeFalse())
+
+		p.update(layer("sha256:a", 10, 100), start)
+		Expect(p.due(start)).To(BeTrue())
+		p.saved(start)
+
+		p.update(layer("sha256:a", 40, 100), start)
+		Expect(p.due(start.Add(time.Second))).To(BeFalse())
+		Expect(p.due(start.Add(progressStatusInterval))).To(BeTrue())
+	})
+
+	It("reports each milestone once", func() {
+		var p pullProgress
+		start := time.Now()
+		p.update(layer("sha256:a", 60, 100), start)
+		Expect(p.milestones()).To(Equal([]int32{25, 50}))
+		Expect(p.milestones()).To(BeEmpty())
+
+		p.update(layer("sha256:a", 100, 100), start)
+		Expect(p.milestones()).To(Equal([]int32{75, 100}))
+	})
+
+	It("estimates the time remaining from the download rate", func() {
+		const MB = 1 << 20
+		var p pullProgress
+		start := time.Now()
+		p.update(layer("sha256:a", 0, 1000*MB), start)
+		Expect(p.estimate()).To(BeEmpty())
+		Expect(p.status().Message).To(Equal("0%"))
+
+		p.update(layer("sha256:a", 50*MB, 1000*MB), start.Add(pullRateInterval))
+		Expect(p.rate).To(BeNumerically("==", 10*MB))
+		Expect(p.estimate()).To(Equal("~2m remaining at 10.0 MiB/s"))
+
+		// The rate is smoothed rather than following every sample
+		p.update(layer("sha256:a", 200*MB, 1000*MB), start.Add(2*pullRateInterval))
+		Expect(p.rate).To(BeNumerically("~", 16*MB, MB))
+		status := p.status()
+		Expect(status.BytesPerSecond).To(BeNumerically("~", 16*MB, MB))
+		Expect(status.RemainingSeconds).To(BeNumerically("~", 50, 5))
+		Expect(status.Message).To(HavePrefix("20%, ~"))
+		Expect(p.stopped().Message).To(BeEmpty())
+
+		p.update(layer("sha256:a", 1000*MB, 1000*MB), start.Add(3*pullRateInterval))
+		Expect(p.estimate()).To(BeEmpty())
+	})
+
+	It("rounds the time remaining", func() {
+		Expect(formatRemaining(2 * time.Second)).To(Equal("~5s"))
+		Expect(formatRemaining(42 * time.Second)).To(Equal("~40s"))
+		Expect(formatRemaining(4*time.Minute + 10*time.Second)).To(Equal("~4m"))
+		Expect(formatRemaining(80*time.Minute + 20*time.Second)).To(Equal("~1h20m"))
+	})
+
+	It("records an event when a pull goes on without milestones", func() {
+		var p pullProgress
+		start := time.Now()
+		Expect(p.eventDue(start.Add(progressEventInterval))).To(BeFalse())
+
+		p.update(layer("sha256:a", 10, 100), start)
+		Expect(p.eventDue(start.Add(time.Minute))).To(BeFalse())
+		Expect(p.eventDue(start.Add(progressEventInterval))).To(BeTrue())
+		p.eventAt = start.Add(progressEventInterval)
+		Expect(p.eventDue(start.Add(progressEventInterval + time.Minute))).To(BeFalse())
+
+		Expect(progressEvent("llama3.2:1b", 62, "~4m remaining at 85.0 MiB/s")).
+			To(Equal("Downloaded 62% of model llama3.2:1b, ~4m remaining at 85.0 MiB/s"))
+		Expect(progressEvent("llama3.2:1b", 25, "")).To(Equal("Downloaded 25% of model llama3.2:1b"))
+	})
+})
@@ -0,0 +1,131 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// pullSlotRecheck is how often a waiting pull rechecks the cap, which may be
+// raised while it waits
+const pullSlotRecheck = time.Second
+
+// QueuedPull is a pull waiting for a free pull slot
+type QueuedPull struct {
+	// Kind is the kind of the resource the model is pulled for, OllamaModel
+	// or OllamaPullJob
+	Kind      string
+	Namespace string
+	Name      string
+	// Model is the Ollama model to pull
+	Model string
+	// Since is when the pull started waiting
+	Since time.Time
+}
+
+// PullSlots caps the number of concurrent pulls. The cap is read on every
+// acquire so that it can be changed while the operator runs. The zero value
+// is ready to use; controllers pulling models share one.
+type PullSlots struct {
+	mu     sync.Mutex
+	active int
+	freed  chan struct{}
+	queue  []*QueuedPull
+}
+
+// Queue returns the pulls waiting for a free slot, longest waiting first
+func (p *PullSlots) Queue() []QueuedPull {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	queue := make([]QueuedPull, 0, len(p.queue))
+	for _, pull := range p.queue {
+		queue = append(queue, *pull)
+	}
+	return queue
+}
+
+// Active returns the number of pulls holding a slot
+func (p *PullSlots) Active() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.active
+}
+
+// acquire waits until fewer than limit pulls are running, or ctx is done. A
+// limit of 0 or less does not cap pulls. Meanwhile pull is queued, and
+// reported by Queue and the pull queue metrics.
+func (p *PullSlots) acquire(ctx context.Context, pull QueuedPull, limit func() int) error {
+	var queued *QueuedPull
+	defer func() {
+		if queued != nil {
+			p.dequeue(queued)
+		}
+	}()
+
+	for {
+		p.mu.Lock()
+		if n := limit(); n <= 0 || p.active < n {
+			p.active++
+			p.mu.Unlock()
+			return nil
+		}
+		if p.freed == nil {
+			p.freed = make(chan struct{})
+		}
+		freed := p.freed
+		if queued == nil {
+			queued = &pull
+			queued.Since = time.Now()
+			p.queue = append(p.queue, queued)
+			pullQueue.add(queued)
+		}
+		p.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-freed:
+		case <-time.After(pullSlotRecheck):
+		}
+	}
+}
+
+// release frees a slot taken by acquire and wakes the waiting pulls
+func (p *PullSlots) release() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.active--
+	if p.freed != nil {
+		close(p.freed)
+		p.freed = nil
+	}
+}
+
+// dequeue removes a pull from the queue once it got a slot or gave up
+func (p *PullSlots) dequeue(queued *QueuedPull) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, pull := range p.queue {
+		if pull == queued {
+			p.queue = append(p.queue[:i], p.queue[i+1:]...)
+			break
+		}
+	}
+	pullQueue.remove(queued)
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Pull slots", func() {
+	It("caps concurrent pulls and follows changes of the cap", func() {
+		var slots PullSlots
+		limit := 1
+		Expect(slots.acquire(context.Background(), QueuedPull{}, func() int { return limit })).To(Succeed())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		Expect(slots.acquire(ctx, QueuedPull{}, func() int { return limit })).To(MatchError(context.DeadlineExceeded))
+
+		acquired := make(chan error, 1)
+		go func() { acquired <- slots.acquire(context.Background(), QueuedPull{}, func() int { return limit }) }()
+		slots.release()
+		Eventually(acquired).Should(Receive(BeNil()))
+
+		limit = 0
+		Expect(slots.acquire(context.Background(), QueuedPull{}, func() int { return limit })).To(Succeed())
+	})
+
+	It("reports the pulls waiting for a slot in order", func() {
+		var slots PullSlots
+		limit := func() int { return 1 }
+		Expect(slots.acquire(context.Background(), QueuedPull{}, limit)).To(Succeed())
+
+		acquired := make(chan error, 2)
+		for _, name := range []string{"first", "second"} {
+			pull := QueuedPull{Kind: "OllamaModel", Namespace: "default", Name: name, Model: "llama3.2:1b"}
+			go func() { acquired <- slots.acquire(context.Background(), pull, limit) }()
+			Eventually(slots.Queue).Should(ContainElement(HaveField("Name", name)))
+		}
+		queue := slots.Queue()
+		Expect(queue).To(HaveLen(2))
+		Expect(queue[0].Name).To(Equal("first"))
+		Expect(queue[0].Since).NotTo(BeZero())
+
+		slots.release()
+		Eventually(acquired).Should(Receive(BeNil()))
+		Expect(slots.Queue()).To(HaveLen(1))
+		slots.release()
+		Eventually(acquired).Should(Receive(BeNil()))
+		Expect(slots.Queue()).To(BeEmpty())
+	})
+})
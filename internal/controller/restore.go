@@ -0,0 +1,74 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ollama/ollama/api"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ollamamodel "github.com/dmk/ollama-operator/api/v1alpha1"
+	"github.com/dmk/ollama-operator/internal/audit"
+	"github.com/dmk/ollama-operator/internal/notify"
+)
+
+// restoreModel restores a model from the snapshot named by its restoreFrom
+// instead of pulling it. A failed restore is retried like a failed pull.
+func (r *OllamaModelReconciler) restoreModel(ctx context.Context, ollamaModel *ollamamodel.OllamaModel, modelName string) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+	snapshot := ollamaModel.Spec.RestoreFrom.Name
+
+	err := r.copySnapshot(ctx, ollamaModel, modelName)
+	r.recordAudit(ctx, audit.ActionCopy, ollamaModel, modelName, err)
+	if err != nil {
+		log.Error(err, "failed to restore model from snapshot", "model", modelName, "snapshot", snapshot)
+		r.Recorder.Event(ollamaModel, "Warning", "RestoreFailed",
+			fmt.Sprintf("Failed to restore model %s from snapshot %s: %v", modelName, snapshot, err))
+		pullFailed(ollamaModel, err)
+		if updateErr := r.updateStatus(ctx, ollamaModel); updateErr != nil {
+			return ctrl.Result{RequeueAfter: time.Second * 5}, updateErr
+		}
+		r.notify(ctx, notify.EventModelFailed, ollamaModel, modelName)
+		return failedResult(ollamaModel), nil
+	}
+
+	log.Info("restored model from snapshot", "name", ollamaModel.Name, "model", modelName, "snapshot", snapshot)
+	r.Recorder.Event(ollamaModel, "Normal", "Restored", fmt.Sprintf("Restored model %s from snapshot %s", modelName, snapshot))
+	return r.updateModelDetails(ctx, ollamaModel, modelName)
+}
+
+// copySnapshot copies the frozen model of the snapshot a model restores from
+// to the model's reference, replacing whatever the reference pointed at
+func (r *OllamaModelReconciler) copySnapshot(ctx context.Context, ollamaModel *ollamamodel.OllamaModel, modelName string) error {
+	snapshot := &ollamamodel.OllamaModelSnapshot{}
+	key := client.ObjectKey{Namespace: ollamaModel.Namespace, Name: ollamaModel.Spec.RestoreFrom.Name}
+	if err := r.Get(ctx, key, snapshot); err != nil {
+		return fmt.Errorf("failed to get snapshot %s: %w", key.Name, err)
+	}
+	switch {
+	case !snapshot.Status.Captured():
+		return fmt.Errorf("snapshot %s is not captured yet", key.Name)
+	case snapshot.Status.FrozenModel == "":
+		return fmt.Errorf("snapshot %s has no frozen model to restore from", key.Name)
+	}
+	return r.Ollama.Copy(ctx, &api.CopyRequest{Source: snapshot.Status.FrozenModel, Destination: modelName})
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync"
+
+	"github.com/ollama/ollama/api"
+)
+
+// sharedPulls lets OllamaModels managing the same Ollama model share a single
+// pull. The first model to pull it leads the pull; the others follow it,
+// receiving its progress and its outcome instead of downloading the model
+// again. The zero value is ready to use.
+type sharedPulls struct {
+	mu    sync.Mutex
+	pulls map[string]*sharedPull
+}
+
+// sharedPull is a pull of an Ollama model followed by other OllamaModels
+type sharedPull struct {
+	// leader is the namespace/name of the OllamaModel running the pull
+	leader string
+	done   chan struct{}
+	err    error
+
+	mu        sync.Mutex
+	followers map[chan api.ProgressResponse]struct{}
+}
+
+// join returns the pull of modelName running for another OllamaModel, or
+// starts one led by leader, in which case lead is true and the pull must be
+// finished with finish
+func (s *sharedPulls) join(modelName, leader string) (pull *sharedPull, lead bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if pull, ok := s.pulls[modelName]; ok {
+		return pull, false
+	}
+	if s.pulls == nil {
+		s.pulls = make(map[string]*sharedPull)
+	}
+	pull = &sharedPull{leader: leader, done: make(chan struct{}), followers: make(map[chan api.ProgressResponse]struct{})}
+	s.pulls[modelName] = pull
+	return pull, true
+}
+
+// finish records the outcome of a pull started by join and wakes its followers
+func (s *sharedPulls) finish(modelName string, pull *sharedPull, err error) {
+	s.mu.Lock()
+	delete(s.pulls, modelName)
+	s.mu.Unlock()
+
+	pull.err = err
+	close(pull.done)
+}
+
+// follow subscribes to the progress of the pull. Only the latest progress is
+// kept for a follower slower than the pull, so it never holds the pull back.
+func (p *sharedPull) follow() chan api.ProgressResponse {
+	updates := make(chan api.ProgressResponse, 1)
+	p.mu.Lock()
+	p.followers[updates] = struct{}{}
+	p.mu.Unlock()
+	return updates
+}
+
+// unfollow stops sending progress to a follower
+func (p *sharedPull) unfollow(updates chan api.ProgressResponse) {
+	p.mu.Lock()
+	delete(p.followers, updates)
+	p.mu.Unlock()
+}
+
+// progress sends the progress of the pull to its followers
+func (p *sharedPull) progress(resp api.ProgressResponse) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for updates := range p.followers {
+		select {
+		case updates <- resp:
+		default:
+			// Replace the progress the follower has not read yet
+			select {
+			case <-updates:
+			default:
+			}
+			select {
+			case updates <- resp:
+			default:
+			}
+		}
+	}
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/ollama/ollama/api"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+// gatedPull is an Ollama client whose pulls report progress and then wait
+// until release is closed, counting the pulls started
+type gatedPull struct {
+	OllamaClient
+	pulls   atomic.Int32
+	started chan struct{}
+	release chan struct{}
+}
+
+func (g *gatedPull) Pull(ctx context.Context, _ *api.PullRequest, fn api.PullProgressFunc) error {
+	g.pulls.Add(1)
+	close(g.started)
+	if err := fn(api.ProgressResponse{Status: "pulling", Total: 100, Completed: 50}); err != nil {
+		return err
+	}
+	select {
+	case <-g.release:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+var _ = Describe("Shared pulls", func() {
+	It("pulls a model managed by two OllamaModels once", func() {
+		newModel := func(name string) *ollamav1alpha1.OllamaModel {
+			model := &ollamav1alpha1.OllamaModel{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+				Spec:       ollamav1alpha1.OllamaModelSpec{Name: "llama3.2", Tag: "1b"},
+			}
+			Expect(k8sClient.Create(ctx, model)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, model)
+			return model
+		}
+		first, second := newModel("shared-pull-a"), newModel("shared-pull-b")
+
+		ollama := &gatedPull{started: make(chan struct{}), release: make(chan struct{})}
+		recorder := record.NewFakeRecorder(10)
+		r := &OllamaModelReconciler{
+			Client:   k8sClient,
+			Scheme:   k8sClient.Scheme(),
+			Ollama:   ollama,
+			Recorder: recorder,
+		}
+		req := &api.PullRequest{Name: first.Spec.Reference()}
+
+		led := make(chan error, 1)
+		go func() {
+			led <- r.pull(ctx, first, req, func(api.ProgressResponse) error { return nil })
+		}()
+		Eventually(ollama.started).Should(BeClosed())
+
+		following := make(chan error, 1)
+		go func() {
+			following <- r.pull(ctx, second, req, func(api.ProgressResponse) error { return nil })
+		}()
+		Eventually(recorder.Events).Should(Receive(ContainSubstring("PullShared")))
+
+		close(ollama.release)
+		Eventually(led).Should(Receive(BeNil()))
+		Eventually(following).Should(Receive(BeNil()))
+		Expect(ollama.pulls.Load()).To(Equal(int32(1)))
+	})
+})
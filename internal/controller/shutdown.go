@@ -0,0 +1,79 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ollamamodel "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+// interruptedStatusTimeout bounds the status update flushed once a pull was
+// interrupted, which runs past the cancellation of the reconcile context
+const interruptedStatusTimeout = 5 * time.Second
+
+// shuttingDown reports whether a pull failed because the operator is shutting
+// down, which cancels the reconcile context, rather than on its own
+func shuttingDown(ctx context.Context, err error) bool {
+	return err != nil && ctx.Err() != nil
+}
+
+// pullInterrupted records that the pull of a model was interrupted by the
+// shutdown of the operator. The model stays Pulling, with the progress made so
+// far, so that the replica taking over pulls it again and resumes the download
+// rather than reporting a failure. ctx is cancelled already, so the pull log
+// and status are written with a context of their own.
+func (r *OllamaModelReconciler) pullInterrupted(ctx context.Context, ollamaModel *ollamamodel.OllamaModel, modelName string, pl *pullLog, progress *pullProgress) (ctrl.Result, error) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), interruptedStatusTimeout)
+	defer cancel()
+
+	status := &ollamaModel.Status
+	if p := progress.stopped(); p.CompletedBytes > 0 {
+		status.Progress = p
+	}
+	percent := int32(0)
+	if status.Progress != nil {
+		percent = status.Progress.Percent
+	}
+	log.FromContext(ctx).Info("pull interrupted by shutdown", "name", ollamaModel.Name, "model", modelName, "percent", percent)
+	pl.add("pull interrupted by the operator shutdown at %d%%", percent)
+	r.savePullLog(ctx, ollamaModel, pl)
+	r.Recorder.Event(ollamaModel, "Warning", "PullInterrupted",
+		fmt.Sprintf("The pull of model %s was interrupted at %d%% by the operator shutdown, it resumes once the operator is back", modelName, percent))
+
+	status.PullInterruptedTime = &metav1.Time{Time: time.Now()}
+	if err := r.updateStatus(ctx, ollamaModel); err != nil {
+		log.FromContext(ctx).Error(err, "failed to record the interrupted pull", "name", ollamaModel.Name)
+	}
+	return ctrl.Result{}, nil
+}
+
+// interruptedMessage returns the message of the conditions of a model whose
+// pull was interrupted, telling how it resumes
+func interruptedMessage(status *ollamamodel.OllamaModelStatus) string {
+	message := "The pull was interrupted by the operator shutdown"
+	if status.Progress != nil && status.Progress.CompletedBytes > 0 {
+		message += fmt.Sprintf(" at %d%%", status.Progress.Percent)
+	}
+	return message + "; it resumes from the layers already downloaded once the operator is back"
+}
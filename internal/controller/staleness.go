@@ -0,0 +1,110 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	ollamamodel "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+// staleness exports the age of a Ready model's last pull and sets its Stale
+// condition. It reports whether the condition changed and how long until the
+// model becomes stale, which is 0 once it is or when no threshold is set.
+func (r *OllamaModelReconciler) staleness(ollamaModel *ollamamodel.OllamaModel, modelName string, now time.Time) (bool, time.Duration) {
+	if ollamaModel.Status.LastPullTime == nil {
+		return false, 0
+	}
+	pulledAt := ollamaModel.Status.LastPullTime.Time
+	modelAges.set(types.NamespacedName{Namespace: ollamaModel.Namespace, Name: ollamaModel.Name}, modelName, pulledAt)
+
+	if r.StaleThreshold <= 0 {
+		return meta.RemoveStatusCondition(&ollamaModel.Status.Conditions, ollamamodel.ConditionStale), 0
+	}
+
+	condition := metav1.Condition{
+		Type:               ollamamodel.ConditionStale,
+		Status:             metav1.ConditionFalse,
+		Reason:             "PulledRecently",
+		Message:            fmt.Sprintf("The model was pulled less than %s ago", r.StaleThreshold),
+		ObservedGeneration: ollamaModel.Generation,
+	}
+	until := pulledAt.Add(r.StaleThreshold).Sub(now)
+	if until <= 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "PullTooOld"
+		condition.Message = fmt.Sprintf("The model was last pulled %s ago, longer than %s",
+			now.Sub(pulledAt).Round(time.Second), r.StaleThreshold)
+		until = 0
+	}
+
+	// Keep the message of an unchanged condition, so that the status is not
+	// rewritten on every reconcile as the age grows
+	if existing := meta.FindStatusCondition(ollamaModel.Status.Conditions, ollamamodel.ConditionStale); existing != nil &&
+		existing.Status == condition.Status {
+		return false, until
+	}
+	return meta.SetStatusCondition(&ollamaModel.Status.Conditions, condition), until
+}
+
+// readyResult requeues a Ready model at the next resync, or after until if
+// that is sooner, such as when it becomes stale
+func (r *OllamaModelReconciler) readyResult(until time.Duration) ctrl.Result {
+	resync := r.Settings.Get().ResyncInterval
+	if until > 0 && (resync == 0 || until < resync) {
+		return ctrl.Result{RequeueAfter: until}
+	}
+	return ctrl.Result{RequeueAfter: resync}
+}
+
+// nextCheck returns the sooner of until, a delay as returned by staleness, and
+// the delays until the next integrity and update checks of a model, including
+// the check of a new version held until its update window opens
+func (r *OllamaModelReconciler) nextCheck(ollamaModel *ollamamodel.OllamaModel, until time.Duration, now time.Time) time.Duration {
+	checks := []struct {
+		interval time.Duration
+		last     *metav1.Time
+	}{
+		{r.IntegrityCheckInterval, ollamaModel.Status.LastIntegrityCheckTime},
+		{r.updateCheckInterval(ollamaModel), ollamaModel.Status.LastUpdateCheckTime},
+	}
+	for _, check := range checks {
+		if check.interval <= 0 {
+			continue
+		}
+		// A model never checked, such as one pulled again, is checked right away
+		next := time.Second
+		if check.last != nil {
+			if d := check.last.Add(check.interval).Sub(now); d > 0 {
+				next = d
+			}
+		}
+		if until <= 0 || next < until {
+			until = next
+		}
+	}
+	if next := untilUpdateWindow(ollamaModel, now); next > 0 && (until <= 0 || next < until) {
+		until = next
+	}
+	return until
+}
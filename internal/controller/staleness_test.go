@@ -0,0 +1,68 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+var _ = Describe("Staleness", func() {
+	now := time.Now()
+	model := func(pulledAgo time.Duration) *ollamav1alpha1.OllamaModel {
+		pulledAt := metav1.NewTime(now.Add(-pulledAgo))
+		return &ollamav1alpha1.OllamaModel{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "staleness", Name: "llama3.2-1b"},
+			Status:     ollamav1alpha1.OllamaModelStatus{State: ollamav1alpha1.StateReady, LastPullTime: &pulledAt},
+		}
+	}
+
+	It("reports models pulled longer ago than the threshold as stale", func() {
+		r := &OllamaModelReconciler{StaleThreshold: 24 * time.Hour}
+
+		fresh := model(time.Hour)
+		changed, until := r.staleness(fresh, "llama3.2:1b", now)
+		Expect(changed).To(BeTrue())
+		Expect(until).To(Equal(23 * time.Hour))
+		Expect(meta.IsStatusConditionFalse(fresh.Status.Conditions, ollamav1alpha1.ConditionStale)).To(BeTrue())
+		Expect(r.readyResult(until).RequeueAfter).To(Equal(23 * time.Hour))
+
+		stale := model(48 * time.Hour)
+		changed, until = r.staleness(stale, "llama3.2:1b", now)
+		Expect(changed).To(BeTrue())
+		Expect(until).To(BeZero())
+		Expect(meta.IsStatusConditionTrue(stale.Status.Conditions, ollamav1alpha1.ConditionStale)).To(BeTrue())
+
+		changed, _ = r.staleness(stale, "llama3.2:1b", now.Add(time.Hour))
+		Expect(changed).To(BeFalse())
+	})
+
+	It("leaves the condition out without a threshold", func() {
+		r := &OllamaModelReconciler{}
+		m := model(48 * time.Hour)
+		changed, until := r.staleness(m, "llama3.2:1b", now)
+		Expect(changed).To(BeFalse())
+		Expect(until).To(BeZero())
+		Expect(m.Status.Conditions).To(BeEmpty())
+	})
+})
@@ -0,0 +1,135 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ollamamodel "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+// updateStatus writes the status of a model, deriving its observedGeneration
+// and kstatus conditions from its state first. The pull holder is only kept
+// while the model is being pulled, along with the time its pull was
+// interrupted, and its progress also once the pull failed, to be resumed from.
+func (r *OllamaModelReconciler) updateStatus(ctx context.Context, ollamaModel *ollamamodel.OllamaModel) error {
+	if state := ollamaModel.Status.State; state != ollamamodel.StatePulling && state != ollamamodel.StateFailed {
+		ollamaModel.Status.Progress = nil
+	}
+	if ollamaModel.Status.State != ollamamodel.StatePulling {
+		ollamaModel.Status.PullHolder = ""
+		ollamaModel.Status.PullStartTime = nil
+		ollamaModel.Status.PullInterruptedTime = nil
+	}
+	if status := &ollamaModel.Status; status.State == ollamamodel.StateReady && status.LastPullTime != nil {
+		// Models Ready before the first Ready time was recorded get the time
+		// of their last pull instead, without being timed
+		recordFirstReady(status, status.LastPullTime, metav1.Now())
+	}
+	ollamaModel.Status.ShortDigest = shortDigest(ollamaModel.Status.Digest)
+	ollamaModel.Status.Endpoints = r.endpoints(ollamaModel)
+	setStateConditions(ollamaModel)
+	return r.Status().Update(ctx, ollamaModel)
+}
+
+// endpoints returns the Ollama servers a model is managed on. The operator
+// manages a single server, on which the model has the state of the resource.
+func (r *OllamaModelReconciler) endpoints(ollamaModel *ollamamodel.OllamaModel) []ollamamodel.EndpointStatus {
+	if r.OllamaURL == "" {
+		return nil
+	}
+	return []ollamamodel.EndpointStatus{{
+		Name:   ollamamodel.DefaultEndpointName,
+		URL:    r.OllamaURL,
+		State:  ollamaModel.Status.State,
+		Digest: ollamaModel.Status.Digest,
+	}}
+}
+
+// shortDigest returns the prefix of a digest shown by kubectl get
+func shortDigest(digest string) string {
+	if len(digest) > 12 {
+		return digest[:12]
+	}
+	return digest
+}
+
+// setStateConditions sets the observedGeneration and the Ready, Reconciling and
+// Stalled conditions of a model to match its state. Reconciling and Stalled are
+// only present while they are True, as kstatus expects.
+func setStateConditions(ollamaModel *ollamamodel.OllamaModel) {
+	status := &ollamaModel.Status
+	status.ObservedGeneration = ollamaModel.Generation
+
+	condition := func(conditionType string, conditionStatus metav1.ConditionStatus, reason, message string) {
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               conditionType,
+			Status:             conditionStatus,
+			Reason:             reason,
+			Message:            message,
+			ObservedGeneration: ollamaModel.Generation,
+		})
+	}
+
+	switch status.State {
+	case ollamamodel.StateReady:
+		condition(ollamamodel.ConditionReady, metav1.ConditionTrue, ollamamodel.ReasonPulled, "The model is pulled and ready to use")
+		meta.RemoveStatusCondition(&status.Conditions, ollamamodel.ConditionReconciling)
+		meta.RemoveStatusCondition(&status.Conditions, ollamamodel.ConditionStalled)
+	case ollamamodel.StateFailed:
+		condition(ollamamodel.ConditionReady, metav1.ConditionFalse, ollamamodel.ReasonPullFailed, status.Error)
+		condition(ollamamodel.ConditionStalled, metav1.ConditionTrue, ollamamodel.ReasonPullFailed, status.Error)
+		meta.RemoveStatusCondition(&status.Conditions, ollamamodel.ConditionReconciling)
+	case ollamamodel.StateDeleting:
+		message := "The model is being deleted from Ollama"
+		if status.Error != "" {
+			message = "Failed to delete the model from Ollama: " + status.Error
+		}
+		condition(ollamamodel.ConditionReady, metav1.ConditionFalse, ollamamodel.ReasonDeleting, message)
+		condition(ollamamodel.ConditionReconciling, metav1.ConditionTrue, ollamamodel.ReasonDeleting, message)
+		meta.RemoveStatusCondition(&status.Conditions, ollamamodel.ConditionStalled)
+	case ollamamodel.StatePulling:
+		if status.PullInterruptedTime != nil {
+			message := interruptedMessage(status)
+			condition(ollamamodel.ConditionReady, metav1.ConditionFalse, ollamamodel.ReasonInterrupted, message)
+			condition(ollamamodel.ConditionReconciling, metav1.ConditionTrue, ollamamodel.ReasonInterrupted, message)
+			meta.RemoveStatusCondition(&status.Conditions, ollamamodel.ConditionStalled)
+			break
+		}
+		condition(ollamamodel.ConditionReady, metav1.ConditionFalse, ollamamodel.ReasonPulling, "The model is being pulled")
+		condition(ollamamodel.ConditionReconciling, metav1.ConditionTrue, ollamamodel.ReasonPulling, "The model is being pulled")
+		meta.RemoveStatusCondition(&status.Conditions, ollamamodel.ConditionStalled)
+	default:
+		condition(ollamamodel.ConditionReady, metav1.ConditionFalse, ollamamodel.ReasonPending, "The model is waiting to be pulled")
+		condition(ollamamodel.ConditionReconciling, metav1.ConditionTrue, ollamamodel.ReasonPending, "The model is waiting to be pulled")
+		meta.RemoveStatusCondition(&status.Conditions, ollamamodel.ConditionStalled)
+	}
+
+	if reason := gaveUp(ollamaModel); status.State == ollamamodel.StateFailed && reason != "" {
+		message := status.Error
+		if reason == ollamamodel.ReasonRetriesExhausted {
+			message = fmt.Sprintf("Gave up after %d failed pulls: %s", status.PullAttempts, status.Error)
+		}
+		condition(ollamamodel.ConditionTerminal, metav1.ConditionTrue, reason, message)
+	} else {
+		meta.RemoveStatusCondition(&status.Conditions, ollamamodel.ConditionTerminal)
+	}
+}
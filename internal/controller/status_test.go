@@ -0,0 +1,109 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+var _ = Describe("State conditions", func() {
+	model := func(state ollamav1alpha1.ModelState) *ollamav1alpha1.OllamaModel {
+		return &ollamav1alpha1.OllamaModel{
+			ObjectMeta: metav1.ObjectMeta{Generation: 3},
+			Status:     ollamav1alpha1.OllamaModelStatus{State: state, Error: "manifest unknown"},
+		}
+	}
+
+	It("reports pulls in progress as reconciling", func() {
+		m := model(ollamav1alpha1.StatePulling)
+		setStateConditions(m)
+		Expect(m.Status.ObservedGeneration).To(Equal(int64(3)))
+		Expect(meta.IsStatusConditionFalse(m.Status.Conditions, ollamav1alpha1.ConditionReady)).To(BeTrue())
+		Expect(meta.IsStatusConditionTrue(m.Status.Conditions, ollamav1alpha1.ConditionReconciling)).To(BeTrue())
+	})
+
+	It("tells how a pull interrupted by a shutdown resumes", func() {
+		m := model(ollamav1alpha1.StatePulling)
+		m.Status.PullInterruptedTime = &metav1.Time{}
+		m.Status.Progress = &ollamav1alpha1.PullProgress{Percent: 40, CompletedBytes: 16 << 30, TotalBytes: 40 << 30}
+		setStateConditions(m)
+
+		ready := meta.FindStatusCondition(m.Status.Conditions, ollamav1alpha1.ConditionReady)
+		Expect(ready.Reason).To(Equal(ollamav1alpha1.ReasonInterrupted))
+		Expect(ready.Message).To(ContainSubstring("at 40%"))
+		Expect(meta.IsStatusConditionTrue(m.Status.Conditions, ollamav1alpha1.ConditionReconciling)).To(BeTrue())
+	})
+
+	It("replaces reconciling with stalled when a pull fails", func() {
+		m := model(ollamav1alpha1.StatePulling)
+		setStateConditions(m)
+		m.Status.State = ollamav1alpha1.StateFailed
+		setStateConditions(m)
+
+		stalled := meta.FindStatusCondition(m.Status.Conditions, ollamav1alpha1.ConditionStalled)
+		Expect(stalled).NotTo(BeNil())
+		Expect(stalled.Message).To(Equal("manifest unknown"))
+		Expect(meta.FindStatusCondition(m.Status.Conditions, ollamav1alpha1.ConditionReconciling)).To(BeNil())
+	})
+
+	It("only keeps Ready once the model is ready", func() {
+		m := model(ollamav1alpha1.StateFailed)
+		setStateConditions(m)
+		m.Status.State = ollamav1alpha1.StateReady
+		setStateConditions(m)
+
+		Expect(m.Status.Conditions).To(HaveLen(1))
+		Expect(meta.IsStatusConditionTrue(m.Status.Conditions, ollamav1alpha1.ConditionReady)).To(BeTrue())
+	})
+
+	It("reports deletions as reconciling and no longer ready", func() {
+		m := model(ollamav1alpha1.StateReady)
+		setStateConditions(m)
+		m.Status.State = ollamav1alpha1.StateDeleting
+		setStateConditions(m)
+
+		ready := meta.FindStatusCondition(m.Status.Conditions, ollamav1alpha1.ConditionReady)
+		Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+		Expect(ready.Reason).To(Equal(ollamav1alpha1.ReasonDeleting))
+		Expect(ready.Message).To(ContainSubstring("manifest unknown"))
+		Expect(meta.IsStatusConditionTrue(m.Status.Conditions, ollamav1alpha1.ConditionReconciling)).To(BeTrue())
+	})
+
+	It("shortens digests for kubectl get", func() {
+		Expect(shortDigest("6d1a6a2ba5ef2f6fbf2ab4d1a03e5c4ed2de6ac1c6e8e3f40c0a9e1bc6ab0c42")).To(Equal("6d1a6a2ba5ef"))
+		Expect(shortDigest("")).To(BeEmpty())
+	})
+
+	It("reports the model on the Ollama server as its endpoint", func() {
+		m := model(ollamav1alpha1.StateReady)
+		m.Status.Digest = "6d1a6a2ba5ef2f6fbf2ab4d1a03e5c4ed2de6ac1c6e8e3f40c0a9e1bc6ab0c42"
+
+		Expect((&OllamaModelReconciler{}).endpoints(m)).To(BeEmpty())
+		r := &OllamaModelReconciler{OllamaURL: "http://ollama.ollama:11434"}
+		Expect(r.endpoints(m)).To(ConsistOf(ollamav1alpha1.EndpointStatus{
+			Name:   ollamav1alpha1.DefaultEndpointName,
+			URL:    "http://ollama.ollama:11434",
+			State:  ollamav1alpha1.StateReady,
+			Digest: m.Status.Digest,
+		}))
+	})
+})
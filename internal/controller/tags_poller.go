@@ -0,0 +1,157 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ollama/ollama/api"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	ollamamodel "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+// ModelLister lists the models stored on the Ollama server
+type ModelLister interface {
+	List(ctx context.Context) (*api.ListResponse, error)
+}
+
+// TagsPoller polls the models stored on the Ollama server and reconciles the
+// Ready OllamaModels whose model was deleted or replaced outside the operator,
+// rather than waiting for their next resync
+type TagsPoller struct {
+	client   client.Reader
+	ollama   ModelLister
+	interval time.Duration
+	// invalidate drops the cached details of a model before it is reconciled
+	invalidate func(model string)
+	events     chan event.GenericEvent
+}
+
+// NewTagsPoller creates a poller listing the models of the Ollama server every
+// interval. invalidate, if not nil, is called with the models found changed so
+// that their reconcile does not read cached details.
+func NewTagsPoller(c client.Reader, ollama ModelLister, interval time.Duration, invalidate func(model string)) *TagsPoller {
+	return &TagsPoller{
+		client:     c,
+		ollama:     ollama,
+		interval:   interval,
+		invalidate: invalidate,
+		events:     make(chan event.GenericEvent, 64),
+	}
+}
+
+// Source returns the source of the reconciles triggered by the poller
+func (p *TagsPoller) Source() source.Source {
+	return source.Channel(p.events, &handler.EnqueueRequestForObject{})
+}
+
+// Start implements manager.Runnable
+func (p *TagsPoller) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("tags-poller")
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		if err := p.poll(ctx); err != nil {
+			logger.Error(err, "failed to poll Ollama models")
+		}
+	}
+}
+
+// poll triggers a reconcile of every model that drifted
+func (p *TagsPoller) poll(ctx context.Context) error {
+	stored, err := p.ollama.List(ctx)
+	if err != nil {
+		return err
+	}
+	var models ollamamodel.OllamaModelList
+	if err := p.client.List(ctx, &models); err != nil {
+		return err
+	}
+
+	for _, model := range driftedModels(stored.Models, models.Items) {
+		log.FromContext(ctx).Info("model changed on the Ollama server, reconciling it",
+			"namespace", model.Namespace, "name", model.Name, "model", model.Spec.Reference())
+		if p.invalidate != nil {
+			p.invalidate(model.Spec.Reference())
+		}
+		select {
+		case p.events <- event.GenericEvent{Object: model}:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+	return nil
+}
+
+// driftedModels returns the Ready models that are missing from the stored
+// models or stored with another digest than the one they were pulled with
+func driftedModels(stored []api.ListModelResponse, models []ollamamodel.OllamaModel) []*ollamamodel.OllamaModel {
+	digests := make(map[string]string, len(stored))
+	for _, model := range stored {
+		digests[ollamamodel.NormalizeModelReference(model.Name)] = model.Digest
+	}
+
+	var drifted []*ollamamodel.OllamaModel
+	for i := range models {
+		model := &models[i]
+		if model.Status.State != ollamamodel.StateReady || !model.DeletionTimestamp.IsZero() {
+			continue
+		}
+		digest, found := digests[ollamamodel.NormalizeModelReference(model.Spec.Reference())]
+		if !found || digestChanged(model, digest) {
+			drifted = append(drifted, model)
+		}
+	}
+	return drifted
+}
+
+// digestChanged reports whether a model is stored with another digest than
+// the one recorded when it was pulled
+func digestChanged(model *ollamamodel.OllamaModel, digest string) bool {
+	return digest != "" && model.Status.Digest != "" && digest != model.Status.Digest
+}
+
+// storedDigestChanged reports whether a Ready model was replaced on the Ollama
+// server outside the operator, such as by an `ollama pull` of a newer version.
+// The model is left alone when the models cannot be listed.
+func (r *OllamaModelReconciler) storedDigestChanged(ctx context.Context, ollamaModel *ollamamodel.OllamaModel, modelName string) bool {
+	listResp, err := r.Ollama.List(ctx)
+	if err != nil {
+		return false
+	}
+	for _, model := range listResp.Models {
+		if ollamamodel.SameModel(model.Name, modelName) && digestChanged(ollamaModel, model.Digest) {
+			r.Recorder.Event(ollamaModel, "Normal", "DigestChanged",
+				fmt.Sprintf("%s was replaced on the Ollama server with digest %s", modelName, model.Digest))
+			return true
+		}
+	}
+	return false
+}
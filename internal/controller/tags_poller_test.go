@@ -0,0 +1,76 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/ollama/ollama/api"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+var _ = Describe("TagsPoller", func() {
+	model := func(name, tag, digest string, state ollamav1alpha1.ModelState) ollamav1alpha1.OllamaModel {
+		return ollamav1alpha1.OllamaModel{
+			ObjectMeta: metav1.ObjectMeta{Name: name + "-" + tag, Namespace: "default"},
+			Spec:       ollamav1alpha1.OllamaModelSpec{Name: name, Tag: tag},
+			Status:     ollamav1alpha1.OllamaModelStatus{State: state, Digest: digest},
+		}
+	}
+
+	It("finds Ready models deleted or replaced on the Ollama server", func() {
+		stored := []api.ListModelResponse{
+			{Name: "llama3.2:1b", Digest: "aaa"},
+			{Name: "gemma3:1b", Digest: "new"},
+		}
+		drifted := driftedModels(stored, []ollamav1alpha1.OllamaModel{
+			model("llama3.2", "1b", "aaa", ollamav1alpha1.StateReady),
+			model("gemma3", "1b", "old", ollamav1alpha1.StateReady),
+			model("phi3", "mini", "ccc", ollamav1alpha1.StateReady),
+			model("mistral", "7b", "", ollamav1alpha1.StatePulling),
+		})
+
+		var names []string
+		for _, m := range drifted {
+			names = append(names, m.Name)
+		}
+		Expect(names).To(Equal([]string{"gemma3-1b", "phi3-mini"}))
+	})
+
+	It("matches qualified references with the names Ollama lists", func() {
+		stored := []api.ListModelResponse{
+			{Name: "llama3.2:1b", Digest: "aaa"},
+			{Name: "registry.example.com/team/model:1b", Digest: "bbb"},
+		}
+		drifted := driftedModels(stored, []ollamav1alpha1.OllamaModel{
+			model("registry.ollama.ai/library/llama3.2", "1b", "aaa", ollamav1alpha1.StateReady),
+			model("registry.example.com/team/model", "1b", "bbb", ollamav1alpha1.StateReady),
+			model("registry.example.com/other/model", "1b", "bbb", ollamav1alpha1.StateReady),
+		})
+		Expect(drifted).To(HaveLen(1))
+		Expect(drifted[0].Spec.Name).To(Equal("registry.example.com/other/model"))
+	})
+
+	It("does not treat an unknown digest as a change", func() {
+		Expect(digestChanged(&ollamav1alpha1.OllamaModel{}, "aaa")).To(BeFalse())
+		ready := model("llama3.2", "1b", "aaa", ollamav1alpha1.StateReady)
+		Expect(digestChanged(&ready, "")).To(BeFalse())
+		Expect(digestChanged(&ready, "bbb")).To(BeTrue())
+	})
+})
@@ -0,0 +1,183 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ollamamodel "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+// updateCheckInterval returns how often the registry is checked for a new
+// version of a model, or 0 when it is not. Restored models are left out, since
+// they are not pulled from the registry.
+func (r *OllamaModelReconciler) updateCheckInterval(ollamaModel *ollamamodel.OllamaModel) time.Duration {
+	if r.Registry == nil || ollamaModel.Spec.RestoreFrom != nil {
+		return 0
+	}
+	return r.UpdateCheckInterval
+}
+
+// updateCheckDue reports whether a Ready model is due for an update check
+func (r *OllamaModelReconciler) updateCheckDue(ollamaModel *ollamamodel.OllamaModel, now time.Time) bool {
+	interval := r.updateCheckInterval(ollamaModel)
+	if interval <= 0 || ollamaModel.Status.State != ollamamodel.StateReady {
+		return false
+	}
+	checked := ollamaModel.Status.LastUpdateCheckTime
+	if checked == nil || !now.Before(checked.Add(interval)) {
+		return true
+	}
+	// A new version held outside of the update window is checked again once
+	// the window opens, so that it is pulled right after the check
+	return updateHeld(ollamaModel, checked.Time) && updateWindowOpen(ollamaModel, now)
+}
+
+// checkForUpdate resolves the model's tag in the registry and records the
+// digest served in status.latestDigest, without pulling anything. A registry
+// that cannot be reached leaves the digest alone until the next check.
+func (r *OllamaModelReconciler) checkForUpdate(ctx context.Context, ollamaModel *ollamamodel.OllamaModel, modelName string) {
+	now := metav1.Now()
+	ollamaModel.Status.LastUpdateCheckTime = &now
+
+	reference := ollamaModel.Spec.ParsedReference()
+	ctx, cancel := context.WithTimeout(ctx, registryLookupTimeout)
+	defer cancel()
+	_, manifest, err := r.Registry.Resolve(ctx, reference.Name(), reference.Tag)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "failed to check the registry for a new version", "model", modelName)
+		return
+	}
+	latest := strings.TrimPrefix(manifest.Digest, "sha256:")
+	if latest != ollamaModel.Status.LatestDigest && latest != ollamaModel.Status.Digest {
+		r.Recorder.Event(ollamaModel, "Normal", "NewVersionAvailable",
+			fmt.Sprintf("The registry serves %s with digest %s, refresh the model to pull it", modelName, latest))
+	}
+	ollamaModel.Status.LatestDigest = latest
+}
+
+// autoUpdate reports whether a model should be refreshed to the new version
+// found by the update check: its update policy is Auto and it is not pinned to
+// a digest
+func autoUpdate(ollamaModel *ollamamodel.OllamaModel) bool {
+	return ollamaModel.Spec.UpdatePolicy == ollamamodel.UpdatePolicyAuto &&
+		ollamaModel.Annotations[ollamamodel.DigestAnnotation] == "" &&
+		meta.IsStatusConditionTrue(ollamaModel.Status.Conditions, ollamamodel.ConditionNewVersionAvailable)
+}
+
+// updateWindowState reports whether the Auto update policy may refresh a model
+// at now, and when that next changes. Models without an update window may
+// always be refreshed.
+func updateWindowState(ollamaModel *ollamamodel.OllamaModel, now time.Time) (bool, time.Time, error) {
+	window := ollamaModel.Spec.UpdateWindow
+	if window == nil {
+		return true, time.Time{}, nil
+	}
+	return windowState(window.TimeZone, window.Windows, now)
+}
+
+// updateWindowOpen reports whether the Auto update policy may refresh a model
+// at now. An invalid update window never opens.
+func updateWindowOpen(ollamaModel *ollamamodel.OllamaModel, now time.Time) bool {
+	open, _, err := updateWindowState(ollamaModel, now)
+	return err == nil && open
+}
+
+// updateHeld reports whether the Auto update policy of a model holds back the
+// new version found by the check at checked, outside of its update window
+func updateHeld(ollamaModel *ollamamodel.OllamaModel, checked time.Time) bool {
+	return ollamaModel.Spec.UpdateWindow != nil && autoUpdate(ollamaModel) && !updateWindowOpen(ollamaModel, checked)
+}
+
+// inUpdateWindow reports whether the Auto update policy may refresh a model at
+// now to the new version just found. A version held back outside of the update
+// window is logged, and an invalid window reported with an event.
+func (r *OllamaModelReconciler) inUpdateWindow(ctx context.Context, ollamaModel *ollamamodel.OllamaModel, modelName string, now time.Time) bool {
+	open, next, err := updateWindowState(ollamaModel, now)
+	if err != nil {
+		r.Recorder.Event(ollamaModel, "Warning", "InvalidUpdateWindow",
+			fmt.Sprintf("Not refreshing %s to digest %s: %v", modelName, ollamaModel.Status.LatestDigest, err))
+		return false
+	}
+	if !open {
+		log.FromContext(ctx).Info("new version available, holding it until the update window opens", "name", ollamaModel.Name,
+			"model", modelName, "digest", ollamaModel.Status.LatestDigest, "opens", next)
+	}
+	return open
+}
+
+// untilUpdateWindow returns how long until the update window of a model holding
+// back a new version opens, or 0 when it holds back none
+func untilUpdateWindow(ollamaModel *ollamamodel.OllamaModel, now time.Time) time.Duration {
+	checked := ollamaModel.Status.LastUpdateCheckTime
+	if checked == nil || !updateHeld(ollamaModel, checked.Time) {
+		return 0
+	}
+	open, next, err := updateWindowState(ollamaModel, now)
+	if err != nil || open {
+		return 0
+	}
+	return next.Sub(now)
+}
+
+// setNewVersionAvailable sets the NewVersionAvailable condition and metric of a
+// Ready model from the digest found by the last update check. It reports
+// whether the condition changed.
+func (r *OllamaModelReconciler) setNewVersionAvailable(ollamaModel *ollamamodel.OllamaModel, modelName string) bool {
+	if r.updateCheckInterval(ollamaModel) <= 0 {
+		forgetUpdate(types.NamespacedName{Namespace: ollamaModel.Namespace, Name: ollamaModel.Name})
+		return meta.RemoveStatusCondition(&ollamaModel.Status.Conditions, ollamamodel.ConditionNewVersionAvailable)
+	}
+	latest := ollamaModel.Status.LatestDigest
+	if latest == "" {
+		return false
+	}
+
+	condition := metav1.Condition{
+		Type:               ollamamodel.ConditionNewVersionAvailable,
+		Status:             metav1.ConditionFalse,
+		Reason:             "UpToDate",
+		Message:            "The registry serves the version of the model that was pulled",
+		ObservedGeneration: ollamaModel.Generation,
+	}
+	value := 0.0
+	if latest != ollamaModel.Status.Digest {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "NewerDigest"
+		condition.Message = fmt.Sprintf("The registry serves %s with digest %s, while %s was pulled",
+			modelName, latest, ollamaModel.Status.Digest)
+		value = 1
+	}
+	// Drop the series of a previous tag of the model
+	forgetUpdate(types.NamespacedName{Namespace: ollamaModel.Namespace, Name: ollamaModel.Name})
+	updateAvailable.With(prometheus.Labels{"namespace": ollamaModel.Namespace, "name": ollamaModel.Name, "model": modelName}).Set(value)
+	return meta.SetStatusCondition(&ollamaModel.Status.Conditions, condition)
+}
+
+// forgetUpdate stops exporting whether a new version of a model is available
+func forgetUpdate(key types.NamespacedName) {
+	updateAvailable.DeletePartialMatch(prometheus.Labels{"namespace": key.Namespace, "name": key.Name})
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+	"github.com/dmk/ollama-operator/internal/registry"
+)
+
+var _ = Describe("Update checks", func() {
+	ctx := context.Background()
+	pulled := strings.Repeat("a", 64)
+	published := strings.Repeat("b", 64)
+
+	var (
+		latest   string
+		recorder *record.FakeRecorder
+		r        *OllamaModelReconciler
+		model    *ollamav1alpha1.OllamaModel
+	)
+
+	BeforeEach(func() {
+		latest = published
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if req.URL.Path != "/v2/library/llama3.2/manifests/1b" {
+				http.NotFound(w, req)
+				return
+			}
+			w.Header().Set("Docker-Content-Digest", "sha256:"+latest)
+			_, _ = w.Write([]byte(`{"config":{"size":1},"layers":[{"size":2}]}`))
+		}))
+		DeferCleanup(server.Close)
+
+		recorder = record.NewFakeRecorder(10)
+		r = &OllamaModelReconciler{
+			Recorder:            recorder,
+			Registry:            registry.NewClient([]string{server.URL}, server.Client()),
+			UpdateCheckInterval: time.Hour,
+		}
+		model = &ollamav1alpha1.OllamaModel{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "updates", Name: "llama3.2-1b"},
+			Spec:       ollamav1alpha1.OllamaModelSpec{Name: "llama3.2", Tag: "1b"},
+			Status:     ollamav1alpha1.OllamaModelStatus{State: ollamav1alpha1.StateReady, Digest: pulled},
+		}
+	})
+
+	It("reports a new version published in the registry without pulling it", func() {
+		Expect(r.updateCheckDue(model, time.Now())).To(BeTrue())
+		r.checkForUpdate(ctx, model, "llama3.2:1b")
+		Expect(model.Status.LatestDigest).To(Equal(published))
+		Expect(r.updateCheckDue(model, time.Now())).To(BeFalse())
+		Expect(recorder.Events).To(Receive(ContainSubstring("NewVersionAvailable")))
+
+		Expect(r.setNewVersionAvailable(model, "llama3.2:1b")).To(BeTrue())
+		Expect(meta.IsStatusConditionTrue(model.Status.Conditions, ollamav1alpha1.ConditionNewVersionAvailable)).To(BeTrue())
+		Expect(testutil.ToFloat64(updateAvailable.WithLabelValues("updates", "llama3.2-1b", "llama3.2:1b"))).To(BeEquivalentTo(1))
+
+		// A refresh pulls the published version
+		model.Status.Digest = published
+		Expect(r.setNewVersionAvailable(model, "llama3.2:1b")).To(BeTrue())
+		Expect(meta.IsStatusConditionFalse(model.Status.Conditions, ollamav1alpha1.ConditionNewVersionAvailable)).To(BeTrue())
+		Expect(testutil.ToFloat64(updateAvailable.WithLabelValues("updates", "llama3.2-1b", "llama3.2:1b"))).To(BeZero())
+	})
+
+	It("reports an up to date model", func() {
+		latest = pulled
+		r.checkForUpdate(ctx, model, "llama3.2:1b")
+		Expect(recorder.Events).NotTo(Receive())
+		r.setNewVersionAvailable(model, "llama3.2:1b")
+		Expect(meta.IsStatusConditionFalse(model.Status.Conditions, ollamav1alpha1.ConditionNewVersionAvailable)).To(BeTrue())
+	})
+
+	It("refreshes models with the Auto update policy unless they are pinned", func() {
+		r.checkForUpdate(ctx, model, "llama3.2:1b")
+		r.setNewVersionAvailable(model, "llama3.2:1b")
+		Expect(autoUpdate(model)).To(BeFalse())
+
+		model.Spec.UpdatePolicy = ollamav1alpha1.UpdatePolicyAuto
+		Expect(autoUpdate(model)).To(BeTrue())
+
+		model.Annotations = map[string]string{ollamav1alpha1.DigestAnnotation: pulled}
+		Expect(autoUpdate(model)).To(BeFalse())
+	})
+
+	It("holds refreshes outside of the update window", func() {
+		r.UpdateCheckInterval = 24 * time.Hour
+		model.Spec.UpdatePolicy = ollamav1alpha1.UpdatePolicyAuto
+		model.Spec.UpdateWindow = &ollamav1alpha1.UpdateWindow{
+			Windows: []ollamav1alpha1.ScheduleWindow{{Start: "02:00", End: "04:00"}},
+		}
+		at := func(day, hour int) time.Time { return time.Date(2025, 3, day, hour, 0, 0, 0, time.UTC) }
+
+		r.checkForUpdate(ctx, model, "llama3.2:1b")
+		r.setNewVersionAvailable(model, "llama3.2:1b")
+		model.Status.LastUpdateCheckTime = &metav1.Time{Time: at(12, 12)}
+		Expect(autoUpdate(model)).To(BeTrue())
+		Expect(r.inUpdateWindow(ctx, model, "llama3.2:1b", at(12, 12))).To(BeFalse())
+
+		// The model is checked again, and refreshed, once the window opens
+		Expect(r.nextCheck(model, 0, at(12, 12))).To(Equal(14 * time.Hour))
+		Expect(r.updateCheckDue(model, at(12, 23))).To(BeFalse())
+		Expect(r.updateCheckDue(model, at(13, 2))).To(BeTrue())
+		Expect(r.inUpdateWindow(ctx, model, "llama3.2:1b", at(13, 2))).To(BeTrue())
+
+		// A version found during the window is not held
+		model.Status.LastUpdateCheckTime = &metav1.Time{Time: at(13, 2)}
+		Expect(r.updateCheckDue(model, at(13, 3))).To(BeFalse())
+		Expect(r.nextCheck(model, 0, at(13, 3))).To(Equal(23 * time.Hour))
+	})
+
+	It("refreshes no model with an invalid update window", func() {
+		model.Spec.UpdatePolicy = ollamav1alpha1.UpdatePolicyAuto
+		model.Spec.UpdateWindow = &ollamav1alpha1.UpdateWindow{
+			TimeZone: "Mars/Olympus",
+			Windows:  []ollamav1alpha1.ScheduleWindow{{Start: "02:00", End: "04:00"}},
+		}
+		Expect(r.inUpdateWindow(ctx, model, "llama3.2:1b", time.Now())).To(BeFalse())
+		Expect(recorder.Events).To(Receive(ContainSubstring("InvalidUpdateWindow")))
+	})
+
+	It("leaves restored models and disabled checks alone", func() {
+		model.Spec.RestoreFrom = &ollamav1alpha1.SnapshotReference{Name: "exp-42"}
+		Expect(r.updateCheckDue(model, time.Now())).To(BeFalse())
+
+		model.Spec.RestoreFrom = nil
+		model.Status.LatestDigest = published
+		r.setNewVersionAvailable(model, "llama3.2:1b")
+		r.UpdateCheckInterval = 0
+		Expect(r.setNewVersionAvailable(model, "llama3.2:1b")).To(BeTrue())
+		Expect(model.Status.Conditions).To(BeEmpty())
+	})
+})
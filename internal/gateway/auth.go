@@ -0,0 +1,114 @@
+package gateway
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	httpapi "github.com/dmk/ollama-operator/internal/api"
+)
+
+// Reasons inference requests fail to authenticate, as in the metrics of the
+// API servers
+const (
+	reasonMissingKey = "missing_key"
+	reasonInvalidKey = "invalid_key"
+	reasonLockedOut  = "locked_out"
+)
+
+// Errors of the inference requests refused before they are resolved
+var (
+	errUnauthenticated  = errors.New("a valid API key is required")
+	errLockedOut        = errors.New("too many failed authentications from this address, retry later")
+	errSourceNotAllowed = errors.New("the client address is not allowed to use the gateway")
+)
+
+// Keyring authenticates inference requests with the API keys of the API
+// servers, which the keyring of the api package holds
+type Keyring interface {
+	// Enabled reports whether a key is required
+	Enabled() bool
+	// KeyNamespaces returns whether value is a valid key, and the namespaces
+	// it may reach, empty for every namespace
+	KeyNamespaces(value string) ([]string, bool)
+}
+
+// caller is the key of an inference request
+type caller struct {
+	// namespaces are the namespaces the key may reach; empty allows every
+	// namespace
+	namespaces []string
+}
+
+// reaches reports whether the caller may use the models of namespace
+func (c caller) reaches(namespace string) bool {
+	return len(c.namespaces) == 0 || slices.Contains(c.namespaces, namespace)
+}
+
+// sourceIP returns the IP address of the client that sent r, from the
+// X-Forwarded-For header behind trusted proxies
+func (g *Gateway) sourceIP(r *http.Request) string {
+	return httpapi.ClientIP(g.config.TrustedProxies, r.RemoteAddr, r.Header.Values("X-Forwarded-For"))
+}
+
+// authenticate returns the caller of a request, given its key as X-API-Key or
+// as a bearer token, the way Ollama clients send it. The key is removed from
+// the request, so that it does not reach the Ollama server. Requests from
+// outside of the allowed networks, from locked out sources or without a valid
+// key are answered here, and authenticate reports they must go no further.
+func (g *Gateway) authenticate(w http.ResponseWriter, r *http.Request) (caller, bool) {
+	source := g.sourceIP(r)
+	if !httpapi.SourceAllowed(g.config.AllowedNetworks, source) {
+		sourceDeniedTotal.Inc()
+		sendError(w, errSourceNotAllowed, http.StatusForbidden)
+		return caller{}, false
+	}
+	if g.config.Keyring == nil || !g.config.Keyring.Enabled() {
+		return caller{}, true
+	}
+
+	if remaining, locked := g.config.Lockout.Locked(source); locked {
+		authenticationFailedTotal.WithLabelValues(reasonLockedOut).Inc()
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(remaining.Seconds()))))
+		sendError(w, errLockedOut, http.StatusTooManyRequests)
+		return caller{}, false
+	}
+
+	value := r.Header.Get("X-API-Key")
+	if value == "" {
+		value, _ = strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	}
+	r.Header.Del("X-API-Key")
+	r.Header.Del("Authorization")
+
+	var namespaces []string
+	ok := false
+	if value != "" {
+		namespaces, ok = g.config.Keyring.KeyNamespaces(value)
+	}
+	if !ok {
+		g.authenticationFailed(r, source, value)
+		sendError(w, errUnauthenticated, http.StatusUnauthorized)
+		return caller{}, false
+	}
+	g.config.Lockout.Succeeded(source)
+	return caller{namespaces: namespaces}, true
+}
+
+// authenticationFailed counts a request without a valid API key from source,
+// and locks the source out after too many of them
+func (g *Gateway) authenticationFailed(r *http.Request, source, value string) {
+	reason := reasonInvalidKey
+	if value == "" {
+		reason = reasonMissingKey
+	}
+	authenticationFailedTotal.WithLabelValues(reason).Inc()
+	if g.config.Lockout.Failed(source) {
+		log.FromContext(r.Context()).Info("locking out source after repeated failed authentications", "source", source)
+	}
+}
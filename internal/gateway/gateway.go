@@ -0,0 +1,433 @@
+// Package gateway proxies inference requests to the Ollama server for the
+// models managed by OllamaModels. Requests name a model the way Ollama does,
+// such as "llama3.2:1b", or by an OllamaModelAlias; the gateway resolves it to
+// the OllamaModel managing it or the alias points at, forwards the request to
+// the model the controller serves it as, and records usage metrics labeled
+// with the OllamaModel.
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/netip"
+	"net/url"
+	"sort"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+	httpapi "github.com/dmk/ollama-operator/internal/api"
+)
+
+// DefaultMaxBodyBytes is the default limit on the size of request bodies
+const DefaultMaxBodyBytes = 10 << 20
+
+// DefaultWriteTimeout is the default limit on the time to write a response,
+// or a part of a streamed one
+const DefaultWriteTimeout = 30 * time.Second
+
+// statusClientClosedRequest is recorded for requests whose client went away
+// before the response was complete, following the nginx convention
+const statusClientClosedRequest = 499
+
+// randInt32N draws the models of splits; tests replace it
+var randInt32N = rand.Int32N
+
+// Paths are the Ollama endpoints the gateway proxies
+var Paths = []string{"/api/generate", "/api/chat", "/api/embed", "/api/embeddings"}
+
+// Config configures the gateway
+type Config struct {
+	// BindAddress is the address the gateway listens on
+	BindAddress string
+	// OllamaURL is the address of the Ollama server
+	OllamaURL *url.URL
+	// Transport reaches the Ollama server; nil uses http.DefaultTransport.
+	// Requests carry the credentials it adds, so it must only add them when
+	// Keyring authenticates the requests.
+	Transport http.RoundTripper
+	// Keyring authenticates the requests by their API key; nil or a disabled
+	// keyring serves every request
+	Keyring Keyring
+	// Lockout refuses the requests of the sources that failed to
+	// authenticate too many times in a row. The API servers share it, so
+	// that keys cannot be guessed through the gateway instead; nil never
+	// locks a source out.
+	Lockout *httpapi.Lockout
+	// AllowedNetworks restricts the clients of the gateway to these
+	// networks; empty allows every client
+	AllowedNetworks []netip.Prefix
+	// TrustedProxies are the networks of the reverse proxies whose
+	// X-Forwarded-For header tells the client address
+	TrustedProxies []netip.Prefix
+	// TLS serves the gateway over TLS; nil serves plain HTTP
+	TLS *httpapi.ServerTLS
+	// MaxBodyBytes caps the size of request bodies; 0 uses DefaultMaxBodyBytes
+	MaxBodyBytes int64
+	// WriteTimeout bounds the time to write a response; 0 uses
+	// DefaultWriteTimeout. Inference responses stream for as long as the model
+	// generates, so it bounds each of their writes instead, which releases the
+	// model when a client stops reading.
+	WriteTimeout time.Duration
+}
+
+// Gateway is an inference proxy in front of the Ollama server
+type Gateway struct {
+	config   Config
+	client   client.Client
+	proxy    *httputil.ReverseProxy
+	mux      *http.ServeMux
+	limiters limiters
+}
+
+// New creates a gateway resolving models with c, which must be able to list
+// OllamaModels by the ModelReferenceField index
+func New(config Config, c client.Client) *Gateway {
+	if config.MaxBodyBytes == 0 {
+		config.MaxBodyBytes = DefaultMaxBodyBytes
+	}
+	if config.WriteTimeout == 0 {
+		config.WriteTimeout = DefaultWriteTimeout
+	}
+	g := &Gateway{config: config, client: c, mux: http.NewServeMux()}
+
+	g.proxy = httputil.NewSingleHostReverseProxy(config.OllamaURL)
+	g.proxy.Transport = config.Transport
+	// Pass tokens through as Ollama generates them
+	g.proxy.FlushInterval = -1
+	g.proxy.ModifyResponse = func(resp *http.Response) error {
+		if u, ok := resp.Request.Context().Value(usageKey{}).(*usage); ok {
+			resp.Body = &usageReader{ReadCloser: resp.Body, usage: u}
+		}
+		return nil
+	}
+	g.proxy.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+		// A client that went away cancels its request to the Ollama server,
+		// which stops generating; there is nobody left to answer
+		if r.Context().Err() != nil {
+			log.FromContext(r.Context()).V(1).Info("client disconnected", "path", r.URL.Path)
+			w.WriteHeader(statusClientClosedRequest)
+			return
+		}
+		log.FromContext(r.Context()).Error(err, "failed to proxy request to the Ollama server", "path", r.URL.Path)
+		sendError(w, fmt.Errorf("the Ollama server cannot be reached: %w", err), http.StatusBadGateway)
+	}
+
+	for _, path := range Paths {
+		g.mux.HandleFunc(path, g.serveInference)
+	}
+	return g
+}
+
+// ServeHTTP implements http.Handler
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.mux.ServeHTTP(w, r)
+}
+
+// Start serves the gateway until ctx is cancelled
+func (g *Gateway) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("gateway")
+	logger.Info("starting inference gateway", "address", g.config.BindAddress)
+
+	server := &http.Server{
+		Addr:              g.config.BindAddress,
+		Handler:           g,
+		BaseContext:       func(net.Listener) context.Context { return log.IntoContext(context.Background(), logger) },
+		ReadHeaderTimeout: 10 * time.Second,
+		WriteTimeout:      g.config.WriteTimeout,
+	}
+
+	go func() {
+		<-ctx.Done()
+		logger.Info("shutting down inference gateway")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	var err error
+	if g.config.TLS != nil {
+		server.TLSConfig = g.config.TLS.Config()
+		err = server.ListenAndServeTLS("", "")
+	} else {
+		err = server.ListenAndServe()
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface.
+// Every replica serves inference requests.
+func (g *Gateway) NeedLeaderElection() bool {
+	return false
+}
+
+// serveInference resolves the model of an inference request and proxies it
+func (g *Gateway) serveInference(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		sendError(w, fmt.Errorf("method %s not allowed", r.Method), http.StatusMethodNotAllowed)
+		return
+	}
+	caller, ok := g.authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, g.config.MaxBodyBytes))
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			sendError(w, fmt.Errorf("request body exceeds %d bytes", maxErr.Limit), http.StatusRequestEntityTooLarge)
+		} else {
+			sendError(w, err, http.StatusBadRequest)
+		}
+		return
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(body, &fields); err != nil {
+		sendError(w, fmt.Errorf("invalid request body: %w", err), http.StatusBadRequest)
+		return
+	}
+	var name string
+	if err := json.Unmarshal(fields["model"], &name); err != nil || name == "" {
+		sendError(w, errors.New("model is required"), http.StatusBadRequest)
+		return
+	}
+
+	model, alias, err := g.resolve(r.Context(), name, caller)
+	if err != nil {
+		log.FromContext(r.Context()).Error(err, "failed to resolve model", "model", name)
+		sendError(w, err, http.StatusInternalServerError)
+		return
+	}
+	if model == nil {
+		sendError(w, fmt.Errorf("model %q is not managed by the operator", name), http.StatusNotFound)
+		return
+	}
+	if model.Status.State != ollamav1alpha1.StateReady {
+		sendError(w, fmt.Errorf("model %q is not ready: %s", name, model.Status.State), http.StatusServiceUnavailable)
+		return
+	}
+
+	release, exceeded := g.limiters.acquire(model)
+	if exceeded != "" {
+		throttledTotal.WithLabelValues(model.Namespace, model.Name, exceeded).Inc()
+		w.Header().Set("Retry-After", "1")
+		sendError(w, fmt.Errorf("model %q is over its %s limit", name, exceeded), http.StatusTooManyRequests)
+		return
+	}
+	defer release()
+
+	// Forward the request to the model the controller serves, which carries
+	// the parameters, system prompt and template of the spec
+	rewrite := false
+	if served := model.ServedModel(); served != name {
+		fields["model"], _ = json.Marshal(served)
+		rewrite = true
+	}
+	if limits := model.Spec.Limits; limits != nil && limits.MaxTokens != nil && generates(r.URL.Path) {
+		rewrite = capTokens(fields, *limits.MaxTokens) || rewrite
+	}
+	if rewrite {
+		if body, err = json.Marshal(fields); err != nil {
+			sendError(w, err, http.StatusInternalServerError)
+			return
+		}
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	r.ContentLength = int64(len(body))
+	r.Header.Del("Content-Length")
+
+	// Generations outlive the server's write timeout, starting with the time
+	// to load the model, so only the writes of the response are bounded
+	rw := newStreamWriter(w, g.config.WriteTimeout)
+	if err := rw.controller.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		log.FromContext(r.Context()).Error(err, "failed to clear write deadline")
+	}
+
+	u := &usage{}
+	start := time.Now()
+	// The proxy aborts the handler when the client goes away in the middle of
+	// a streamed response, so the request is recorded on the way out
+	defer func() {
+		status := rw.status
+		if r.Context().Err() != nil {
+			status = statusClientClosedRequest
+		}
+		observe(model, alias, r.URL.Path, status, u, time.Since(start), start)
+	}()
+	g.proxy.ServeHTTP(rw, r.WithContext(context.WithValue(r.Context(), usageKey{}, u)))
+}
+
+// resolve returns the OllamaModel the name of a request designates, or nil if
+// none does, along with the OllamaModelAlias it was resolved through, if any.
+// The name is either that of an alias or an Ollama model managed by an
+// OllamaModel. When several namespaces the caller reaches match, the first
+// Ready one in namespace order is used.
+func (g *Gateway) resolve(ctx context.Context, name string, caller caller) (*ollamav1alpha1.OllamaModel, *ollamav1alpha1.OllamaModelAlias, error) {
+	if model, alias, err := g.resolveAlias(ctx, name, caller); model != nil || err != nil {
+		return model, alias, err
+	}
+
+	var list ollamav1alpha1.OllamaModelList
+	if err := g.client.List(ctx, &list, client.MatchingFields{ollamav1alpha1.ModelReferenceField: reference(name)}); err != nil {
+		return nil, nil, err
+	}
+	var models ollamav1alpha1.OllamaModelList
+	for _, model := range list.Items {
+		if caller.reaches(model.Namespace) {
+			models.Items = append(models.Items, model)
+		}
+	}
+	if len(models.Items) == 0 {
+		return nil, nil, nil
+	}
+
+	sort.Slice(models.Items, func(i, j int) bool {
+		a, b := models.Items[i], models.Items[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	for i := range models.Items {
+		if models.Items[i].Status.State == ollamav1alpha1.StateReady {
+			return &models.Items[i], nil, nil
+		}
+	}
+	return &models.Items[0], nil, nil
+}
+
+// resolveAlias returns the OllamaModel an OllamaModelAlias named name points
+// at, along with the alias, or nil if there is no such alias or none of its
+// models with a weight exists. The models of a split are drawn at random in
+// proportion to the weights of the Ready ones. The alias is read on every
+// request, so repointing it switches the following requests over at once.
+func (g *Gateway) resolveAlias(ctx context.Context, name string, caller caller) (*ollamav1alpha1.OllamaModel, *ollamav1alpha1.OllamaModelAlias, error) {
+	var aliases ollamav1alpha1.OllamaModelAliasList
+	if err := g.client.List(ctx, &aliases); err != nil {
+		return nil, nil, err
+	}
+	var matching []ollamav1alpha1.OllamaModelAlias
+	for _, alias := range aliases.Items {
+		if alias.Name == name && alias.Spec.Valid() && caller.reaches(alias.Namespace) {
+			matching = append(matching, alias)
+		}
+	}
+	sort.Slice(matching, func(i, j int) bool { return matching[i].Namespace < matching[j].Namespace })
+
+	var resolved *ollamav1alpha1.OllamaModel
+	var resolvedAlias *ollamav1alpha1.OllamaModelAlias
+	for i := range matching {
+		alias := &matching[i]
+		var ready []*ollamav1alpha1.OllamaModel
+		var weights []int32
+		for _, branch := range alias.Spec.Branches() {
+			model := &ollamav1alpha1.OllamaModel{}
+			if err := g.client.Get(ctx, client.ObjectKey{Namespace: alias.Namespace, Name: branch.Model}, model); err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				return nil, nil, err
+			}
+			switch {
+			case branch.Weight == 0:
+			case model.Status.State == ollamav1alpha1.StateReady:
+				ready = append(ready, model)
+				weights = append(weights, branch.Weight)
+			case resolved == nil:
+				resolved, resolvedAlias = model, alias
+			}
+		}
+		if len(ready) > 0 {
+			return ready[pick(weights)], alias, nil
+		}
+	}
+	return resolved, resolvedAlias, nil
+}
+
+// pick returns the index of a weight chosen at random in proportion to the
+// weights, which add up to more than 0
+func pick(weights []int32) int {
+	var total int32
+	for _, weight := range weights {
+		total += weight
+	}
+	n := randInt32N(total)
+	for i, weight := range weights {
+		if n < weight {
+			return i
+		}
+		n -= weight
+	}
+	return len(weights) - 1
+}
+
+// reference returns an Ollama model name with its tag, which defaults to
+// latest, in the canonical form the OllamaModels are indexed by
+func reference(name string) string {
+	return ollamav1alpha1.NormalizeModelReference(name)
+}
+
+// generates reports whether requests to an endpoint generate tokens
+func generates(path string) bool {
+	return path == "/api/generate" || path == "/api/chat"
+}
+
+// sendError sends an error the way the Ollama API does
+func sendError(w http.ResponseWriter, err error, status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
+
+// streamWriter passes a response through to the client as it is written,
+// capturing its status code. Each write must complete within writeTimeout, so
+// that a client that stops reading does not hold its model; a slow one slows
+// down the reads from the Ollama server instead.
+type streamWriter struct {
+	http.ResponseWriter
+	controller   *http.ResponseController
+	writeTimeout time.Duration
+	status       int
+}
+
+func newStreamWriter(w http.ResponseWriter, writeTimeout time.Duration) *streamWriter {
+	return &streamWriter{ResponseWriter: w, controller: http.NewResponseController(w), writeTimeout: writeTimeout, status: http.StatusOK}
+}
+
+func (w *streamWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *streamWriter) Write(p []byte) (int, error) {
+	if err := w.controller.SetWriteDeadline(time.Now().Add(w.writeTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return 0, err
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// Flush lets streamed responses through as they are generated
+func (w *streamWriter) Flush() {
+	_ = w.controller.Flush()
+}
+
+// Unwrap exposes the underlying ResponseWriter to http.ResponseController
+func (w *streamWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
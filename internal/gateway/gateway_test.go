@@ -0,0 +1,347 @@
+package gateway
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+	httpapi "github.com/dmk/ollama-operator/internal/api"
+)
+
+// fakeKeyring accepts the keys it maps to their namespaces
+type fakeKeyring map[string][]string
+
+func (k fakeKeyring) Enabled() bool {
+	return true
+}
+
+func (k fakeKeyring) KeyNamespaces(value string) ([]string, bool) {
+	namespaces, ok := k[value]
+	return namespaces, ok
+}
+
+var _ = Describe("Gateway", func() {
+	ctx := context.Background()
+
+	var (
+		gateway       *Gateway
+		c             client.Client
+		ollama        *httptest.Server
+		received      map[string]any
+		authorization string
+	)
+
+	newModel := func(namespace, name, tag string, state ollamav1alpha1.ModelState) *ollamav1alpha1.OllamaModel {
+		return &ollamav1alpha1.OllamaModel{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       ollamav1alpha1.OllamaModelSpec{Name: "llama3.2", Tag: tag},
+			Status:     ollamav1alpha1.OllamaModelStatus{State: state},
+		}
+	}
+
+	do := func(path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		gateway.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+		return rec
+	}
+
+	BeforeEach(func() {
+		received = nil
+		ollama = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authorization = r.Header.Get("Authorization")
+			Expect(json.NewDecoder(r.Body).Decode(&received)).To(Succeed())
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			_, _ = io.WriteString(w, `{"model":"llama3.2:1b","response":"Hel","done":false}`+"\n")
+			_, _ = io.WriteString(w, `{"model":"llama3.2:1b","response":"lo","done":false}`+"\n")
+			_, _ = io.WriteString(w, `{"model":"llama3.2:1b","response":"","done":true,"prompt_eval_count":12,"eval_count":2}`+"\n")
+		}))
+		DeferCleanup(ollama.Close)
+
+		scheme := runtime.NewScheme()
+		Expect(ollamav1alpha1.AddToScheme(scheme)).To(Succeed())
+		derived := newModel("team-b", "llama-3b", "3b", ollamav1alpha1.StateReady)
+		derived.Status.DerivedModel = "llama3.2:3b-team-b-llama-3b"
+		limited := newModel("team-c", "llama-70b", "70b", ollamav1alpha1.StateReady)
+		limited.Spec.Limits = &ollamav1alpha1.InferenceLimits{RequestsPerSecond: ptr.To[int32](2), MaxTokens: ptr.To[int32](64)}
+		c = fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(
+				newModel("team-a", "llama", "1b", ollamav1alpha1.StateReady),
+				newModel("default", "llama", "1b", ollamav1alpha1.StatePulling),
+				derived,
+				newModel("default", "llama-8b", "8b", ollamav1alpha1.StatePulling),
+				newModel("team-b", "llama-1b", "1b", ollamav1alpha1.StateReady),
+				limited,
+				&ollamav1alpha1.OllamaModelAlias{
+					ObjectMeta: metav1.ObjectMeta{Name: "assistant", Namespace: "team-b"},
+					Spec:       ollamav1alpha1.OllamaModelAliasSpec{Model: "llama-3b"},
+				},
+				&ollamav1alpha1.OllamaModelAlias{
+					ObjectMeta: metav1.ObjectMeta{Name: "canary", Namespace: "team-b"},
+					Spec: ollamav1alpha1.OllamaModelAliasSpec{Split: []ollamav1alpha1.AliasBranch{
+						{Model: "llama-3b", Weight: 90},
+						{Model: "llama-1b", Weight: 10},
+					}},
+				},
+			).
+			WithIndex(&ollamav1alpha1.OllamaModel{}, ollamav1alpha1.ModelReferenceField, func(o client.Object) []string {
+				return []string{ollamav1alpha1.NormalizeModelReference(o.(*ollamav1alpha1.OllamaModel).Spec.Reference())}
+			}).
+			Build()
+
+		target, err := url.Parse(ollama.URL)
+		Expect(err).NotTo(HaveOccurred())
+		gateway = New(Config{OllamaURL: target}, c)
+	})
+
+	It("proxies requests for managed models and records their usage", func() {
+		requests := testutil.ToFloat64(requestsTotal.WithLabelValues("team-a", "llama", "/api/generate", "200"))
+		completion := testutil.ToFloat64(completionTokensTotal.WithLabelValues("team-a", "llama"))
+
+		rec := do("/api/generate", `{"model":"llama3.2:1b","prompt":"Hi"}`)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(ContainSubstring(`"response":"Hel"`))
+		Expect(received).To(HaveKeyWithValue("prompt", "Hi"))
+
+		// The Ready model is used over the one still being pulled in another namespace
+		Expect(testutil.ToFloat64(requestsTotal.WithLabelValues("team-a", "llama", "/api/generate", "200"))).To(Equal(requests + 1))
+		Expect(testutil.ToFloat64(completionTokensTotal.WithLabelValues("team-a", "llama"))).To(Equal(completion + 2))
+		Expect(testutil.ToFloat64(promptTokensTotal.WithLabelValues("team-a", "llama"))).To(BeNumerically(">=", 12))
+	})
+
+	It("forwards requests to the derived model", func() {
+		Expect(do("/api/chat", `{"model":"llama3.2:3b","messages":[]}`).Code).To(Equal(http.StatusOK))
+		Expect(received).To(HaveKeyWithValue("model", "llama3.2:3b-team-b-llama-3b"))
+	})
+
+	It("resolves aliases to the model they point at", func() {
+		Expect(do("/api/generate", `{"model":"assistant","prompt":"Hi"}`).Code).To(Equal(http.StatusOK))
+		Expect(received).To(HaveKeyWithValue("model", "llama3.2:3b-team-b-llama-3b"))
+
+		// Repointing the alias switches the next request over
+		alias := &ollamav1alpha1.OllamaModelAlias{}
+		Expect(c.Get(ctx, client.ObjectKey{Namespace: "team-b", Name: "assistant"}, alias)).To(Succeed())
+		alias.Spec.Model = "llama-1b"
+		Expect(c.Update(ctx, alias)).To(Succeed())
+
+		requests := testutil.ToFloat64(requestsTotal.WithLabelValues("team-b", "llama-1b", "/api/generate", "200"))
+		Expect(do("/api/generate", `{"model":"assistant","prompt":"Hi"}`).Code).To(Equal(http.StatusOK))
+		Expect(received).To(HaveKeyWithValue("model", "llama3.2:1b"))
+		Expect(testutil.ToFloat64(requestsTotal.WithLabelValues("team-b", "llama-1b", "/api/generate", "200"))).To(Equal(requests + 1))
+	})
+
+	It("splits the requests for an alias between its Ready models", func() {
+		// Draw the last share of the weights, which belongs to llama-1b
+		randInt32N = func(n int32) int32 { return n - 1 }
+		DeferCleanup(func() { randInt32N = rand.Int32N })
+
+		requests := testutil.ToFloat64(aliasRequestsTotal.WithLabelValues("team-b", "canary", "llama-1b", "200"))
+		Expect(do("/api/generate", `{"model":"canary","prompt":"Hi"}`).Code).To(Equal(http.StatusOK))
+		Expect(received).To(HaveKeyWithValue("model", "llama3.2:1b"))
+		Expect(testutil.ToFloat64(aliasRequestsTotal.WithLabelValues("team-b", "canary", "llama-1b", "200"))).To(Equal(requests + 1))
+
+		// A model that is not Ready gets no requests
+		model := &ollamav1alpha1.OllamaModel{}
+		Expect(c.Get(ctx, client.ObjectKey{Namespace: "team-b", Name: "llama-1b"}, model)).To(Succeed())
+		model.Status.State = ollamav1alpha1.StatePulling
+		Expect(c.Update(ctx, model)).To(Succeed())
+		Expect(do("/api/generate", `{"model":"canary","prompt":"Hi"}`).Code).To(Equal(http.StatusOK))
+		Expect(received).To(HaveKeyWithValue("model", "llama3.2:3b-team-b-llama-3b"))
+	})
+
+	It("draws models in proportion to their weights", func() {
+		draws := []int32{0, 89, 90, 99}
+		randInt32N = func(n int32) int32 {
+			Expect(n).To(Equal(int32(100)))
+			draw := draws[0]
+			draws = draws[1:]
+			return draw
+		}
+		DeferCleanup(func() { randInt32N = rand.Int32N })
+
+		weights := []int32{90, 10}
+		Expect([]int{pick(weights), pick(weights), pick(weights), pick(weights)}).To(Equal([]int{0, 0, 1, 1}))
+	})
+
+	It("enforces the limits of a model", func() {
+		Expect(do("/api/generate", `{"model":"llama3.2:70b","prompt":"Hi","options":{"num_predict":1000}}`).Code).To(Equal(http.StatusOK))
+		Expect(received).To(HaveKeyWithValue("options", HaveKeyWithValue("num_predict", BeNumerically("==", 64))))
+		Expect(do("/api/generate", `{"model":"llama3.2:70b","prompt":"Hi"}`).Code).To(Equal(http.StatusOK))
+
+		throttled := testutil.ToFloat64(throttledTotal.WithLabelValues("team-c", "llama-70b", limitRate))
+		rec := do("/api/generate", `{"model":"llama3.2:70b","prompt":"Hi"}`)
+		Expect(rec.Code).To(Equal(http.StatusTooManyRequests))
+		Expect(rec.Header().Get("Retry-After")).To(Equal("1"))
+		Expect(testutil.ToFloat64(throttledTotal.WithLabelValues("team-c", "llama-70b", limitRate))).To(Equal(throttled + 1))
+	})
+
+	It("rejects models that are not managed or not ready", func() {
+		Expect(do("/api/generate", `{"model":"mistral"}`).Code).To(Equal(http.StatusNotFound))
+		Expect(do("/api/generate", `{"model":"llama3.2:8b"}`).Code).To(Equal(http.StatusServiceUnavailable))
+		Expect(do("/api/generate", `{"prompt":"Hi"}`).Code).To(Equal(http.StatusBadRequest))
+		Expect(received).To(BeNil())
+	})
+
+	It("requires one of the API keys once the keyring is enabled", func() {
+		gateway.config.Keyring = fakeKeyring{"ci-key": nil, "team-b-key": {"team-b"}}
+		send := func(header, value, body string) int {
+			req := httptest.NewRequest(http.MethodPost, "/api/generate", strings.NewReader(body))
+			if header != "" {
+				req.Header.Set(header, value)
+			}
+			rec := httptest.NewRecorder()
+			gateway.ServeHTTP(rec, req)
+			return rec.Code
+		}
+
+		Expect(send("", "", `{"model":"llama3.2:1b"}`)).To(Equal(http.StatusUnauthorized))
+		Expect(send("X-API-Key", "wrong", `{"model":"llama3.2:1b"}`)).To(Equal(http.StatusUnauthorized))
+		Expect(received).To(BeNil())
+
+		// The key does not reach the Ollama server
+		Expect(send("Authorization", "Bearer ci-key", `{"model":"llama3.2:1b"}`)).To(Equal(http.StatusOK))
+		Expect(authorization).To(BeEmpty())
+		Expect(send("X-API-Key", "ci-key", `{"model":"llama3.2:1b"}`)).To(Equal(http.StatusOK))
+
+		// Keys restricted to some namespaces only resolve the models of those
+		requests := testutil.ToFloat64(requestsTotal.WithLabelValues("team-b", "llama-1b", "/api/generate", "200"))
+		Expect(send("X-API-Key", "team-b-key", `{"model":"llama3.2:1b"}`)).To(Equal(http.StatusOK))
+		Expect(testutil.ToFloat64(requestsTotal.WithLabelValues("team-b", "llama-1b", "/api/generate", "200"))).To(Equal(requests + 1))
+		Expect(send("X-API-Key", "team-b-key", `{"model":"llama3.2:70b"}`)).To(Equal(http.StatusNotFound))
+	})
+
+	It("locks out the sources that fail to authenticate, sharing the lockout of the API servers", func() {
+		gateway.config.Keyring = fakeKeyring{"ci-key": nil}
+		gateway.config.Lockout = httpapi.NewLockout(2, time.Minute)
+		send := func(key string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodPost, "/api/generate", strings.NewReader(`{"model":"llama3.2:1b"}`))
+			req.Header.Set("X-API-Key", key)
+			rec := httptest.NewRecorder()
+			gateway.ServeHTTP(rec, req)
+			return rec
+		}
+
+		failed := testutil.ToFloat64(authenticationFailedTotal.WithLabelValues(reasonInvalidKey))
+		Expect(send("wrong").Code).To(Equal(http.StatusUnauthorized))
+		Expect(send("wrong").Code).To(Equal(http.StatusUnauthorized))
+		Expect(testutil.ToFloat64(authenticationFailedTotal.WithLabelValues(reasonInvalidKey))).To(Equal(failed + 2))
+
+		// Even the right key is refused until the lockout ends
+		rec := send("ci-key")
+		Expect(rec.Code).To(Equal(http.StatusTooManyRequests))
+		Expect(rec.Header().Get("Retry-After")).To(Equal("60"))
+		Expect(received).To(BeNil())
+	})
+
+	It("refuses the clients outside of the allowed networks", func() {
+		gateway.config.AllowedNetworks = []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+		gateway.config.TrustedProxies = []netip.Prefix{netip.MustParsePrefix("172.16.0.0/12")}
+		send := func(remote, forwarded string) int {
+			req := httptest.NewRequest(http.MethodPost, "/api/generate", strings.NewReader(`{"model":"llama3.2:1b"}`))
+			req.RemoteAddr = remote
+			if forwarded != "" {
+				req.Header.Set("X-Forwarded-For", forwarded)
+			}
+			rec := httptest.NewRecorder()
+			gateway.ServeHTTP(rec, req)
+			return rec.Code
+		}
+
+		denied := testutil.ToFloat64(sourceDeniedTotal)
+		Expect(send("203.0.113.7:51234", "")).To(Equal(http.StatusForbidden))
+		Expect(send("203.0.113.7:51234", "10.1.2.3")).To(Equal(http.StatusForbidden))
+		Expect(testutil.ToFloat64(sourceDeniedTotal)).To(Equal(denied + 2))
+		Expect(received).To(BeNil())
+
+		Expect(send("10.1.2.3:51234", "")).To(Equal(http.StatusOK))
+		Expect(send("172.16.0.5:443", "10.1.2.3")).To(Equal(http.StatusOK))
+	})
+
+	It("defaults the tag to latest", func() {
+		Expect(reference("llama3.2")).To(Equal("llama3.2:latest"))
+		Expect(reference("llama3.2:1b")).To(Equal("llama3.2:1b"))
+		Expect(reference("localhost:5000/llama3.2")).To(Equal("localhost:5000/llama3.2:latest"))
+	})
+})
+
+var _ = Describe("Streaming", func() {
+	It("passes tokens through as they are generated and stops when the client goes away", func() {
+		cancelled := make(chan struct{})
+		ollama := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			_, _ = io.WriteString(w, `{"model":"llama3.2:1b","response":"Hel","done":false}`+"\n")
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+			close(cancelled)
+		}))
+		DeferCleanup(ollama.Close)
+
+		scheme := runtime.NewScheme()
+		Expect(ollamav1alpha1.AddToScheme(scheme)).To(Succeed())
+		c := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(&ollamav1alpha1.OllamaModel{
+				ObjectMeta: metav1.ObjectMeta{Name: "streamed", Namespace: "default"},
+				Spec:       ollamav1alpha1.OllamaModelSpec{Name: "llama3.2", Tag: "1b"},
+				Status:     ollamav1alpha1.OllamaModelStatus{State: ollamav1alpha1.StateReady},
+			}).
+			WithIndex(&ollamav1alpha1.OllamaModel{}, ollamav1alpha1.ModelReferenceField, func(o client.Object) []string {
+				return []string{ollamav1alpha1.NormalizeModelReference(o.(*ollamav1alpha1.OllamaModel).Spec.Reference())}
+			}).
+			Build()
+		target, err := url.Parse(ollama.URL)
+		Expect(err).NotTo(HaveOccurred())
+		gateway := httptest.NewServer(New(Config{OllamaURL: target}, c))
+		DeferCleanup(gateway.Close)
+
+		requests := testutil.ToFloat64(requestsTotal.WithLabelValues("default", "streamed", "/api/generate", "499"))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, gateway.URL+"/api/generate",
+			strings.NewReader(`{"model":"llama3.2:1b","prompt":"Hi"}`))
+		Expect(err).NotTo(HaveOccurred())
+		resp, err := http.DefaultClient.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+
+		// The first token arrives while the generation goes on
+		line, err := bufio.NewReader(resp.Body).ReadString('\n')
+		Expect(err).NotTo(HaveOccurred())
+		Expect(line).To(ContainSubstring(`"response":"Hel"`))
+
+		cancel()
+		Eventually(cancelled).Should(BeClosed())
+		Eventually(func() float64 {
+			return testutil.ToFloat64(requestsTotal.WithLabelValues("default", "streamed", "/api/generate", "499"))
+		}).Should(Equal(requests + 1))
+	})
+})
+
+var _ = Describe("Usage", func() {
+	It("reads the token counts of a response that is not streamed", func() {
+		u := &usage{}
+		r := &usageReader{ReadCloser: io.NopCloser(strings.NewReader(`{"embeddings":[[0.1]],"prompt_eval_count":7}`)), usage: u}
+		_, err := io.ReadAll(r)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*u).To(Equal(usage{promptTokens: 7}))
+	})
+})
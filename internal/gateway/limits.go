@@ -0,0 +1,98 @@
+package gateway
+
+import (
+	"encoding/json"
+	"sync"
+
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/types"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+// Limits a request can exceed, as reported in the throttled requests metric
+const (
+	limitRate        = "rate"
+	limitConcurrency = "concurrency"
+)
+
+// limiter tracks the requests of an OllamaModel against its limits
+type limiter struct {
+	rate     *rate.Limiter
+	inflight int32
+}
+
+// limiters enforces the inference limits of OllamaModels. The limits are read
+// from the model on every request, so changes to them apply right away.
+type limiters struct {
+	mu      sync.Mutex
+	byModel map[types.NamespacedName]*limiter
+}
+
+// acquire admits a request for a model, returning a function to call once it
+// is served, or the limit it exceeds
+func (l *limiters) acquire(model *ollamav1alpha1.OllamaModel) (func(), string) {
+	limits := model.Spec.Limits
+	key := types.NamespacedName{Namespace: model.Namespace, Name: model.Name}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	lim := l.byModel[key]
+	if limits == nil || (limits.RequestsPerSecond == nil && limits.MaxConcurrentRequests == nil) {
+		// Requests admitted under limits since removed still release their slot
+		if lim != nil && lim.inflight == 0 {
+			delete(l.byModel, key)
+		}
+		return func() {}, ""
+	}
+	if lim == nil {
+		if l.byModel == nil {
+			l.byModel = map[types.NamespacedName]*limiter{}
+		}
+		lim = &limiter{}
+		l.byModel[key] = lim
+	}
+
+	if limits.RequestsPerSecond != nil {
+		perSecond := int(*limits.RequestsPerSecond)
+		if lim.rate == nil {
+			lim.rate = rate.NewLimiter(rate.Limit(perSecond), perSecond)
+		} else if lim.rate.Burst() != perSecond {
+			lim.rate.SetLimit(rate.Limit(perSecond))
+			lim.rate.SetBurst(perSecond)
+		}
+	} else {
+		lim.rate = nil
+	}
+
+	if limits.MaxConcurrentRequests != nil && lim.inflight >= *limits.MaxConcurrentRequests {
+		return nil, limitConcurrency
+	}
+	if lim.rate != nil && !lim.rate.Allow() {
+		return nil, limitRate
+	}
+	lim.inflight++
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		lim.inflight--
+	}, ""
+}
+
+// capTokens lowers the num_predict option of a generate or chat request to
+// the maximum number of tokens of its model, and reports whether it changed
+func capTokens(fields map[string]json.RawMessage, maxTokens int32) bool {
+	options := map[string]json.RawMessage{}
+	if raw, ok := fields["options"]; ok && json.Unmarshal(raw, &options) != nil {
+		options = map[string]json.RawMessage{}
+	}
+	var numPredict int64
+	if raw, ok := options["num_predict"]; ok && json.Unmarshal(raw, &numPredict) == nil && numPredict > 0 && numPredict <= int64(maxTokens) {
+		return false
+	}
+	// Unset and negative values generate until the model stops
+	options["num_predict"], _ = json.Marshal(maxTokens)
+	fields["options"], _ = json.Marshal(options)
+	return true
+}
@@ -0,0 +1,73 @@
+package gateway
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+var _ = Describe("Limits", func() {
+	limited := func(limits *ollamav1alpha1.InferenceLimits) *ollamav1alpha1.OllamaModel {
+		return &ollamav1alpha1.OllamaModel{
+			ObjectMeta: metav1.ObjectMeta{Name: "llama", Namespace: "default"},
+			Spec:       ollamav1alpha1.OllamaModelSpec{Name: "llama3.2", Tag: "1b", Limits: limits},
+		}
+	}
+
+	It("caps the requests served at the same time", func() {
+		var l limiters
+		model := limited(&ollamav1alpha1.InferenceLimits{MaxConcurrentRequests: ptr.To[int32](2)})
+
+		first, exceeded := l.acquire(model)
+		Expect(exceeded).To(BeEmpty())
+		_, exceeded = l.acquire(model)
+		Expect(exceeded).To(BeEmpty())
+		_, exceeded = l.acquire(model)
+		Expect(exceeded).To(Equal(limitConcurrency))
+
+		first()
+		_, exceeded = l.acquire(model)
+		Expect(exceeded).To(BeEmpty())
+	})
+
+	It("limits the rate of requests", func() {
+		var l limiters
+		model := limited(&ollamav1alpha1.InferenceLimits{RequestsPerSecond: ptr.To[int32](1)})
+
+		release, exceeded := l.acquire(model)
+		Expect(exceeded).To(BeEmpty())
+		release()
+		_, exceeded = l.acquire(model)
+		Expect(exceeded).To(Equal(limitRate))
+
+		// Removing the limits lets requests through again
+		_, exceeded = l.acquire(limited(nil))
+		Expect(exceeded).To(BeEmpty())
+		Expect(l.byModel).To(BeEmpty())
+	})
+
+	It("caps the tokens generated for a request", func() {
+		capped := func(body string) (bool, string) {
+			fields := map[string]json.RawMessage{}
+			Expect(json.Unmarshal([]byte(body), &fields)).To(Succeed())
+			changed := capTokens(fields, 64)
+			return changed, string(fields["options"])
+		}
+
+		changed, options := capped(`{"model":"llama3.2"}`)
+		Expect(changed).To(BeTrue())
+		Expect(options).To(MatchJSON(`{"num_predict":64}`))
+
+		changed, options = capped(`{"model":"llama3.2","options":{"num_predict":-1,"temperature":0.2}}`)
+		Expect(changed).To(BeTrue())
+		Expect(options).To(MatchJSON(`{"num_predict":64,"temperature":0.2}`))
+
+		changed, _ = capped(`{"model":"llama3.2","options":{"num_predict":32}}`)
+		Expect(changed).To(BeFalse())
+	})
+})
@@ -0,0 +1,141 @@
+package gateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+var (
+	requestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ollama_gateway_requests_total",
+		Help: "Inference requests proxied by the gateway",
+	}, []string{"namespace", "name", "endpoint", "code"})
+
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ollama_gateway_request_duration_seconds",
+		Help:    "Duration of inference requests proxied by the gateway, until the response is complete",
+		Buckets: prometheus.ExponentialBuckets(0.1, 2, 12),
+	}, []string{"namespace", "name", "endpoint"})
+
+	promptTokensTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ollama_gateway_prompt_tokens_total",
+		Help: "Prompt tokens evaluated for inference requests proxied by the gateway",
+	}, []string{"namespace", "name"})
+
+	completionTokensTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ollama_gateway_completion_tokens_total",
+		Help: "Tokens generated for inference requests proxied by the gateway",
+	}, []string{"namespace", "name"})
+
+	aliasRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ollama_gateway_alias_requests_total",
+		Help: "Inference requests proxied by the gateway for an alias, by the model of its split serving them",
+	}, []string{"namespace", "alias", "model", "code"})
+
+	throttledTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ollama_gateway_throttled_requests_total",
+		Help: "Inference requests rejected by the gateway for exceeding a limit of their model",
+	}, []string{"namespace", "name", "limit"})
+
+	lastRequest = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ollama_gateway_last_request_timestamp_seconds",
+		Help: "Unix time of the last inference request proxied by the gateway",
+	}, []string{"namespace", "name"})
+
+	authenticationFailedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ollama_gateway_authentication_failed_total",
+		Help: "Inference requests rejected by the gateway because they carry no valid API key, or come from a locked out source",
+	}, []string{"reason"})
+
+	sourceDeniedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "ollama_gateway_source_denied_total",
+		Help: "Inference requests rejected by the gateway because their client address is not in the allowed networks",
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(requestsTotal, requestDuration, promptTokensTotal, completionTokensTotal, aliasRequestsTotal, throttledTotal, lastRequest,
+		authenticationFailedTotal, sourceDeniedTotal)
+}
+
+// observe records a proxied request in the metrics of its OllamaModel, and of
+// the OllamaModelAlias it was requested through, if any
+func observe(model *ollamav1alpha1.OllamaModel, alias *ollamav1alpha1.OllamaModelAlias, endpoint string, status int, u *usage, duration time.Duration, start time.Time) {
+	requestsTotal.WithLabelValues(model.Namespace, model.Name, endpoint, strconv.Itoa(status)).Inc()
+	requestDuration.WithLabelValues(model.Namespace, model.Name, endpoint).Observe(duration.Seconds())
+	promptTokensTotal.WithLabelValues(model.Namespace, model.Name).Add(float64(u.promptTokens))
+	completionTokensTotal.WithLabelValues(model.Namespace, model.Name).Add(float64(u.completionTokens))
+	lastRequest.WithLabelValues(model.Namespace, model.Name).Set(float64(start.Unix()))
+	if alias != nil {
+		aliasRequestsTotal.WithLabelValues(alias.Namespace, alias.Name, model.Name, strconv.Itoa(status)).Inc()
+	}
+}
+
+// maxLineBytes bounds the part of a response line kept to look for token
+// counts; the counts are in short final lines, not in long generated ones
+const maxLineBytes = 64 << 10
+
+// usageKey is the context key of the usage of a proxied request
+type usageKey struct{}
+
+// usage is the number of tokens an inference request consumed
+type usage struct {
+	promptTokens     int
+	completionTokens int
+}
+
+// usageReader reads the token counts Ollama reports in the last object of a
+// response, whether streamed as JSON lines or not, while passing it through
+type usageReader struct {
+	io.ReadCloser
+	usage *usage
+	line  []byte
+}
+
+func (r *usageReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	data := p[:n]
+	for len(data) > 0 {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			r.buffer(data)
+			break
+		}
+		r.buffer(data[:i])
+		r.parse()
+		data = data[i+1:]
+	}
+	if err == io.EOF {
+		r.parse()
+	}
+	return n, err
+}
+
+// buffer adds data to the current line, up to maxLineBytes
+func (r *usageReader) buffer(data []byte) {
+	if room := maxLineBytes - len(r.line); room > 0 {
+		r.line = append(r.line, data[:min(len(data), room)]...)
+	}
+}
+
+// parse records the token counts of the current line, if any, and starts a
+// new line
+func (r *usageReader) parse() {
+	var counts struct {
+		PromptEvalCount int `json:"prompt_eval_count"`
+		EvalCount       int `json:"eval_count"`
+	}
+	if len(r.line) > 0 && json.Unmarshal(r.line, &counts) == nil && (counts.PromptEvalCount > 0 || counts.EvalCount > 0) {
+		r.usage.promptTokens = counts.PromptEvalCount
+		r.usage.completionTokens = counts.EvalCount
+	}
+	r.line = r.line[:0]
+}
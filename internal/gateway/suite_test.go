@@ -0,0 +1,14 @@
+package gateway
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestGateway(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Gateway Suite")
+}
@@ -0,0 +1,218 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Alert kinds
+const (
+	AlertModelFailed = "model.failed"
+	AlertPullStuck   = "model.pull_stuck"
+)
+
+// DefaultAlertTemplate renders the message of an alert unless configured otherwise
+const DefaultAlertTemplate = `{{if eq .Kind "model.pull_stuck" -}}
+Model {{.Model}} ({{.Namespace}}/{{.Name}}) has been pulling for {{.Duration}}
+{{- else -}}
+Model {{.Model}} ({{.Namespace}}/{{.Name}}) failed: {{.Error}}
+{{- end}}`
+
+// pagerDutyEventsURL is the PagerDuty Events API v2 endpoint
+const pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// Alert is a condition of a model that needs attention
+type Alert struct {
+	Kind      string    `json:"kind"`
+	Time      time.Time `json:"time"`
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Model     string    `json:"model"`
+	Error     string    `json:"error,omitempty"`
+	// Duration is how long a stuck pull has been running, such as "1h0m0s"
+	Duration string `json:"duration,omitempty"`
+}
+
+// Provider sends rendered alerts to an alerting service
+type Provider interface {
+	// Name identifies the provider in logs
+	Name() string
+	// Request builds the request delivering alert with the rendered message
+	Request(ctx context.Context, alert Alert, message string) (*http.Request, error)
+}
+
+// ParseProvider parses an alert flag of the form "slack=URL", "webhook=URL" or
+// "pagerduty=PATH", where PATH is a file holding a PagerDuty routing key
+func ParseProvider(spec string) (Provider, error) {
+	kind, value, _ := strings.Cut(spec, "=")
+	if value == "" {
+		return nil, fmt.Errorf("alert provider %q has no target, expected KIND=VALUE", spec)
+	}
+
+	switch kind {
+	case "slack":
+		return slackProvider{url: value}, nil
+	case "webhook":
+		return webhookProvider{url: value}, nil
+	case "pagerduty":
+		key, err := os.ReadFile(value)
+		if err != nil {
+			return nil, fmt.Errorf("alert provider pagerduty: reading routing key: %w", err)
+		}
+		return pagerDutyProvider{url: pagerDutyEventsURL, routingKey: strings.TrimSpace(string(key))}, nil
+	default:
+		return nil, fmt.Errorf("unknown alert provider %q, must be slack, webhook or pagerduty", kind)
+	}
+}
+
+// slackProvider posts alerts to a Slack incoming webhook
+type slackProvider struct {
+	url string
+}
+
+func (p slackProvider) Name() string { return "slack" }
+
+func (p slackProvider) Request(ctx context.Context, _ Alert, message string) (*http.Request, error) {
+	return jsonRequest(ctx, p.url, map[string]string{"text": message})
+}
+
+// webhookProvider posts alerts with their rendered message as JSON
+type webhookProvider struct {
+	url string
+}
+
+func (p webhookProvider) Name() string { return "webhook" }
+
+func (p webhookProvider) Request(ctx context.Context, alert Alert, message string) (*http.Request, error) {
+	return jsonRequest(ctx, p.url, struct {
+		Alert
+		Message string `json:"message"`
+	}{alert, message})
+}
+
+// pagerDutyProvider triggers PagerDuty incidents through the Events API v2.
+// Repeated alerts of the same kind for a model are grouped into one incident.
+type pagerDutyProvider struct {
+	url        string
+	routingKey string
+}
+
+func (p pagerDutyProvider) Name() string { return "pagerduty" }
+
+func (p pagerDutyProvider) Request(ctx context.Context, alert Alert, message string) (*http.Request, error) {
+	severity := "error"
+	if alert.Kind == AlertPullStuck {
+		severity = "warning"
+	}
+	return jsonRequest(ctx, p.url, map[string]interface{}{
+		"routing_key":  p.routingKey,
+		"event_action": "trigger",
+		"dedup_key":    fmt.Sprintf("%s/%s/%s", alert.Namespace, alert.Name, alert.Kind),
+		"payload": map[string]interface{}{
+			"summary":        message,
+			"source":         "ollama-operator",
+			"severity":       severity,
+			"timestamp":      alert.Time.Format(time.RFC3339),
+			"custom_details": alert,
+		},
+	})
+}
+
+// jsonRequest builds a POST request with body encoded as JSON
+func jsonRequest(ctx context.Context, url string, body interface{}) (*http.Request, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+// Alerter renders alerts with a template and sends them to its providers. A
+// nil Alerter discards alerts.
+type Alerter struct {
+	providers  []Provider
+	template   *template.Template
+	httpClient *http.Client
+}
+
+// NewAlerter creates an alerter sending to providers, rendering messages with
+// the Go template text, or DefaultAlertTemplate when it is empty
+func NewAlerter(providers []Provider, text string, httpClient *http.Client) (*Alerter, error) {
+	if text == "" {
+		text = DefaultAlertTemplate
+	}
+	tmpl, err := template.New("alert").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid alert template: %w", err)
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: deliveryTimeout}
+	}
+	return &Alerter{providers: providers, template: tmpl, httpClient: httpClient}, nil
+}
+
+// Alert sends alert to every provider in the background, retrying failed
+// deliveries a few times like Notify
+func (a *Alerter) Alert(ctx context.Context, alert Alert) {
+	if a == nil || len(a.providers) == 0 {
+		return
+	}
+	if alert.Time.IsZero() {
+		alert.Time = time.Now().UTC()
+	}
+
+	logger := log.FromContext(ctx).WithName("alert")
+	var message strings.Builder
+	if err := a.template.Execute(&message, alert); err != nil {
+		logger.Error(err, "failed to render alert", "kind", alert.Kind, "name", alert.Name)
+		return
+	}
+
+	for _, provider := range a.providers {
+		go func(provider Provider) {
+			var err error
+			for i := 0; i < deliveryAttempts; i++ {
+				if err = a.send(provider, alert, message.String()); err == nil {
+					return
+				}
+				time.Sleep(time.Second * time.Duration(1<<uint(i)))
+			}
+			logger.Error(err, "failed to send alert", "provider", provider.Name(), "kind", alert.Kind, "name", alert.Name)
+		}(provider)
+	}
+}
+
+// send delivers an alert to a single provider
+func (a *Alerter) send(provider Provider, alert Alert, message string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), deliveryTimeout)
+	defer cancel()
+
+	req, err := provider.Request(ctx, alert, message)
+	if err != nil {
+		return err
+	}
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s returned status %d", provider.Name(), resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Alerter", func() {
+	var (
+		server   *httptest.Server
+		received chan map[string]interface{}
+	)
+
+	BeforeEach(func() {
+		received = make(chan map[string]interface{}, 1)
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var body map[string]interface{}
+			Expect(json.NewDecoder(r.Body).Decode(&body)).To(Succeed())
+			received <- body
+		}))
+		DeferCleanup(server.Close)
+	})
+
+	failed := Alert{Kind: AlertModelFailed, Namespace: "default", Name: "phi3-mini", Model: "phi3:mini", Error: "manifest unknown"}
+
+	It("posts the rendered message to Slack", func() {
+		alerter, err := NewAlerter([]Provider{slackProvider{url: server.URL}}, "", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		alerter.Alert(context.Background(), failed)
+		Eventually(received).Should(Receive(Equal(map[string]interface{}{
+			"text": "Model phi3:mini (default/phi3-mini) failed: manifest unknown",
+		})))
+	})
+
+	It("renders custom templates for generic webhooks", func() {
+		alerter, err := NewAlerter([]Provider{webhookProvider{url: server.URL}}, "{{.Kind}} {{.Name}} after {{.Duration}}", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		alerter.Alert(context.Background(), Alert{Kind: AlertPullStuck, Name: "phi3-mini", Duration: "1h0m0s"})
+		var body map[string]interface{}
+		Eventually(received).Should(Receive(&body))
+		Expect(body).To(HaveKeyWithValue("message", "model.pull_stuck phi3-mini after 1h0m0s"))
+		Expect(body).To(HaveKeyWithValue("kind", AlertPullStuck))
+	})
+
+	It("triggers PagerDuty incidents deduplicated per model and kind", func() {
+		keyFile := filepath.Join(GinkgoT().TempDir(), "routing-key")
+		Expect(os.WriteFile(keyFile, []byte("R0UT1NG\n"), 0o600)).To(Succeed())
+		provider, err := ParseProvider("pagerduty=" + keyFile)
+		Expect(err).NotTo(HaveOccurred())
+		pagerDuty := provider.(pagerDutyProvider)
+		pagerDuty.url = server.URL
+
+		alerter, err := NewAlerter([]Provider{pagerDuty}, "", nil)
+		Expect(err).NotTo(HaveOccurred())
+		alerter.Alert(context.Background(), failed)
+
+		var body map[string]interface{}
+		Eventually(received).Should(Receive(&body))
+		Expect(body).To(HaveKeyWithValue("routing_key", "R0UT1NG"))
+		Expect(body).To(HaveKeyWithValue("event_action", "trigger"))
+		Expect(body).To(HaveKeyWithValue("dedup_key", "default/phi3-mini/model.failed"))
+	})
+
+	It("rejects unknown providers and invalid templates", func() {
+		_, err := ParseProvider("opsgenie=https://example.com")
+		Expect(err).To(HaveOccurred())
+		_, err = NewAlerter(nil, "{{.Kind", nil)
+		Expect(err).To(HaveOccurred())
+	})
+})
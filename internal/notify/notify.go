@@ -0,0 +1,162 @@
+// Package notify delivers model state transitions to outbound webhooks so
+// external systems can react to pulls completing without polling.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Event types
+const (
+	EventModelReady   = "model.ready"
+	EventModelFailed  = "model.failed"
+	EventModelDeleted = "model.deleted"
+)
+
+// Delivery headers
+const (
+	EventHeader     = "X-Ollama-Operator-Event"
+	SignatureHeader = "X-Ollama-Operator-Signature"
+)
+
+const (
+	deliveryAttempts = 3
+	deliveryTimeout  = 10 * time.Second
+)
+
+// Event is the JSON payload posted to webhooks
+type Event struct {
+	Type      string    `json:"type"`
+	Time      time.Time `json:"time"`
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Model     string    `json:"model"`
+	State     string    `json:"state,omitempty"`
+	Digest    string    `json:"digest,omitempty"`
+	Size      int64     `json:"size,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// Webhook is a single webhook sink. When Secret is set every delivery is
+// signed with an HMAC-SHA256 of the body in the signature header.
+type Webhook struct {
+	URL    string
+	Secret []byte
+}
+
+// ParseWebhook parses a webhook flag of the form "URL" or
+// "URL,secret-file=PATH", reading the HMAC secret from PATH
+func ParseWebhook(spec string) (Webhook, error) {
+	rawURL, options, _ := strings.Cut(spec, ",")
+	if rawURL == "" {
+		return Webhook{}, fmt.Errorf("webhook %q has no URL", spec)
+	}
+
+	webhook := Webhook{URL: rawURL}
+	if options == "" {
+		return webhook, nil
+	}
+
+	key, value, _ := strings.Cut(options, "=")
+	if key != "secret-file" || value == "" {
+		return Webhook{}, fmt.Errorf("webhook %q: unknown option %q", rawURL, options)
+	}
+	secret, err := os.ReadFile(value)
+	if err != nil {
+		return Webhook{}, fmt.Errorf("webhook %q: reading secret: %w", rawURL, err)
+	}
+	webhook.Secret = bytes.TrimSpace(secret)
+	return webhook, nil
+}
+
+// Sign returns the signature header value for body
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Notifier posts events to its webhooks. A nil Notifier discards events.
+type Notifier struct {
+	webhooks   []Webhook
+	httpClient *http.Client
+}
+
+// NewNotifier creates a notifier delivering to the given webhooks
+func NewNotifier(webhooks []Webhook, httpClient *http.Client) *Notifier {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: deliveryTimeout}
+	}
+	return &Notifier{webhooks: webhooks, httpClient: httpClient}
+}
+
+// Notify delivers event to every webhook in the background so a slow
+// receiver never holds up reconciliation. Failed deliveries are retried a
+// few times and then logged.
+func (n *Notifier) Notify(ctx context.Context, event Event) {
+	if n == nil || len(n.webhooks) == 0 {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "failed to encode webhook event", "type", event.Type)
+		return
+	}
+
+	logger := log.FromContext(ctx).WithName("notify")
+	for _, webhook := range n.webhooks {
+		go func(webhook Webhook) {
+			var err error
+			for i := 0; i < deliveryAttempts; i++ {
+				if err = n.deliver(webhook, event.Type, body); err == nil {
+					return
+				}
+				time.Sleep(time.Second * time.Duration(1<<uint(i)))
+			}
+			logger.Error(err, "failed to deliver webhook", "url", webhook.URL, "type", event.Type, "name", event.Name)
+		}(webhook)
+	}
+}
+
+// deliver posts body to a single webhook
+func (n *Notifier) deliver(webhook Webhook, eventType string, body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), deliveryTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, eventType)
+	if len(webhook.Secret) > 0 {
+		req.Header.Set(SignatureHeader, Sign(webhook.Secret, body))
+	}
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
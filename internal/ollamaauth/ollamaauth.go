@@ -0,0 +1,63 @@
+// Package ollamaauth authenticates requests to the Ollama server with a bearer
+// token, for Ollama servers sitting behind an authenticating reverse proxy.
+package ollamaauth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// DefaultSecretKey is the Secret key holding the token unless configured otherwise
+const DefaultSecretKey = "token"
+
+// ErrNotLoaded is returned for requests made before the token was loaded
+var ErrNotLoaded = errors.New("ollama bearer token has not been loaded yet")
+
+// Transport is an http.RoundTripper adding an Authorization header with a
+// bearer token that can be replaced while the operator runs
+type Transport struct {
+	base  http.RoundTripper
+	token atomic.Pointer[string]
+}
+
+// NewTransport creates a Transport sending requests through base. Requests
+// fail until a token is set.
+func NewTransport(base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{base: base}
+}
+
+// SetToken replaces the token sent with new requests
+func (t *Transport) SetToken(token string) {
+	t.token.Store(&token)
+}
+
+// LoadSecret sets the token from the given key of a Secret
+func (t *Transport) LoadSecret(secret *corev1.Secret, key string) error {
+	token := strings.TrimSpace(string(secret.Data[key]))
+	if token == "" {
+		return fmt.Errorf("key %q is missing or empty", key)
+	}
+	t.SetToken(token)
+	return nil
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token := t.token.Load()
+	if token == nil {
+		return nil, ErrNotLoaded
+	}
+
+	// RoundTrippers must not modify the request they are given
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+*token)
+	return t.base.RoundTrip(req)
+}
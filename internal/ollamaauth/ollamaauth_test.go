@@ -0,0 +1,58 @@
+package ollamaauth
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("Transport", func() {
+	var (
+		server        *httptest.Server
+		authorization string
+		transport     *Transport
+	)
+
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authorization = r.Header.Get("Authorization")
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		DeferCleanup(server.Close)
+		transport = NewTransport(nil)
+	})
+
+	get := func() error {
+		resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		return err
+	}
+
+	It("fails requests until a token is loaded", func() {
+		Expect(errors.Is(get(), ErrNotLoaded)).To(BeTrue())
+	})
+
+	It("sends the token from the Secret, picking up rotations", func() {
+		Expect(transport.LoadSecret(&corev1.Secret{Data: map[string][]byte{"token": []byte("first\n")}}, "token")).To(Succeed())
+		Expect(get()).To(Succeed())
+		Expect(authorization).To(Equal("Bearer first"))
+
+		Expect(transport.LoadSecret(&corev1.Secret{Data: map[string][]byte{"token": []byte("second")}}, "token")).To(Succeed())
+		Expect(get()).To(Succeed())
+		Expect(authorization).To(Equal("Bearer second"))
+	})
+
+	It("keeps the previous token when the key is missing", func() {
+		transport.SetToken("first")
+		Expect(transport.LoadSecret(&corev1.Secret{}, "token")).NotTo(Succeed())
+		Expect(get()).To(Succeed())
+		Expect(authorization).To(Equal("Bearer first"))
+	})
+})
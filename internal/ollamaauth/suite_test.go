@@ -0,0 +1,14 @@
+package ollamaauth
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestOllamaAuth(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Ollama Auth Suite")
+}
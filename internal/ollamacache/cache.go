@@ -0,0 +1,158 @@
+// Package ollamacache caches Ollama Show and List responses for a short time so
+// that resyncing many models does not issue a Show and a full List per model.
+package ollamacache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ollama/ollama/api"
+)
+
+// Upstream is the subset of the Ollama client that is cached or invalidates the cache
+type Upstream interface {
+	Delete(ctx context.Context, req *api.DeleteRequest) error
+	Show(ctx context.Context, req *api.ShowRequest) (*api.ShowResponse, error)
+	Pull(ctx context.Context, req *api.PullRequest, fn api.PullProgressFunc) error
+	Create(ctx context.Context, req *api.CreateRequest, fn api.CreateProgressFunc) error
+	Copy(ctx context.Context, req *api.CopyRequest) error
+	Embed(ctx context.Context, req *api.EmbedRequest) (*api.EmbedResponse, error)
+	List(ctx context.Context) (*api.ListResponse, error)
+}
+
+// verboseSuffix distinguishes the cache keys of verbose Show requests
+const verboseSuffix = "?verbose"
+
+// entry is a cached response and the time it expires
+type entry[T any] struct {
+	value   T
+	expires time.Time
+}
+
+// Client caches successful Show and List responses of an upstream client for
+// a TTL. Pulls, creates, copies and deletes made through the client invalidate
+// the affected entries. Cached responses are shared and must not be modified.
+type Client struct {
+	upstream Upstream
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu    sync.Mutex
+	shows map[string]entry[*api.ShowResponse]
+	list  *entry[*api.ListResponse]
+}
+
+// New wraps upstream with a cache holding responses for ttl
+func New(upstream Upstream, ttl time.Duration) *Client {
+	return &Client{
+		upstream: upstream,
+		ttl:      ttl,
+		now:      time.Now,
+		shows:    make(map[string]entry[*api.ShowResponse]),
+	}
+}
+
+// Show returns the cached details of a model, or fetches them. Errors are not
+// cached, so a model that is missing is looked up again on the next call.
+func (c *Client) Show(ctx context.Context, req *api.ShowRequest) (*api.ShowResponse, error) {
+	key := showKey(req)
+
+	c.mu.Lock()
+	cached, ok := c.shows[key]
+	c.mu.Unlock()
+	if ok && c.now().Before(cached.expires) {
+		return cached.value, nil
+	}
+
+	resp, err := c.upstream.Show(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.shows[key] = entry[*api.ShowResponse]{value: resp, expires: c.now().Add(c.ttl)}
+	c.mu.Unlock()
+	return resp, nil
+}
+
+// List returns the cached list of models, or fetches it
+func (c *Client) List(ctx context.Context) (*api.ListResponse, error) {
+	c.mu.Lock()
+	cached := c.list
+	c.mu.Unlock()
+	if cached != nil && c.now().Before(cached.expires) {
+		return cached.value, nil
+	}
+
+	resp, err := c.upstream.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.list = &entry[*api.ListResponse]{value: resp, expires: c.now().Add(c.ttl)}
+	c.mu.Unlock()
+	return resp, nil
+}
+
+// Pull pulls a model and invalidates its cached details and the model list,
+// whether or not the pull succeeded
+func (c *Client) Pull(ctx context.Context, req *api.PullRequest, fn api.PullProgressFunc) error {
+	defer c.Invalidate(modelKey(req.Model, req.Name))
+	return c.upstream.Pull(ctx, req, fn)
+}
+
+// Create creates a model and invalidates its cached details and the model list,
+// whether or not the model was created
+func (c *Client) Create(ctx context.Context, req *api.CreateRequest, fn api.CreateProgressFunc) error {
+	defer c.Invalidate(modelKey(req.Model, req.Name))
+	return c.upstream.Create(ctx, req, fn)
+}
+
+// Copy copies a model and invalidates the cached details of the destination
+// and the model list
+func (c *Client) Copy(ctx context.Context, req *api.CopyRequest) error {
+	defer c.Invalidate(req.Destination)
+	return c.upstream.Copy(ctx, req)
+}
+
+// Embed generates embeddings, which are never cached
+func (c *Client) Embed(ctx context.Context, req *api.EmbedRequest) (*api.EmbedResponse, error) {
+	return c.upstream.Embed(ctx, req)
+}
+
+// Delete deletes a model and invalidates its cached details and the model list
+func (c *Client) Delete(ctx context.Context, req *api.DeleteRequest) error {
+	defer c.Invalidate(modelKey(req.Model, req.Name))
+	return c.upstream.Delete(ctx, req)
+}
+
+// Invalidate drops the cached details of a model and the model list. Callers
+// that change models on the Ollama server without going through the client
+// use it to avoid serving stale responses until the TTL expires.
+func (c *Client) Invalidate(model string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.shows, model)
+	delete(c.shows, model+verboseSuffix)
+	c.list = nil
+}
+
+// showKey returns the cache key of a Show request
+func showKey(req *api.ShowRequest) string {
+	key := modelKey(req.Model, req.Name)
+	if req.Verbose {
+		key += verboseSuffix
+	}
+	return key
+}
+
+// modelKey returns the model named by a request, which may use either the
+// current Model field or the deprecated Name field
+func modelKey(model, name string) string {
+	if model != "" {
+		return model
+	}
+	return name
+}
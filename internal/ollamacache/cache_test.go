@@ -0,0 +1,114 @@
+package ollamacache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/ollama/ollama/api"
+)
+
+// countingOllama counts the calls reaching the upstream Ollama client
+type countingOllama struct {
+	shows, lists int
+	showErr      error
+}
+
+func (f *countingOllama) Delete(context.Context, *api.DeleteRequest) error { return nil }
+
+func (f *countingOllama) Pull(context.Context, *api.PullRequest, api.PullProgressFunc) error {
+	return nil
+}
+
+func (f *countingOllama) Create(context.Context, *api.CreateRequest, api.CreateProgressFunc) error {
+	return nil
+}
+
+func (f *countingOllama) Copy(context.Context, *api.CopyRequest) error { return nil }
+
+func (f *countingOllama) Embed(context.Context, *api.EmbedRequest) (*api.EmbedResponse, error) {
+	return &api.EmbedResponse{}, nil
+}
+
+func (f *countingOllama) Show(context.Context, *api.ShowRequest) (*api.ShowResponse, error) {
+	f.shows++
+	if f.showErr != nil {
+		return nil, f.showErr
+	}
+	return &api.ShowResponse{Modelfile: "FROM llama3.2:1b"}, nil
+}
+
+func (f *countingOllama) List(context.Context) (*api.ListResponse, error) {
+	f.lists++
+	return &api.ListResponse{Models: []api.ListModelResponse{{Name: "llama3.2:1b"}}}, nil
+}
+
+var _ = Describe("Client", func() {
+	var (
+		upstream *countingOllama
+		cache    *Client
+		now      time.Time
+		ctx      = context.Background()
+	)
+
+	BeforeEach(func() {
+		upstream = &countingOllama{}
+		cache = New(upstream, 10*time.Second)
+		now = time.Date(2025, 3, 25, 19, 0, 0, 0, time.UTC)
+		cache.now = func() time.Time { return now }
+	})
+
+	It("serves Show and List from the cache until the TTL expires", func() {
+		for i := 0; i < 3; i++ {
+			_, err := cache.Show(ctx, &api.ShowRequest{Name: "llama3.2:1b"})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = cache.List(ctx)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(upstream.shows).To(Equal(1))
+		Expect(upstream.lists).To(Equal(1))
+
+		now = now.Add(11 * time.Second)
+		_, _ = cache.Show(ctx, &api.ShowRequest{Name: "llama3.2:1b"})
+		_, _ = cache.List(ctx)
+		Expect(upstream.shows).To(Equal(2))
+		Expect(upstream.lists).To(Equal(2))
+	})
+
+	It("invalidates the model and list after a pull, copy or delete", func() {
+		_, _ = cache.Show(ctx, &api.ShowRequest{Name: "llama3.2:1b"})
+		_, _ = cache.Show(ctx, &api.ShowRequest{Name: "phi3:mini"})
+		_, _ = cache.List(ctx)
+
+		Expect(cache.Pull(ctx, &api.PullRequest{Name: "llama3.2:1b"}, nil)).To(Succeed())
+		_, _ = cache.Show(ctx, &api.ShowRequest{Name: "llama3.2:1b"})
+		_, _ = cache.Show(ctx, &api.ShowRequest{Name: "phi3:mini"})
+		_, _ = cache.List(ctx)
+		Expect(upstream.shows).To(Equal(3))
+		Expect(upstream.lists).To(Equal(2))
+
+		Expect(cache.Delete(ctx, &api.DeleteRequest{Model: "phi3:mini"})).To(Succeed())
+		_, _ = cache.Show(ctx, &api.ShowRequest{Model: "phi3:mini"})
+		Expect(upstream.shows).To(Equal(4))
+
+		Expect(cache.Copy(ctx, &api.CopyRequest{Source: "llama3.2:1b", Destination: "phi3:mini"})).To(Succeed())
+		_, _ = cache.Show(ctx, &api.ShowRequest{Model: "phi3:mini"})
+		_, _ = cache.List(ctx)
+		Expect(upstream.shows).To(Equal(5))
+		Expect(upstream.lists).To(Equal(3))
+	})
+
+	It("does not cache errors", func() {
+		upstream.showErr = errors.New("model not found")
+		_, err := cache.Show(ctx, &api.ShowRequest{Name: "llama3.2:1b"})
+		Expect(err).To(HaveOccurred())
+
+		upstream.showErr = nil
+		_, err = cache.Show(ctx, &api.ShowRequest{Name: "llama3.2:1b"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(upstream.shows).To(Equal(2))
+	})
+})
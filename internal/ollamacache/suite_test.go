@@ -0,0 +1,14 @@
+package ollamacache
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestOllamaCache(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Ollama Cache Suite")
+}
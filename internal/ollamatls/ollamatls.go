@@ -0,0 +1,138 @@
+// Package ollamatls configures TLS for connections to the Ollama server, from
+// files or from a Secret that is reloaded when it changes.
+package ollamatls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync/atomic"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Keys of a Secret holding the TLS configuration, matching kubernetes.io/tls
+// Secrets and the ca.crt key written by cert-manager
+const (
+	CAKey   = "ca.crt"
+	CertKey = corev1.TLSCertKey
+	KeyKey  = corev1.TLSPrivateKeyKey
+)
+
+// ErrNotLoaded is returned for requests made before the TLS configuration was loaded
+var ErrNotLoaded = errors.New("ollama TLS configuration has not been loaded yet")
+
+// Options configures TLS from files
+type Options struct {
+	// CAFile is a PEM bundle of the CAs trusted in addition to the system roots
+	CAFile string
+	// CertFile and KeyFile hold the client certificate presented for mTLS
+	CertFile string
+	KeyFile  string
+	// InsecureSkipVerify disables verification of the server certificate
+	InsecureSkipVerify bool
+}
+
+// Load builds a TLS configuration from the files named by the options
+func (o Options) Load() (*tls.Config, error) {
+	if (o.CertFile == "") != (o.KeyFile == "") {
+		return nil, errors.New("a client certificate and key must be given together")
+	}
+
+	var caPEM, certPEM, keyPEM []byte
+	var err error
+	if o.CAFile != "" {
+		if caPEM, err = os.ReadFile(o.CAFile); err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+	}
+	if o.CertFile != "" {
+		if certPEM, err = os.ReadFile(o.CertFile); err != nil {
+			return nil, fmt.Errorf("failed to read client certificate: %w", err)
+		}
+		if keyPEM, err = os.ReadFile(o.KeyFile); err != nil {
+			return nil, fmt.Errorf("failed to read client key: %w", err)
+		}
+	}
+	return build(caPEM, certPEM, keyPEM, o.InsecureSkipVerify)
+}
+
+// FromSecret builds a TLS configuration from the ca.crt, tls.crt and tls.key
+// keys of a Secret. All keys are optional, but tls.crt and tls.key must be
+// given together.
+func FromSecret(secret *corev1.Secret, insecureSkipVerify bool) (*tls.Config, error) {
+	certPEM, keyPEM := secret.Data[CertKey], secret.Data[KeyKey]
+	if (len(certPEM) == 0) != (len(keyPEM) == 0) {
+		return nil, fmt.Errorf("keys %q and %q must be given together", CertKey, KeyKey)
+	}
+	return build(secret.Data[CAKey], certPEM, keyPEM, insecureSkipVerify)
+}
+
+// build assembles a TLS configuration from PEM data
+func build(caPEM, certPEM, keyPEM []byte, insecureSkipVerify bool) (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: insecureSkipVerify,
+	}
+
+	if len(caPEM) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, errors.New("CA bundle contains no PEM certificates")
+		}
+		config.RootCAs = pool
+	}
+
+	if len(certPEM) > 0 {
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+// Transport is an http.RoundTripper whose TLS configuration can be replaced
+// while the operator runs, so that rotated certificates are picked up without
+// a restart
+type Transport struct {
+	current atomic.Pointer[http.Transport]
+}
+
+// NewTransport creates a Transport using config. A nil config leaves the
+// transport unusable until SetConfig is called, which is the case while a
+// Secret holding the configuration is being loaded.
+func NewTransport(config *tls.Config) *Transport {
+	t := &Transport{}
+	if config != nil {
+		t.SetConfig(config)
+	}
+	return t
+}
+
+// SetConfig replaces the TLS configuration used by new connections and closes
+// the idle connections made with the previous one
+func (t *Transport) SetConfig(config *tls.Config) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = config
+	if previous := t.current.Swap(transport); previous != nil {
+		previous.CloseIdleConnections()
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := t.current.Load()
+	if transport == nil {
+		return nil, ErrNotLoaded
+	}
+	return transport.RoundTrip(req)
+}
@@ -0,0 +1,65 @@
+package ollamatls
+
+import (
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("Transport", func() {
+	var server *httptest.Server
+
+	BeforeEach(func() {
+		server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		DeferCleanup(server.Close)
+	})
+
+	serverCA := func() []byte {
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	}
+
+	get := func(transport *Transport) error {
+		resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		return err
+	}
+
+	It("fails requests until a configuration is loaded", func() {
+		transport := NewTransport(nil)
+		Expect(errors.Is(get(transport), ErrNotLoaded)).To(BeTrue())
+
+		config, err := FromSecret(&corev1.Secret{Data: map[string][]byte{CAKey: serverCA()}}, false)
+		Expect(err).NotTo(HaveOccurred())
+		transport.SetConfig(config)
+		Expect(get(transport)).To(Succeed())
+	})
+
+	It("does not trust servers signed by an unknown CA", func() {
+		config, err := FromSecret(&corev1.Secret{}, false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(get(NewTransport(config))).NotTo(Succeed())
+	})
+
+	It("skips verification when asked to", func() {
+		config, err := Options{InsecureSkipVerify: true}.Load()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(get(NewTransport(config))).To(Succeed())
+	})
+
+	It("rejects a client certificate without its key", func() {
+		_, err := FromSecret(&corev1.Secret{Data: map[string][]byte{CertKey: []byte("cert")}}, false)
+		Expect(err).To(HaveOccurred())
+		_, err = Options{CertFile: "tls.crt"}.Load()
+		Expect(err).To(HaveOccurred())
+	})
+})
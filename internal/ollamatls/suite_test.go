@@ -0,0 +1,14 @@
+package ollamatls
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestOllamaTLS(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Ollama TLS Suite")
+}
@@ -0,0 +1,67 @@
+// Package opconfig holds the runtime configuration of the operator. It starts
+// out with the values given on the command line, which an OllamaOperatorConfig
+// overrides while the operator runs.
+package opconfig
+
+import (
+	"sync/atomic"
+	"time"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+// Settings is a snapshot of the runtime configuration
+type Settings struct {
+	// MaxConcurrentPulls caps the number of models pulled at the same time; 0 removes the cap
+	MaxConcurrentPulls int
+	// ResyncInterval is how often Ready models are checked; 0 disables it
+	ResyncInterval time.Duration
+	// PruneMode controls whether the prune endpoint deletes unmanaged models
+	PruneMode ollamav1alpha1.PruneMode
+	// MaxBodyBytes bounds the size of API request bodies; 0 keeps the API server's limit
+	MaxBodyBytes int64
+}
+
+// Store holds the current Settings. It is safe for concurrent use, and a nil
+// Store returns zero Settings.
+type Store struct {
+	defaults Settings
+	current  atomic.Pointer[Settings]
+}
+
+// NewStore creates a Store starting out with defaults
+func NewStore(defaults Settings) *Store {
+	s := &Store{defaults: defaults}
+	s.current.Store(&defaults)
+	return s
+}
+
+// Get returns the current Settings
+func (s *Store) Get() Settings {
+	if s == nil {
+		return Settings{}
+	}
+	return *s.current.Load()
+}
+
+// Apply overrides the defaults with the fields set in spec and returns the
+// resulting Settings. A nil spec restores the defaults.
+func (s *Store) Apply(spec *ollamav1alpha1.OllamaOperatorConfigSpec) Settings {
+	settings := s.defaults
+	if spec != nil {
+		if spec.MaxConcurrentPulls != nil {
+			settings.MaxConcurrentPulls = int(*spec.MaxConcurrentPulls)
+		}
+		if spec.ResyncInterval != nil {
+			settings.ResyncInterval = spec.ResyncInterval.Duration
+		}
+		if spec.PruneMode != "" {
+			settings.PruneMode = spec.PruneMode
+		}
+		if spec.API != nil && spec.API.MaxBodyBytes != nil {
+			settings.MaxBodyBytes = *spec.API.MaxBodyBytes
+		}
+	}
+	s.current.Store(&settings)
+	return settings
+}
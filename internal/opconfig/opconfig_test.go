@@ -0,0 +1,46 @@
+package opconfig
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+var _ = Describe("Store", func() {
+	defaults := Settings{MaxConcurrentPulls: 1, PruneMode: ollamav1alpha1.PruneEnabled}
+
+	It("overrides the defaults with the fields set in the spec", func() {
+		store := NewStore(defaults)
+		settings := store.Apply(&ollamav1alpha1.OllamaOperatorConfigSpec{
+			ResyncInterval: &metav1.Duration{Duration: 10 * time.Minute},
+			API:            &ollamav1alpha1.OperatorAPIConfig{MaxBodyBytes: ptr.To[int64](4096)},
+		})
+		Expect(settings).To(Equal(Settings{
+			MaxConcurrentPulls: 1,
+			ResyncInterval:     10 * time.Minute,
+			PruneMode:          ollamav1alpha1.PruneEnabled,
+			MaxBodyBytes:       4096,
+		}))
+		Expect(store.Get()).To(Equal(settings))
+	})
+
+	It("restores the defaults when the config is removed", func() {
+		store := NewStore(defaults)
+		store.Apply(&ollamav1alpha1.OllamaOperatorConfigSpec{MaxConcurrentPulls: ptr.To[int32](0)})
+		Expect(store.Get().MaxConcurrentPulls).To(BeZero())
+
+		store.Apply(nil)
+		Expect(store.Get()).To(Equal(defaults))
+	})
+
+	It("returns zero settings when unset", func() {
+		var store *Store
+		Expect(store.Get()).To(BeZero())
+	})
+})
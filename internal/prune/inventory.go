@@ -0,0 +1,124 @@
+package prune
+
+import (
+	"context"
+	"time"
+
+	"github.com/ollama/ollama/api"
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+var (
+	unmanagedModels = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "ollama_unmanaged_models",
+		Help: "Number of models stored on the Ollama server that no OllamaModel manages",
+	})
+
+	unmanagedModel = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ollama_unmanaged_model",
+		Help: "Models stored on the Ollama server that no OllamaModel manages, set to 1",
+	}, []string{"model"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(unmanagedModels, unmanagedModel)
+}
+
+// Lister lists the models stored on the Ollama server
+type Lister interface {
+	List(ctx context.Context) (*api.ListResponse, error)
+}
+
+// Report is the result of an inventory of the Ollama server
+type Report struct {
+	Unmanaged []string  `json:"unmanaged"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+// Inventory periodically lists the unmanaged models, so that admins see the
+// drift before enabling prune. The result is exported as metrics and recorded
+// in the status of the OllamaOperatorConfig.
+type Inventory struct {
+	client     client.Client
+	reader     client.Reader
+	ollama     Lister
+	configName string
+	interval   time.Duration
+}
+
+// NewInventory creates an inventory listing the unmanaged models every
+// interval and recording them in the OllamaOperatorConfig named configName.
+// The models and snapshots are listed with reader, which must see every
+// namespace and label, such as the API reader of the manager when its cache
+// is scoped.
+func NewInventory(c client.Client, reader client.Reader, ollama Lister, configName string, interval time.Duration) *Inventory {
+	return &Inventory{client: c, reader: reader, ollama: ollama, configName: configName, interval: interval}
+}
+
+// Start implements manager.Runnable. It only runs on the leader, so that a
+// single replica writes the status.
+func (i *Inventory) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("inventory")
+	ticker := time.NewTicker(i.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := i.Check(ctx); err != nil {
+			logger.Error(err, "failed to list unmanaged models")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check lists the unmanaged models and publishes the result
+func (i *Inventory) Check(ctx context.Context) (*Report, error) {
+	stored, err := i.ollama.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// The Ollama server is shared by every namespace, so all OllamaModels count
+	var models ollamav1alpha1.OllamaModelList
+	if err := i.reader.List(ctx, &models); err != nil {
+		return nil, err
+	}
+	var snapshots ollamav1alpha1.OllamaModelSnapshotList
+	if err := i.reader.List(ctx, &snapshots); err != nil {
+		return nil, err
+	}
+
+	report := &Report{Unmanaged: Unmanaged(stored.Models, models.Items, snapshots.Items), CheckedAt: time.Now().UTC()}
+	if report.Unmanaged == nil {
+		report.Unmanaged = []string{}
+	}
+
+	unmanagedModels.Set(float64(len(report.Unmanaged)))
+	unmanagedModel.Reset()
+	for _, name := range report.Unmanaged {
+		unmanagedModel.WithLabelValues(name).Set(1)
+	}
+
+	return report, i.recordStatus(ctx, report)
+}
+
+// recordStatus records a report in the status of the OllamaOperatorConfig, if
+// it exists
+func (i *Inventory) recordStatus(ctx context.Context, report *Report) error {
+	config := &ollamav1alpha1.OllamaOperatorConfig{}
+	if err := i.client.Get(ctx, client.ObjectKey{Name: i.configName}, config); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	patch := client.MergeFrom(config.DeepCopy())
+	config.Status.UnmanagedModels = report.Unmanaged
+	config.Status.UnmanagedCheckTime = &metav1.Time{Time: report.CheckedAt}
+	return i.client.Status().Patch(ctx, config, patch)
+}
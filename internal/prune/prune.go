@@ -0,0 +1,45 @@
+// Package prune finds models stored on the Ollama server that no OllamaModel
+// resource manages.
+package prune
+
+import (
+	"sort"
+
+	"github.com/ollama/ollama/api"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+// normalize returns the canonical form of an Ollama model reference, so that
+// models pulled from the public library compare equal to their short names
+func normalize(name string) string {
+	return ollamav1alpha1.NormalizeModelReference(name)
+}
+
+// Unmanaged returns the names of the stored models that are not referenced by
+// any of the given OllamaModels, either as the pulled model or as the model
+// derived with their parameters, nor frozen by any of the given snapshots,
+// sorted by name
+func Unmanaged(stored []api.ListModelResponse, managed []ollamav1alpha1.OllamaModel, snapshots []ollamav1alpha1.OllamaModelSnapshot) []string {
+	references := make(map[string]bool, len(managed))
+	for _, model := range managed {
+		references[normalize(model.Spec.Reference())] = true
+		if model.Status.DerivedModel != "" {
+			references[normalize(model.Status.DerivedModel)] = true
+		}
+	}
+	for _, snapshot := range snapshots {
+		if snapshot.Status.FrozenModel != "" {
+			references[normalize(snapshot.Status.FrozenModel)] = true
+		}
+	}
+
+	var unmanaged []string
+	for _, model := range stored {
+		if !references[normalize(model.Name)] {
+			unmanaged = append(unmanaged, model.Name)
+		}
+	}
+	sort.Strings(unmanaged)
+	return unmanaged
+}
@@ -0,0 +1,323 @@
+package registry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+)
+
+// DefaultRegistry is the registry serving the public Ollama model library
+const DefaultRegistry = "https://registry.ollama.ai"
+
+const (
+	// maxRepositories caps the number of repositories returned per registry
+	maxRepositories = 20
+	// maxTags caps the number of tags resolved per repository
+	maxTags = 25
+	// manifestMediaType is the manifest format served by Ollama registries
+	manifestMediaType = "application/vnd.docker.distribution.manifest.v2+json"
+	// maxResponseBytes caps the manifests, catalogs and tag lists read from a
+	// registry, so that a misbehaving registry cannot exhaust memory
+	maxResponseBytes = 8 << 20
+)
+
+// DefaultTimeout bounds each request to a registry when NewClient is not
+// given an HTTP client
+const DefaultTimeout = 30 * time.Second
+
+// ErrNotFound is returned when a repository or tag does not exist in the registry
+var ErrNotFound = errors.New("not found in registry")
+
+// Tag describes a single tag of a model in a registry
+type Tag struct {
+	Name   string `json:"tag"`
+	Digest string `json:"digest,omitempty"`
+	Size   int64  `json:"size,omitempty"`
+}
+
+// Model describes a model repository available in a registry
+type Model struct {
+	Registry string `json:"registry"`
+	Name     string `json:"name"`
+	Tags     []Tag  `json:"tags"`
+}
+
+// Manifest holds the details of a resolved model manifest
+type Manifest struct {
+	Digest string
+	Size   int64
+}
+
+// Client queries Ollama-compatible registries using the OCI distribution API
+type Client struct {
+	registries []string
+	httpClient *http.Client
+}
+
+// NewClient creates a registry client for the given registry base URLs.
+// The public Ollama library is used when no registries are configured.
+func NewClient(registries []string, httpClient *http.Client) *Client {
+	if len(registries) == 0 {
+		registries = []string{DefaultRegistry}
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: DefaultTimeout}
+	}
+	return &Client{registries: registries, httpClient: httpClient}
+}
+
+// Search returns the models matching query across all configured registries.
+// Registries exposing a catalog are searched by substring; for those that don't
+// (such as the Ollama library), the query is looked up as an exact model name.
+func (c *Client) Search(ctx context.Context, query string) ([]Model, error) {
+	var models []Model
+	var lastErr error
+
+	for _, registry := range c.registries {
+		found, err := c.searchRegistry(ctx, registry, query)
+		if err != nil {
+			lastErr = fmt.Errorf("searching %s: %w", registry, err)
+			continue
+		}
+		models = append(models, found...)
+	}
+
+	if len(models) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	return models, nil
+}
+
+// Resolve looks a model tag up in the configured registries in order and
+// returns the first registry holding it along with its manifest. Names
+// qualified with a registry host, such as "registry.example.com/team/model",
+// are only looked up in that registry. ErrNotFound is returned when no
+// registry has the tag and all of them could be reached.
+func (c *Client) Resolve(ctx context.Context, name, tag string) (string, *Manifest, error) {
+	var lastErr error
+	for _, registry := range c.registriesFor(name) {
+		manifest, err := c.Manifest(ctx, registry, name, tag)
+		if err == nil {
+			return registry, manifest, nil
+		}
+		if !errors.Is(err, ErrNotFound) {
+			lastErr = fmt.Errorf("resolving %s: %w", registry, err)
+		}
+	}
+
+	if lastErr != nil {
+		return "", nil, lastErr
+	}
+	return "", nil, ErrNotFound
+}
+
+// Manifest resolves the manifest of a model tag in the given registry
+func (c *Client) Manifest(ctx context.Context, registry, name, tag string) (*Manifest, error) {
+	repository, err := repositoryPath(repositoryFor(name))
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.get(ctx, registry, fmt.Sprintf("/v2/%s/manifests/%s", repository, url.PathEscape(tag)), manifestMediaType)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxResponseBytes {
+		return nil, fmt.Errorf("manifest exceeds %d bytes", maxResponseBytes)
+	}
+
+	var manifest struct {
+		Config struct {
+			Size int64 `json:"size"`
+		} `json:"config"`
+		Layers []struct {
+			Size int64 `json:"size"`
+		} `json:"layers"`
+	}
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		return nil, fmt.Errorf("decoding manifest: %w", err)
+	}
+
+	result := &Manifest{
+		Digest: resp.Header.Get("Docker-Content-Digest"),
+		Size:   manifest.Config.Size,
+	}
+	for _, layer := range manifest.Layers {
+		result.Size += layer.Size
+	}
+	if result.Digest == "" {
+		sum := sha256.Sum256(body)
+		result.Digest = "sha256:" + hex.EncodeToString(sum[:])
+	}
+
+	return result, nil
+}
+
+// searchRegistry searches a single registry for models matching query
+func (c *Client) searchRegistry(ctx context.Context, registry, query string) ([]Model, error) {
+	repositories, err := c.catalog(ctx, registry, query)
+	if err != nil {
+		// Registries without a catalog only support exact lookups
+		repositories = []string{repositoryFor(query)}
+	}
+
+	host := registry
+	if u, err := url.Parse(registry); err == nil && u.Host != "" {
+		host = u.Host
+	}
+
+	var models []Model
+	for _, repository := range repositories {
+		tags, err := c.tags(ctx, registry, repository)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		name := strings.TrimPrefix(repository, ollamav1alpha1.DefaultModelNamespace+"/")
+		model := Model{Registry: host, Name: name, Tags: make([]Tag, 0, len(tags))}
+		for _, tag := range tags {
+			entry := Tag{Name: tag}
+			// Sizes are best effort; a tag without a resolvable manifest is still listed
+			if manifest, err := c.Manifest(ctx, registry, repository, tag); err == nil {
+				entry.Digest = manifest.Digest
+				entry.Size = manifest.Size
+			}
+			model.Tags = append(model.Tags, entry)
+		}
+		models = append(models, model)
+	}
+
+	return models, nil
+}
+
+// catalog returns the repositories of a registry that contain query
+func (c *Client) catalog(ctx context.Context, registry, query string) ([]string, error) {
+	resp, err := c.get(ctx, registry, "/v2/_catalog", "")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var catalog struct {
+		Repositories []string `json:"repositories"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(&catalog); err != nil {
+		return nil, fmt.Errorf("decoding catalog: %w", err)
+	}
+
+	var matches []string
+	for _, repository := range catalog.Repositories {
+		if strings.Contains(repository, query) {
+			matches = append(matches, repository)
+			if len(matches) == maxRepositories {
+				break
+			}
+		}
+	}
+	return matches, nil
+}
+
+// tags returns the tags of a repository
+func (c *Client) tags(ctx context.Context, registry, repository string) ([]string, error) {
+	path, err := repositoryPath(repository)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.get(ctx, registry, fmt.Sprintf("/v2/%s/tags/list", path), "")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var list struct {
+		Tags []string `json:"tags"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(&list); err != nil {
+		return nil, fmt.Errorf("decoding tags: %w", err)
+	}
+
+	if len(list.Tags) > maxTags {
+		list.Tags = list.Tags[:maxTags]
+	}
+	return list.Tags, nil
+}
+
+// get issues a GET request against a registry and checks the response status
+func (c *Client) get(ctx context.Context, registry, path, accept string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(registry, "/")+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		resp.Body.Close()
+		return nil, ErrNotFound
+	case resp.StatusCode != http.StatusOK:
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status %d from %s", resp.StatusCode, req.URL)
+	}
+	return resp, nil
+}
+
+// registriesFor returns the registries serving a model name: the registry of
+// its host when it names one other than the public library, reached with the
+// URL it is configured with if any, and the configured registries otherwise
+func (c *Client) registriesFor(name string) []string {
+	host := ollamav1alpha1.ParseModelReference(name).Host
+	if host == ollamav1alpha1.DefaultRegistryHost {
+		return c.registries
+	}
+	for _, registry := range c.registries {
+		if u, err := url.Parse(registry); err == nil && strings.EqualFold(u.Host, host) {
+			return []string{registry}
+		}
+	}
+	return []string{"https://" + host}
+}
+
+// repositoryFor maps a model name to its registry repository, dropping its
+// registry host and placing unqualified names in the library namespace
+func repositoryFor(name string) string {
+	return ollamav1alpha1.ParseModelReference(name).Repository()
+}
+
+// repositoryPath escapes each segment of a repository for the path of a
+// registry URL. Repositories with empty, "." or ".." segments, which could
+// reach other paths of the registry, are not found.
+func repositoryPath(repository string) (string, error) {
+	segments := strings.Split(repository, "/")
+	for i, segment := range segments {
+		if segment == "" || segment == "." || segment == ".." {
+			return "", ErrNotFound
+		}
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/"), nil
+}
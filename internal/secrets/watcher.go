@@ -0,0 +1,104 @@
+// Package secrets watches individual Secrets holding operator credentials.
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+
+// Watcher watches a single Secret and invokes a callback whenever it changes.
+// The callback receives nil when the Secret is deleted.
+type Watcher struct {
+	key      types.NamespacedName
+	cache    cache.Cache
+	onChange func(*corev1.Secret)
+}
+
+// NewWatcher creates a Watcher for the Secret identified by key. Only that
+// Secret is cached, so the operator never holds other Secrets in memory.
+func NewWatcher(cfg *rest.Config, scheme *runtime.Scheme, key types.NamespacedName, onChange func(*corev1.Secret)) (*Watcher, error) {
+	c, err := cache.New(cfg, cache.Options{
+		Scheme: scheme,
+		ByObject: map[client.Object]cache.ByObject{
+			&corev1.Secret{}: {
+				Namespaces: map[string]cache.Config{key.Namespace: {}},
+				Field:      fields.OneTermEqualSelector("metadata.name", key.Name),
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Watcher{key: key, cache: c, onChange: onChange}, nil
+}
+
+// ParseKey parses a Secret reference in "namespace/name" or "name" form,
+// using defaultNamespace for the latter
+func ParseKey(ref, defaultNamespace string) (types.NamespacedName, error) {
+	parts := strings.Split(ref, "/")
+	switch {
+	case len(parts) == 1 && parts[0] != "":
+		return types.NamespacedName{Namespace: defaultNamespace, Name: parts[0]}, nil
+	case len(parts) == 2 && parts[0] != "" && parts[1] != "":
+		return types.NamespacedName{Namespace: parts[0], Name: parts[1]}, nil
+	default:
+		return types.NamespacedName{}, fmt.Errorf("invalid secret reference %q, expected [namespace/]name", ref)
+	}
+}
+
+// Start watches the Secret until ctx is cancelled
+func (w *Watcher) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("secret-watcher").WithValues("secret", w.key.String())
+
+	informer, err := w.cache.GetInformer(ctx, &corev1.Secret{})
+	if err != nil {
+		return err
+	}
+
+	_, err = informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			logger.Info("secret loaded")
+			w.notify(obj)
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			logger.Info("secret changed, reloading")
+			w.notify(obj)
+		},
+		DeleteFunc: func(interface{}) {
+			logger.Info("secret deleted")
+			w.onChange(nil)
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return w.cache.Start(ctx)
+}
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface.
+// Every replica needs its own view of the Secret.
+func (w *Watcher) NeedLeaderElection() bool {
+	return false
+}
+
+// notify invokes the callback for an informer object
+func (w *Watcher) notify(obj interface{}) {
+	if secret, ok := obj.(*corev1.Secret); ok {
+		w.onChange(secret)
+	}
+}
@@ -0,0 +1,33 @@
+// Package version holds build information injected at link time, e.g.:
+//
+//	go build -ldflags "-X github.com/dmk/ollama-operator/internal/version.Version=v0.1.0"
+package version
+
+import "runtime"
+
+var (
+	// Version is the operator release version
+	Version = "dev"
+	// GitCommit is the git commit the operator was built from
+	GitCommit = "unknown"
+	// BuildDate is the date the operator was built, in RFC 3339 format
+	BuildDate = "unknown"
+)
+
+// Info describes the operator build
+type Info struct {
+	Version   string `json:"version"`
+	GitCommit string `json:"gitCommit"`
+	BuildDate string `json:"buildDate"`
+	GoVersion string `json:"goVersion"`
+}
+
+// Get returns the build information of the running operator
+func Get() Info {
+	return Info{
+		Version:   Version,
+		GitCommit: GitCommit,
+		BuildDate: BuildDate,
+		GoVersion: runtime.Version(),
+	}
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+	"github.com/dmk/ollama-operator/internal/registry"
+)
+
+// registryCheckTimeout bounds the registry lookup of an admission request, well
+// within the timeout of the API server calling the webhook
+const registryCheckTimeout = 5 * time.Second
+
+// ollamamodellog is for logging in this package.
+var ollamamodellog = logf.Log.WithName("ollamamodel-resource")
+
+// SetupOllamaModelWebhookWithManager registers the webhook for OllamaModel in
+// the manager, looking models up in the registries of registryClient
+func SetupOllamaModelWebhookWithManager(mgr ctrl.Manager, registryClient *registry.Client) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&ollamav1alpha1.OllamaModel{}).
+		WithValidator(&OllamaModelCustomValidator{Registry: registryClient}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-ollama-smithforge-dev-v1alpha1-ollamamodel,mutating=false,failurePolicy=fail,sideEffects=None,groups=ollama.smithforge.dev,resources=ollamamodels,verbs=create;update,versions=v1alpha1,name=vollamamodel-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// OllamaModelCustomValidator validates OllamaModels when they are created or
+// their model changes. Besides the naming rules of Ollama, the model is looked
+// up in the registry, so that a typo in its name or tag is rejected when the
+// model is applied rather than once its pull fails.
+type OllamaModelCustomValidator struct {
+	// Registry looks the models up; nil skips the lookup
+	Registry *registry.Client
+}
+
+var _ webhook.CustomValidator = &OllamaModelCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator
+func (v *OllamaModelCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	model, ok := obj.(*ollamav1alpha1.OllamaModel)
+	if !ok {
+		return nil, fmt.Errorf("expected an OllamaModel object but got %T", obj)
+	}
+	return v.validate(ctx, model)
+}
+
+// ValidateUpdate implements webhook.CustomValidator. The registry is only
+// checked when the model changes, so that a model whose tag was since removed
+// from the registry can still be updated and deleted.
+func (v *OllamaModelCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldModel, ok := oldObj.(*ollamav1alpha1.OllamaModel)
+	if !ok {
+		return nil, fmt.Errorf("expected an OllamaModel object for the old object but got %T", oldObj)
+	}
+	model, ok := newObj.(*ollamav1alpha1.OllamaModel)
+	if !ok {
+		return nil, fmt.Errorf("expected an OllamaModel object for the new object but got %T", newObj)
+	}
+	if !model.DeletionTimestamp.IsZero() || model.Spec.Reference() == oldModel.Spec.Reference() {
+		return nil, nil
+	}
+	return v.validate(ctx, model)
+}
+
+// ValidateDelete implements webhook.CustomValidator
+func (v *OllamaModelCustomValidator) ValidateDelete(context.Context, runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate checks the reference of a model and looks it up in the registry.
+// A registry that cannot be reached lets the model through with a warning, as
+// the pull may still succeed once it is back.
+func (v *OllamaModelCustomValidator) validate(ctx context.Context, model *ollamav1alpha1.OllamaModel) (admission.Warnings, error) {
+	specPath := field.NewPath("spec")
+	if errs := model.Spec.ValidateReference(specPath); len(errs) > 0 {
+		return nil, invalid(model, errs)
+	}
+	// Restored models are not pulled from the registry
+	if v.Registry == nil || model.Spec.RestoreFrom != nil {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, registryCheckTimeout)
+	defer cancel()
+	reference := model.Spec.ParsedReference()
+	source, manifest, err := v.Registry.Resolve(ctx, reference.Name(), reference.Tag)
+	switch {
+	case errors.Is(err, registry.ErrNotFound):
+		refPath := specPath
+		if model.Spec.ModelRef != "" {
+			refPath = specPath.Child("modelRef")
+		}
+		return nil, invalid(model, field.ErrorList{field.Invalid(refPath, model.Spec.Reference(),
+			"the model was not found in the registry, check its name and tag")})
+	case err != nil:
+		ollamamodellog.Info("could not check the model in the registry", "namespace", model.Namespace, "name", model.Name,
+			"model", model.Spec.Reference(), "error", err.Error())
+		return admission.Warnings{fmt.Sprintf("%s could not be checked in the registry: %v", model.Spec.Reference(), err)}, nil
+	}
+	ollamamodellog.Info("checked the model in the registry", "namespace", model.Namespace, "name", model.Name,
+		"model", model.Spec.Reference(), "registry", source, "size", manifest.Size)
+	return nil, nil
+}
+
+// invalid returns the error rejecting a model for errs
+func invalid(model *ollamav1alpha1.OllamaModel, errs field.ErrorList) error {
+	return apierrors.NewInvalid(ollamav1alpha1.GroupVersion.WithKind("OllamaModel").GroupKind(), model.Name, errs)
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ollamav1alpha1 "github.com/dmk/ollama-operator/api/v1alpha1"
+	"github.com/dmk/ollama-operator/internal/registry"
+)
+
+var _ = Describe("OllamaModel Webhook", func() {
+	var (
+		registryServer *httptest.Server
+		validator      *OllamaModelCustomValidator
+	)
+
+	BeforeEach(func() {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/v2/library/llama3.2/manifests/1b", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Docker-Content-Digest", "sha256:abc")
+			_, _ = w.Write([]byte(`{"config":{"size":100},"layers":[{"size":1000}]}`))
+		})
+		registryServer = httptest.NewServer(mux)
+		DeferCleanup(registryServer.Close)
+		validator = &OllamaModelCustomValidator{Registry: registry.NewClient([]string{registryServer.URL}, nil)}
+	})
+
+	model := func(name, tag string) *ollamav1alpha1.OllamaModel {
+		return &ollamav1alpha1.OllamaModel{
+			ObjectMeta: metav1.ObjectMeta{Name: "model", Namespace: "default"},
+			Spec:       ollamav1alpha1.OllamaModelSpec{Name: name, Tag: tag},
+		}
+	}
+
+	It("admits models published in the registry", func() {
+		warnings, err := validator.ValidateCreate(context.Background(), model("llama3.2", "1b"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(BeEmpty())
+	})
+
+	It("rejects models missing from the registry", func() {
+		_, err := validator.ValidateCreate(context.Background(), model("llama3.2", "1bb"))
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("llama3.2:1bb"))
+		Expect(err.Error()).To(ContainSubstring("check its name and tag"))
+	})
+
+	It("rejects models breaking the naming rules without a lookup", func() {
+		registryServer.Close()
+		_, err := validator.ValidateCreate(context.Background(), model("llama 3.2", "1b"))
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+	})
+
+	It("admits models with a warning when the registry cannot be reached", func() {
+		registryServer.Close()
+		warnings, err := validator.ValidateCreate(context.Background(), model("llama3.2", "1b"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(HaveLen(1))
+	})
+
+	It("only looks updated models up when their model changes", func() {
+		old := model("llama3.2", "1bb")
+		updated := old.DeepCopy()
+		updated.Labels = map[string]string{"team": "a"}
+		_, err := validator.ValidateUpdate(context.Background(), old, updated)
+		Expect(err).NotTo(HaveOccurred())
+
+		updated.Spec.Tag = "3bb"
+		_, err = validator.ValidateUpdate(context.Background(), old, updated)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+	})
+
+	It("does not look restored models up", func() {
+		restored := model("llama3.2", "1bb")
+		restored.Spec.RestoreFrom = &ollamav1alpha1.SnapshotReference{Name: "snapshot"}
+		_, err := validator.ValidateCreate(context.Background(), restored)
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
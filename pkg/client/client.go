@@ -0,0 +1,449 @@
+// Package client is a Go client for the ollama-operator REST API.
+//
+//	c, err := client.New(client.Config{BaseURL: "http://ollama-operator:8082", APIKey: key})
+//	model, err := c.Create(ctx, "llama3.2", "1b")
+//	op, err := c.WatchProgress(ctx, model.OperationID, func(op *client.Operation) {
+//		fmt.Println(op.State, op.Progress)
+//	})
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dmk/ollama-operator/internal/version"
+)
+
+// APIVersion is the version of the REST API this client speaks
+const APIVersion = "v1"
+
+const (
+	// DefaultMaxRetries is the number of times a failed idempotent request is retried
+	DefaultMaxRetries = 3
+
+	// initialBackoff is the delay before the first retry; it doubles on each attempt
+	initialBackoff = 250 * time.Millisecond
+)
+
+// Model states
+const (
+	StatePulling  = "Pulling"
+	StateReady    = "Ready"
+	StateFailed   = "Failed"
+	StateDeleting = "Deleting"
+)
+
+// Model is a model managed by the operator
+type Model struct {
+	Name                string            `json:"name"`
+	Namespace           string            `json:"namespace"`
+	ModelName           string            `json:"modelName"`
+	Tag                 string            `json:"tag"`
+	Quantization        string            `json:"quantization,omitempty"`
+	ModelRef            string            `json:"modelRef,omitempty"`
+	Parameters          map[string]string `json:"parameters,omitempty"`
+	System              string            `json:"system,omitempty"`
+	Template            string            `json:"template,omitempty"`
+	Type                string            `json:"type,omitempty"`
+	ExpectedDimensions  *int32            `json:"expectedDimensions,omitempty"`
+	EmbeddingDimensions int32             `json:"embeddingDimensions,omitempty"`
+	DerivedModel        string            `json:"derivedModel,omitempty"`
+	State               string            `json:"state"`
+	Progress            *Progress         `json:"progress,omitempty"`
+	Conditions          []Condition       `json:"conditions,omitempty"`
+	Digest              string            `json:"digest,omitempty"`
+	Size                int64             `json:"size,omitempty"`
+	FormattedSize       string            `json:"formattedSize,omitempty"`
+	LastPullTime        string            `json:"lastPullTime,omitempty"`
+	Error               string            `json:"error,omitempty"`
+	FailureReason       string            `json:"failureReason,omitempty"`
+	PullAttempts        int32             `json:"pullAttempts,omitempty"`
+	LastFailureTime     string            `json:"lastFailureTime,omitempty"`
+	CreatedBy           string            `json:"createdBy,omitempty"`
+	RefreshedBy         string            `json:"refreshedBy,omitempty"`
+	Endpoints           []Endpoint        `json:"endpoints,omitempty"`
+	OperationID         string            `json:"operationId,omitempty"`
+}
+
+// Progress is the download progress of a model being pulled
+type Progress struct {
+	Percent          int32  `json:"percent"`
+	CompletedBytes   int64  `json:"completedBytes,omitempty"`
+	TotalBytes       int64  `json:"totalBytes,omitempty"`
+	BytesPerSecond   int64  `json:"bytesPerSecond,omitempty"`
+	RemainingSeconds int64  `json:"remainingSeconds,omitempty"`
+	Message          string `json:"message,omitempty"`
+}
+
+// Condition is a condition of a model, such as Ready or Stale
+type Condition struct {
+	Type               string `json:"type"`
+	Status             string `json:"status"`
+	Reason             string `json:"reason,omitempty"`
+	Message            string `json:"message,omitempty"`
+	LastTransitionTime string `json:"lastTransitionTime,omitempty"`
+}
+
+// Endpoint is a model on one Ollama server
+type Endpoint struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	State  string `json:"state,omitempty"`
+	Digest string `json:"digest,omitempty"`
+}
+
+// Operation is a long-running create or refresh of a model
+type Operation struct {
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	State     string `json:"state"`
+	Progress  string `json:"progress,omitempty"`
+	Completed int64  `json:"completed,omitempty"`
+	Total     int64  `json:"total,omitempty"`
+	Estimate  string `json:"estimate,omitempty"`
+	Error     string `json:"error,omitempty"`
+	CreatedAt string `json:"createdAt"`
+	Result    *Model `json:"result,omitempty"`
+}
+
+// Error is returned for API responses with a non-success status
+type Error struct {
+	StatusCode int
+	// Code classifies the error, such as NotFound, AlreadyExists,
+	// ValidationFailed or BackendUnavailable
+	Code    string
+	Message string
+	// Details lists the fields of the request that failed validation
+	Details []ErrorDetail
+	// Retryable tells whether the same request may succeed later
+	Retryable bool
+	RequestID string
+}
+
+// ErrorDetail describes why a field of a request is invalid
+type ErrorDetail struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	if e.RequestID != "" {
+		return fmt.Sprintf("ollama-operator API: %s (status %d, request %s)", e.Message, e.StatusCode, e.RequestID)
+	}
+	return fmt.Sprintf("ollama-operator API: %s (status %d)", e.Message, e.StatusCode)
+}
+
+// IsNotFound reports whether err is an API error with status 404
+func IsNotFound(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// IsConflict reports whether err is an API error with status 409
+func IsConflict(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict
+}
+
+// Config configures a Client
+type Config struct {
+	// BaseURL is the address of the API server, e.g. http://ollama-operator:8082
+	BaseURL string
+	// APIKey is sent in the X-API-Key header when set
+	APIKey string
+	// Namespace selects the namespace of the models; the server's default
+	// namespace is used when empty
+	Namespace string
+	// HTTPClient is used for requests; http.DefaultClient when nil
+	HTTPClient *http.Client
+	// MaxRetries is the number of retries of idempotent requests that fail
+	// with a network error or a 429 or 5xx status. Zero selects
+	// DefaultMaxRetries; use a negative value to disable retries.
+	MaxRetries int
+}
+
+// Client talks to the ollama-operator REST API
+type Client struct {
+	baseURL    *url.URL
+	apiKey     string
+	namespace  string
+	httpClient *http.Client
+	maxRetries int
+}
+
+// New creates a client from config
+func New(config Config) (*Client, error) {
+	baseURL, err := url.Parse(strings.TrimSuffix(config.BaseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if baseURL.Scheme == "" || baseURL.Host == "" {
+		return nil, fmt.Errorf("invalid base URL %q: scheme and host are required", config.BaseURL)
+	}
+
+	c := &Client{
+		baseURL:    baseURL,
+		apiKey:     config.APIKey,
+		namespace:  config.Namespace,
+		httpClient: config.HTTPClient,
+		maxRetries: config.MaxRetries,
+	}
+	if c.httpClient == nil {
+		c.httpClient = http.DefaultClient
+	}
+	switch {
+	case c.maxRetries == 0:
+		c.maxRetries = DefaultMaxRetries
+	case c.maxRetries < 0:
+		c.maxRetries = 0
+	}
+	return c, nil
+}
+
+// List returns the models in the client's namespace
+func (c *Client) List(ctx context.Context) ([]Model, error) {
+	var list struct {
+		Items []Model `json:"items"`
+	}
+	if err := c.do(ctx, http.MethodGet, c.modelsPath(), nil, &list, true); err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// Get returns a model by its resource name, e.g. llama3.2-1b
+func (c *Client) Get(ctx context.Context, name string) (*Model, error) {
+	model := &Model{}
+	if err := c.do(ctx, http.MethodGet, c.modelPath(name), nil, model, true); err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+// Create starts pulling a model. The returned model is usually still pending;
+// use WatchProgress or GetOperation to follow the pull.
+func (c *Client) Create(ctx context.Context, name, tag string) (*Model, error) {
+	body := map[string]string{"name": name, "tag": tag}
+	model := &Model{}
+	// Creates are not retried, as a lost response would turn the retry into a conflict
+	if err := c.do(ctx, http.MethodPost, c.modelsPath(), body, model, false); err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+// Delete deletes a model by its resource name
+func (c *Client) Delete(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodDelete, c.modelPath(name), nil, nil, true)
+}
+
+// Refresh requests a model to be pulled again
+func (c *Client) Refresh(ctx context.Context, name string) (*Model, error) {
+	model := &Model{}
+	if err := c.do(ctx, http.MethodPost, c.modelPath(name)+"/refresh", nil, model, true); err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+// Retry requests a Failed model to be pulled again, even when its retries are
+// exhausted
+func (c *Client) Retry(ctx context.Context, name string) (*Model, error) {
+	model := &Model{}
+	if err := c.do(ctx, http.MethodPost, c.modelPath(name)+"/retry", nil, model, true); err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+// CancelPull requests the running pull or refresh of a model to be cancelled
+func (c *Client) CancelPull(ctx context.Context, name string) (*Model, error) {
+	model := &Model{}
+	if err := c.do(ctx, http.MethodDelete, c.modelPath(name)+"/pull", nil, model, true); err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+// GetOperation returns the state of a create or refresh operation
+func (c *Client) GetOperation(ctx context.Context, id string) (*Operation, error) {
+	op := &Operation{}
+	if err := c.do(ctx, http.MethodGet, "/api/"+APIVersion+"/operations/"+url.PathEscape(id), nil, op, true); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+// Export returns the models of the client's namespace, or of all namespaces,
+// as a YAML List of OllamaModel manifests pinned to their pulled digests
+func (c *Client) Export(ctx context.Context, allNamespaces bool) ([]byte, error) {
+	path := c.scopedPath("/export")
+	if allNamespaces {
+		path += "?allNamespaces=true"
+	}
+
+	var manifests []byte
+	if err := c.do(ctx, http.MethodGet, path, nil, &manifests, true); err != nil {
+		return nil, err
+	}
+	return manifests, nil
+}
+
+// scopedPath returns the path of a namespace-scoped endpoint in the client's namespace
+func (c *Client) scopedPath(endpoint string) string {
+	if c.namespace == "" {
+		return "/api/" + APIVersion + endpoint
+	}
+	return "/api/" + APIVersion + "/namespaces/" + url.PathEscape(c.namespace) + endpoint
+}
+
+// modelsPath returns the path of the models collection in the client's namespace
+func (c *Client) modelsPath() string {
+	return c.scopedPath("/models")
+}
+
+// modelPath returns the path of a single model
+func (c *Client) modelPath(name string) string {
+	return c.modelsPath() + "/" + url.PathEscape(name)
+}
+
+// do sends a request with an optional JSON body and decodes the JSON response
+// into out. If out is a *[]byte, the response is requested as YAML and stored
+// as is. Idempotent requests are retried with exponential backoff.
+func (c *Client) do(ctx context.Context, method, path string, in, out interface{}, idempotent bool) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return err
+		}
+	}
+
+	accept := "application/json"
+	if _, raw := out.(*[]byte); raw {
+		accept = "application/yaml"
+	}
+
+	attempts := 1
+	if idempotent {
+		attempts += c.maxRetries
+	}
+
+	var lastErr error
+	backoff := initialBackoff
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		resp, err := c.send(ctx, method, path, accept, body)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			lastErr = err
+			continue
+		}
+
+		err = decodeResponse(resp, out)
+		if !retryable(err) {
+			return err
+		}
+		lastErr = err
+	}
+	return lastErr
+}
+
+// send issues a single request
+func (c *Client) send(ctx context.Context, method, path, accept string, body []byte) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL.String()+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", accept)
+	req.Header.Set("User-Agent", "ollama-operator-client/"+version.Version)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.apiKey != "" {
+		req.Header.Set("X-API-Key", c.apiKey)
+	}
+
+	return c.httpClient.Do(req)
+}
+
+// decodeResponse closes resp after decoding its body into out, or returns an
+// *Error for non-success statuses
+func decodeResponse(resp *http.Response, out interface{}) error {
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := &Error{StatusCode: resp.StatusCode, RequestID: resp.Header.Get("X-Request-ID")}
+		var body struct {
+			Code      string        `json:"code"`
+			Message   string        `json:"message"`
+			Details   []ErrorDetail `json:"details"`
+			Retryable bool          `json:"retryable"`
+			RequestID string        `json:"requestID"`
+			Error     string        `json:"error"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err == nil && (body.Message != "" || body.Error != "") {
+			apiErr.Code = body.Code
+			apiErr.Message = body.Message
+			apiErr.Details = body.Details
+			apiErr.Retryable = body.Retryable
+			// Servers of earlier versions only send error
+			if apiErr.Message == "" {
+				apiErr.Message = body.Error
+			}
+			if apiErr.RequestID == "" {
+				apiErr.RequestID = body.RequestID
+			}
+		} else {
+			apiErr.Message = http.StatusText(resp.StatusCode)
+		}
+		return apiErr
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if raw, ok := out.(*[]byte); ok {
+		var err error
+		*raw, err = io.ReadAll(resp.Body)
+		return err
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
+
+// retryable reports whether a request failing with err may succeed when retried
+func retryable(err error) bool {
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= 500
+}
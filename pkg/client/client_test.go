@@ -0,0 +1,148 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Client", func() {
+	var (
+		server  *httptest.Server
+		handler http.HandlerFunc
+		c       *Client
+	)
+
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			handler(w, r)
+		}))
+		DeferCleanup(server.Close)
+
+		var err error
+		c, err = New(Config{BaseURL: server.URL, APIKey: "secret", Namespace: "team-a"})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	reply := func(w http.ResponseWriter, status int, body interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		Expect(json.NewEncoder(w).Encode(body)).To(Succeed())
+	}
+
+	It("lists models in its namespace with the API key", func() {
+		handler = func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.URL.Path).To(Equal("/api/v1/namespaces/team-a/models"))
+			Expect(r.Header.Get("X-API-Key")).To(Equal("secret"))
+			reply(w, http.StatusOK, map[string]interface{}{"items": []Model{{Name: "llama3.2-1b", State: StateReady}}})
+		}
+
+		models, err := c.List(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(models).To(HaveLen(1))
+		Expect(models[0].State).To(Equal(StateReady))
+	})
+
+	It("returns API errors with their status and request ID", func() {
+		handler = func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Request-ID", "req-1")
+			reply(w, http.StatusNotFound, map[string]string{"error": "model not found: phi3-mini"})
+		}
+
+		_, err := c.Get(context.Background(), "phi3-mini")
+		Expect(IsNotFound(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("model not found: phi3-mini"))
+		Expect(err.Error()).To(ContainSubstring("req-1"))
+	})
+
+	It("decodes structured API errors", func() {
+		handler = func(w http.ResponseWriter, r *http.Request) {
+			reply(w, http.StatusConflict, map[string]interface{}{
+				"code":      "AlreadyExists",
+				"message":   "model already exists: phi3-mini",
+				"retryable": false,
+				"requestID": "req-2",
+				"error":     "model already exists: phi3-mini",
+			})
+		}
+
+		_, err := c.Create(context.Background(), "phi3", "mini")
+		var apiErr *Error
+		Expect(errors.As(err, &apiErr)).To(BeTrue())
+		Expect(apiErr.Code).To(Equal("AlreadyExists"))
+		Expect(apiErr.Message).To(Equal("model already exists: phi3-mini"))
+		Expect(apiErr.RequestID).To(Equal("req-2"))
+		Expect(IsConflict(err)).To(BeTrue())
+	})
+
+	It("retries idempotent requests on server errors", func() {
+		var calls atomic.Int32
+		handler = func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) == 1 {
+				reply(w, http.StatusServiceUnavailable, map[string]string{"error": "not ready"})
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}
+
+		Expect(c.Delete(context.Background(), "phi3-mini")).To(Succeed())
+		Expect(calls.Load()).To(Equal(int32(2)))
+	})
+
+	It("does not retry creates", func() {
+		var calls atomic.Int32
+		handler = func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			reply(w, http.StatusInternalServerError, map[string]string{"error": "boom"})
+		}
+
+		_, err := c.Create(context.Background(), "phi3", "mini")
+		Expect(err).To(HaveOccurred())
+		Expect(calls.Load()).To(Equal(int32(1)))
+	})
+
+	It("follows an operation until it finishes", func() {
+		var calls atomic.Int32
+		handler = func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.URL.Path).To(Equal("/api/v1/operations/op-1"))
+			op := Operation{ID: "op-1", Type: "create", Name: "phi3-mini", State: OperationRunning, Progress: "pulling manifest"}
+			if calls.Add(1) > 1 {
+				op.State, op.Error = OperationFailed, "manifest unknown"
+			}
+			reply(w, http.StatusOK, op)
+		}
+
+		var seen []string
+		op, err := c.WatchProgress(context.Background(), "op-1", func(op *Operation) {
+			seen = append(seen, op.State)
+		})
+		Expect(err).To(MatchError(ContainSubstring("manifest unknown")))
+		Expect(op.State).To(Equal(OperationFailed))
+		Expect(seen).To(Equal([]string{OperationRunning, OperationFailed}))
+	})
+
+	It("exports manifests as YAML", func() {
+		handler = func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.URL.Path).To(Equal("/api/v1/namespaces/team-a/export"))
+			Expect(r.URL.Query().Get("allNamespaces")).To(Equal("true"))
+			Expect(r.Header.Get("Accept")).To(Equal("application/yaml"))
+			w.Header().Set("Content-Type", "application/yaml")
+			_, _ = w.Write([]byte("apiVersion: v1\nkind: List\nitems: []\n"))
+		}
+
+		manifests, err := c.Export(context.Background(), true)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(manifests)).To(HavePrefix("apiVersion: v1"))
+	})
+
+	It("rejects base URLs without a host", func() {
+		_, err := New(Config{BaseURL: "ollama-operator:8082"})
+		Expect(err).To(HaveOccurred())
+	})
+})
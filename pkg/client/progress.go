@@ -0,0 +1,54 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Operation states
+const (
+	OperationPending   = "pending"
+	OperationRunning   = "running"
+	OperationSucceeded = "succeeded"
+	OperationFailed    = "failed"
+)
+
+// progressPollInterval is how often WatchProgress polls an operation
+const progressPollInterval = time.Second
+
+// WatchProgress follows the operation returned by Create or Refresh (see
+// Model.OperationID) until it finishes, calling fn whenever its state or
+// progress changes. It returns the finished operation, and an error if the
+// operation failed.
+func (c *Client) WatchProgress(ctx context.Context, operationID string, fn func(*Operation)) (*Operation, error) {
+	ticker := time.NewTicker(progressPollInterval)
+	defer ticker.Stop()
+
+	var last Operation
+	for {
+		op, err := c.GetOperation(ctx, operationID)
+		if err != nil {
+			return nil, err
+		}
+
+		if fn != nil && (op.State != last.State || op.Progress != last.Progress || op.Completed != last.Completed ||
+			op.Estimate != last.Estimate) {
+			fn(op)
+		}
+		last = *op
+
+		switch op.State {
+		case OperationSucceeded:
+			return op, nil
+		case OperationFailed:
+			return op, fmt.Errorf("%s of %s failed: %s", op.Type, op.Name, op.Error)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}